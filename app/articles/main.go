@@ -0,0 +1,131 @@
+// Command articles exports and imports articles in bulk against the database
+// configured for the server.
+//
+//	articles export [-format jsonl|csv|markdown] [-o file]
+//	articles import [-format jsonl|csv|markdown|wxr] [-dry-run] [-author-map old=new,...] [-default-author username] file
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"net/url"
+	"os"
+	"strings"
+
+	_ "github.com/go-sql-driver/mysql"
+	"github.com/joho/godotenv"
+	"gorm.io/driver/mysql"
+	"gorm.io/gorm"
+
+	"github.com/bxcodec/go-clean-arch/domain"
+	"github.com/bxcodec/go-clean-arch/internal/content"
+	mysqlRepo "github.com/bxcodec/go-clean-arch/internal/repository/mysql"
+	"github.com/bxcodec/go-clean-arch/internal/rest/response"
+	"github.com/bxcodec/go-clean-arch/internal/usecase/transfer"
+)
+
+func main() {
+	if len(os.Args) < 2 {
+		usage()
+	}
+	// the environment may come from the shell rather than a .env file
+	_ = godotenv.Load()
+
+	switch os.Args[1] {
+	case "export":
+		export(os.Args[2:])
+	case "import":
+		importArticles(os.Args[2:])
+	default:
+		usage()
+	}
+}
+
+func usage() {
+	fmt.Fprintln(os.Stderr, "usage: articles export [-format jsonl|csv|markdown] [-o file]")
+	fmt.Fprintln(os.Stderr, "       articles import [-format jsonl|csv|markdown|wxr] [-dry-run] [-author-map old=new,...] [-default-author username] file")
+	os.Exit(2)
+}
+
+func export(args []string) {
+	fs := flag.NewFlagSet("export", flag.ExitOnError)
+	format := fs.String("format", string(domain.TransferJSONL), "jsonl, csv or markdown")
+	output := fs.String("o", "", "output file, standard output when empty")
+	_ = fs.Parse(args)
+
+	var w io.Writer = os.Stdout
+	if *output != "" {
+		f, err := os.Create(*output)
+		if err != nil {
+			log.Fatal(err)
+		}
+		defer f.Close()
+		w = f
+	}
+	if err := service().Export(context.Background(), domain.TransferFormat(*format), w); err != nil {
+		log.Fatal("failed to export articles: ", err)
+	}
+}
+
+func importArticles(args []string) {
+	fs := flag.NewFlagSet("import", flag.ExitOnError)
+	format := fs.String("format", string(domain.TransferJSONL), "jsonl, csv, markdown or wxr")
+	dryRun := fs.Bool("dry-run", false, "validate and report without writing")
+	authorMap := fs.String("author-map", "", "comma separated old=new usernames")
+	defaultAuthor := fs.String("default-author", "", "username of the articles whose author is unknown")
+	_ = fs.Parse(args)
+	if fs.NArg() != 1 {
+		usage()
+	}
+
+	opts := domain.ImportOptions{DryRun: *dryRun, AuthorMap: map[string]string{}, DefaultAuthor: *defaultAuthor}
+	for _, pair := range strings.Split(*authorMap, ",") {
+		if pair = strings.TrimSpace(pair); pair == "" {
+			continue
+		}
+		from, to, ok := strings.Cut(pair, "=")
+		if !ok {
+			log.Fatalf("invalid author mapping %q", pair)
+		}
+		opts.AuthorMap[from] = to
+	}
+
+	f, err := os.Open(fs.Arg(0))
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer f.Close()
+	report, err := service().Import(context.Background(), domain.TransferFormat(*format), f, opts)
+	if err != nil {
+		log.Fatal("failed to import articles: ", err)
+	}
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(response.NewImportReportFromDomain(&report)); err != nil {
+		log.Fatal(err)
+	}
+}
+
+func service() *transfer.Service {
+	connection := fmt.Sprintf("%s:%s@tcp(%s:%s)/%s",
+		os.Getenv("DATABASE_USER"), os.Getenv("DATABASE_PASS"),
+		os.Getenv("DATABASE_HOST"), os.Getenv("DATABASE_PORT"), os.Getenv("DATABASE_NAME"))
+	val := url.Values{}
+	val.Add("parseTime", "1")
+	val.Add("loc", "Asia/Jakarta")
+	db, err := gorm.Open(mysql.Open(fmt.Sprintf("%s?%s", connection, val.Encode())), &gorm.Config{})
+	if err != nil {
+		log.Fatal("failed to open connection to database: ", err)
+	}
+	return transfer.NewService(
+		mysqlRepo.NewArticleRepository(db),
+		mysqlRepo.NewUserRepository(db),
+		mysqlRepo.NewCategoryRepository(db),
+		mysqlRepo.NewContributorRepository(db),
+		content.NewRenderer(),
+	)
+}
@@ -9,6 +9,7 @@ import (
 	"os"
 	"os/signal"
 	"strconv"
+	"strings"
 	"syscall"
 	"time"
 
@@ -18,14 +19,33 @@ import (
 	"gorm.io/driver/mysql"
 	"gorm.io/gorm"
 
+	"github.com/bxcodec/go-clean-arch/domain"
+	"github.com/bxcodec/go-clean-arch/internal/blob"
+	"github.com/bxcodec/go-clean-arch/internal/content"
+	"github.com/bxcodec/go-clean-arch/internal/events"
+	"github.com/bxcodec/go-clean-arch/internal/leader"
+	"github.com/bxcodec/go-clean-arch/internal/queue"
 	mysqlRepo "github.com/bxcodec/go-clean-arch/internal/repository/mysql"
 	myRedisCache "github.com/bxcodec/go-clean-arch/internal/repository/redis"
+	"github.com/bxcodec/go-clean-arch/internal/sharelink"
 	"github.com/bxcodec/go-clean-arch/internal/workers"
 
 	"github.com/bxcodec/go-clean-arch/internal/rest"
 	"github.com/bxcodec/go-clean-arch/internal/rest/middleware"
 	"github.com/bxcodec/go-clean-arch/internal/usecase/article"
+	"github.com/bxcodec/go-clean-arch/internal/usecase/attachment"
+	"github.com/bxcodec/go-clean-arch/internal/usecase/bookmark"
+	"github.com/bxcodec/go-clean-arch/internal/usecase/comment"
+	"github.com/bxcodec/go-clean-arch/internal/usecase/feed"
+	"github.com/bxcodec/go-clean-arch/internal/usecase/notification"
+	"github.com/bxcodec/go-clean-arch/internal/usecase/reaction"
+	"github.com/bxcodec/go-clean-arch/internal/usecase/related"
+	"github.com/bxcodec/go-clean-arch/internal/usecase/series"
+	"github.com/bxcodec/go-clean-arch/internal/usecase/sitemap"
+	"github.com/bxcodec/go-clean-arch/internal/usecase/syndication"
+	"github.com/bxcodec/go-clean-arch/internal/usecase/transfer"
 	"github.com/bxcodec/go-clean-arch/internal/usecase/user"
+	"github.com/bxcodec/go-clean-arch/internal/usecase/webhook"
 	"github.com/joho/godotenv"
 )
 
@@ -35,6 +55,10 @@ const (
 	defaultCacheDB     = 0
 	dbMaxRetry         = 10
 	dbRetryIntervalSec = 2
+	// shutdownTimeout bounds the wait for requests in flight, workerStopTimeout
+	// the one for the workers to flush and release their lease
+	shutdownTimeout   = 5 * time.Second
+	workerStopTimeout = 30 * time.Second
 )
 
 func init() {
@@ -135,13 +159,26 @@ func main() {
 		timeout = defaultTimeout
 	}
 	timeoutContext := time.Duration(timeout) * time.Second
-	route.Use(middleware.SetRequestContextWithTimeout(timeoutContext))
+	// the stream is long lived and ends when the client disconnects, bulk
+	// transfers take as long as the amount of articles requires
+	route.Use(middleware.SetRequestContextWithTimeout(timeoutContext, "/articles/stream", "/admin/articles/export", "/admin/articles/import"))
 
 	// Prepare Repository
 	userRepo := mysqlRepo.NewUserRepository(db)
 	articleRepo := mysqlRepo.NewArticleRepository(db)
 	articleCache := myRedisCache.NewArticleCache(client)
 
+	replicaID := os.Getenv("REPLICA_ID")
+	if replicaID == "" {
+		replicaID = defaultReplicaID()
+	}
+
+	// feeds and sitemaps are read elsewhere, their links need the absolute address of the site
+	baseURL := os.Getenv("BASE_URL")
+	if u, err := url.Parse(baseURL); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		log.Fatalf("BASE_URL must be the absolute address of the site, got %q", baseURL)
+	}
+
 	// Build service Layer
 	jwtSecret := []byte(os.Getenv("JWT_SECRET"))
 	jwtTTLStr := os.Getenv("JWT_EXPIRE_HOURS")
@@ -150,32 +187,283 @@ func main() {
 		log.Println("failed to parse JWT TTL, using default 24 hours")
 		jwtTTL = 24
 	}
-	articleSvc := article.NewService(articleRepo, userRepo, articleCache)
+	jobQueue := queue.NewRedisQueue(client, queue.RedisOptions{Consumer: replicaID})
+	broadcaster := events.NewBroadcaster(client, events.DefaultReplaySize)
+	seriesRepo := mysqlRepo.NewSeriesRepository(db)
+	contributorRepo := mysqlRepo.NewContributorRepository(db)
+	categoryRepo := mysqlRepo.NewCategoryRepository(db)
+	shareSecret := []byte(os.Getenv("SHARE_LINK_SECRET"))
+	if len(shareSecret) == 0 {
+		shareSecret = jwtSecret
+	}
+	similarityThreshold, err := strconv.ParseFloat(os.Getenv("DUPLICATE_SIMILARITY_THRESHOLD"), 64)
+	if err != nil || similarityThreshold <= 0 || similarityThreshold > 1 {
+		log.Println("failed to parse duplicate similarity threshold, using default", article.DefaultSimilarityThreshold)
+		similarityThreshold = article.DefaultSimilarityThreshold
+	}
+	rejectDuplicates, _ := strconv.ParseBool(os.Getenv("DUPLICATE_REJECT"))
+	relatedCache := myRedisCache.NewRelatedCache(client)
+	articleSvc := article.NewService(articleRepo, userRepo, articleCache, jobQueue, broadcaster, relatedCache, seriesRepo, contributorRepo, sharelink.NewSigner(shareSecret), content.NewRenderer(), categoryRepo,
+		article.DuplicatePolicy{Threshold: similarityThreshold, Reject: rejectDuplicates})
 	userSvc := user.NewService(userRepo, jwtSecret, time.Duration(jwtTTL)*time.Hour)
+	allowPrivateWebhooks, _ := strconv.ParseBool(os.Getenv("WEBHOOK_ALLOW_PRIVATE_NETWORKS"))
+	webhookSvc := webhook.NewService(mysqlRepo.NewWebhookRepository(db), contributorRepo, allowPrivateWebhooks)
+	followRepo := mysqlRepo.NewFollowRepository(db)
+	notificationRepo := mysqlRepo.NewNotificationRepository(db)
+	notificationSvc := notification.NewService(notificationRepo, followRepo)
+	requireApproval, _ := strconv.ParseBool(os.Getenv("COMMENTS_REQUIRE_APPROVAL"))
+	commentSvc := comment.NewService(mysqlRepo.NewCommentRepository(db), articleRepo, userRepo, articleCache, contributorRepo, notificationSvc, requireApproval)
+	reactionRepo := mysqlRepo.NewReactionRepository(db)
+	reactionCache := myRedisCache.NewReactionCache(client)
+	reactionSvc := reaction.NewService(reactionRepo, reactionCache, articleRepo, notificationSvc, reactionKinds())
+	bookmarkSvc := bookmark.NewService(mysqlRepo.NewBookmarkRepository(db), articleRepo)
+	fanOutLimit, err := strconv.ParseInt(os.Getenv("FEED_FANOUT_LIMIT"), 10, 64)
+	if err != nil {
+		fanOutLimit = feed.DefaultFanOutLimit
+	}
+	feedSvc := feed.NewService(followRepo, articleRepo, userRepo, myRedisCache.NewFeedCache(client), notificationSvc, fanOutLimit)
 	articleHandler := rest.NewArticleHandler(articleSvc)
 	userHandler := rest.NewUserHandler(userSvc)
+	webhookHandler := rest.NewWebhookHandler(webhookSvc)
+	streamHandler := rest.NewStreamHandler(broadcaster)
+	commentHandler := rest.NewCommentHandler(commentSvc)
+	reactionHandler := rest.NewReactionHandler(reactionSvc)
+	bookmarkHandler := rest.NewBookmarkHandler(bookmarkSvc)
+	feedHandler := rest.NewFeedHandler(feedSvc)
+	notificationHandler := rest.NewNotificationHandler(notificationSvc)
+	contributorHandler := rest.NewContributorHandler(articleSvc)
+	batchHandler := rest.NewBatchHandler(articleSvc)
+	similarContentHandler := rest.NewSimilarContentHandler(articleSvc)
+	articleSourceHandler := rest.NewArticleSourceHandler(articleSvc)
+	shareLinkHandler := rest.NewShareLinkHandler(articleSvc)
+	seriesHandler := rest.NewSeriesHandler(series.NewService(seriesRepo, articleRepo, userRepo))
+	uploadMaxBytes, err := strconv.ParseInt(os.Getenv("UPLOAD_MAX_BYTES"), 10, 64)
+	if err != nil || uploadMaxBytes <= 0 {
+		log.Println("failed to parse upload size limit, using default", attachment.DefaultMaxSize, "bytes")
+		uploadMaxBytes = attachment.DefaultMaxSize
+	}
+	uploadQuotaBytes, err := strconv.ParseInt(os.Getenv("UPLOAD_QUOTA_BYTES"), 10, 64)
+	if err != nil || uploadQuotaBytes <= 0 {
+		log.Println("failed to parse upload quota, using default", attachment.DefaultQuota, "bytes")
+		uploadQuotaBytes = attachment.DefaultQuota
+	}
+	attachmentSvc := attachment.NewService(mysqlRepo.NewAttachmentRepository(db), blobStore(), articleRepo, contributorRepo, uploadMaxBytes, uploadQuotaBytes)
+	attachmentHandler := rest.NewAttachmentHandler(attachmentSvc)
+	feedSize, err := strconv.ParseInt(os.Getenv("SYNDICATION_FEED_SIZE"), 10, 64)
+	if err != nil || feedSize <= 0 {
+		log.Println("failed to parse syndication feed size, using default", syndication.DefaultSize)
+		feedSize = syndication.DefaultSize
+	}
+	syndicationSvc := syndication.NewService(articleRepo, userRepo, categoryRepo, myRedisCache.NewSyndicationCache(client), baseURL, feedSize)
+	syndicationHandler := rest.NewSyndicationHandler(syndicationSvc)
+	sitemapSvc, err := sitemap.NewService(mysqlRepo.NewSitemapRepository(db), myRedisCache.NewSitemapCache(client), baseURL)
+	if err != nil {
+		log.Fatal(err)
+	}
+	sitemapHandler := rest.NewSitemapHandler(sitemapSvc)
+	relatedSvc := related.NewService(articleRepo, userRepo, categoryRepo, relatedCache)
+	relatedHandler := rest.NewRelatedHandler(relatedSvc)
+	transferHandler := rest.NewTransferHandler(transfer.NewService(articleRepo, userRepo, categoryRepo, contributorRepo, content.NewRenderer()))
 
 	authMiddleware := middleware.AuthMiddleware(string(jwtSecret))
+	optionalAuthMiddleware := middleware.OptionalAuthMiddleware(string(jwtSecret))
+	adminMiddleware := middleware.AdminMiddleware(adminUserIDs())
 
 	// Start worker
-	syncer := workers.NewSyncViewWorker(articleRepo, articleCache)
+	leaseTTL, err := time.ParseDuration(os.Getenv("LEADER_LEASE_TTL"))
+	if err != nil {
+		log.Println("failed to parse leader lease TTL, using default TTL")
+		leaseTTL = 0
+	}
+	elector := leader.NewElector(client, replicaID, leaseTTL)
+	scheduler := workers.NewScheduler(elector)
+	statusHandler := rest.NewStatusHandler(elector, scheduler)
+
+	syncer := workers.NewSyncViewWorker(articleRepo, articleCache, elector)
+	if err := scheduler.Register(syncer.Job()); err != nil {
+		log.Fatal("failed to register job: ", err)
+	}
+	reactionSyncer := workers.NewSyncReactionsWorker(reactionRepo, reactionCache, elector)
+	if err := scheduler.Register(reactionSyncer.Job()); err != nil {
+		log.Fatal("failed to register job: ", err)
+	}
+
+	notificationRetention := workers.NewNotificationRetentionWorker(notificationRepo, workers.DefaultReadNotificationRetention, workers.DefaultNotificationRetention)
+	if err := scheduler.Register(notificationRetention.Job()); err != nil {
+		log.Fatal("failed to register job: ", err)
+	}
+
+	attachmentCleanup := workers.NewAttachmentCleanupWorker(attachmentSvc)
+	if err := scheduler.Register(attachmentCleanup.Job()); err != nil {
+		log.Fatal("failed to register job: ", err)
+	}
+
+	contentRender := workers.NewContentRenderWorker(articleSvc)
+	if err := scheduler.Register(contentRender.Job()); err != nil {
+		log.Fatal("failed to register job: ", err)
+	}
+
+	sitemapWorker := workers.NewSitemapWorker(sitemapSvc)
+	if err := scheduler.Register(sitemapWorker.Job()); err != nil {
+		log.Fatal("failed to register job: ", err)
+	}
+
+	relatedWorker := workers.NewRelatedWorker(relatedSvc)
+	if err := scheduler.Register(relatedWorker.Job()); err != nil {
+		log.Fatal("failed to register job: ", err)
+	}
+
+	sinks := append(eventSinks(client), webhookSvc, broadcaster, feedSvc, notificationSvc, syndicationSvc, sitemapSvc, relatedSvc)
+	outboxRelay := workers.NewOutboxRelayWorker(mysqlRepo.NewOutboxRepository(db), elector, sinks...)
+	if err := scheduler.Register(outboxRelay.Job()); err != nil {
+		log.Fatal("failed to register job: ", err)
+	}
+	webhookDelivery := workers.NewWebhookDeliveryWorker(webhookSvc)
+	if err := scheduler.Register(webhookDelivery.Job()); err != nil {
+		log.Fatal("failed to register job: ", err)
+	}
+
+	jobMux := queue.NewMux()
+	jobMux.Handle(article.JobRefreshCache, articleSvc.HandleRefreshCache)
+	err = scheduler.RegisterDaemon(workers.Daemon{
+		Name: "job-queue-consumer",
+		Mode: workers.EveryReplica,
+		Run: func(ctx context.Context) error {
+			return jobQueue.Consume(ctx, jobMux.Process)
+		},
+	})
+	if err != nil {
+		log.Fatal("failed to register daemon: ", err)
+	}
+	err = scheduler.RegisterDaemon(workers.Daemon{
+		Name: "article-stream",
+		Mode: workers.EveryReplica,
+		Run:  broadcaster.Run,
+	})
+	if err != nil {
+		log.Fatal("failed to register daemon: ", err)
+	}
+
 	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
 	defer stop()
 
-	syncer.Start(ctx)
+	scheduler.Start(ctx)
+	// the elector outlives the scheduler so that leader-only jobs flush while
+	// this replica still holds the lease, it is only released once they are done
+	electorCtx, stopElector := context.WithCancel(context.Background())
+	defer stopElector()
+	electorDone := make(chan struct{})
+	go func() {
+		defer close(electorDone)
+		elector.Run(electorCtx)
+	}()
 
 	// Register routes
 	route.POST("/register", userHandler.Register)
 	route.POST("/login", userHandler.Login)
+	route.POST("/password-reset", userHandler.ResetPassword)
+	route.GET("/series", seriesHandler.Fetch)
+	route.GET("/series/:id", seriesHandler.GetByID)
+	route.GET("/users/:id/followers", feedHandler.Followers)
+	route.GET("/users/:id/following", feedHandler.Following)
 
 	route.GET("/articles", articleHandler.FetchArticle)
-	route.GET("/articles/:id", articleHandler.GetByID)
+	route.GET("/articles/stream", streamHandler.Articles)
+	route.GET("/articles/:id", optionalAuthMiddleware, articleHandler.GetByID)
+	route.GET("/articles/:id/comments", commentHandler.Fetch)
+	route.GET("/articles/:id/contributors", optionalAuthMiddleware, contributorHandler.Fetch)
+	route.GET("/articles/:id/related", relatedHandler.Fetch)
+	route.GET("/articles/:id/attachments", optionalAuthMiddleware, attachmentHandler.Fetch)
+	route.GET("/files/*key", optionalAuthMiddleware, attachmentHandler.Serve)
+	route.GET("/articles/:id/reactions", optionalAuthMiddleware, reactionHandler.Summary)
+
+	for _, feed := range []struct {
+		file   string
+		format domain.SyndicationFormat
+	}{
+		{"rss.xml", domain.SyndicationRSS},
+		{"atom.xml", domain.SyndicationAtom},
+		{"feed.json", domain.SyndicationJSON},
+	} {
+		route.GET("/feeds/"+feed.file, syndicationHandler.Feed(feed.format))
+		route.GET("/feeds/authors/:id/"+feed.file, syndicationHandler.Feed(feed.format))
+		route.GET("/feeds/tags/:tag/"+feed.file, syndicationHandler.Feed(feed.format))
+	}
+
+	route.GET("/sitemap.xml", sitemapHandler.Index)
+	route.GET("/sitemaps/:file", sitemapHandler.Chunk)
 
 	authorized := route.Group("/")
 	authorized.Use(authMiddleware)
 	{
 		authorized.POST("/articles", articleHandler.Store)
+		authorized.POST("/articles/batch", batchHandler.Apply)
+		authorized.PUT("/articles/:id", articleHandler.Update)
 		authorized.DELETE("/articles/:id", articleHandler.Delete)
+		authorized.GET("/articles/:id/source", articleSourceHandler.Fetch)
+
+		authorized.PUT("/articles/:id/contributors/:userID", contributorHandler.Store)
+		authorized.DELETE("/articles/:id/contributors/:userID", contributorHandler.Delete)
+		authorized.POST("/articles/:id/share-links", shareLinkHandler.Store)
+		authorized.GET("/articles/:id/similar-content", similarContentHandler.Fetch)
+		authorized.PUT("/articles/:id/attachments", attachmentHandler.Link)
+		authorized.POST("/attachments", attachmentHandler.Upload)
+		authorized.GET("/attachments/usage", attachmentHandler.Usage)
+		authorized.DELETE("/attachments/:id", attachmentHandler.Delete)
+
+		authorized.POST("/articles/:id/comments", commentHandler.Store)
+		authorized.GET("/articles/:id/comments/moderation", commentHandler.FetchForModeration)
+		authorized.PUT("/comments/:id", commentHandler.Update)
+		authorized.DELETE("/comments/:id", commentHandler.Delete)
+		authorized.PUT("/comments/:id/status", commentHandler.Moderate)
+
+		authorized.PUT("/articles/:id/reactions/:kind", reactionHandler.React)
+		authorized.DELETE("/articles/:id/reactions/:kind", reactionHandler.Unreact)
+
+		authorized.GET("/bookmarks", bookmarkHandler.Fetch)
+		authorized.PUT("/bookmarks/:id", bookmarkHandler.Store)
+		authorized.DELETE("/bookmarks/:id", bookmarkHandler.Delete)
+		authorized.PUT("/bookmarks/:id/read", bookmarkHandler.MarkRead)
+		authorized.GET("/reading-lists", bookmarkHandler.FetchLists)
+		authorized.POST("/reading-lists", bookmarkHandler.StoreList)
+		authorized.PUT("/reading-lists/:id", bookmarkHandler.UpdateList)
+		authorized.DELETE("/reading-lists/:id", bookmarkHandler.DeleteList)
+		authorized.GET("/reading-lists/:id/bookmarks", bookmarkHandler.FetchList)
+		authorized.PUT("/reading-lists/:id/order", bookmarkHandler.Reorder)
+
+		authorized.POST("/series", seriesHandler.Store)
+		authorized.PUT("/series/:id", seriesHandler.Update)
+		authorized.DELETE("/series/:id", seriesHandler.Delete)
+		authorized.PUT("/series/:id/articles", seriesHandler.SetArticles)
+
+		authorized.POST("/users/:id/follow", feedHandler.Follow)
+		authorized.DELETE("/users/:id/follow", feedHandler.Unfollow)
+		authorized.GET("/feed", feedHandler.Feed)
+
+		authorized.GET("/notifications", notificationHandler.Fetch)
+		authorized.GET("/notifications/unread-count", notificationHandler.UnreadCount)
+		authorized.PUT("/notifications/read", notificationHandler.MarkAllRead)
+		authorized.PUT("/notifications/:id/read", notificationHandler.MarkRead)
+		authorized.GET("/notifications/preferences", notificationHandler.Preferences)
+		authorized.PUT("/notifications/preferences", notificationHandler.UpdatePreferences)
+
+		authorized.POST("/webhooks", webhookHandler.Store)
+		authorized.GET("/webhooks", webhookHandler.Fetch)
+		authorized.GET("/webhooks/:id", webhookHandler.GetByID)
+		authorized.PUT("/webhooks/:id", webhookHandler.Update)
+		authorized.DELETE("/webhooks/:id", webhookHandler.Delete)
+		authorized.GET("/webhooks/:id/deliveries", webhookHandler.FetchDeliveries)
+		authorized.GET("/webhooks/:id/deliveries/:deliveryID", webhookHandler.GetDelivery)
+		authorized.POST("/webhooks/:id/deliveries/:deliveryID/replay", webhookHandler.Replay)
+
+		admin := authorized.Group("/admin")
+		admin.Use(adminMiddleware)
+		admin.GET("/articles/export", transferHandler.Export)
+		admin.POST("/articles/import", transferHandler.Import)
+		admin.POST("/users/:id/password-reset", userHandler.CreatePasswordReset)
+		// replica ids, fencing tokens and job errors are for operators only
+		admin.GET("/status/leader", statusHandler.Leader)
+		admin.GET("/status/jobs", statusHandler.Jobs)
 	}
 
 	// Start Server
@@ -187,6 +475,8 @@ func main() {
 		Addr:    address,
 		Handler: route,
 	}
+	// Shutdown does not cancel requests, the live streams are ended first
+	srv.RegisterOnShutdown(broadcaster.Close)
 	go func() {
 		log.Printf("Server is running on %s\n", address)
 		if err := srv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
@@ -198,15 +488,113 @@ func main() {
 	<-ctx.Done()
 	log.Println("Shutdown signal received, stopping server...")
 
-	shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
 	defer cancel()
 
+	// the workers still flush and release the lease when requests are cut off
 	if err := srv.Shutdown(shutdownCtx); err != nil {
-		log.Fatal("Server forced to shutdown: ", err)
+		log.Println("Server forced to shutdown: ", err)
 	}
 
-	log.Println("Waiting for worker to cleanup...")
-	time.Sleep(2 * time.Second)
+	log.Println("Waiting for workers to cleanup...")
+	stopCtx, cancelStop := context.WithTimeout(context.Background(), workerStopTimeout)
+	defer cancelStop()
+	if err := scheduler.Stop(stopCtx); err != nil {
+		log.Println("Workers did not stop in time: ", err)
+	}
+	stopElector()
+	<-electorDone
 
 	log.Println("Server exiting")
 }
+
+// defaultReplicaID identifies this process when REPLICA_ID is not set
+func defaultReplicaID() string {
+	host, err := os.Hostname()
+	if err != nil {
+		host = "unknown"
+	}
+	return fmt.Sprintf("%s-%d", host, os.Getpid())
+}
+
+// reactionKinds reads the reaction set from REACTIONS (comma separated), like is always included
+func reactionKinds() []string {
+	var kinds []string
+	for _, kind := range strings.Split(os.Getenv("REACTIONS"), ",") {
+		if kind = strings.TrimSpace(kind); kind != "" {
+			kinds = append(kinds, kind)
+		}
+	}
+	return kinds
+}
+
+// adminUserIDs reads the ids of the administrators from ADMIN_USER_IDS (comma separated)
+func adminUserIDs() []int64 {
+	var ids []int64
+	for _, field := range strings.Split(os.Getenv("ADMIN_USER_IDS"), ",") {
+		if field = strings.TrimSpace(field); field == "" {
+			continue
+		}
+		id, err := strconv.ParseInt(field, 10, 64)
+		if err != nil {
+			log.Fatalf("invalid admin user id %q", field)
+		}
+		ids = append(ids, id)
+	}
+	return ids
+}
+
+// blobStore builds the store of uploaded files selected by BLOB_STORE: local
+// (files under BLOB_LOCAL_DIR) or s3 (any S3 compatible service)
+func blobStore() domain.BlobStore {
+	switch os.Getenv("BLOB_STORE") {
+	case "", "local":
+		dir := os.Getenv("BLOB_LOCAL_DIR")
+		if dir == "" {
+			dir = "uploads"
+		}
+		store, err := blob.NewLocalStore(dir)
+		if err != nil {
+			log.Fatal("failed to prepare the upload directory: ", err)
+		}
+		return store
+	case "s3":
+		return blob.NewS3Store(blob.S3Config{
+			Endpoint:  os.Getenv("S3_ENDPOINT"),
+			Region:    os.Getenv("S3_REGION"),
+			Bucket:    os.Getenv("S3_BUCKET"),
+			AccessKey: os.Getenv("S3_ACCESS_KEY"),
+			SecretKey: os.Getenv("S3_SECRET_KEY"),
+		}, nil)
+	default:
+		log.Fatalf("unknown blob store %q", os.Getenv("BLOB_STORE"))
+		return nil
+	}
+}
+
+// eventSinks builds the outbox sinks listed in OUTBOX_SINKS (comma separated: log, redis, webhook)
+func eventSinks(client *redis.Client) []domain.EventSink {
+	names := os.Getenv("OUTBOX_SINKS")
+	if names == "" {
+		names = "log"
+	}
+
+	var sinks []domain.EventSink
+	for _, name := range strings.Split(names, ",") {
+		switch strings.TrimSpace(name) {
+		case "log":
+			sinks = append(sinks, events.NewLogSink())
+		case "redis":
+			sinks = append(sinks, events.NewRedisStreamSink(client, os.Getenv("OUTBOX_STREAM")))
+		case "webhook":
+			webhookURL := os.Getenv("OUTBOX_WEBHOOK_URL")
+			if webhookURL == "" {
+				log.Fatal("OUTBOX_WEBHOOK_URL is required for the webhook sink")
+			}
+			sinks = append(sinks, events.NewWebhookSink(webhookURL, nil))
+		default:
+			log.Printf("unknown outbox sink %q, skipping", name)
+		}
+	}
+	return sinks
+}
@@ -5,25 +5,229 @@ import (
 	"time"
 )
 
+type ArticleStatus string
+
+// Valid reports whether the status is one of the known article statuses
+func (s ArticleStatus) Valid() bool {
+	return s == ArticleStatusDraft || s == ArticleStatusPublished
+}
+
+const (
+	ArticleStatusDraft     ArticleStatus = "draft"
+	ArticleStatusPublished ArticleStatus = "published"
+)
+
+type ArticleVisibility string
+
+const (
+	// ArticleVisibilityPublic articles are listed everywhere
+	ArticleVisibilityPublic ArticleVisibility = "public"
+	// ArticleVisibilityUnlisted articles are readable by link but left out of listings and feeds
+	ArticleVisibilityUnlisted ArticleVisibility = "unlisted"
+	// ArticleVisibilityPrivate articles are only readable by their contributors and through share links
+	ArticleVisibilityPrivate ArticleVisibility = "private"
+)
+
+// Valid reports whether the visibility is one of the known article visibilities
+func (v ArticleVisibility) Valid() bool {
+	return v == ArticleVisibilityPublic || v == ArticleVisibilityUnlisted || v == ArticleVisibilityPrivate
+}
+
+type ContentFormat string
+
+const (
+	ContentFormatMarkdown ContentFormat = "markdown"
+	ContentFormatHTML     ContentFormat = "html"
+)
+
+// Valid reports whether the format is one of the known content formats
+func (f ContentFormat) Valid() bool {
+	return f == ContentFormatMarkdown || f == ContentFormatHTML
+}
+
+// TOCEntry is a heading of an article, ID is the anchor of the heading in the rendered HTML
+type TOCEntry struct {
+	Level int
+	ID    string
+	Text  string
+}
+
+// RenderedContent is the sanitized HTML of an article and the metadata derived from it
+type RenderedContent struct {
+	HTML      string
+	Excerpt   string
+	WordCount int64
+	TOC       []TOCEntry
+	// Fingerprint is the SimHash of the text, zero when it has no words
+	Fingerprint uint64
+}
+
+// ContentRenderer turns article sources into sanitized HTML
+type ContentRenderer interface {
+	// Render converts the source to HTML, strips everything outside the allowed
+	// markup and gives every heading an anchor
+	Render(format ContentFormat, source string) (RenderedContent, error)
+}
+
+// Viewer is the reader of an article, UserID is empty for anonymous readers
+// and ShareToken holds the share link they followed, if any
+type Viewer struct {
+	UserID     int64
+	ShareToken string
+}
+
+// ShareLink grants read access to an article until it expires
+type ShareLink struct {
+	ArticleID int64
+	Token     string
+	ExpiresAt time.Time
+}
+
+// ShareSigner signs and verifies share link tokens
+type ShareSigner interface {
+	Sign(articleID int64, expiresAt time.Time) string
+	// Verify returns the article a token grants access to, false when it is invalid or expired
+	Verify(token string) (articleID int64, ok bool)
+}
+
 // Article is representing the Article data struct
 type Article struct {
-	ID        int64
-	Title     string
-	Content   string
-	User      User
-	UpdatedAt time.Time
-	CreatedAt time.Time
-	Views     int64
+	ID    int64
+	Title string
+	// Content is the source as written by the author in ContentFormat
+	Content       string
+	ContentFormat ContentFormat
+	// ContentHTML is the sanitized rendering of Content, the only form served to readers
+	ContentHTML string
+	// Excerpt is the beginning of the plain text of the article
+	Excerpt        string
+	WordCount      int64
+	ReadingMinutes int64
+	// TOC lists the headings of the article in document order
+	TOC []TOCEntry
+	// Fingerprint is the SimHash of the text of the article, zero until it is
+	// rendered or when it has no words
+	Fingerprint uint64
+	Status      ArticleStatus
+	// Visibility is empty for articles cached before visibilities existed, they are public
+	Visibility ArticleVisibility
+	User       User
+	UpdatedAt  time.Time
+	CreatedAt  time.Time
+	Views      int64
+	// CommentCount is the number of approved, not deleted comments
+	CommentCount int64
+	// Contributors lists everyone who worked on the article, starting with User as the author
+	Contributors []Contributor
+	// Tags is only loaded by the reads that show it
+	Tags []Category
+	// Series is set on single article reads when the article is part of a series,
+	// it is left out of the cache so navigation always reflects the current series
+	Series *SeriesNavigation `json:"-"`
+	// SimilarContent is only set by stores and updates, it lists the articles
+	// whose content is a near duplicate of this one
+	SimilarContent []SimilarArticle `json:"-"`
+}
+
+// Listed reports whether the article shows up in listings and feeds
+func (a *Article) Listed() bool {
+	return a.Status == ArticleStatusPublished &&
+		a.Visibility != ArticleVisibilityUnlisted && a.Visibility != ArticleVisibilityPrivate
+}
+
+// Reachable reports whether anyone with a link to the article may read it
+func (a *Article) Reachable() bool {
+	return a.Status == ArticleStatusPublished && a.Visibility != ArticleVisibilityPrivate
+}
+
+// Contributes reports whether the user is one of the contributors of the article
+func (a *Article) Contributes(userID int64) bool {
+	if userID == 0 {
+		return false
+	}
+	for _, c := range a.Contributors {
+		if c.User.ID == userID {
+			return true
+		}
+	}
+	return false
+}
+
+// ViewBatch is a set of buffered view deltas keyed by article id.
+// ID identifies the batch so that it is applied to the database at most once.
+// A batch synced by the leader carries its FencingToken so that a write from a
+// replica that lost the lease is rejected, it is 0 for direct writes.
+type ViewBatch struct {
+	ID           string
+	Views        map[int64]int64
+	FencingToken int64
+}
+
+// StoreOptions are the extras of storing an article
+type StoreOptions struct {
+	// CategoryIDs are linked to the article along with it
+	CategoryIDs []int64
+	// NewCategories are created and linked in the same transaction, a
+	// category whose tag exists by then is linked instead. Their ids are set
+	// even when the article is not stored in the end.
+	NewCategories []Category
+	// Imported articles come from another site, they are stored without an
+	// ArticlePublished event since they are not news to followers and subscribers
+	Imported bool
 }
 
 type ArticleRepository interface {
-	Fetch(ctx context.Context, cursor string, num int64) (res []Article, nextCursor string, err error)
+	// Fetch returns a page of the listed articles, their content and TOC are
+	// only loaded when withContent is set
+	Fetch(ctx context.Context, cursor string, num int64, withContent bool) (res []Article, nextCursor string, err error)
 	GetByID(ctx context.Context, id int64) (Article, error)
 	GetByTitle(ctx context.Context, title string) (Article, error)
-	AddViews(ctx context.Context, id int64, newViews int64) error
-	Update(ctx context.Context, ar *Article) error
+	// FetchByTitleSlug returns the articles without their content whose title
+	// may have the given slug. Titles are compared ignoring case and accents,
+	// so the slugs of the results still have to be checked.
+	FetchByTitleSlug(ctx context.Context, slug string) ([]Article, error)
+	// FetchByIDs returns the articles with the given ids that still exist without
+	// their content, in no particular order
+	FetchByIDs(ctx context.Context, ids []int64) ([]Article, error)
+	// FetchByAuthors returns up to num listed articles of the given authors created
+	// before the given time without their content, newest first
+	FetchByAuthors(ctx context.Context, authorIDs []int64, before time.Time, num int64) ([]Article, error)
+	// FetchSimilar returns up to limit articles other than excludeID whose
+	// fingerprint is at most maxDistance bits away, the closest first
+	FetchSimilar(ctx context.Context, fingerprint uint64, maxDistance int, excludeID int64, limit int) ([]SimilarArticle, error)
+	// ApplyChanges writes all the changes in one transaction, a failing change
+	// is reported as a *BatchItemError and none of them is kept
+	ApplyChanges(ctx context.Context, changes []ArticleChange) error
+	// FetchAfter returns up to limit articles of any status with an id above
+	// afterID, in id order. Their content and TOC are only loaded when
+	// withContent is set.
+	FetchAfter(ctx context.Context, afterID int64, limit int, withContent bool) ([]Article, error)
+	// FetchRenderedByIDs returns the id, title and rendered content of the
+	// articles with the given ids that still exist, in no particular order
+	FetchRenderedByIDs(ctx context.Context, ids []int64) ([]Article, error)
+	// FetchRecent returns up to num listed articles with their content, newest
+	// first, of one author and one category when they are set
+	FetchRecent(ctx context.Context, authorID, categoryID int64, num int64) ([]Article, error)
+	// AddViews applies a view batch, a batch with a stale fencing token is
+	// rejected with ErrStaleLeader
+	AddViews(ctx context.Context, batch ViewBatch) error
+	// PurgeViewBatches forgets the applied view batches recorded before the given
+	// time, they can no longer be redelivered
+	PurgeViewBatches(ctx context.Context, before time.Time) error
+	// Update applies the non zero fields of the article on behalf of editorID,
+	// whose role is checked under the article row lock, see ArticleChange
+	Update(ctx context.Context, ar *Article, editorID int64) error
 	Store(ctx context.Context, a *Article) error
+	// StoreWith stores the article with its categories in a single transaction
+	StoreWith(ctx context.Context, a *Article, opts StoreOptions) error
 	Delete(ctx context.Context, id int64) error
+	// FetchUnrendered returns up to limit articles with an id greater than afterID
+	// that were stored before their content was rendered or their metadata
+	// computed, in id order
+	FetchUnrendered(ctx context.Context, afterID int64, limit int) ([]Article, error)
+	// SaveRendering stores the rendered content and metadata of an article without
+	// touching UpdatedAt or recording events, the source is unchanged
+	SaveRendering(ctx context.Context, ar *Article) error
 }
 
 type ArticleCache interface {
@@ -31,13 +235,18 @@ type ArticleCache interface {
 	Set(ctx context.Context, ar *Article) (err error)
 	Del(ctx context.Context, id int64) (err error)
 	Incr(ctx context.Context, id int64) (views int64, err error)
-	FetchAndResetViews(ctx context.Context) (map[int64]int64, error)
+	FetchViews(ctx context.Context) (ViewBatch, error)
+	AckViews(ctx context.Context, batchID string, ids []int64) error
+	// FailViews counts a failed sync of the pending view batch. Once it failed
+	// maxAttempts times the batch is moved aside so that newer views are synced,
+	// which is reported as true.
+	FailViews(ctx context.Context, batchID string, maxAttempts int) (bool, error)
 }
 
 type ArticleUsecase interface {
-	Fetch(ctx context.Context, cursor string, num int64) ([]Article, string, error)
-	GetByID(ctx context.Context, id int64) (Article, error)
+	Fetch(ctx context.Context, cursor string, num int64, withContent bool) ([]Article, string, error)
+	GetByID(ctx context.Context, id int64, viewer Viewer) (Article, error)
 	Store(ctx context.Context, ar *Article) error
 	Update(ctx context.Context, ar *Article) error
-	Delete(ctx context.Context, id int64) error
+	Delete(ctx context.Context, userID, id int64) error
 }
@@ -0,0 +1,73 @@
+package domain
+
+import (
+	"context"
+	"io"
+	"strings"
+	"time"
+)
+
+// BlobStore keeps the files of attachments under opaque keys
+type BlobStore interface {
+	Put(ctx context.Context, key string, r io.Reader, size int64, contentType string) error
+	// Get returns ErrNotFound when nothing is stored under the key
+	Get(ctx context.Context, key string) (io.ReadCloser, error)
+	// Delete succeeds when nothing is stored under the key
+	Delete(ctx context.Context, key string) error
+}
+
+// Attachment is a file uploaded by a user, ArticleID is zero until it is
+// linked to an article
+type Attachment struct {
+	ID        int64
+	UserID    int64
+	ArticleID int64
+	Key       string
+	// ThumbnailKey is empty for files that are not images
+	ThumbnailKey string
+	Filename     string
+	ContentType  string
+	Size         int64
+	// ThumbnailSize is the size of the thumbnail, it counts against the quota too
+	ThumbnailSize int64
+	// Width and Height are set for images
+	Width     int
+	Height    int
+	CreatedAt time.Time
+}
+
+func (a *Attachment) IsImage() bool {
+	return strings.HasPrefix(a.ContentType, "image/")
+}
+
+// StoredSize is the storage used by the attachment with its thumbnail
+func (a *Attachment) StoredSize() int64 {
+	return a.Size + a.ThumbnailSize
+}
+
+// AttachmentFile is the content of an attachment or of its thumbnail. Public
+// is set when the file belongs to a listed article and may be cached by anyone.
+type AttachmentFile struct {
+	Attachment  Attachment
+	Content     io.ReadCloser
+	ContentType string
+	Public      bool
+}
+
+type AttachmentRepository interface {
+	// Store inserts the attachment, failing with ErrQuotaExceeded when the
+	// attachments of its user would exceed quota bytes
+	Store(ctx context.Context, a *Attachment, quota int64) error
+	GetByID(ctx context.Context, id int64) (Attachment, error)
+	// GetByKey returns the attachment stored or thumbnailed under key
+	GetByKey(ctx context.Context, key string) (Attachment, error)
+	FetchByArticle(ctx context.Context, articleID int64) ([]Attachment, error)
+	// Link links the given attachments of userID to an article and reports how many it linked
+	Link(ctx context.Context, userID, articleID int64, ids []int64) (int64, error)
+	// Usage returns the total size of the attachments of a user
+	Usage(ctx context.Context, userID int64) (int64, error)
+	// FetchOrphans returns up to limit attachments created before the given time
+	// that are not linked to an existing article
+	FetchOrphans(ctx context.Context, before time.Time, limit int) ([]Attachment, error)
+	Delete(ctx context.Context, ids []int64) error
+}
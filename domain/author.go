@@ -15,6 +15,10 @@ type User struct {
 	UpdatedAt time.Time
 }
 
+// UnusablePassword is stored for users created without a password, it never
+// matches one so they sign in once they went through a password reset
+const UnusablePassword = "!"
+
 type UserRepository interface {
 	GetByID(ctx context.Context, id int64) (User, error)
 	Insert(ctx context.Context, a *User) error
@@ -0,0 +1,66 @@
+package domain
+
+import "fmt"
+
+// MaxBatchSize bounds the operations of a batch
+const MaxBatchSize = 100
+
+type BatchAction string
+
+const (
+	// BatchUpdate changes the fields that are set and replaces the tags when they are set
+	BatchUpdate  BatchAction = "update"
+	BatchDelete  BatchAction = "delete"
+	BatchPublish BatchAction = "publish"
+)
+
+func (a BatchAction) Valid() bool {
+	return a == BatchUpdate || a == BatchDelete || a == BatchPublish
+}
+
+// BatchOperation is one operation of a batch on an article
+type BatchOperation struct {
+	Action    BatchAction
+	ArticleID int64
+	// Changes holds the fields of an update, zero fields are left alone
+	Changes Article
+	// Tags replaces the tags of the article on update when it is not nil
+	Tags []string
+}
+
+// BatchResult is the outcome of the operation at the same index of a batch.
+// An operation without error that was not applied was rolled back with its batch.
+type BatchResult struct {
+	ArticleID int64
+	Action    BatchAction
+	Applied   bool
+	Err       error
+}
+
+// ArticleChange is an operation of a batch ready to be written
+type ArticleChange struct {
+	// UserID makes the change. Their role is checked again under the article
+	// row lock so that it cannot be revoked between the check and the write:
+	// a delete takes the author, other changes an editor, else ErrForbidden.
+	UserID int64
+	Delete bool
+	// Article holds the id and the fields to update
+	Article Article
+	// SetTags replaces the categories of the article with CategoryIDs
+	SetTags     bool
+	CategoryIDs []int64
+}
+
+// BatchItemError is the error of the change at Index of a batch
+type BatchItemError struct {
+	Index int
+	Err   error
+}
+
+func (e *BatchItemError) Error() string {
+	return fmt.Sprintf("change %d: %v", e.Index, e.Err)
+}
+
+func (e *BatchItemError) Unwrap() error {
+	return e.Err
+}
@@ -0,0 +1,63 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+// ReadingList is a named, ordered collection of bookmarks of one user
+type ReadingList struct {
+	ID        int64
+	UserID    int64
+	Name      string
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// Bookmark is an article saved by a user. A user bookmarks an article once,
+// the bookmark optionally belongs to one of their reading lists (ListID 0 means none)
+// where Position orders it. Article is loaded with the bookmark and stays empty
+// once the article is deleted.
+type Bookmark struct {
+	UserID    int64
+	ArticleID int64
+	ListID    int64
+	Position  int64
+	ReadAt    time.Time
+	Article   Article
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// Available reports whether the bookmarked article can still be read
+func (b *Bookmark) Available() bool {
+	return b.Article.ID != 0 && b.Article.Reachable()
+}
+
+// BookmarkFilter narrows a bookmark listing
+type BookmarkFilter struct {
+	UnreadOnly bool
+}
+
+type BookmarkRepository interface {
+	// Save inserts the bookmark or moves an existing one to b.ListID at b.Position
+	Save(ctx context.Context, b *Bookmark) error
+	Get(ctx context.Context, userID, articleID int64) (Bookmark, error)
+	Delete(ctx context.Context, userID, articleID int64) error
+	SetRead(ctx context.Context, userID, articleID int64, readAt time.Time) error
+	// Fetch returns a page of the bookmarks of a user, newest first
+	Fetch(ctx context.Context, userID int64, filter BookmarkFilter, cursor string, num int64) (res []Bookmark, nextCursor string, err error)
+	// FetchList returns a page of the bookmarks of a reading list in position order
+	FetchList(ctx context.Context, listID int64, filter BookmarkFilter, cursor string, num int64) (res []Bookmark, nextCursor string, err error)
+	NextPosition(ctx context.Context, listID int64) (int64, error)
+	// Reorder sets the positions of the list bookmarks to the order of articleIDs
+	Reorder(ctx context.Context, listID int64, articleIDs []int64) error
+	ListArticleIDs(ctx context.Context, listID int64) ([]int64, error)
+
+	StoreList(ctx context.Context, l *ReadingList) error
+	GetList(ctx context.Context, id int64) (ReadingList, error)
+	FetchLists(ctx context.Context, userID int64) ([]ReadingList, error)
+	UpdateList(ctx context.Context, l *ReadingList) error
+	// DeleteList removes the list, its bookmarks are kept without a list
+	DeleteList(ctx context.Context, id int64) error
+}
@@ -0,0 +1,23 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+// Category is a tag of articles, Tag is the slug used in URLs
+type Category struct {
+	ID        int64
+	Name      string
+	Tag       string
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+type CategoryRepository interface {
+	GetByTag(ctx context.Context, tag string) (Category, error)
+	FetchByTags(ctx context.Context, tags []string) ([]Category, error)
+	Store(ctx context.Context, c *Category) error
+	// FetchByArticles returns the categories of every given article that has any
+	FetchByArticles(ctx context.Context, articleIDs []int64) (map[int64][]Category, error)
+}
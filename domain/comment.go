@@ -0,0 +1,55 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+type CommentStatus string
+
+const (
+	CommentPending  CommentStatus = "pending"
+	CommentApproved CommentStatus = "approved"
+	CommentRejected CommentStatus = "rejected"
+	CommentSpam     CommentStatus = "spam"
+)
+
+// Valid reports whether the status is one of the known moderation states
+func (s CommentStatus) Valid() bool {
+	switch s {
+	case CommentPending, CommentApproved, CommentRejected, CommentSpam:
+		return true
+	}
+	return false
+}
+
+// Comment is a reader response to an article. Top level comments have no ParentID,
+// replies keep the id of the top level comment of their thread in RootID.
+// Deleted comments stay in place, without content, so their replies keep their thread.
+type Comment struct {
+	ID        int64
+	ArticleID int64
+	ParentID  int64
+	RootID    int64
+	User      User
+	Content   string
+	Status    CommentStatus
+	Deleted   bool
+	Replies   []Comment
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+type CommentRepository interface {
+	// Store inserts the comment and refreshes the comment count of its article
+	Store(ctx context.Context, c *Comment) error
+	GetByID(ctx context.Context, id int64) (Comment, error)
+	// Update saves the content, status and deleted flag and refreshes the comment count of its article
+	Update(ctx context.Context, c *Comment) error
+	// FetchThreads returns a page of approved top level comments, oldest first
+	FetchThreads(ctx context.Context, articleID int64, cursor string, num int64) (res []Comment, nextCursor string, err error)
+	// FetchReplies returns the approved replies of the given threads, oldest first
+	FetchReplies(ctx context.Context, rootIDs []int64) ([]Comment, error)
+	// FetchByStatus returns a page of comments of an article in the given moderation state, oldest first
+	FetchByStatus(ctx context.Context, articleID int64, status CommentStatus, cursor string, num int64) (res []Comment, nextCursor string, err error)
+}
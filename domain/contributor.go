@@ -0,0 +1,57 @@
+package domain
+
+import "context"
+
+type ContributorRole string
+
+const (
+	// ContributorAuthor is the user who created the article, every article has exactly one
+	ContributorAuthor   ContributorRole = "author"
+	ContributorCoAuthor ContributorRole = "co-author"
+	ContributorEditor   ContributorRole = "editor"
+	ContributorReviewer ContributorRole = "reviewer"
+)
+
+// ContributorRoles lists the roles in the order contributors are presented
+var ContributorRoles = []ContributorRole{ContributorAuthor, ContributorCoAuthor, ContributorEditor, ContributorReviewer}
+
+// Valid reports whether the role can be assigned, the author role cannot
+func (r ContributorRole) Valid() bool {
+	return r == ContributorCoAuthor || r == ContributorEditor || r == ContributorReviewer
+}
+
+// CanEdit reports whether the role may change the article and moderate its comments
+func (r ContributorRole) CanEdit() bool {
+	return r == ContributorAuthor || r == ContributorCoAuthor || r == ContributorEditor
+}
+
+// CanManage reports whether the role may delete the article and change its contributors
+func (r ContributorRole) CanManage() bool {
+	return r == ContributorAuthor
+}
+
+// Rank orders roles as listed in ContributorRoles
+func (r ContributorRole) Rank() int {
+	for i, v := range ContributorRoles {
+		if r == v {
+			return i
+		}
+	}
+	return len(ContributorRoles)
+}
+
+type Contributor struct {
+	User User
+	Role ContributorRole
+}
+
+type ContributorRepository interface {
+	// FetchByArticles returns the contributors of each article, the author first,
+	// with their users resolved in a single query
+	FetchByArticles(ctx context.Context, articleIDs []int64) (map[int64][]Contributor, error)
+	// Role returns the role of a user on an article, an empty role when the user does not contribute
+	Role(ctx context.Context, articleID, userID int64) (ContributorRole, error)
+	// Save adds a contributor or changes its role
+	Save(ctx context.Context, articleID, userID int64, role ContributorRole) error
+	Remove(ctx context.Context, articleID, userID int64) error
+}
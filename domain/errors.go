@@ -15,8 +15,18 @@ var (
 	ErrUserAlreadyExists = errors.New("user with given username already exists")
 	// ErrUnauthorized will throw if the user is unauthorized to access the resource
 	ErrUnauthorized = errors.New("you are unauthorized to access this resource")
+	// ErrForbidden will throw if the user is authenticated but not allowed to change the resource
+	ErrForbidden = errors.New("you are not allowed to modify this resource")
 	// ErrUserNotFound will throw if the requested user is not exists
 	ErrUserNotFound = errors.New("requested user is not found")
 	// ErrBadParamInput will throw if the given request-body or params is not valid
 	ErrInvalidCredentials = errors.New("invalid credentials")
+	// ErrTooLarge will throw if an uploaded file exceeds the size limit
+	ErrTooLarge = errors.New("the uploaded file is too large")
+	// ErrUnsupportedMediaType will throw if an uploaded file is not of an accepted type
+	ErrUnsupportedMediaType = errors.New("the uploaded file type is not supported")
+	// ErrQuotaExceeded will throw if an upload would take the user over their storage quota
+	ErrQuotaExceeded = errors.New("your upload quota is exceeded")
+	// ErrDuplicateContent will throw if the content of an article duplicates an existing one
+	ErrDuplicateContent = errors.New("the content duplicates an existing article")
 )
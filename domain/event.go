@@ -0,0 +1,57 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+type ArticleEventType string
+
+const (
+	ArticleCreated   ArticleEventType = "article.created"
+	ArticleUpdated   ArticleEventType = "article.updated"
+	ArticleDeleted   ArticleEventType = "article.deleted"
+	ArticlePublished ArticleEventType = "article.published"
+)
+
+// ArticleEvent is a domain event recorded in the outbox together with the article change.
+// Article is a snapshot of the article right after the change, or right before a delete.
+type ArticleEvent struct {
+	ID         int64
+	Type       ArticleEventType
+	ArticleID  int64
+	Article    Article
+	OccurredAt time.Time
+	Attempts   int
+	// Delivered lists the sinks that already accepted the event, a retry skips them
+	Delivered []string
+	// RetryAt is when a failed event is due again
+	RetryAt time.Time
+	// PreviousTags are the tags of the article right before the change, so that
+	// sinks also reach what the article was removed from
+	PreviousTags []string
+}
+
+type OutboxRepository interface {
+	// FetchPending returns the events that are neither published nor
+	// dead-lettered in the order they were recorded. Events whose retry is
+	// not due and the later events of their article are left out, so that a
+	// failing article does not hold back the others.
+	FetchPending(ctx context.Context, limit int) ([]ArticleEvent, error)
+	// The writes below are only made by the leader and reject a stale fencing
+	// token with ErrStaleLeader.
+	MarkPublished(ctx context.Context, ids []int64, fencingToken int64) error
+	// MarkFailed counts a failed attempt and saves the Delivered sinks and the
+	// RetryAt of the event
+	MarkFailed(ctx context.Context, event ArticleEvent, reason string, fencingToken int64) error
+	// DeadLetter gives up on an event, it is kept for inspection but no longer pending
+	DeadLetter(ctx context.Context, event ArticleEvent, reason string, fencingToken int64) error
+	// Purge deletes events published before the given time
+	Purge(ctx context.Context, before time.Time) error
+}
+
+// EventSink is a destination the outbox relay publishes article events to
+type EventSink interface {
+	Name() string
+	Publish(ctx context.Context, event ArticleEvent) error
+}
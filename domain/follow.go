@@ -0,0 +1,54 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+// Follow is an edge of the follow graph. User is the other side of the edge
+// in follower and following listings.
+type Follow struct {
+	FollowerID int64
+	FolloweeID int64
+	User       User
+	CreatedAt  time.Time
+}
+
+type FollowStats struct {
+	Followers int64
+	Following int64
+}
+
+type FollowRepository interface {
+	// Follow stores the edge and reports false when it already exists
+	Follow(ctx context.Context, f *Follow) (bool, error)
+	// Unfollow deletes the edge and reports false when there was nothing to delete
+	Unfollow(ctx context.Context, followerID, followeeID int64) (bool, error)
+	// FetchFollowers returns a page of the followers of a user, newest first
+	FetchFollowers(ctx context.Context, userID int64, cursor string, num int64) (res []Follow, nextCursor string, err error)
+	// FetchFollowing returns a page of the users followed by a user, newest first
+	FetchFollowing(ctx context.Context, userID int64, cursor string, num int64) (res []Follow, nextCursor string, err error)
+	FollowingIDs(ctx context.Context, userID int64) ([]int64, error)
+	// FollowerIDs returns up to limit follower ids greater than afterID, in id order
+	FollowerIDs(ctx context.Context, userID, afterID int64, limit int) ([]int64, error)
+	Stats(ctx context.Context, userID int64) (FollowStats, error)
+	// FilterPopular returns the given users that have more than minFollowers followers
+	FilterPopular(ctx context.Context, userIDs []int64, minFollowers int64) ([]int64, error)
+}
+
+// FeedEntry is an article in a home feed timeline
+type FeedEntry struct {
+	ArticleID int64
+	CreatedAt time.Time
+}
+
+// FeedCache stores the precomputed home feed timelines
+type FeedCache interface {
+	// Push adds the entries to the timelines of the given users that already have one
+	Push(ctx context.Context, userIDs []int64, entries []FeedEntry) error
+	// Rebuild replaces the timeline of a user
+	Rebuild(ctx context.Context, userID int64, entries []FeedEntry) error
+	// Fetch returns up to num entries created before the given time, newest first,
+	// and reports false when the user has no timeline yet
+	Fetch(ctx context.Context, userID int64, before time.Time, num int64) ([]FeedEntry, bool, error)
+}
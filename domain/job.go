@@ -0,0 +1,24 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+// Job is a unit of asynchronous work delivered through a JobQueue
+type Job struct {
+	ID         string
+	Type       string
+	Payload    []byte
+	Attempts   int
+	EnqueuedAt time.Time
+}
+
+// JobHandler processes a job, a returned error makes the queue retry it
+type JobHandler func(ctx context.Context, job Job) error
+
+type JobQueue interface {
+	Enqueue(ctx context.Context, jobType string, payload []byte) error
+	// Consume delivers jobs to handler until ctx is done
+	Consume(ctx context.Context, handler JobHandler) error
+}
@@ -0,0 +1,19 @@
+package domain
+
+import (
+	"errors"
+	"time"
+)
+
+// ErrStaleLeader will throw if a leader-only write carries a fencing token older
+// than one already seen by the store, i.e. it comes from a replica that lost the lease
+var ErrStaleLeader = errors.New("the fencing token belongs to a previous leader")
+
+// LeaderStatus describes the leadership state seen by this replica
+type LeaderStatus struct {
+	ReplicaID    string
+	IsLeader     bool
+	Leader       string
+	FencingToken int64
+	LeaderSince  time.Time
+}
@@ -0,0 +1,97 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+type NotificationType string
+
+const (
+	// NotificationComment is a comment on an article of the recipient
+	NotificationComment NotificationType = "comment"
+	// NotificationReply is a reply to a comment of the recipient
+	NotificationReply NotificationType = "reply"
+	// NotificationReaction is a reaction to an article of the recipient
+	NotificationReaction NotificationType = "reaction"
+	// NotificationFollow is a new follower of the recipient
+	NotificationFollow NotificationType = "follow"
+	// NotificationArticle is a new article of an author the recipient follows
+	NotificationArticle NotificationType = "article"
+)
+
+var NotificationTypes = []NotificationType{
+	NotificationComment,
+	NotificationReply,
+	NotificationReaction,
+	NotificationFollow,
+	NotificationArticle,
+}
+
+func (t NotificationType) Valid() bool {
+	for _, v := range NotificationTypes {
+		if t == v {
+			return true
+		}
+	}
+	return false
+}
+
+// Activity is something a user did that concerns another user. CommentID is
+// the comment of the recipient that was replied to.
+type Activity struct {
+	Type        NotificationType
+	ActorID     int64
+	RecipientID int64
+	ArticleID   int64
+	CommentID   int64
+	OccurredAt  time.Time
+}
+
+// Notifier records activities as notifications. Notifying is best effort and
+// never fails the action that caused it.
+type Notifier interface {
+	Notify(ctx context.Context, a Activity)
+}
+
+// Notification aggregates the activities with the same group key until it is
+// read, Actor is the latest of ActorCount distinct actors
+type Notification struct {
+	ID           int64
+	UserID       int64
+	Type         NotificationType
+	GroupKey     string
+	ArticleID    int64
+	ArticleTitle string
+	CommentID    int64
+	Actor        User
+	ActorCount   int64
+	ReadAt       time.Time
+	CreatedAt    time.Time
+	UpdatedAt    time.Time
+}
+
+func (n *Notification) Read() bool {
+	return !n.ReadAt.IsZero()
+}
+
+type NotificationRepository interface {
+	// Record adds actorID to the unread notification of n.UserID with n.GroupKey,
+	// creating it when there is none. An actor is only counted once per notification.
+	Record(ctx context.Context, n *Notification, actorID int64) error
+	// RecordMany creates single actor notifications, skipping those whose
+	// group already has an unread notification
+	RecordMany(ctx context.Context, notifications []Notification) error
+	// Fetch returns a page of notifications of a user, most recently updated first
+	Fetch(ctx context.Context, userID int64, unreadOnly bool, cursor string, num int64) (res []Notification, nextCursor string, err error)
+	CountUnread(ctx context.Context, userID int64) (int64, error)
+	MarkRead(ctx context.Context, userID, id int64, readAt time.Time) error
+	MarkAllRead(ctx context.Context, userID int64, readAt time.Time) (int64, error)
+	// Purge deletes up to limit notifications read before readBefore or created before before
+	Purge(ctx context.Context, readBefore, before time.Time, limit int) (int64, error)
+	// Preferences returns the preferences a user changed, the other types are enabled
+	Preferences(ctx context.Context, userID int64) (map[NotificationType]bool, error)
+	SetPreferences(ctx context.Context, userID int64, prefs map[NotificationType]bool) error
+	// Muted returns the given users that disabled the notification type
+	Muted(ctx context.Context, t NotificationType, userIDs []int64) ([]int64, error)
+}
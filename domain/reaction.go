@@ -0,0 +1,72 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+// ReactionLike is always available, other reaction kinds are configured
+const ReactionLike = "like"
+
+// Reaction is a reaction of a user to an article, a user reacts at most once per kind
+type Reaction struct {
+	ArticleID int64
+	UserID    int64
+	Kind      string
+	CreatedAt time.Time
+}
+
+// ReactionKey identifies a reaction counter
+type ReactionKey struct {
+	ArticleID int64
+	Kind      string
+}
+
+// ReactionBatch is a set of buffered reaction count deltas.
+// ID identifies the batch so that it is applied to the database at most once
+// and FencingToken the leadership term it is synced in, see ViewBatch.
+type ReactionBatch struct {
+	ID           string
+	Counts       map[ReactionKey]int64
+	FencingToken int64
+}
+
+// ReactionSummary holds the reaction counts of an article and, for an authenticated
+// reader, the kinds they reacted with
+type ReactionSummary struct {
+	ArticleID int64
+	Counts    map[string]int64
+	Mine      []string
+}
+
+type ReactionRepository interface {
+	// Add stores the reaction and reports false when the user already reacted with that kind
+	Add(ctx context.Context, r *Reaction) (bool, error)
+	// Remove deletes the reaction and reports false when there was nothing to delete
+	Remove(ctx context.Context, r *Reaction) (bool, error)
+	ListKinds(ctx context.Context, articleID, userID int64) ([]string, error)
+	Counts(ctx context.Context, articleID int64) (map[string]int64, error)
+	// AddCounts applies a reaction batch, a batch with a stale fencing token is
+	// rejected with ErrStaleLeader
+	AddCounts(ctx context.Context, batch ReactionBatch) error
+	// PurgeBatches forgets the applied reaction batches recorded before the given time
+	PurgeBatches(ctx context.Context, before time.Time) error
+	// Recount counts the stored reactions of up to limit articles with an id
+	// greater than afterArticleID that have reactions or counters, in id order.
+	// Counters left without reactions are returned with a count of 0.
+	Recount(ctx context.Context, afterArticleID int64, limit int) (map[ReactionKey]int64, error)
+	// SetCounts overwrites the counters, it is fenced like AddCounts
+	SetCounts(ctx context.Context, counts map[ReactionKey]int64, fencingToken int64) error
+}
+
+type ReactionCache interface {
+	Incr(ctx context.Context, key ReactionKey, delta int64) error
+	// Pending returns the deltas of an article that are not synced to the database yet
+	Pending(ctx context.Context, articleID int64, kinds []string) (map[string]int64, error)
+	// PendingKeys is Pending for any set of counters
+	PendingKeys(ctx context.Context, keys []ReactionKey) (map[ReactionKey]int64, error)
+	FetchReactions(ctx context.Context) (ReactionBatch, error)
+	AckReactions(ctx context.Context, batchID string, keys []ReactionKey) error
+	// FailReactions counts a failed sync of the pending batch, see ArticleCache.FailViews
+	FailReactions(ctx context.Context, batchID string, maxAttempts int) (bool, error)
+}
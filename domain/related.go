@@ -0,0 +1,40 @@
+package domain
+
+import "context"
+
+// RelatedScore is a precomputed recommendation of an article next to another one
+type RelatedScore struct {
+	ArticleID int64   `json:"id"`
+	Score     float64 `json:"score"`
+}
+
+// RelatedArticle is an article recommended next to another one, the best first
+type RelatedArticle struct {
+	Article Article
+	Score   float64
+}
+
+// CoViewRecorder is notified of the articles read by signed in readers so that
+// articles read together can be recommended together
+type CoViewRecorder interface {
+	RecordCoView(ctx context.Context, userID, articleID int64) error
+}
+
+// RelatedCache keeps the precomputed recommendations of every article and the
+// co-view counts they are computed from
+type RelatedCache interface {
+	CoViewRecorder
+	// CoViews returns up to limit articles read by the readers of an article
+	// with the number of readers they share
+	CoViews(ctx context.Context, articleID int64, limit int) (map[int64]float64, error)
+	// Get returns the recommendations of an article, redis.Nil until they are computed
+	Get(ctx context.Context, articleID int64) ([]RelatedScore, error)
+	Set(ctx context.Context, articleID int64, related []RelatedScore) error
+	Del(ctx context.Context, articleID int64) error
+	// MarkDirty queues articles whose recommendations must be computed again
+	MarkDirty(ctx context.Context, articleIDs ...int64) error
+	PopDirty(ctx context.Context, limit int) ([]int64, error)
+	// Cursor is the last article of the rolling refresh of every recommendation
+	Cursor(ctx context.Context) (int64, error)
+	SaveCursor(ctx context.Context, articleID int64) error
+}
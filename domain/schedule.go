@@ -0,0 +1,24 @@
+package domain
+
+import "time"
+
+// JobRun is a single execution of a scheduled background job
+type JobRun struct {
+	StartedAt time.Time
+	Duration  time.Duration
+	Err       string
+}
+
+// ScheduledJob describes a registered background job together with its run history
+type ScheduledJob struct {
+	Name     string
+	Schedule string
+	Mode     string
+	Running  bool
+	NextRun  time.Time
+	Runs     int64
+	Failures int64
+	Panics   int64
+	LastRun  JobRun
+	History  []JobRun
+}
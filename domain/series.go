@@ -0,0 +1,98 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+// WordsPerMinute is the reading speed used to estimate reading times
+const WordsPerMinute = 200
+
+// ReadingMinutes estimates the reading time of the given number of words, rounded up
+func ReadingMinutes(words int64) int64 {
+	if words <= 0 {
+		return 0
+	}
+	return (words + WordsPerMinute - 1) / WordsPerMinute
+}
+
+// Series is an ordered collection of articles of one author. ArticleCount and
+// WordCount only cover listed articles.
+type Series struct {
+	ID           int64
+	User         User
+	Title        string
+	Description  string
+	CoverURL     string
+	CoverAlt     string
+	ArticleCount int64
+	WordCount    int64
+	// Articles is only loaded for a single series, in series order
+	Articles  []Article
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+func (s *Series) ReadingMinutes() int64 {
+	return ReadingMinutes(s.WordCount)
+}
+
+// Navigation places an article in its series. Articles left out of listings are
+// skipped unless the article itself is one, so readers of an unlisted article or
+// authors previewing a draft still see its place.
+func (s *Series) Navigation(articleID int64) (SeriesNavigation, bool) {
+	nav := SeriesNavigation{SeriesID: s.ID, Title: s.Title}
+	var visible []Article
+	for _, a := range s.Articles {
+		if a.Listed() || a.ID == articleID {
+			visible = append(visible, a)
+		}
+	}
+	for i, a := range visible {
+		if a.ID != articleID {
+			continue
+		}
+		nav.Position = i + 1
+		nav.Total = len(visible)
+		if i > 0 {
+			nav.Prev = &SeriesLink{ID: visible[i-1].ID, Title: visible[i-1].Title}
+		}
+		if i+1 < len(visible) {
+			nav.Next = &SeriesLink{ID: visible[i+1].ID, Title: visible[i+1].Title}
+		}
+		return nav, true
+	}
+	return SeriesNavigation{}, false
+}
+
+type SeriesLink struct {
+	ID    int64
+	Title string
+}
+
+// SeriesNavigation places an article in its series, Position counts the listed articles from 1
+type SeriesNavigation struct {
+	SeriesID int64
+	Title    string
+	Position int
+	Total    int
+	Prev     *SeriesLink
+	Next     *SeriesLink
+}
+
+type SeriesRepository interface {
+	Store(ctx context.Context, s *Series) error
+	GetByID(ctx context.Context, id int64) (Series, error)
+	Update(ctx context.Context, s *Series) error
+	// Delete removes the series, its articles are kept
+	Delete(ctx context.Context, id int64) error
+	// Fetch returns a page of series, newest first, of one author when authorID is set
+	Fetch(ctx context.Context, authorID int64, cursor string, num int64) (res []Series, nextCursor string, err error)
+	// Articles returns the articles of a series in order, without their content,
+	// listedOnly leaves out drafts, unlisted and private articles
+	Articles(ctx context.Context, seriesID int64, listedOnly bool) ([]Article, error)
+	// SetArticles replaces the articles of a series, ErrConflict when one belongs to another series
+	SetArticles(ctx context.Context, seriesID int64, articleIDs []int64) error
+	// GetByArticle returns the series of an article with its articles, ErrNotFound when it has none
+	GetByArticle(ctx context.Context, articleID int64) (Series, error)
+}
@@ -0,0 +1,37 @@
+package domain
+
+import "math/bits"
+
+// FingerprintBits is the size of article fingerprints
+const FingerprintBits = 64
+
+// SimilarArticle is an article whose fingerprint is Distance bits away from another one
+type SimilarArticle struct {
+	Article  Article
+	Distance int
+	// Hidden near duplicates are not readable by the user they are listed to,
+	// only their similarity is kept
+	Hidden bool
+}
+
+// Similarity is the share of the bits of both fingerprints that are equal,
+// from 0 to 1 for identical texts
+func (s SimilarArticle) Similarity() float64 {
+	return Similarity(s.Distance)
+}
+
+// Similarity converts a distance between fingerprints to a similarity
+func Similarity(distance int) float64 {
+	return 1 - float64(distance)/FingerprintBits
+}
+
+// FingerprintDistance is the number of bits that differ between two fingerprints
+func FingerprintDistance(a, b uint64) int {
+	return bits.OnesCount64(a ^ b)
+}
+
+// MaxFingerprintDistance is the largest distance between fingerprints still
+// at or above a similarity threshold
+func MaxFingerprintDistance(threshold float64) int {
+	return int((1 - threshold) * FingerprintBits)
+}
@@ -0,0 +1,87 @@
+package domain
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// SitemapChunkSize is the number of ids covered by a sitemap chunk, it matches
+// the 50,000 URL limit of a sitemap file
+const SitemapChunkSize = 50000
+
+type SitemapKind string
+
+const (
+	SitemapArticles SitemapKind = "articles"
+	SitemapAuthors  SitemapKind = "authors"
+)
+
+// SitemapChunk is one sitemap file listing the pages of a kind whose ids fall
+// within the range of its number. Ranges never move, so a change only ever
+// touches the chunk of the changed id.
+type SitemapChunk struct {
+	Kind   SitemapKind
+	Number int64
+}
+
+// SitemapChunkOf returns the chunk that covers id
+func SitemapChunkOf(kind SitemapKind, id int64) SitemapChunk {
+	return SitemapChunk{Kind: kind, Number: (id - 1) / SitemapChunkSize}
+}
+
+// IDRange returns the first and last id covered by the chunk
+func (c SitemapChunk) IDRange() (from, to int64) {
+	from = c.Number*SitemapChunkSize + 1
+	return from, from + SitemapChunkSize - 1
+}
+
+func (c SitemapChunk) Name() string {
+	return fmt.Sprintf("%s-%d", c.Kind, c.Number)
+}
+
+// ParseSitemapChunk is the reverse of Name
+func ParseSitemapChunk(name string) (SitemapChunk, bool) {
+	kind, number, ok := strings.Cut(name, "-")
+	if !ok || (SitemapKind(kind) != SitemapArticles && SitemapKind(kind) != SitemapAuthors) {
+		return SitemapChunk{}, false
+	}
+	n, err := strconv.ParseInt(number, 10, 64)
+	if err != nil || n < 0 {
+		return SitemapChunk{}, false
+	}
+	return SitemapChunk{Kind: SitemapKind(kind), Number: n}, true
+}
+
+// SitemapEntry is a page of a sitemap, an article or an author
+type SitemapEntry struct {
+	ID      int64
+	LastMod time.Time
+}
+
+type SitemapRepository interface {
+	// ArticleEntries returns the listed articles with an id in [fromID, toID]
+	ArticleEntries(ctx context.Context, fromID, toID int64) ([]SitemapEntry, error)
+	// AuthorEntries returns the users with an id in [fromID, toID] who have a
+	// listed article, their pages change with their articles
+	AuthorEntries(ctx context.Context, fromID, toID int64) ([]SitemapEntry, error)
+	MaxIDs(ctx context.Context) (articleID, userID int64, err error)
+}
+
+// SitemapCache holds the generated sitemap files, they are only ever served from there
+type SitemapCache interface {
+	// Index returns redis.Nil until the index was generated
+	Index(ctx context.Context) ([]byte, error)
+	SaveIndex(ctx context.Context, body []byte) error
+	// Chunk returns redis.Nil for chunks that do not exist
+	Chunk(ctx context.Context, chunk SitemapChunk) ([]byte, error)
+	// SaveChunk stores a chunk and the last change of its pages, a nil body removes it
+	SaveChunk(ctx context.Context, chunk SitemapChunk, body []byte, lastMod time.Time) error
+	// Chunks returns the stored chunks with the last change of their pages
+	Chunks(ctx context.Context) (map[SitemapChunk]time.Time, error)
+	MarkDirty(ctx context.Context, chunks ...SitemapChunk) error
+	// PopDirty takes up to limit chunks to regenerate
+	PopDirty(ctx context.Context, limit int) ([]SitemapChunk, error)
+}
@@ -0,0 +1,17 @@
+package domain
+
+// StreamEventViews is the type of stream events carrying view count deltas keyed by article id
+const StreamEventViews = "article.views"
+
+// StreamEvent is a message pushed to live article stream subscribers.
+// IDs are assigned in publish order and are shared by all replicas.
+type StreamEvent struct {
+	ID   int64
+	Type string
+	Data []byte
+}
+
+// ViewRecorder is notified of every article view so that live subscribers see view counts move
+type ViewRecorder interface {
+	RecordView(articleID int64)
+}
@@ -0,0 +1,40 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+type SyndicationFormat string
+
+const (
+	SyndicationRSS  SyndicationFormat = "rss"
+	SyndicationAtom SyndicationFormat = "atom"
+	SyndicationJSON SyndicationFormat = "json"
+)
+
+var SyndicationFormats = []SyndicationFormat{SyndicationRSS, SyndicationAtom, SyndicationJSON}
+
+// SyndicationScope selects the articles of a syndication feed, everything
+// when both fields are empty
+type SyndicationScope struct {
+	AuthorID int64
+	Tag      string
+}
+
+// SyndicationDocument is a rendered syndication feed
+type SyndicationDocument struct {
+	Body         []byte
+	ContentType  string
+	ETag         string
+	LastModified time.Time
+}
+
+// SyndicationCache keeps rendered syndication feeds until the articles they list change
+type SyndicationCache interface {
+	// Get returns redis.Nil when the document is not cached
+	Get(ctx context.Context, scope SyndicationScope, format SyndicationFormat) (SyndicationDocument, error)
+	Set(ctx context.Context, scope SyndicationScope, format SyndicationFormat, doc SyndicationDocument) error
+	// Invalidate drops every format of the given scopes
+	Invalidate(ctx context.Context, scopes ...SyndicationScope) error
+}
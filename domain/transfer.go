@@ -0,0 +1,86 @@
+package domain
+
+// TransferFormat is a file format articles are exported to and imported from
+type TransferFormat string
+
+const (
+	// TransferJSONL is one JSON object per line
+	TransferJSONL TransferFormat = "jsonl"
+	// TransferCSV is a header row followed by one row per article
+	TransferCSV TransferFormat = "csv"
+	// TransferMarkdown is a zip of Markdown files with a YAML front matter
+	TransferMarkdown TransferFormat = "markdown"
+	// TransferWXR is a WordPress export, it can only be imported
+	TransferWXR TransferFormat = "wxr"
+)
+
+func (f TransferFormat) Valid() bool {
+	switch f {
+	case TransferJSONL, TransferCSV, TransferMarkdown, TransferWXR:
+		return true
+	}
+	return false
+}
+
+type ImportOptions struct {
+	// DryRun validates every row and reports what would happen without writing
+	DryRun bool
+	// AuthorMap renames the authors of the source to usernames of this site
+	AuthorMap map[string]string
+	// DefaultAuthor is the username that gets the rows whose author is unknown,
+	// those rows fail when it is empty
+	DefaultAuthor string
+}
+
+type ImportResult string
+
+const (
+	ImportCreated ImportResult = "created"
+	// ImportSkipped marks duplicates of an article of the site or of an earlier row
+	ImportSkipped ImportResult = "skipped"
+	ImportFailed  ImportResult = "failed"
+)
+
+// ImportRow is the outcome of one row of an import
+type ImportRow struct {
+	// Row counts the records of the source from 1
+	Row int
+	// SourceID and SourceURL are the id and address the article had in the source
+	SourceID  int64
+	SourceURL string
+	Title     string
+	Slug      string
+	Result    ImportResult
+	ArticleID int64
+	Error     string
+}
+
+// ImportAuthor maps an author of the source to a user of the site
+type ImportAuthor struct {
+	SourceID int64
+	Username string
+	UserID   int64
+	// Created users have no usable password until they went through a password reset
+	Created bool
+	Error   string
+}
+
+// ImportCategory maps a category or tag of the source to a category of the site
+type ImportCategory struct {
+	SourceID   int64
+	Tag        string
+	CategoryID int64
+	Created    bool
+	Error      string
+}
+
+type ImportReport struct {
+	DryRun  bool
+	Created int
+	Skipped int
+	Failed  int
+	Rows    []ImportRow
+	// Authors and Categories are only set by importers that create them
+	Authors    []ImportAuthor
+	Categories []ImportCategory
+}
@@ -0,0 +1,87 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+type WebhookDeliveryStatus string
+
+const (
+	WebhookDeliveryPending   WebhookDeliveryStatus = "pending"
+	WebhookDeliverySucceeded WebhookDeliveryStatus = "succeeded"
+	WebhookDeliveryFailed    WebhookDeliveryStatus = "failed"
+)
+
+// WebhookSubscription asks for article events to be POSTed to URL.
+// An empty Events filter subscribes to every event type.
+type WebhookSubscription struct {
+	ID        int64
+	UserID    int64
+	URL       string
+	Secret    string
+	Events    []ArticleEventType
+	Active    bool
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// Matches reports whether the subscription wants events of the given type
+func (s *WebhookSubscription) Matches(t ArticleEventType) bool {
+	if !s.Active {
+		return false
+	}
+	if len(s.Events) == 0 {
+		return true
+	}
+	for _, e := range s.Events {
+		if e == t {
+			return true
+		}
+	}
+	return false
+}
+
+// WebhookDelivery is one event to be delivered to one subscription
+type WebhookDelivery struct {
+	ID             string
+	SubscriptionID int64
+	EventID        int64
+	EventType      ArticleEventType
+	Payload        string
+	Status         WebhookDeliveryStatus
+	Attempts       int
+	NextAttemptAt  time.Time
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
+}
+
+// WebhookAttempt records a single HTTP call made for a delivery
+type WebhookAttempt struct {
+	ID          int64
+	DeliveryID  string
+	StatusCode  int
+	Error       string
+	Duration    time.Duration
+	AttemptedAt time.Time
+}
+
+type WebhookRepository interface {
+	StoreSubscription(ctx context.Context, s *WebhookSubscription) error
+	GetSubscription(ctx context.Context, id int64) (WebhookSubscription, error)
+	ListSubscriptions(ctx context.Context, userID int64) ([]WebhookSubscription, error)
+	// ListActiveSubscriptions returns every active subscription regardless of owner
+	ListActiveSubscriptions(ctx context.Context) ([]WebhookSubscription, error)
+	UpdateSubscription(ctx context.Context, s *WebhookSubscription) error
+	DeleteSubscription(ctx context.Context, id int64) error
+
+	// StoreDeliveries skips deliveries already recorded for the same subscription and event
+	StoreDeliveries(ctx context.Context, ds []WebhookDelivery) error
+	GetDelivery(ctx context.Context, id string) (WebhookDelivery, error)
+	ListDeliveries(ctx context.Context, subscriptionID int64, num int64) ([]WebhookDelivery, error)
+	// FetchDueDeliveries returns pending deliveries whose next attempt is due
+	FetchDueDeliveries(ctx context.Context, now time.Time, limit int) ([]WebhookDelivery, error)
+	UpdateDelivery(ctx context.Context, d *WebhookDelivery) error
+	StoreAttempt(ctx context.Context, a *WebhookAttempt) error
+	ListAttempts(ctx context.Context, deliveryID string) ([]WebhookAttempt, error)
+}
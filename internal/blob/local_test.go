@@ -0,0 +1,38 @@
+package blob_test
+
+import (
+	"context"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bxcodec/go-clean-arch/domain"
+	"github.com/bxcodec/go-clean-arch/internal/blob"
+)
+
+func TestLocalStore(t *testing.T) {
+	ctx := context.Background()
+	store, err := blob.NewLocalStore(t.TempDir())
+	require.NoError(t, err)
+
+	require.NoError(t, store.Put(ctx, "attachments/1/a.txt", strings.NewReader("hello"), 5, "text/plain"))
+	r, err := store.Get(ctx, "attachments/1/a.txt")
+	require.NoError(t, err)
+	data, err := io.ReadAll(r)
+	r.Close()
+	require.NoError(t, err)
+	assert.Equal(t, "hello", string(data))
+
+	require.NoError(t, store.Delete(ctx, "attachments/1/a.txt"))
+	_, err = store.Get(ctx, "attachments/1/a.txt")
+	assert.ErrorIs(t, err, domain.ErrNotFound)
+	// deleting twice is fine
+	assert.NoError(t, store.Delete(ctx, "attachments/1/a.txt"))
+
+	for _, key := range []string{"../escape", "/abs", "a//b", "a/./b", ""} {
+		assert.ErrorIs(t, store.Put(ctx, key, strings.NewReader("x"), 1, "text/plain"), domain.ErrBadParamInput, key)
+	}
+}
@@ -0,0 +1,178 @@
+package blob
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/bxcodec/go-clean-arch/domain"
+)
+
+// S3Config locates a bucket of an S3 compatible service. Endpoint is the base
+// URL of the service, e.g. https://s3.eu-west-1.amazonaws.com or the address
+// of a MinIO server.
+type S3Config struct {
+	Endpoint  string
+	Region    string
+	Bucket    string
+	AccessKey string
+	SecretKey string
+}
+
+// S3Store keeps blobs as objects of an S3 compatible bucket, addressed
+// path-style and signed with AWS signature version 4
+type S3Store struct {
+	cfg    S3Config
+	client *http.Client
+	now    func() time.Time
+}
+
+func NewS3Store(cfg S3Config, client *http.Client) *S3Store {
+	if client == nil {
+		client = http.DefaultClient
+	}
+	cfg.Endpoint = strings.TrimSuffix(cfg.Endpoint, "/")
+	return &S3Store{
+		cfg:    cfg,
+		client: client,
+		now:    time.Now,
+	}
+}
+
+// unsignedPayload lets uploads stream instead of hashing the body up front
+const unsignedPayload = "UNSIGNED-PAYLOAD"
+
+// emptyPayloadHash is the SHA-256 of an empty body
+const emptyPayloadHash = "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855"
+
+func (s *S3Store) newRequest(ctx context.Context, method, key string, body io.Reader) (*http.Request, error) {
+	if !validKey(key) {
+		return nil, domain.ErrBadParamInput
+	}
+	segments := strings.Split(key, "/")
+	for i := range segments {
+		segments[i] = url.PathEscape(segments[i])
+	}
+	return http.NewRequestWithContext(ctx, method, s.cfg.Endpoint+"/"+url.PathEscape(s.cfg.Bucket)+"/"+strings.Join(segments, "/"), body)
+}
+
+// Put implements domain.BlobStore
+func (s *S3Store) Put(ctx context.Context, key string, r io.Reader, size int64, contentType string) error {
+	req, err := s.newRequest(ctx, http.MethodPut, key, r)
+	if err != nil {
+		return err
+	}
+	req.ContentLength = size
+	req.Header.Set("Content-Type", contentType)
+	s.sign(req, unsignedPayload)
+
+	res, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return responseError(res)
+	}
+	return nil
+}
+
+// Get implements domain.BlobStore
+func (s *S3Store) Get(ctx context.Context, key string) (io.ReadCloser, error) {
+	req, err := s.newRequest(ctx, http.MethodGet, key, nil)
+	if err != nil {
+		return nil, domain.ErrNotFound
+	}
+	s.sign(req, emptyPayloadHash)
+
+	res, err := s.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	switch res.StatusCode {
+	case http.StatusOK:
+		return res.Body, nil
+	case http.StatusNotFound:
+		res.Body.Close()
+		return nil, domain.ErrNotFound
+	default:
+		defer res.Body.Close()
+		return nil, responseError(res)
+	}
+}
+
+// Delete implements domain.BlobStore
+func (s *S3Store) Delete(ctx context.Context, key string) error {
+	req, err := s.newRequest(ctx, http.MethodDelete, key, nil)
+	if err != nil {
+		return err
+	}
+	s.sign(req, emptyPayloadHash)
+
+	res, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	// S3 answers 204 whether or not the object existed, other services may answer 404
+	if res.StatusCode != http.StatusNoContent && res.StatusCode != http.StatusOK && res.StatusCode != http.StatusNotFound {
+		return responseError(res)
+	}
+	return nil
+}
+
+func responseError(res *http.Response) error {
+	body, _ := io.ReadAll(io.LimitReader(res.Body, 512))
+	return fmt.Errorf("blob store answered %s: %s", res.Status, strings.TrimSpace(string(body)))
+}
+
+// sign adds the AWS signature version 4 headers, signing the host and the
+// x-amz headers only
+func (s *S3Store) sign(req *http.Request, payloadHash string) {
+	now := s.now().UTC()
+	amzDate := now.Format("20060102T150405Z")
+	date := now.Format("20060102")
+	req.Header.Set("X-Amz-Date", amzDate)
+	req.Header.Set("X-Amz-Content-Sha256", payloadHash)
+
+	const signedHeaders = "host;x-amz-content-sha256;x-amz-date"
+	canonicalHeaders := "host:" + req.URL.Host + "\n" +
+		"x-amz-content-sha256:" + payloadHash + "\n" +
+		"x-amz-date:" + amzDate + "\n"
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		req.URL.EscapedPath(),
+		req.URL.RawQuery,
+		canonicalHeaders,
+		signedHeaders,
+		payloadHash,
+	}, "\n")
+
+	scope := date + "/" + s.cfg.Region + "/s3/aws4_request"
+	hashed := sha256.Sum256([]byte(canonicalRequest))
+	stringToSign := "AWS4-HMAC-SHA256\n" + amzDate + "\n" + scope + "\n" + hex.EncodeToString(hashed[:])
+	signature := hex.EncodeToString(hmacSHA256(signingKey(s.cfg.SecretKey, date, s.cfg.Region, "s3"), stringToSign))
+
+	req.Header.Set("Authorization", "AWS4-HMAC-SHA256 Credential="+s.cfg.AccessKey+"/"+scope+
+		", SignedHeaders="+signedHeaders+", Signature="+signature)
+}
+
+func signingKey(secret, date, region, service string) []byte {
+	key := hmacSHA256([]byte("AWS4"+secret), date)
+	key = hmacSHA256(key, region)
+	key = hmacSHA256(key, service)
+	return hmacSHA256(key, "aws4_request")
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
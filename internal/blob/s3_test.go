@@ -0,0 +1,79 @@
+package blob
+
+import (
+	"context"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bxcodec/go-clean-arch/domain"
+)
+
+// fakeS3 stands in for an S3 compatible service, keeping objects in memory
+type fakeS3 struct {
+	mu      sync.Mutex
+	objects map[string]string
+}
+
+func (f *fakeS3) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if !strings.HasPrefix(r.Header.Get("Authorization"), "AWS4-HMAC-SHA256 Credential=key/") ||
+		r.Header.Get("X-Amz-Date") == "" || r.Header.Get("X-Amz-Content-Sha256") == "" {
+		w.WriteHeader(http.StatusForbidden)
+		return
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	switch r.Method {
+	case http.MethodPut:
+		data, _ := io.ReadAll(r.Body)
+		f.objects[r.URL.Path] = string(data)
+	case http.MethodGet:
+		data, ok := f.objects[r.URL.Path]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		io.WriteString(w, data)
+	case http.MethodDelete:
+		delete(f.objects, r.URL.Path)
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+func TestS3Store(t *testing.T) {
+	ctx := context.Background()
+	fake := &fakeS3{objects: map[string]string{}}
+	server := httptest.NewServer(fake)
+	defer server.Close()
+
+	store := NewS3Store(S3Config{Endpoint: server.URL + "/", Region: "us-east-1", Bucket: "uploads", AccessKey: "key", SecretKey: "secret"}, server.Client())
+
+	require.NoError(t, store.Put(ctx, "attachments/1/a.txt", strings.NewReader("hello"), 5, "text/plain"))
+	assert.Equal(t, "hello", fake.objects["/uploads/attachments/1/a.txt"])
+
+	r, err := store.Get(ctx, "attachments/1/a.txt")
+	require.NoError(t, err)
+	data, _ := io.ReadAll(r)
+	r.Close()
+	assert.Equal(t, "hello", string(data))
+
+	require.NoError(t, store.Delete(ctx, "attachments/1/a.txt"))
+	_, err = store.Get(ctx, "attachments/1/a.txt")
+	assert.ErrorIs(t, err, domain.ErrNotFound)
+
+	bad := NewS3Store(S3Config{Endpoint: server.URL, Region: "us-east-1", Bucket: "uploads", AccessKey: "other", SecretKey: "secret"}, server.Client())
+	assert.Error(t, bad.Put(ctx, "a.txt", strings.NewReader("x"), 1, "text/plain"))
+}
+
+func TestSigningKey(t *testing.T) {
+	// example from the AWS signature version 4 documentation
+	key := signingKey("wJalrXUtnFEMI/K7MDENG+bPxRfiCYEXAMPLEKEY", "20120215", "us-east-1", "iam")
+	assert.Equal(t, "f4780e2d9f65fa895f9c67b32ce1baf0b0d8a43505a000a1a9e090d414db404d", hex.EncodeToString(key))
+}
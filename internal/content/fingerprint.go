@@ -0,0 +1,46 @@
+package content
+
+import (
+	"hash/fnv"
+	"strings"
+	"unicode"
+)
+
+// shingleSize is the number of consecutive words hashed together, it keeps
+// documents sharing a vocabulary but not their sentences apart
+const shingleSize = 3
+
+// Fingerprint returns the 64 bit SimHash of the words of text. Texts that
+// differ by a few words have fingerprints that differ by a few bits, text
+// without words has none and gets zero.
+func Fingerprint(text string) uint64 {
+	words := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	if len(words) == 0 {
+		return 0
+	}
+
+	var weights [64]int
+	n := max(1, len(words)-shingleSize+1)
+	for i := 0; i < n; i++ {
+		h := fnv.New64a()
+		h.Write([]byte(strings.Join(words[i:min(i+shingleSize, len(words))], " ")))
+		sum := h.Sum64()
+		for bit := range weights {
+			if sum&(1<<bit) != 0 {
+				weights[bit]++
+			} else {
+				weights[bit]--
+			}
+		}
+	}
+
+	var res uint64
+	for bit, w := range weights {
+		if w > 0 {
+			res |= 1 << bit
+		}
+	}
+	return res
+}
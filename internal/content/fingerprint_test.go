@@ -0,0 +1,40 @@
+package content_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/bxcodec/go-clean-arch/domain"
+	"github.com/bxcodec/go-clean-arch/internal/content"
+)
+
+const fingerprintText = `Go is an open source programming language that makes it simple to build
+secure, scalable systems. It was designed at Google to improve programming productivity
+in an era of multicore, networked machines and large codebases. The designers wanted to
+address criticism of other languages in use at Google while keeping their useful
+characteristics: static typing and run-time efficiency, readability and usability, and
+high-performance networking and multiprocessing.`
+
+func TestFingerprint(t *testing.T) {
+	base := content.Fingerprint(fingerprintText)
+	assert.NotZero(t, base)
+	assert.Zero(t, content.Fingerprint(" \n.,!"))
+
+	t.Run("formatting and case are ignored", func(t *testing.T) {
+		assert.Equal(t, base, content.Fingerprint(strings.ToUpper(strings.Join(strings.Fields(fingerprintText), "  "))))
+	})
+
+	t.Run("small edits stay close", func(t *testing.T) {
+		edited := strings.Replace(fingerprintText, "large codebases", "huge codebases", 1)
+		assert.GreaterOrEqual(t, domain.Similarity(domain.FingerprintDistance(base, content.Fingerprint(edited))), 0.9)
+	})
+
+	t.Run("different texts are far apart", func(t *testing.T) {
+		other := content.Fingerprint(`Rust is a multi-paradigm, general-purpose programming language that
+emphasizes performance, type safety, and concurrency. It enforces memory safety without a
+garbage collector, using a borrow checker that tracks the lifetime of references at compile time.`)
+		assert.Less(t, domain.Similarity(domain.FingerprintDistance(base, other)), 0.9)
+	})
+}
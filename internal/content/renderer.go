@@ -0,0 +1,223 @@
+package content
+
+import (
+	"bytes"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/microcosm-cc/bluemonday"
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/extension"
+	"github.com/yuin/goldmark/renderer/html"
+	nethtml "golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+
+	"github.com/bxcodec/go-clean-arch/domain"
+)
+
+// ExcerptLength is the length in characters of article excerpts
+const ExcerptLength = 200
+
+// Renderer turns article sources into HTML that is safe to embed. Markdown is
+// converted first and may contain raw HTML, every output then goes through the
+// same allowlist policy.
+type Renderer struct {
+	markdown goldmark.Markdown
+	policy   *bluemonday.Policy
+}
+
+func NewRenderer() *Renderer {
+	return &Renderer{
+		markdown: goldmark.New(
+			goldmark.WithExtensions(extension.GFM),
+			// raw HTML is kept here and left to the sanitizer
+			goldmark.WithRendererOptions(html.WithUnsafe()),
+		),
+		policy: newPolicy(),
+	}
+}
+
+// newPolicy allows the formatting of user generated content: text, links,
+// images, lists, tables and code, without scripts, styles or event handlers
+func newPolicy() *bluemonday.Policy {
+	p := bluemonday.UGCPolicy()
+	p.RequireNoFollowOnLinks(true)
+	p.AddTargetBlankToFullyQualifiedLinks(true)
+	p.AllowAttrs("class").Matching(bluemonday.SpaceSeparatedTokens).OnElements("code")
+	return p
+}
+
+// Render implements domain.ContentRenderer
+func (r *Renderer) Render(format domain.ContentFormat, source string) (domain.RenderedContent, error) {
+	var sanitized string
+	switch format {
+	case domain.ContentFormatMarkdown:
+		var buf bytes.Buffer
+		if err := r.markdown.Convert([]byte(source), &buf); err != nil {
+			return domain.RenderedContent{}, err
+		}
+		sanitized = r.policy.Sanitize(buf.String())
+	case domain.ContentFormatHTML:
+		sanitized = r.policy.Sanitize(source)
+	default:
+		return domain.RenderedContent{}, domain.ErrBadParamInput
+	}
+	return analyze(sanitized)
+}
+
+var headingLevels = map[atom.Atom]int{
+	atom.H1: 1, atom.H2: 2, atom.H3: 3, atom.H4: 4, atom.H5: 5, atom.H6: 6,
+}
+
+var blockElements = map[atom.Atom]bool{
+	atom.Address: true, atom.Article: true, atom.Blockquote: true, atom.Br: true, atom.Dd: true,
+	atom.Div: true, atom.Dl: true, atom.Dt: true, atom.Figcaption: true, atom.Figure: true,
+	atom.H1: true, atom.H2: true, atom.H3: true, atom.H4: true, atom.H5: true, atom.H6: true,
+	atom.Hr: true, atom.Li: true, atom.Ol: true, atom.P: true, atom.Pre: true,
+	atom.Section: true, atom.Table: true, atom.Td: true, atom.Th: true, atom.Tr: true, atom.Ul: true,
+}
+
+// analyze gives the headings of sanitized HTML unique anchors and derives the
+// metadata from its text
+func analyze(sanitized string) (domain.RenderedContent, error) {
+	nodes, err := nethtml.ParseFragment(strings.NewReader(sanitized), &nethtml.Node{
+		Type:     nethtml.ElementNode,
+		Data:     "body",
+		DataAtom: atom.Body,
+	})
+	if err != nil {
+		return domain.RenderedContent{}, err
+	}
+
+	res := domain.RenderedContent{TOC: []domain.TOCEntry{}}
+	var text strings.Builder
+	used := map[string]bool{}
+	var walk func(n *nethtml.Node)
+	walk = func(n *nethtml.Node) {
+		if n.Type == nethtml.TextNode {
+			text.WriteString(n.Data)
+			return
+		}
+		if level, ok := headingLevels[n.DataAtom]; ok {
+			title := collapse(textOf(n))
+			id := uniqueSlug(title, used)
+			setAttr(n, "id", id)
+			res.TOC = append(res.TOC, domain.TOCEntry{Level: level, ID: id, Text: title})
+		}
+		block := blockElements[n.DataAtom]
+		// block boundaries separate words even without whitespace in the source
+		if block {
+			text.WriteByte(' ')
+		}
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			walk(c)
+		}
+		if block {
+			text.WriteByte(' ')
+		}
+	}
+
+	var out bytes.Buffer
+	for _, n := range nodes {
+		walk(n)
+		if err := nethtml.Render(&out, n); err != nil {
+			return domain.RenderedContent{}, err
+		}
+	}
+
+	plain := collapse(text.String())
+	res.HTML = out.String()
+	res.Excerpt = excerpt(plain, ExcerptLength)
+	res.WordCount = int64(len(strings.Fields(plain)))
+	res.Fingerprint = Fingerprint(plain)
+	return res, nil
+}
+
+func textOf(n *nethtml.Node) string {
+	if n.Type == nethtml.TextNode {
+		return n.Data
+	}
+	var b strings.Builder
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		b.WriteString(textOf(c))
+	}
+	return b.String()
+}
+
+func setAttr(n *nethtml.Node, key, val string) {
+	for i := range n.Attr {
+		if n.Attr[i].Key == key {
+			n.Attr[i].Val = val
+			return
+		}
+	}
+	n.Attr = append(n.Attr, nethtml.Attribute{Key: key, Val: val})
+}
+
+// uniqueSlug turns a heading into an anchor, numbering repeated headings
+func uniqueSlug(title string, used map[string]bool) string {
+	slug := Slug(title)
+	if slug == "" {
+		slug = "section"
+	}
+	id := slug
+	for i := 2; used[id]; i++ {
+		id = slug + "-" + strconv.Itoa(i)
+	}
+	used[id] = true
+	return id
+}
+
+// Slug lowercases text and joins its letters and digits with dashes
+func Slug(text string) string {
+	var b strings.Builder
+	dash := false
+	for _, r := range strings.ToLower(text) {
+		switch {
+		case unicode.IsLetter(r) || unicode.IsDigit(r):
+			if dash && b.Len() > 0 {
+				b.WriteByte('-')
+			}
+			dash = false
+			b.WriteRune(r)
+		default:
+			dash = true
+		}
+	}
+	return b.String()
+}
+
+func collapse(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
+
+// excerpt shortens text to maxRunes, cutting at a word boundary when there is one
+func excerpt(text string, maxRunes int) string {
+	if utf8.RuneCountInString(text) <= maxRunes {
+		return text
+	}
+	cut := string([]rune(text)[:maxRunes])
+	if i := strings.LastIndexByte(cut, ' '); i > 0 {
+		return cut[:i] + "…"
+	}
+	return cut + "…"
+}
+
+// PlainText returns the text of HTML with its whitespace collapsed, every tag
+// separates words
+func PlainText(s string) string {
+	z := nethtml.NewTokenizer(strings.NewReader(s))
+	var b strings.Builder
+	for {
+		switch z.Next() {
+		case nethtml.ErrorToken:
+			return collapse(b.String())
+		case nethtml.TextToken:
+			b.Write(z.Text())
+		default:
+			b.WriteByte(' ')
+		}
+	}
+}
@@ -0,0 +1,61 @@
+package content_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bxcodec/go-clean-arch/domain"
+	"github.com/bxcodec/go-clean-arch/internal/content"
+)
+
+func TestRenderMarkdown(t *testing.T) {
+	r := content.NewRenderer()
+
+	res, err := r.Render(domain.ContentFormatMarkdown, "# Title\n\nSome **bold** [link](https://example.com)\n\n<script>alert(1)</script>")
+	require.NoError(t, err)
+	assert.Contains(t, res.HTML, `<h1 id="title">Title</h1>`)
+	assert.Contains(t, res.HTML, "<strong>bold</strong>")
+	assert.Contains(t, res.HTML, `href="https://example.com"`)
+	assert.Contains(t, res.HTML, `rel="nofollow noopener"`)
+	assert.NotContains(t, res.HTML, "script")
+}
+
+func TestRenderSanitizesHTML(t *testing.T) {
+	r := content.NewRenderer()
+
+	res, err := r.Render(domain.ContentFormatHTML, `<p onclick="steal()">Hi <a href="javascript:alert(1)">there</a></p><iframe src="x"></iframe><img src="a.png" onerror="x()">`)
+	require.NoError(t, err)
+	assert.Equal(t, `<p>Hi there</p><img src="a.png"/>`, res.HTML)
+
+	_, err = r.Render("rtf", "x")
+	assert.ErrorIs(t, err, domain.ErrBadParamInput)
+}
+
+func TestRenderMetadata(t *testing.T) {
+	r := content.NewRenderer()
+
+	res, err := r.Render(domain.ContentFormatMarkdown, "# Intro\n\nFirst &amp; *sec*ond\n\n## Setup\n\ntext\n\n## Setup\n\n### Déjà vu!\n")
+	require.NoError(t, err)
+	assert.Equal(t, "Intro First & second Setup text Setup Déjà vu!", res.Excerpt)
+	assert.Equal(t, int64(9), res.WordCount)
+	assert.Equal(t, []domain.TOCEntry{
+		{Level: 1, ID: "intro", Text: "Intro"},
+		{Level: 2, ID: "setup", Text: "Setup"},
+		{Level: 2, ID: "setup-2", Text: "Setup"},
+		{Level: 3, ID: "déjà-vu", Text: "Déjà vu!"},
+	}, res.TOC)
+	assert.Contains(t, res.HTML, `<h2 id="setup-2">Setup</h2>`)
+}
+
+func TestRenderExcerpt(t *testing.T) {
+	r := content.NewRenderer()
+
+	res, err := r.Render(domain.ContentFormatHTML, "<p>"+strings.Repeat("word ", 100)+"</p>")
+	require.NoError(t, err)
+	assert.Equal(t, strings.Repeat("word ", 39)+"word…", res.Excerpt)
+	assert.Empty(t, res.TOC)
+	assert.Equal(t, int64(100), res.WordCount)
+}
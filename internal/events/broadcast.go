@@ -0,0 +1,257 @@
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/sirupsen/logrus"
+
+	"github.com/bxcodec/go-clean-arch/domain"
+)
+
+const (
+	KeyStreamSeq      = "stream:articles:seq"
+	KeyStreamBuffer   = "stream:articles:buffer"
+	StreamChannel     = "stream:articles"
+	DefaultReplaySize = 1000
+
+	subscriberBuffer  = 64
+	viewFlushInterval = time.Second
+)
+
+// publishStreamScript assigns the next event id, appends the event to the
+// bounded replay buffer and publishes it to every replica in one step so that
+// ids are published in order.
+var publishStreamScript = redis.NewScript(`
+local id = redis.call('INCR', KEYS[1])
+local entry = cjson.encode({id = id, type = ARGV[1], data = ARGV[2]})
+redis.call('ZADD', KEYS[2], id, entry)
+redis.call('ZREMRANGEBYRANK', KEYS[2], 0, -(tonumber(ARGV[3]) + 1))
+redis.call('PUBLISH', ARGV[4], entry)
+return id
+`)
+
+type streamEntry struct {
+	ID   int64  `json:"id"`
+	Type string `json:"type"`
+	Data string `json:"data"`
+}
+
+func (e streamEntry) toDomain() domain.StreamEvent {
+	return domain.StreamEvent{ID: e.ID, Type: e.Type, Data: []byte(e.Data)}
+}
+
+// Broadcaster fans article events out to live stream subscribers on every replica.
+// Events are published through Redis pub/sub and kept in a bounded replay buffer
+// so that reconnecting clients can resume from the last event they saw.
+//
+// It is an EventSink for the outbox relay and a ViewRecorder for the article
+// service; views are aggregated locally and published once per second.
+type Broadcaster struct {
+	client     *redis.Client
+	replaySize int64
+
+	mu          sync.Mutex
+	subscribers map[chan domain.StreamEvent]struct{}
+	views       map[int64]int64
+	stopped     bool
+}
+
+func NewBroadcaster(client *redis.Client, replaySize int64) *Broadcaster {
+	if replaySize <= 0 {
+		replaySize = DefaultReplaySize
+	}
+	return &Broadcaster{
+		client:      client,
+		replaySize:  replaySize,
+		subscribers: make(map[chan domain.StreamEvent]struct{}),
+		views:       make(map[int64]int64),
+	}
+}
+
+func (b *Broadcaster) Name() string {
+	return "stream"
+}
+
+// Publish implements domain.EventSink
+func (b *Broadcaster) Publish(ctx context.Context, event domain.ArticleEvent) error {
+	if !event.Article.Listed() {
+		// the stream is public, drafts and hidden articles stay out of it
+		return nil
+	}
+	data, err := json.Marshal(NewMessage(&event))
+	if err != nil {
+		return err
+	}
+	return b.publish(ctx, string(event.Type), data)
+}
+
+// RecordView implements domain.ViewRecorder
+func (b *Broadcaster) RecordView(articleID int64) {
+	b.mu.Lock()
+	b.views[articleID]++
+	b.mu.Unlock()
+}
+
+func (b *Broadcaster) publish(ctx context.Context, eventType string, data []byte) error {
+	return publishStreamScript.Run(ctx, b.client, []string{KeyStreamSeq, KeyStreamBuffer},
+		eventType, string(data), b.replaySize, StreamChannel).Err()
+}
+
+// Subscribe registers a live subscriber. The channel is closed when the
+// subscriber falls too far behind or the broadcaster stops; the returned
+// function unsubscribes.
+func (b *Broadcaster) Subscribe() (<-chan domain.StreamEvent, func()) {
+	ch := make(chan domain.StreamEvent, subscriberBuffer)
+	b.mu.Lock()
+	if b.stopped {
+		close(ch)
+	} else {
+		b.subscribers[ch] = struct{}{}
+	}
+	b.mu.Unlock()
+	return ch, func() { b.unsubscribe(ch) }
+}
+
+func (b *Broadcaster) unsubscribe(ch chan domain.StreamEvent) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if _, ok := b.subscribers[ch]; ok {
+		delete(b.subscribers, ch)
+		close(ch)
+	}
+}
+
+// Replay returns the buffered events published after the given id. complete is
+// false when some of those events were already evicted from the buffer.
+func (b *Broadcaster) Replay(ctx context.Context, after int64) (res []domain.StreamEvent, complete bool, err error) {
+	oldest, err := b.client.ZRangeWithScores(ctx, KeyStreamBuffer, 0, 0).Result()
+	if err != nil {
+		return nil, false, err
+	}
+	if len(oldest) == 0 {
+		return nil, true, nil
+	}
+	complete = int64(oldest[0].Score) <= after+1
+
+	entries, err := b.client.ZRangeByScore(ctx, KeyStreamBuffer, &redis.ZRangeBy{
+		Min: "(" + strconv.FormatInt(after, 10),
+		Max: "+inf",
+	}).Result()
+	if err != nil {
+		return nil, false, err
+	}
+	for _, raw := range entries {
+		var e streamEntry
+		if err := json.Unmarshal([]byte(raw), &e); err != nil {
+			logrus.Warnf("skipping malformed stream entry: %v", err)
+			continue
+		}
+		res = append(res, e.toDomain())
+	}
+	return res, complete, nil
+}
+
+// Run relays events published by any replica to the local subscribers and
+// flushes the aggregated views until ctx is done, then closes every subscriber.
+// Subscribers are also closed when the subscription breaks, so that clients
+// reconnect and replay what they missed instead of silently skipping events.
+func (b *Broadcaster) Run(ctx context.Context) error {
+	pubsub := b.client.Subscribe(ctx, StreamChannel)
+	defer pubsub.Close()
+	if _, err := pubsub.Receive(ctx); err != nil {
+		if ctx.Err() != nil {
+			b.closeAll(true)
+			return nil
+		}
+		return err
+	}
+
+	flush := time.NewTicker(viewFlushInterval)
+	defer flush.Stop()
+	messages := pubsub.Channel()
+	for {
+		select {
+		case <-ctx.Done():
+			b.flushViews(context.WithoutCancel(ctx))
+			b.closeAll(true)
+			return nil
+		case <-flush.C:
+			b.flushViews(ctx)
+		case msg, ok := <-messages:
+			if !ok {
+				b.closeAll(false)
+				return errors.New("stream subscription closed")
+			}
+			var e streamEntry
+			if err := json.Unmarshal([]byte(msg.Payload), &e); err != nil {
+				logrus.Warnf("skipping malformed stream message: %v", err)
+				continue
+			}
+			b.deliver(e.toDomain())
+		}
+	}
+}
+
+// deliver sends the event to every local subscriber. A subscriber whose buffer
+// is full is dropped rather than blocking the others, it resumes through Replay.
+func (b *Broadcaster) deliver(event domain.StreamEvent) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for ch := range b.subscribers {
+		select {
+		case ch <- event:
+		default:
+			delete(b.subscribers, ch)
+			close(ch)
+		}
+	}
+}
+
+func (b *Broadcaster) flushViews(ctx context.Context) {
+	b.mu.Lock()
+	if len(b.views) == 0 {
+		b.mu.Unlock()
+		return
+	}
+	views := b.views
+	b.views = make(map[int64]int64)
+	b.mu.Unlock()
+
+	data, err := json.Marshal(viewsMessage{Views: views})
+	if err != nil {
+		logrus.Warnf("failed to encode view deltas: %v", err)
+		return
+	}
+	if err := b.publish(ctx, domain.StreamEventViews, data); err != nil {
+		// the views are still counted in the database, only the live update is lost
+		logrus.Warnf("failed to publish view deltas: %v", err)
+	}
+}
+
+// Close ends every live stream and refuses new subscribers. The server calls it
+// when it shuts down, as open streams would otherwise hold the shutdown until
+// their clients disconnect.
+func (b *Broadcaster) Close() {
+	b.closeAll(true)
+}
+
+// closeAll drops every subscriber, stopped refuses new ones for good
+func (b *Broadcaster) closeAll(stopped bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.stopped = b.stopped || stopped
+	for ch := range b.subscribers {
+		delete(b.subscribers, ch)
+		close(ch)
+	}
+}
+
+type viewsMessage struct {
+	Views map[int64]int64 `json:"views"`
+}
@@ -0,0 +1,105 @@
+package events_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/go-redis/redismock/v9"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bxcodec/go-clean-arch/domain"
+	"github.com/bxcodec/go-clean-arch/internal/events"
+)
+
+func TestBroadcasterPublish(t *testing.T) {
+	db, mock := redismock.NewClientMock()
+	b := events.NewBroadcaster(db, 100)
+
+	mock.Regexp().ExpectEvalSha(".+", []string{events.KeyStreamSeq, events.KeyStreamBuffer},
+		string(domain.ArticleCreated), `.*"article_id":7.*`, "100", events.StreamChannel).SetVal(int64(1))
+
+	article := domain.Article{ID: 7, Status: domain.ArticleStatusPublished, Visibility: domain.ArticleVisibilityPublic}
+	err := b.Publish(context.Background(), domain.ArticleEvent{ID: 1, Type: domain.ArticleCreated, ArticleID: 7, Article: article})
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestBroadcasterPublishSkipsHiddenArticles(t *testing.T) {
+	db, mock := redismock.NewClientMock()
+	b := events.NewBroadcaster(db, 100)
+
+	for _, article := range []domain.Article{
+		{ID: 7, Status: domain.ArticleStatusDraft},
+		{ID: 7, Status: domain.ArticleStatusPublished, Visibility: domain.ArticleVisibilityUnlisted},
+		{ID: 7, Status: domain.ArticleStatusPublished, Visibility: domain.ArticleVisibilityPrivate},
+	} {
+		err := b.Publish(context.Background(), domain.ArticleEvent{ID: 1, Type: domain.ArticleUpdated, ArticleID: 7, Article: article})
+		assert.NoError(t, err)
+	}
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestBroadcasterReplay(t *testing.T) {
+	entries := []string{
+		`{"id":4,"type":"article.created","data":"{\"article_id\":1}"}`,
+		`{"id":5,"type":"article.views","data":"{\"views\":{\"1\":2}}"}`,
+	}
+
+	t.Run("complete", func(t *testing.T) {
+		db, mock := redismock.NewClientMock()
+		b := events.NewBroadcaster(db, 100)
+		mock.ExpectZRangeWithScores(events.KeyStreamBuffer, 0, 0).SetVal([]redis.Z{{Score: 3, Member: "x"}})
+		mock.ExpectZRangeByScore(events.KeyStreamBuffer, &redis.ZRangeBy{Min: "(3", Max: "+inf"}).SetVal(entries)
+
+		res, complete, err := b.Replay(context.Background(), 3)
+		require.NoError(t, err)
+		assert.True(t, complete)
+		require.Len(t, res, 2)
+		assert.Equal(t, int64(4), res[0].ID)
+		assert.Equal(t, domain.StreamEventViews, res[1].Type)
+		assert.JSONEq(t, `{"views":{"1":2}}`, string(res[1].Data))
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("evicted", func(t *testing.T) {
+		db, mock := redismock.NewClientMock()
+		b := events.NewBroadcaster(db, 100)
+		mock.ExpectZRangeWithScores(events.KeyStreamBuffer, 0, 0).SetVal([]redis.Z{{Score: 4, Member: "x"}})
+		mock.ExpectZRangeByScore(events.KeyStreamBuffer, &redis.ZRangeBy{Min: "(1", Max: "+inf"}).SetVal(entries)
+
+		res, complete, err := b.Replay(context.Background(), 1)
+		require.NoError(t, err)
+		assert.False(t, complete)
+		assert.Len(t, res, 2)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("empty buffer", func(t *testing.T) {
+		db, mock := redismock.NewClientMock()
+		b := events.NewBroadcaster(db, 100)
+		mock.ExpectZRangeWithScores(events.KeyStreamBuffer, 0, 0).SetVal([]redis.Z{})
+
+		res, complete, err := b.Replay(context.Background(), 10)
+		require.NoError(t, err)
+		assert.True(t, complete)
+		assert.Empty(t, res)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestBroadcasterCloseEndsStreams(t *testing.T) {
+	db, _ := redismock.NewClientMock()
+	b := events.NewBroadcaster(db, 100)
+
+	ch, unsubscribe := b.Subscribe()
+	defer unsubscribe()
+	b.Close()
+	_, ok := <-ch
+	assert.False(t, ok, "open streams end")
+
+	late, _ := b.Subscribe()
+	_, ok = <-late
+	assert.False(t, ok, "no stream starts after the close")
+}
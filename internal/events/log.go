@@ -0,0 +1,29 @@
+package events
+
+import (
+	"context"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/bxcodec/go-clean-arch/domain"
+)
+
+// LogSink writes article events to the application log
+type LogSink struct{}
+
+func NewLogSink() *LogSink {
+	return &LogSink{}
+}
+
+func (s *LogSink) Name() string {
+	return "log"
+}
+
+func (s *LogSink) Publish(_ context.Context, event domain.ArticleEvent) error {
+	logrus.WithFields(logrus.Fields{
+		"event_id":   event.ID,
+		"type":       event.Type,
+		"article_id": event.ArticleID,
+	}).Info("article event")
+	return nil
+}
@@ -0,0 +1,46 @@
+package events
+
+import (
+	"time"
+
+	"github.com/bxcodec/go-clean-arch/domain"
+)
+
+// Message is the wire representation of an article event shared by all sinks
+type Message struct {
+	ID         int64   `json:"id"`
+	Type       string  `json:"type"`
+	ArticleID  int64   `json:"article_id"`
+	OccurredAt string  `json:"occurred_at"`
+	Article    Article `json:"article"`
+}
+
+type Article struct {
+	ID         int64  `json:"id"`
+	Title      string `json:"title"`
+	Content    string `json:"content"`
+	Status     string `json:"status"`
+	Visibility string `json:"visibility"`
+	UserID     int64  `json:"user_id"`
+	UpdatedAt  string `json:"updated_at"`
+	CreatedAt  string `json:"created_at"`
+}
+
+func NewMessage(e *domain.ArticleEvent) Message {
+	return Message{
+		ID:         e.ID,
+		Type:       string(e.Type),
+		ArticleID:  e.ArticleID,
+		OccurredAt: e.OccurredAt.Format(time.RFC3339),
+		Article: Article{
+			ID:         e.Article.ID,
+			Title:      e.Article.Title,
+			Content:    e.Article.ContentHTML,
+			Status:     string(e.Article.Status),
+			Visibility: string(e.Article.Visibility),
+			UserID:     e.Article.User.ID,
+			UpdatedAt:  e.Article.UpdatedAt.Format(time.RFC3339),
+			CreatedAt:  e.Article.CreatedAt.Format(time.RFC3339),
+		},
+	}
+}
@@ -0,0 +1,55 @@
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"strconv"
+
+	"github.com/redis/go-redis/v9"
+
+	"github.com/bxcodec/go-clean-arch/domain"
+)
+
+const (
+	DefaultStream = "events:articles"
+
+	defaultStreamMaxLen = 100000
+)
+
+// RedisStreamSink appends article events to a Redis Stream that other services consume with their own groups
+type RedisStreamSink struct {
+	client *redis.Client
+	stream string
+}
+
+func NewRedisStreamSink(client *redis.Client, stream string) *RedisStreamSink {
+	if stream == "" {
+		stream = DefaultStream
+	}
+	return &RedisStreamSink{
+		client: client,
+		stream: stream,
+	}
+}
+
+func (s *RedisStreamSink) Name() string {
+	return "redis"
+}
+
+func (s *RedisStreamSink) Publish(ctx context.Context, event domain.ArticleEvent) error {
+	data, err := json.Marshal(NewMessage(&event))
+	if err != nil {
+		return err
+	}
+	return s.client.XAdd(ctx, &redis.XAddArgs{
+		Stream: s.stream,
+		MaxLen: defaultStreamMaxLen,
+		Approx: true,
+		Values: map[string]any{
+			"event_id":   strconv.FormatInt(event.ID, 10),
+			"type":       string(event.Type),
+			"article_id": strconv.FormatInt(event.ArticleID, 10),
+			"data":       string(data),
+		},
+	}).Err()
+}
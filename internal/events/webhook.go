@@ -0,0 +1,63 @@
+package events
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/bxcodec/go-clean-arch/domain"
+)
+
+const defaultWebhookTimeout = 10 * time.Second
+
+// WebhookSink POSTs every article event as JSON to a fixed URL.
+// Receivers should deduplicate on the X-Event-ID header since delivery is at-least-once.
+type WebhookSink struct {
+	url    string
+	client *http.Client
+}
+
+func NewWebhookSink(url string, client *http.Client) *WebhookSink {
+	if client == nil {
+		client = &http.Client{Timeout: defaultWebhookTimeout}
+	}
+	return &WebhookSink{
+		url:    url,
+		client: client,
+	}
+}
+
+func (s *WebhookSink) Name() string {
+	return "webhook"
+}
+
+func (s *WebhookSink) Publish(ctx context.Context, event domain.ArticleEvent) error {
+	body, err := json.Marshal(NewMessage(&event))
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Event-ID", strconv.FormatInt(event.ID, 10))
+	req.Header.Set("X-Event-Type", string(event.Type))
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, resp.Body)
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook responded with status %d", resp.StatusCode)
+	}
+	return nil
+}
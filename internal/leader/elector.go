@@ -0,0 +1,238 @@
+package leader
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/sirupsen/logrus"
+
+	"github.com/bxcodec/go-clean-arch/domain"
+)
+
+const (
+	KeyLease = "leader:lease"
+	KeyToken = "leader:token"
+
+	defaultTTL = 15 * time.Second
+)
+
+// acquireScript takes the lease if it is free and hands out a new fencing token.
+// The lease value is "<replica id>:<token>".
+var acquireScript = redis.NewScript(`
+if redis.call('EXISTS', KEYS[1]) == 1 then
+	return 0
+end
+local token = redis.call('INCR', KEYS[2])
+redis.call('SET', KEYS[1], ARGV[1] .. ':' .. token, 'PX', ARGV[2])
+return token
+`)
+
+// renewScript extends the lease only if it is still held with the same token
+var renewScript = redis.NewScript(`
+if redis.call('GET', KEYS[1]) == ARGV[1] then
+	return redis.call('PEXPIRE', KEYS[1], ARGV[2])
+end
+return 0
+`)
+
+// releaseScript gives the lease up only if it is still held with the same token
+var releaseScript = redis.NewScript(`
+if redis.call('GET', KEYS[1]) == ARGV[1] then
+	return redis.call('DEL', KEYS[1])
+end
+return 0
+`)
+
+// Elector competes for a Redis lease shared by all replicas. The replica that
+// holds the lease is the leader until it fails to renew it within the TTL.
+type Elector struct {
+	client    *redis.Client
+	replicaID string
+	ttl       time.Duration
+
+	mu          sync.RWMutex
+	token       int64
+	leaderSince time.Time
+	onElected   []func(ctx context.Context)
+}
+
+// NewElector will create a leader elector for the given replica.
+// A non positive ttl falls back to the default lease of 15 seconds.
+func NewElector(client *redis.Client, replicaID string, ttl time.Duration) *Elector {
+	if ttl <= 0 {
+		ttl = defaultTTL
+	}
+	return &Elector{
+		client:    client,
+		replicaID: replicaID,
+		ttl:       ttl,
+	}
+}
+
+// OnElected registers fn to be started every time this replica becomes leader.
+// The context passed to fn is cancelled as soon as the leadership is lost.
+// It must be called before Run.
+func (e *Elector) OnElected(fn func(ctx context.Context)) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.onElected = append(e.onElected, fn)
+}
+
+// Run campaigns for the lease until ctx is done, renewing it every third of the TTL.
+// The lease is released on return so another replica can take over immediately.
+func (e *Elector) Run(ctx context.Context) {
+	ticker := time.NewTicker(e.ttl / 3)
+	defer ticker.Stop()
+
+	var cancel context.CancelFunc
+	stepDown := func() {
+		if cancel != nil {
+			cancel()
+			cancel = nil
+		}
+		e.setToken(0)
+	}
+
+	for {
+		if e.IsLeader() {
+			if err := e.renew(ctx); err != nil {
+				logrus.Warnf("leader %s lost the lease: %v", e.replicaID, err)
+				stepDown()
+			}
+		} else {
+			token, err := e.tryAcquire(ctx)
+			if err != nil {
+				logrus.Warnf("failed to acquire leader lease: %v", err)
+			} else if token > 0 {
+				logrus.Infof("replica %s elected leader with fencing token %d", e.replicaID, token)
+				e.setToken(token)
+				cancel = e.startElected(ctx)
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			token := e.Token()
+			stepDown()
+			if token > 0 {
+				e.release(token)
+			}
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// IsLeader reports whether this replica currently holds the lease
+func (e *Elector) IsLeader() bool {
+	return e.Token() > 0
+}
+
+// Token returns the fencing token of the current term, or 0 if not leader.
+// Tokens increase monotonically across terms, so stores can reject writes from stale leaders.
+func (e *Elector) Token() int64 {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	return e.token
+}
+
+// Status reports the local leadership state together with the current lease holder
+func (e *Elector) Status(ctx context.Context) (domain.LeaderStatus, error) {
+	e.mu.RLock()
+	status := domain.LeaderStatus{
+		ReplicaID:    e.replicaID,
+		IsLeader:     e.token > 0,
+		FencingToken: e.token,
+		LeaderSince:  e.leaderSince,
+	}
+	e.mu.RUnlock()
+
+	value, err := e.client.Get(ctx, KeyLease).Result()
+	if errors.Is(err, redis.Nil) {
+		return status, nil
+	}
+	if err != nil {
+		return status, err
+	}
+
+	holder, token, err := parseLease(value)
+	if err != nil {
+		return status, err
+	}
+	status.Leader = holder
+	if !status.IsLeader {
+		status.FencingToken = token
+	}
+	return status, nil
+}
+
+func (e *Elector) tryAcquire(ctx context.Context) (int64, error) {
+	return acquireScript.Run(ctx, e.client, []string{KeyLease, KeyToken},
+		e.replicaID, e.ttl.Milliseconds()).Int64()
+}
+
+func (e *Elector) renew(ctx context.Context) error {
+	ok, err := renewScript.Run(ctx, e.client, []string{KeyLease},
+		e.leaseValue(e.Token()), e.ttl.Milliseconds()).Int64()
+	if err != nil {
+		return err
+	}
+	if ok == 0 {
+		return errors.New("lease is held by another replica")
+	}
+	return nil
+}
+
+func (e *Elector) release(token int64) {
+	// the parent context is already done at this point
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	err := releaseScript.Run(ctx, e.client, []string{KeyLease}, e.leaseValue(token)).Err()
+	if err != nil {
+		logrus.Warnf("failed to release leader lease: %v", err)
+	}
+}
+
+// startElected starts the elected callbacks under a context that lives for the current term
+func (e *Elector) startElected(parent context.Context) context.CancelFunc {
+	ctx, cancel := context.WithCancel(parent)
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	for _, fn := range e.onElected {
+		go fn(ctx)
+	}
+	return cancel
+}
+
+func (e *Elector) setToken(token int64) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.token = token
+	if token > 0 {
+		e.leaderSince = time.Now()
+	} else {
+		e.leaderSince = time.Time{}
+	}
+}
+
+func (e *Elector) leaseValue(token int64) string {
+	return fmt.Sprintf("%s:%d", e.replicaID, token)
+}
+
+func parseLease(value string) (holder string, token int64, err error) {
+	i := strings.LastIndex(value, ":")
+	if i < 0 {
+		return "", 0, fmt.Errorf("malformed leader lease %q", value)
+	}
+	token, err = strconv.ParseInt(value[i+1:], 10, 64)
+	if err != nil {
+		return "", 0, fmt.Errorf("malformed leader lease %q: %w", value, err)
+	}
+	return value[:i], token, nil
+}
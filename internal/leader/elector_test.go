@@ -0,0 +1,70 @@
+package leader_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/go-redis/redismock/v9"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/bxcodec/go-clean-arch/internal/leader"
+)
+
+func TestStatus(t *testing.T) {
+	db, mock := redismock.NewClientMock()
+	elector := leader.NewElector(db, "replica-a", time.Second)
+
+	t.Run("other replica leads", func(t *testing.T) {
+		mock.ExpectGet(leader.KeyLease).SetVal("replica-b:7")
+
+		status, err := elector.Status(context.Background())
+
+		assert.NoError(t, err)
+		assert.Equal(t, "replica-a", status.ReplicaID)
+		assert.False(t, status.IsLeader)
+		assert.Equal(t, "replica-b", status.Leader)
+		assert.Equal(t, int64(7), status.FencingToken)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("no leader", func(t *testing.T) {
+		mock.ExpectGet(leader.KeyLease).RedisNil()
+
+		status, err := elector.Status(context.Background())
+
+		assert.NoError(t, err)
+		assert.Empty(t, status.Leader)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestRunElectsAndReleases(t *testing.T) {
+	db, mock := redismock.NewClientMock()
+	elector := leader.NewElector(db, "replica-a", time.Hour)
+
+	mock.Regexp().ExpectEvalSha(".+", []string{leader.KeyLease, leader.KeyToken}, "replica-a", ".+").SetVal(int64(3))
+	mock.Regexp().ExpectEvalSha(".+", []string{leader.KeyLease}, "replica-a:3").SetVal(int64(1))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	elected := make(chan context.Context, 1)
+	elector.OnElected(func(leaderCtx context.Context) {
+		elected <- leaderCtx
+	})
+
+	done := make(chan struct{})
+	go func() {
+		elector.Run(ctx)
+		close(done)
+	}()
+
+	leaderCtx := <-elected
+	assert.True(t, elector.IsLeader())
+	assert.Equal(t, int64(3), elector.Token())
+
+	cancel()
+	<-done
+	<-leaderCtx.Done()
+	assert.False(t, elector.IsLeader())
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,113 @@
+package queue
+
+import (
+	"context"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/bxcodec/go-clean-arch/domain"
+)
+
+// DeadJob is a job that exhausted its retries
+type DeadJob struct {
+	Job domain.Job
+	Err string
+}
+
+// MemoryQueue is an in-process JobQueue meant for tests and local development.
+// Jobs are lost when the process exits.
+type MemoryQueue struct {
+	policy RetryPolicy
+
+	mu    sync.Mutex
+	seq   int64
+	ready []domain.Job
+	dead  []DeadJob
+	wake  chan struct{}
+}
+
+func NewMemoryQueue(policy RetryPolicy) *MemoryQueue {
+	return &MemoryQueue{
+		policy: policy.withDefaults(),
+		wake:   make(chan struct{}, 1),
+	}
+}
+
+func (q *MemoryQueue) Enqueue(_ context.Context, jobType string, payload []byte) error {
+	q.mu.Lock()
+	q.seq++
+	job := domain.Job{
+		ID:         strconv.FormatInt(q.seq, 10),
+		Type:       jobType,
+		Payload:    payload,
+		EnqueuedAt: time.Now(),
+	}
+	q.mu.Unlock()
+	q.push(job)
+	return nil
+}
+
+// Consume delivers jobs to handler until ctx is done. Failed jobs are retried
+// after the policy backoff and dead-lettered after the last attempt.
+func (q *MemoryQueue) Consume(ctx context.Context, handler domain.JobHandler) error {
+	for {
+		// stop even when the queue never drains
+		select {
+		case <-ctx.Done():
+			return nil
+		default:
+		}
+
+		job, ok := q.pop()
+		if !ok {
+			select {
+			case <-ctx.Done():
+				return nil
+			case <-q.wake:
+			}
+			continue
+		}
+
+		job.Attempts++
+		err := safeHandle(ctx, handler, job)
+		if err == nil {
+			continue
+		}
+		if job.Attempts >= q.policy.MaxAttempts {
+			q.mu.Lock()
+			q.dead = append(q.dead, DeadJob{Job: job, Err: err.Error()})
+			q.mu.Unlock()
+			continue
+		}
+		time.AfterFunc(q.policy.Backoff(job.Attempts), func() { q.push(job) })
+	}
+}
+
+// Dead returns the jobs that exhausted their retries
+func (q *MemoryQueue) Dead() []DeadJob {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return append([]DeadJob(nil), q.dead...)
+}
+
+func (q *MemoryQueue) push(job domain.Job) {
+	q.mu.Lock()
+	q.ready = append(q.ready, job)
+	q.mu.Unlock()
+	select {
+	case q.wake <- struct{}{}:
+	default:
+	}
+}
+
+func (q *MemoryQueue) pop() (domain.Job, bool) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if len(q.ready) == 0 {
+		return domain.Job{}, false
+	}
+	job := q.ready[0]
+	q.ready = q.ready[1:]
+	return job, true
+}
@@ -0,0 +1,98 @@
+package queue_test
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bxcodec/go-clean-arch/domain"
+	"github.com/bxcodec/go-clean-arch/internal/queue"
+)
+
+func TestMemoryQueueRetriesUntilSuccess(t *testing.T) {
+	q := queue.NewMemoryQueue(queue.RetryPolicy{MaxAttempts: 3, BaseBackoff: time.Millisecond})
+	var attempts atomic.Int64
+	done := make(chan domain.Job, 1)
+
+	mux := queue.NewMux()
+	mux.Handle("flaky", func(_ context.Context, job domain.Job) error {
+		if attempts.Add(1) < 3 {
+			return errors.New("not yet")
+		}
+		done <- job
+		return nil
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() { _ = q.Consume(ctx, mux.Process) }()
+
+	require.NoError(t, q.Enqueue(ctx, "flaky", []byte(`{"id":1}`)))
+
+	select {
+	case job := <-done:
+		assert.Equal(t, 3, job.Attempts)
+		assert.Equal(t, `{"id":1}`, string(job.Payload))
+	case <-time.After(time.Second):
+		t.Fatal("job was not retried")
+	}
+	assert.Empty(t, q.Dead())
+}
+
+func TestMemoryQueueDeadLetters(t *testing.T) {
+	q := queue.NewMemoryQueue(queue.RetryPolicy{MaxAttempts: 2, BaseBackoff: time.Millisecond})
+	mux := queue.NewMux()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() { _ = q.Consume(ctx, mux.Process) }()
+
+	require.NoError(t, q.Enqueue(ctx, "unknown", nil))
+
+	assert.Eventually(t, func() bool { return len(q.Dead()) == 1 }, time.Second, time.Millisecond)
+	dead := q.Dead()[0]
+	assert.Equal(t, "unknown", dead.Job.Type)
+	assert.Equal(t, 2, dead.Job.Attempts)
+	assert.Contains(t, dead.Err, "no handler registered")
+}
+
+func TestMemoryQueueStopsWhileBusy(t *testing.T) {
+	q := queue.NewMemoryQueue(queue.RetryPolicy{})
+	ctx, cancel := context.WithCancel(context.Background())
+
+	// every job enqueues the next one, so the queue never runs empty
+	var runs atomic.Int64
+	handler := func(ctx context.Context, _ domain.Job) error {
+		if runs.Add(1) == 3 {
+			cancel()
+		}
+		return q.Enqueue(ctx, "loop", nil)
+	}
+	require.NoError(t, q.Enqueue(ctx, "loop", nil))
+
+	done := make(chan error, 1)
+	go func() { done <- q.Consume(ctx, handler) }()
+
+	select {
+	case err := <-done:
+		assert.NoError(t, err)
+		assert.Equal(t, int64(3), runs.Load())
+	case <-time.After(time.Second):
+		cancel()
+		t.Fatal("consume did not stop on a busy queue")
+	}
+}
+
+func TestBackoff(t *testing.T) {
+	p := queue.RetryPolicy{BaseBackoff: time.Second, MaxBackoff: 5 * time.Second}
+
+	assert.Equal(t, time.Second, p.Backoff(1))
+	assert.Equal(t, 2*time.Second, p.Backoff(2))
+	assert.Equal(t, 4*time.Second, p.Backoff(3))
+	assert.Equal(t, 5*time.Second, p.Backoff(4))
+}
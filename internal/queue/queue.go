@@ -0,0 +1,82 @@
+package queue
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/bxcodec/go-clean-arch/domain"
+)
+
+const (
+	defaultMaxAttempts = 5
+	defaultBaseBackoff = time.Second
+	defaultMaxBackoff  = 5 * time.Minute
+)
+
+// RetryPolicy controls how failed jobs are retried before they are dead-lettered
+type RetryPolicy struct {
+	MaxAttempts int
+	BaseBackoff time.Duration
+	MaxBackoff  time.Duration
+}
+
+func (p RetryPolicy) withDefaults() RetryPolicy {
+	if p.MaxAttempts <= 0 {
+		p.MaxAttempts = defaultMaxAttempts
+	}
+	if p.BaseBackoff <= 0 {
+		p.BaseBackoff = defaultBaseBackoff
+	}
+	if p.MaxBackoff <= 0 {
+		p.MaxBackoff = defaultMaxBackoff
+	}
+	return p
+}
+
+// Backoff returns the delay before the given attempt is retried, doubling on every attempt
+func (p RetryPolicy) Backoff(attempt int) time.Duration {
+	d := p.BaseBackoff
+	for i := 1; i < attempt; i++ {
+		d *= 2
+		if d >= p.MaxBackoff {
+			return p.MaxBackoff
+		}
+	}
+	return d
+}
+
+// Mux routes jobs to the handler registered for their type
+type Mux struct {
+	handlers map[string]domain.JobHandler
+}
+
+func NewMux() *Mux {
+	return &Mux{
+		handlers: make(map[string]domain.JobHandler),
+	}
+}
+
+// Handle registers the handler for the given job type
+func (m *Mux) Handle(jobType string, handler domain.JobHandler) {
+	m.handlers[jobType] = handler
+}
+
+// Process implements domain.JobHandler
+func (m *Mux) Process(ctx context.Context, job domain.Job) error {
+	handler, ok := m.handlers[job.Type]
+	if !ok {
+		return fmt.Errorf("no handler registered for job type %q", job.Type)
+	}
+	return handler(ctx, job)
+}
+
+// safeHandle turns a panicking handler into a failed job
+func safeHandle(ctx context.Context, handler domain.JobHandler, job domain.Job) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("job %s panicked: %v", job.ID, r)
+		}
+	}()
+	return handler(ctx, job)
+}
@@ -0,0 +1,325 @@
+package queue
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/sirupsen/logrus"
+
+	"github.com/bxcodec/go-clean-arch/domain"
+)
+
+const (
+	DefaultStream = "queue:jobs"
+	DefaultGroup  = "workers"
+
+	defaultBatchSize         = 10
+	defaultBlockTimeout      = 5 * time.Second
+	defaultVisibilityTimeout = time.Minute
+	defaultMaxLen            = 100000
+	promoteInterval          = time.Second
+)
+
+// promoteScript moves delayed retries that are due from the sorted set back into the stream
+var promoteScript = redis.NewScript(`
+local due = redis.call('ZRANGEBYSCORE', KEYS[1], '-inf', ARGV[1], 'LIMIT', 0, ARGV[2])
+for _, member in ipairs(due) do
+	redis.call('ZREM', KEYS[1], member)
+	local job = cjson.decode(member)
+	redis.call('XADD', KEYS[2], 'MAXLEN', '~', ARGV[3], '*',
+		'type', job.type, 'payload', job.payload,
+		'attempts', job.attempts, 'enqueued_at', job.enqueued_at)
+end
+return #due
+`)
+
+// RedisOptions configures a RedisQueue. Zero values fall back to sane defaults.
+type RedisOptions struct {
+	Stream   string
+	Group    string
+	Consumer string
+	Retry    RetryPolicy
+	// BatchSize is the number of entries read per XREADGROUP call
+	BatchSize int64
+	// BlockTimeout is how long a read waits for new entries
+	BlockTimeout time.Duration
+	// VisibilityTimeout bounds a single handler run; entries left pending longer
+	// than that by a dead consumer are reclaimed by the others
+	VisibilityTimeout time.Duration
+	// MaxLen approximately caps the stream length
+	MaxLen int64
+}
+
+// RedisQueue is a durable JobQueue backed by a Redis Stream and a consumer group.
+// Jobs are acknowledged only after their handler succeeded, failed jobs are
+// retried with exponential backoff through a delayed sorted set and moved to a
+// dead-letter stream once they run out of attempts.
+type RedisQueue struct {
+	client *redis.Client
+	opts   RedisOptions
+}
+
+func NewRedisQueue(client *redis.Client, opts RedisOptions) *RedisQueue {
+	if opts.Stream == "" {
+		opts.Stream = DefaultStream
+	}
+	if opts.Group == "" {
+		opts.Group = DefaultGroup
+	}
+	if opts.BatchSize <= 0 {
+		opts.BatchSize = defaultBatchSize
+	}
+	if opts.BlockTimeout <= 0 {
+		opts.BlockTimeout = defaultBlockTimeout
+	}
+	if opts.VisibilityTimeout <= 0 {
+		opts.VisibilityTimeout = defaultVisibilityTimeout
+	}
+	if opts.MaxLen <= 0 {
+		opts.MaxLen = defaultMaxLen
+	}
+	opts.Retry = opts.Retry.withDefaults()
+	return &RedisQueue{
+		client: client,
+		opts:   opts,
+	}
+}
+
+func (q *RedisQueue) delayedKey() string {
+	return q.opts.Stream + ":delayed"
+}
+
+// DeadLetterKey is the stream that receives jobs which exhausted their retries
+func (q *RedisQueue) DeadLetterKey() string {
+	return q.opts.Stream + ":dead"
+}
+
+func (q *RedisQueue) Enqueue(ctx context.Context, jobType string, payload []byte) error {
+	return q.client.XAdd(ctx, &redis.XAddArgs{
+		Stream: q.opts.Stream,
+		MaxLen: q.opts.MaxLen,
+		Approx: true,
+		Values: map[string]any{
+			"type":        jobType,
+			"payload":     string(payload),
+			"attempts":    0,
+			"enqueued_at": time.Now().UnixMilli(),
+		},
+	}).Err()
+}
+
+// Consume reads new entries for this consumer, reclaims entries abandoned by
+// dead consumers and promotes due retries until ctx is done.
+func (q *RedisQueue) Consume(ctx context.Context, handler domain.JobHandler) error {
+	if q.opts.Consumer == "" {
+		return errors.New("redis queue requires a consumer name")
+	}
+	if err := q.ensureGroup(ctx); err != nil {
+		return err
+	}
+
+	promote := time.NewTicker(promoteInterval)
+	defer promote.Stop()
+	reclaim := time.NewTicker(q.opts.VisibilityTimeout / 2)
+	defer reclaim.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-promote.C:
+			if err := q.promote(ctx); err != nil {
+				logrus.Warnf("failed to promote delayed jobs: %v", err)
+			}
+			continue
+		case <-reclaim.C:
+			if err := q.reclaim(ctx, handler); err != nil {
+				logrus.Warnf("failed to reclaim pending jobs: %v", err)
+			}
+			continue
+		default:
+		}
+
+		streams, err := q.client.XReadGroup(ctx, &redis.XReadGroupArgs{
+			Group:    q.opts.Group,
+			Consumer: q.opts.Consumer,
+			Streams:  []string{q.opts.Stream, ">"},
+			Count:    q.opts.BatchSize,
+			Block:    q.opts.BlockTimeout,
+		}).Result()
+		if err != nil {
+			if errors.Is(err, redis.Nil) || ctx.Err() != nil {
+				continue
+			}
+			logrus.Warnf("failed to read jobs: %v", err)
+			select {
+			case <-ctx.Done():
+			case <-time.After(time.Second):
+			}
+			continue
+		}
+
+		for _, stream := range streams {
+			for _, msg := range stream.Messages {
+				q.process(ctx, handler, msg)
+			}
+		}
+	}
+}
+
+func (q *RedisQueue) ensureGroup(ctx context.Context) error {
+	err := q.client.XGroupCreateMkStream(ctx, q.opts.Stream, q.opts.Group, "0").Err()
+	if err != nil && !strings.HasPrefix(err.Error(), "BUSYGROUP") {
+		return fmt.Errorf("failed to create consumer group: %w", err)
+	}
+	return nil
+}
+
+// process runs the handler for one entry and either acknowledges it, schedules
+// a retry or dead-letters it. The handler run is detached from ctx so shutdown
+// does not abort a job half way, it is bounded by the visibility timeout instead.
+func (q *RedisQueue) process(ctx context.Context, handler domain.JobHandler, msg redis.XMessage) {
+	job := decodeJob(msg)
+	job.Attempts++
+
+	runCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), q.opts.VisibilityTimeout)
+	defer cancel()
+	handleErr := safeHandle(runCtx, handler, job)
+
+	var err error
+	switch {
+	case handleErr == nil:
+		err = q.client.XAck(runCtx, q.opts.Stream, q.opts.Group, msg.ID).Err()
+	case job.Attempts >= q.opts.Retry.MaxAttempts:
+		logrus.Warnf("job %s (%s) dead-lettered after %d attempts: %v", job.ID, job.Type, job.Attempts, handleErr)
+		err = q.deadLetter(runCtx, job, handleErr)
+	default:
+		logrus.Warnf("job %s (%s) failed on attempt %d: %v", job.ID, job.Type, job.Attempts, handleErr)
+		err = q.retry(runCtx, job)
+	}
+	if err != nil {
+		// the entry stays pending and is reclaimed after the visibility timeout
+		logrus.Errorf("failed to settle job %s: %v", job.ID, err)
+	}
+}
+
+func (q *RedisQueue) retry(ctx context.Context, job domain.Job) error {
+	// numbers are encoded as strings so the promote script copies them verbatim
+	member, err := json.Marshal(map[string]string{
+		"id":          job.ID,
+		"type":        job.Type,
+		"payload":     string(job.Payload),
+		"attempts":    strconv.Itoa(job.Attempts),
+		"enqueued_at": strconv.FormatInt(job.EnqueuedAt.UnixMilli(), 10),
+	})
+	if err != nil {
+		return err
+	}
+	due := time.Now().Add(q.opts.Retry.Backoff(job.Attempts))
+
+	_, err = q.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.ZAdd(ctx, q.delayedKey(), redis.Z{Score: float64(due.UnixMilli()), Member: member})
+		pipe.XAck(ctx, q.opts.Stream, q.opts.Group, job.ID)
+		return nil
+	})
+	return err
+}
+
+func (q *RedisQueue) deadLetter(ctx context.Context, job domain.Job, cause error) error {
+	_, err := q.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.XAdd(ctx, &redis.XAddArgs{
+			Stream: q.DeadLetterKey(),
+			MaxLen: q.opts.MaxLen,
+			Approx: true,
+			Values: map[string]any{
+				"id":          job.ID,
+				"type":        job.Type,
+				"payload":     string(job.Payload),
+				"attempts":    job.Attempts,
+				"enqueued_at": job.EnqueuedAt.UnixMilli(),
+				"error":       cause.Error(),
+			},
+		})
+		pipe.XAck(ctx, q.opts.Stream, q.opts.Group, job.ID)
+		return nil
+	})
+	return err
+}
+
+func (q *RedisQueue) promote(ctx context.Context) error {
+	return promoteScript.Run(ctx, q.client, []string{q.delayedKey(), q.opts.Stream},
+		time.Now().UnixMilli(), q.opts.BatchSize, q.opts.MaxLen).Err()
+}
+
+// reclaim claims entries that stayed pending longer than the visibility timeout,
+// which means their consumer died before settling them. Entries delivered more
+// often than the retry policy allows are dead-lettered instead of run again.
+func (q *RedisQueue) reclaim(ctx context.Context, handler domain.JobHandler) error {
+	pending, err := q.client.XPendingExt(ctx, &redis.XPendingExtArgs{
+		Stream: q.opts.Stream,
+		Group:  q.opts.Group,
+		Idle:   q.opts.VisibilityTimeout,
+		Start:  "-",
+		End:    "+",
+		Count:  q.opts.BatchSize,
+	}).Result()
+	if err != nil || len(pending) == 0 {
+		return err
+	}
+
+	ids := make([]string, 0, len(pending))
+	deliveries := make(map[string]int64, len(pending))
+	for _, p := range pending {
+		ids = append(ids, p.ID)
+		deliveries[p.ID] = p.RetryCount
+	}
+
+	msgs, err := q.client.XClaim(ctx, &redis.XClaimArgs{
+		Stream:   q.opts.Stream,
+		Group:    q.opts.Group,
+		Consumer: q.opts.Consumer,
+		MinIdle:  q.opts.VisibilityTimeout,
+		Messages: ids,
+	}).Result()
+	if err != nil {
+		return err
+	}
+
+	for _, msg := range msgs {
+		if deliveries[msg.ID] > int64(q.opts.Retry.MaxAttempts) {
+			job := decodeJob(msg)
+			job.Attempts = int(deliveries[msg.ID])
+			if err := q.deadLetter(ctx, job, errors.New("consumer died while processing the job")); err != nil {
+				logrus.Errorf("failed to dead-letter job %s: %v", job.ID, err)
+			}
+			continue
+		}
+		q.process(ctx, handler, msg)
+	}
+	return nil
+}
+
+func decodeJob(msg redis.XMessage) domain.Job {
+	job := domain.Job{ID: msg.ID}
+	if v, ok := msg.Values["type"].(string); ok {
+		job.Type = v
+	}
+	if v, ok := msg.Values["payload"].(string); ok {
+		job.Payload = []byte(v)
+	}
+	if v, ok := msg.Values["attempts"].(string); ok {
+		job.Attempts, _ = strconv.Atoi(v)
+	}
+	if v, ok := msg.Values["enqueued_at"].(string); ok {
+		if ms, err := strconv.ParseInt(v, 10, 64); err == nil {
+			job.EnqueuedAt = time.UnixMilli(ms)
+		}
+	}
+	return job
+}
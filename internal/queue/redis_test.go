@@ -0,0 +1,186 @@
+package queue
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/go-redis/redismock/v9"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bxcodec/go-clean-arch/domain"
+)
+
+// fields returns the field values of an XADD command, whose order is not stable
+func fields(args []any) map[string]string {
+	res := make(map[string]string)
+	for i, arg := range args {
+		if arg != "*" {
+			continue
+		}
+		for j := i + 1; j+1 < len(args); j += 2 {
+			res[fmt.Sprint(args[j])] = fmt.Sprint(args[j+1])
+		}
+		break
+	}
+	return res
+}
+
+// expectDeadLetter expects the job to be moved to the dead-letter stream and acknowledged
+func expectDeadLetter(mock redismock.ClientMock, q *RedisQueue, id, attempts string) {
+	mock.ExpectTxPipeline()
+	mock.CustomMatch(func(_, actual []any) error {
+		f := fields(actual)
+		if actual[1] != q.DeadLetterKey() || f["id"] != id || f["attempts"] != attempts || f["error"] == "" {
+			return fmt.Errorf("unexpected dead letter %v", actual)
+		}
+		return nil
+	}).ExpectXAdd(&redis.XAddArgs{
+		Stream: q.DeadLetterKey(),
+		MaxLen: q.opts.MaxLen,
+		Approx: true,
+		Values: []any{"id", id, "type", "", "payload", "", "attempts", attempts, "enqueued_at", "", "error", ""},
+	}).SetVal("9-0")
+	mock.ExpectXAck(q.opts.Stream, q.opts.Group, id).SetVal(1)
+	mock.ExpectTxPipelineExec()
+}
+
+func newTestQueue() (*RedisQueue, redismock.ClientMock) {
+	db, mock := redismock.NewClientMock()
+	q := NewRedisQueue(db, RedisOptions{
+		Consumer: "replica-a",
+		Retry:    RetryPolicy{MaxAttempts: 3, BaseBackoff: time.Second},
+	})
+	return q, mock
+}
+
+func message(id string, attempts int) redis.XMessage {
+	return redis.XMessage{ID: id, Values: map[string]any{
+		"type":        "refresh",
+		"payload":     `{"id":1}`,
+		"attempts":    fmt.Sprint(attempts),
+		"enqueued_at": "1700000000000",
+	}}
+}
+
+func TestRedisQueueProcess(t *testing.T) {
+	failing := func(context.Context, domain.Job) error { return errors.New("unavailable") }
+
+	t.Run("acknowledges a handled job", func(t *testing.T) {
+		q, mock := newTestQueue()
+		mock.ExpectXAck(q.opts.Stream, q.opts.Group, "1-0").SetVal(1)
+
+		var got domain.Job
+		q.process(context.Background(), func(_ context.Context, job domain.Job) error {
+			got = job
+			return nil
+		}, message("1-0", 0))
+
+		assert.Equal(t, "refresh", got.Type)
+		assert.Equal(t, 1, got.Attempts)
+		assert.Equal(t, `{"id":1}`, string(got.Payload))
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("delays a failed job", func(t *testing.T) {
+		q, mock := newTestQueue()
+		before := time.Now()
+		mock.ExpectTxPipeline()
+		mock.CustomMatch(func(_, actual []any) error {
+			var member map[string]string
+			if err := json.Unmarshal(actual[3].([]byte), &member); err != nil {
+				return err
+			}
+			due := time.UnixMilli(int64(actual[2].(float64)))
+			if actual[1] != q.delayedKey() || member["attempts"] != "2" || member["type"] != "refresh" ||
+				due.Before(before.Add(2*time.Second-time.Millisecond)) {
+				return fmt.Errorf("unexpected retry %v", actual)
+			}
+			return nil
+		}).ExpectZAdd(q.delayedKey(), redis.Z{}).SetVal(1)
+		mock.ExpectXAck(q.opts.Stream, q.opts.Group, "1-0").SetVal(1)
+		mock.ExpectTxPipelineExec()
+
+		q.process(context.Background(), failing, message("1-0", 1))
+
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("dead-letters after the last attempt", func(t *testing.T) {
+		q, mock := newTestQueue()
+		expectDeadLetter(mock, q, "1-0", "3")
+
+		q.process(context.Background(), failing, message("1-0", 2))
+
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("leaves the job pending when it cannot be settled", func(t *testing.T) {
+		q, mock := newTestQueue()
+		mock.ExpectXAck(q.opts.Stream, q.opts.Group, "1-0").SetErr(errors.New("connection refused"))
+
+		q.process(context.Background(), func(context.Context, domain.Job) error { return nil }, message("1-0", 0))
+
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestRedisQueueReclaim(t *testing.T) {
+	q, mock := newTestQueue()
+	pendingArgs := &redis.XPendingExtArgs{
+		Stream: q.opts.Stream,
+		Group:  q.opts.Group,
+		Idle:   q.opts.VisibilityTimeout,
+		Start:  "-",
+		End:    "+",
+		Count:  q.opts.BatchSize,
+	}
+	mock.ExpectXPendingExt(pendingArgs).SetVal([]redis.XPendingExt{
+		{ID: "1-0", Consumer: "replica-b", RetryCount: 1},
+		{ID: "2-0", Consumer: "replica-b", RetryCount: 4},
+	})
+	mock.ExpectXClaim(&redis.XClaimArgs{
+		Stream:   q.opts.Stream,
+		Group:    q.opts.Group,
+		Consumer: "replica-a",
+		MinIdle:  q.opts.VisibilityTimeout,
+		Messages: []string{"1-0", "2-0"},
+	}).SetVal([]redis.XMessage{message("1-0", 0), message("2-0", 0)})
+	// the first entry is run again, the second was delivered too often
+	mock.ExpectXAck(q.opts.Stream, q.opts.Group, "1-0").SetVal(1)
+	expectDeadLetter(mock, q, "2-0", "4")
+
+	var handled []string
+	err := q.reclaim(context.Background(), func(_ context.Context, job domain.Job) error {
+		handled = append(handled, job.ID)
+		return nil
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, []string{"1-0"}, handled)
+	assert.NoError(t, mock.ExpectationsWereMet())
+
+	t.Run("nothing to reclaim", func(t *testing.T) {
+		mock.ExpectXPendingExt(pendingArgs).SetVal([]redis.XPendingExt{})
+
+		assert.NoError(t, q.reclaim(context.Background(), nil))
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestRedisQueuePromote(t *testing.T) {
+	q, mock := newTestQueue()
+	keys := []string{q.delayedKey(), q.opts.Stream}
+	mock.Regexp().ExpectEvalSha(".+", keys, `^\d{13}$`, "10", "100000").SetVal(int64(2))
+
+	assert.NoError(t, q.promote(context.Background()))
+	assert.NoError(t, mock.ExpectationsWereMet())
+
+	mock.Regexp().ExpectEvalSha(".+", keys, ".+", ".+", ".+").SetErr(errors.New("connection refused"))
+	assert.Error(t, q.promote(context.Background()))
+}
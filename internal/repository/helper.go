@@ -2,6 +2,9 @@ package repository
 
 import (
 	"encoding/base64"
+	"errors"
+	"strconv"
+	"strings"
 	"time"
 )
 
@@ -40,3 +43,30 @@ func PageVerify(pageSize *int64) {
 		*pageSize = MinPageSize
 	}
 }
+
+// DecodeKeyCursor will decode a cursor of a time and the id breaking ties on it
+func DecodeKeyCursor(encoded string) (time.Time, int64, error) {
+	byt, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return time.Time{}, 0, err
+	}
+
+	timeString, idString, ok := strings.Cut(string(byt), ",")
+	if !ok {
+		return time.Time{}, 0, errors.New("cursor without id")
+	}
+	t, err := time.Parse(timeFormat, timeString)
+	if err != nil {
+		return time.Time{}, 0, err
+	}
+	id, err := strconv.ParseInt(idString, 10, 64)
+	return t, id, err
+}
+
+// EncodeKeyCursor will encode a time and the id breaking ties on it, for
+// listings ordered by a time that is not unique or changes
+func EncodeKeyCursor(t time.Time, id int64) string {
+	key := t.Format(timeFormat) + "," + strconv.FormatInt(id, 10)
+
+	return base64.StdEncoding.EncodeToString([]byte(key))
+}
@@ -2,9 +2,16 @@ package mysql
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+	"time"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 
 	"github.com/bxcodec/go-clean-arch/domain"
 	"github.com/bxcodec/go-clean-arch/internal/repository"
@@ -20,9 +27,12 @@ func NewArticleRepository(db *gorm.DB) *ArticleRepository {
 	return &ArticleRepository{db}
 }
 
+// articleContentColumns are the large columns left out of listings
+var articleContentColumns = []string{"content", "content_html", "toc"}
+
 // TODO 从数据库中拿文章时应该使用连表查询把user信息也查出来
 
-func (m *ArticleRepository) Fetch(ctx context.Context, cursor string, num int64) (res []domain.Article, nextCursor string, err error) {
+func (m *ArticleRepository) Fetch(ctx context.Context, cursor string, num int64, withContent bool) (res []domain.Article, nextCursor string, err error) {
 	var articles []model.Article
 	decodedCursor, err := repository.DecodeCursor(cursor)
 	if err != nil && cursor != "" {
@@ -30,7 +40,13 @@ func (m *ArticleRepository) Fetch(ctx context.Context, cursor string, num int64)
 	}
 
 	repository.PageVerify(&num)
-	err = m.DB.WithContext(ctx).Where("created_at > ?", decodedCursor).
+	query := m.DB.WithContext(ctx)
+	if !withContent {
+		query = query.Omit(articleContentColumns...)
+	}
+	err = query.
+		Where("status = ? AND visibility = ?", domain.ArticleStatusPublished, domain.ArticleVisibilityPublic).
+		Where("created_at > ?", decodedCursor).
 		Order("created_at").
 		Limit(int(num)).
 		Find(&articles).
@@ -69,55 +85,463 @@ func (m *ArticleRepository) GetByTitle(ctx context.Context, title string) (res d
 	return
 }
 
+func (m *ArticleRepository) FetchByTitleSlug(ctx context.Context, slug string) ([]domain.Article, error) {
+	words := strings.Split(slug, "-")
+	for i, w := range words {
+		if w == "" {
+			return nil, nil
+		}
+		words[i] = regexp.QuoteMeta(w)
+	}
+	// the words of the slug with only separators around and between them,
+	// the collation of the title column ignores case
+	pattern := "^[^[:alnum:]]*" + strings.Join(words, "[^[:alnum:]]+") + "[^[:alnum:]]*$"
+	var articles []model.Article
+	err := m.DB.WithContext(ctx).
+		Omit(articleContentColumns...).
+		Where("title REGEXP ?", pattern).
+		Order("id").
+		Find(&articles).
+		Error
+	if err != nil {
+		return nil, err
+	}
+	res := make([]domain.Article, len(articles))
+	for i := range articles {
+		res[i] = articles[i].ToDomain()
+	}
+	return res, nil
+}
+
+func (m *ArticleRepository) FetchByIDs(ctx context.Context, ids []int64) ([]domain.Article, error) {
+	if len(ids) == 0 {
+		return nil, nil
+	}
+	var articles []model.Article
+	if err := m.DB.WithContext(ctx).Omit(articleContentColumns...).Where("id IN ?", ids).Find(&articles).Error; err != nil {
+		return nil, err
+	}
+	res := make([]domain.Article, len(articles))
+	for i := range articles {
+		res[i] = articles[i].ToDomain()
+	}
+	return res, nil
+}
+
+func (m *ArticleRepository) FetchRenderedByIDs(ctx context.Context, ids []int64) ([]domain.Article, error) {
+	if len(ids) == 0 {
+		return nil, nil
+	}
+	var articles []model.Article
+	if err := m.DB.WithContext(ctx).Select("id", "title", "content_html").Where("id IN ?", ids).Find(&articles).Error; err != nil {
+		return nil, err
+	}
+	res := make([]domain.Article, len(articles))
+	for i := range articles {
+		res[i] = articles[i].ToDomain()
+	}
+	return res, nil
+}
+
+func (m *ArticleRepository) FetchByAuthors(ctx context.Context, authorIDs []int64, before time.Time, num int64) ([]domain.Article, error) {
+	if len(authorIDs) == 0 {
+		return nil, nil
+	}
+	repository.PageVerify(&num)
+	var articles []model.Article
+	err := m.DB.WithContext(ctx).
+		Omit(articleContentColumns...).
+		Where("user_id IN ? AND status = ? AND visibility = ? AND created_at < ?",
+			authorIDs, domain.ArticleStatusPublished, domain.ArticleVisibilityPublic, before).
+		Order("created_at DESC").
+		Limit(int(num)).
+		Find(&articles).
+		Error
+	if err != nil {
+		return nil, err
+	}
+	res := make([]domain.Article, len(articles))
+	for i := range articles {
+		res[i] = articles[i].ToDomain()
+	}
+	return res, nil
+}
+
+func (m *ArticleRepository) FetchAfter(ctx context.Context, afterID int64, limit int, withContent bool) ([]domain.Article, error) {
+	var articles []model.Article
+	query := m.DB.WithContext(ctx)
+	if !withContent {
+		query = query.Omit(articleContentColumns...)
+	}
+	err := query.
+		Where("id > ?", afterID).
+		Order("id").
+		Limit(limit).
+		Find(&articles).
+		Error
+	if err != nil {
+		return nil, err
+	}
+	res := make([]domain.Article, len(articles))
+	for i := range articles {
+		res[i] = articles[i].ToDomain()
+	}
+	return res, nil
+}
+
+// similarColumns are the columns of near duplicates, their content is not needed
+const similarColumns = "id, title, excerpt, word_count, reading_minutes, fingerprint, status, visibility, " +
+	"user_id, views, comment_count, updated_at, created_at"
+
+func (m *ArticleRepository) FetchSimilar(ctx context.Context, fingerprint uint64, maxDistance int, excludeID int64, limit int) ([]domain.SimilarArticle, error) {
+	var rows []model.SimilarArticle
+	err := m.DB.WithContext(ctx).
+		Table("article").
+		Select(similarColumns+", BIT_COUNT(fingerprint ^ ?) AS distance", fingerprint).
+		Where("fingerprint IS NOT NULL AND id <> ? AND BIT_COUNT(fingerprint ^ ?) <= ?", excludeID, fingerprint, maxDistance).
+		Order("distance, id").
+		Limit(limit).
+		Scan(&rows).
+		Error
+	if err != nil {
+		return nil, err
+	}
+	res := make([]domain.SimilarArticle, len(rows))
+	for i := range rows {
+		res[i] = rows[i].ToDomain()
+	}
+	return res, nil
+}
+
+func (m *ArticleRepository) FetchRecent(ctx context.Context, authorID, categoryID int64, num int64) ([]domain.Article, error) {
+	repository.PageVerify(&num)
+	query := m.DB.WithContext(ctx).
+		Where("article.status = ? AND article.visibility = ?", domain.ArticleStatusPublished, domain.ArticleVisibilityPublic)
+	if authorID != 0 {
+		query = query.Where("article.user_id = ?", authorID)
+	}
+	if categoryID != 0 {
+		query = query.Where("EXISTS (SELECT 1 FROM article_category WHERE article_category.article_id = article.id AND article_category.category_id = ?)", categoryID)
+	}
+	var articles []model.Article
+	if err := query.Order("article.created_at DESC").Limit(int(num)).Find(&articles).Error; err != nil {
+		return nil, err
+	}
+	res := make([]domain.Article, len(articles))
+	for i := range articles {
+		res[i] = articles[i].ToDomain()
+	}
+	return res, nil
+}
+
+func (m *ArticleRepository) FetchUnrendered(ctx context.Context, afterID int64, limit int) ([]domain.Article, error) {
+	var articles []model.Article
+	err := m.DB.WithContext(ctx).
+		Where("id > ? AND (content_html IS NULL OR toc IS NULL OR (fingerprint IS NULL AND word_count > 0))", afterID).
+		Order("id").
+		Limit(limit).
+		Find(&articles).
+		Error
+	if err != nil {
+		return nil, err
+	}
+	res := make([]domain.Article, len(articles))
+	for i := range articles {
+		res[i] = articles[i].ToDomain()
+	}
+	return res, nil
+}
+
+func (m *ArticleRepository) SaveRendering(ctx context.Context, ar *domain.Article) error {
+	return m.DB.WithContext(ctx).Model(&model.Article{}).
+		Where("id = ?", ar.ID).
+		UpdateColumns(map[string]any{
+			"content_format":  string(ar.ContentFormat),
+			"content_html":    ar.ContentHTML,
+			"excerpt":         ar.Excerpt,
+			"word_count":      ar.WordCount,
+			"reading_minutes": ar.ReadingMinutes,
+			"toc":             model.NewTOCFromDomain(ar.TOC),
+			"fingerprint":     model.NewFingerprintFromDomain(ar.Fingerprint),
+		}).
+		Error
+}
+
+// Store inserts the article and records its events in the outbox within the same transaction
 func (m *ArticleRepository) Store(ctx context.Context, a *domain.Article) (err error) {
+	return m.StoreWith(ctx, a, domain.StoreOptions{})
+}
+
+func (m *ArticleRepository) StoreWith(ctx context.Context, a *domain.Article, opts domain.StoreOptions) error {
 	articleModel := model.NewArticleFromDomain(a)
-	result := m.DB.WithContext(ctx).Create(&articleModel)
-	if result.Error != nil {
-		return result.Error
-	}
-	a.ID = articleModel.ID
-	a.CreatedAt = articleModel.CreatedAt
-	a.UpdatedAt = articleModel.UpdatedAt
-	return
+	return m.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&articleModel).Error; err != nil {
+			return err
+		}
+		a.ID = articleModel.ID
+		a.CreatedAt = articleModel.CreatedAt
+		a.UpdatedAt = articleModel.UpdatedAt
+		categoryIDs := append([]int64{}, opts.CategoryIDs...)
+		for i := range opts.NewCategories {
+			c := &opts.NewCategories[i]
+			if err := firstOrCreateCategory(tx, c); err != nil {
+				return err
+			}
+			if !slices.Contains(categoryIDs, c.ID) {
+				categoryIDs = append(categoryIDs, c.ID)
+			}
+		}
+		if len(categoryIDs) > 0 {
+			if err := replaceCategories(tx, a.ID, categoryIDs); err != nil {
+				return err
+			}
+		}
+
+		snapshot := articleModel.ToDomain()
+		events := []domain.ArticleEventType{domain.ArticleCreated}
+		if snapshot.Status == domain.ArticleStatusPublished && !opts.Imported {
+			events = append(events, domain.ArticlePublished)
+		}
+		return writeOutbox(tx, &snapshot, nil, events...)
+	})
 }
 
+// Delete removes the article and records an ArticleDeleted event carrying its last state
 func (m *ArticleRepository) Delete(ctx context.Context, id int64) error {
-	result := m.DB.WithContext(ctx).Delete(&model.Article{}, id)
+	return m.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		return deleteArticle(tx, id)
+	})
+}
+
+func deleteArticle(tx *gorm.DB, id int64) error {
+	before, err := lockArticle(tx, id)
+	if err != nil {
+		return err
+	}
 
+	tags, err := articleTags(tx, id)
+	if err != nil {
+		return err
+	}
+
+	result := tx.Delete(&model.Article{}, id)
 	if result.Error != nil {
 		return result.Error
 	}
-
 	if result.RowsAffected == 0 {
 		return domain.ErrNotFound
 	}
+	if err := tx.Where("article_id = ?", id).Delete(&model.ArticleContributor{}).Error; err != nil {
+		return err
+	}
 
-	return nil
+	snapshot := before.ToDomain()
+	return writeOutbox(tx, &snapshot, tags, domain.ArticleDeleted)
+}
+
+// Update applies the non zero fields of the article and records an ArticleUpdated event,
+// plus ArticlePublished when the update moves a draft to published
+func (m *ArticleRepository) Update(ctx context.Context, ar *domain.Article, editorID int64) (err error) {
+	return m.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		return updateArticle(tx, ar, editorID, nil)
+	})
 }
 
-func (m *ArticleRepository) Update(ctx context.Context, ar *domain.Article) (err error) {
+// updateArticle applies the non zero fields of the article on behalf of
+// editorID and replaces its categories when categoryIDs is not nil
+func updateArticle(tx *gorm.DB, ar *domain.Article, editorID int64, categoryIDs []int64) error {
 	articleModel := model.NewArticleFromDomain(ar)
-	result := m.DB.WithContext(ctx).Model(&articleModel).Updates(&articleModel)
+	before, err := lockArticle(tx, ar.ID)
+	if err != nil {
+		return err
+	}
+	role, err := contributorRole(tx, ar.ID, editorID)
+	if err != nil {
+		return err
+	}
+	if !role.CanEdit() {
+		return domain.ErrForbidden
+	}
+	tags, err := articleTags(tx, ar.ID)
+	if err != nil {
+		return err
+	}
+
+	result := tx.Model(&articleModel).Updates(&articleModel)
 	if result.Error != nil {
 		return result.Error
 	}
-
 	if result.RowsAffected == 0 {
 		return domain.ErrNotFound
 	}
 
-	return
+	var after model.Article
+	if err := tx.First(&after, "id = ?", ar.ID).Error; err != nil {
+		return err
+	}
+	snapshot := after.ToDomain()
+	if categoryIDs != nil {
+		if err := replaceCategories(tx, ar.ID, categoryIDs); err != nil {
+			return err
+		}
+	}
+	events := []domain.ArticleEventType{domain.ArticleUpdated}
+	if before.Status != string(domain.ArticleStatusPublished) && snapshot.Status == domain.ArticleStatusPublished {
+		events = append(events, domain.ArticlePublished)
+	}
+	return writeOutbox(tx, &snapshot, tags, events...)
 }
 
-func (m *ArticleRepository) AddViews(ctx context.Context, id int64, deltaViews int64) (err error) {
-	result := m.DB.WithContext(ctx).Model(&model.Article{}).Where("id = ?", id).Update("views", gorm.Expr("views + ?", deltaViews))
-	if result.Error != nil {
-		return fmt.Errorf("failed to add views: %w", result.Error)
+// articleTags returns the tags of an article
+func articleTags(tx *gorm.DB, articleID int64) ([]string, error) {
+	var tags []string
+	err := tx.Table("article_category").
+		Joins("JOIN category ON category.id = article_category.category_id").
+		Where("article_category.article_id = ?", articleID).
+		Order("category.tag").
+		Pluck("category.tag", &tags).
+		Error
+	return tags, err
+}
+
+// firstOrCreateCategory sets the id of the category with the tag of c,
+// creating it when there is none
+func firstOrCreateCategory(tx *gorm.DB, c *domain.Category) error {
+	category := model.NewCategoryFromDomain(c)
+	if err := tx.Where("tag = ?", c.Tag).FirstOrCreate(category).Error; err != nil {
+		return err
+	}
+	c.ID = category.ID
+	return nil
+}
+
+func replaceCategories(tx *gorm.DB, articleID int64, categoryIDs []int64) error {
+	if err := tx.Where("article_id = ?", articleID).Delete(&model.ArticleCategoryLink{}).Error; err != nil {
+		return err
+	}
+	if len(categoryIDs) == 0 {
+		return nil
+	}
+	rows := make([]model.ArticleCategoryLink, len(categoryIDs))
+	for i, id := range categoryIDs {
+		rows[i] = model.ArticleCategoryLink{ArticleID: articleID, CategoryID: id}
 	}
+	return tx.Create(&rows).Error
+}
 
-	if result.RowsAffected == 0 {
-		return domain.ErrNotFound
+func (m *ArticleRepository) ApplyChanges(ctx context.Context, changes []domain.ArticleChange) error {
+	return m.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		for i := range changes {
+			change := &changes[i]
+			var err error
+			switch {
+			case change.Delete:
+				err = deleteOwnArticle(tx, change.Article.ID, change.UserID)
+			case change.SetTags:
+				err = updateArticle(tx, &change.Article, change.UserID, append([]int64{}, change.CategoryIDs...))
+			default:
+				err = updateArticle(tx, &change.Article, change.UserID, nil)
+			}
+			if err != nil {
+				return &domain.BatchItemError{Index: i, Err: err}
+			}
+		}
+		return nil
+	})
+}
+
+// deleteOwnArticle deletes the article if userID is its author
+func deleteOwnArticle(tx *gorm.DB, id, userID int64) error {
+	if _, err := lockArticle(tx, id); err != nil {
+		return err
+	}
+	role, err := contributorRole(tx, id, userID)
+	if err != nil {
+		return err
 	}
+	if !role.CanManage() {
+		return domain.ErrForbidden
+	}
+	return deleteArticle(tx, id)
+}
 
-	return
+// lockArticle reads the article with a row lock so concurrent changes record their events in commit order
+func lockArticle(tx *gorm.DB, id int64) (model.Article, error) {
+	var article model.Article
+	err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&article, "id = ?", id).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return article, domain.ErrNotFound
+	}
+	return article, err
+}
+
+// writeOutbox records events of the article, previousTags are its tags before the change
+func writeOutbox(tx *gorm.DB, a *domain.Article, previousTags []string, types ...domain.ArticleEventType) error {
+	now := time.Now()
+	for _, t := range types {
+		event, err := model.NewOutboxEvent(t, a, previousTags, now)
+		if err != nil {
+			return err
+		}
+		if err := tx.Create(event).Error; err != nil {
+			return fmt.Errorf("failed to write outbox event: %w", err)
+		}
+	}
+	return nil
+}
+
+// AddViews applies a whole view batch in a single transaction. Batches with an
+// id are recorded so a batch that is redelivered after a crash is skipped.
+func (m *ArticleRepository) AddViews(ctx context.Context, batch domain.ViewBatch) error {
+	if len(batch.Views) == 0 {
+		return nil
+	}
+
+	ids := make([]int64, 0, len(batch.Views))
+	for id := range batch.Views {
+		ids = append(ids, id)
+	}
+	// keep a stable lock order between concurrent batches
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+
+	var caseExpr strings.Builder
+	args := make([]any, 0, len(ids)*2)
+	caseExpr.WriteString("views + CASE id")
+	for _, id := range ids {
+		caseExpr.WriteString(" WHEN ? THEN ?")
+		args = append(args, id, batch.Views[id])
+	}
+	caseExpr.WriteString(" ELSE 0 END")
+
+	err := m.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if batch.FencingToken != 0 {
+			if err := checkFence(tx, batch.FencingToken); err != nil {
+				return err
+			}
+		}
+		if batch.ID != "" {
+			result := tx.Clauses(clause.Insert{Modifier: "IGNORE"}).
+				Create(&model.ViewBatch{ID: batch.ID, AppliedAt: time.Now()})
+			if result.Error != nil {
+				return result.Error
+			}
+			if result.RowsAffected == 0 {
+				// already applied by a previous run
+				return nil
+			}
+		}
+
+		return tx.Model(&model.Article{}).
+			Where("id IN ?", ids).
+			UpdateColumn("views", gorm.Expr(caseExpr.String(), args...)).
+			Error
+	})
+	if err != nil {
+		return fmt.Errorf("failed to add views: %w", err)
+	}
+	return nil
+}
+
+func (m *ArticleRepository) PurgeViewBatches(ctx context.Context, before time.Time) error {
+	return m.DB.WithContext(ctx).Where("applied_at < ?", before).Delete(&model.ViewBatch{}).Error
 }
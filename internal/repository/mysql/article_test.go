@@ -0,0 +1,57 @@
+package mysql_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bxcodec/go-clean-arch/domain"
+	mysqlRepo "github.com/bxcodec/go-clean-arch/internal/repository/mysql"
+)
+
+func TestFetchByTitleSlugMatchesTheWholeTitle(t *testing.T) {
+	db, mock := newMockDB(t)
+	repo := mysqlRepo.NewArticleRepository(db)
+
+	mock.ExpectQuery("SELECT .+ FROM `article` WHERE title REGEXP \\? ORDER BY id").
+		WithArgs("^[^[:alnum:]]*hot[^[:alnum:]]+soup[^[:alnum:]]*$").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "title"}).AddRow(int64(9), "Hot: soup"))
+
+	res, err := repo.FetchByTitleSlug(context.Background(), "hot-soup")
+	require.NoError(t, err)
+	require.Len(t, res, 1)
+	assert.Equal(t, int64(9), res[0].ID)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestStoreWithCreatesCategoriesInTheTransaction(t *testing.T) {
+	db, mock := newMockDB(t)
+	repo := mysqlRepo.NewArticleRepository(db)
+
+	mock.ExpectBegin()
+	mock.ExpectExec("INSERT INTO `article`").WillReturnResult(sqlmock.NewResult(7, 1))
+	mock.ExpectQuery("SELECT \\* FROM `category` WHERE tag = \\?").
+		WithArgs("winter", 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+	mock.ExpectExec("INSERT INTO `category`").WillReturnResult(sqlmock.NewResult(4, 1))
+	mock.ExpectExec("DELETE FROM `article_category`").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec("INSERT INTO `article_category`").
+		WithArgs(int64(7), int64(1), int64(7), int64(4)).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+	mock.ExpectExec("INSERT INTO `article_outbox`").WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectCommit()
+
+	ar := &domain.Article{Title: "Soup", Status: domain.ArticleStatusPublished}
+	opts := domain.StoreOptions{
+		CategoryIDs:   []int64{1},
+		NewCategories: []domain.Category{{Name: "Winter", Tag: "winter"}},
+		Imported:      true,
+	}
+	require.NoError(t, repo.StoreWith(context.Background(), ar, opts))
+	assert.Equal(t, int64(7), ar.ID)
+	assert.Equal(t, int64(4), opts.NewCategories[0].ID)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,142 @@
+package mysql
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/bxcodec/go-clean-arch/domain"
+	"github.com/bxcodec/go-clean-arch/internal/repository/mysql/model"
+)
+
+type AttachmentRepository struct {
+	DB *gorm.DB
+}
+
+// NewAttachmentRepository will create an object that represent the domain.AttachmentRepository interface
+func NewAttachmentRepository(db *gorm.DB) *AttachmentRepository {
+	return &AttachmentRepository{db}
+}
+
+// Store locks the attachments of the user while checking the quota so that
+// concurrent uploads cannot both pass it
+func (m *AttachmentRepository) Store(ctx context.Context, a *domain.Attachment, quota int64) error {
+	attachment := model.NewAttachmentFromDomain(a)
+	return m.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var used int64
+		err := tx.Model(&model.Attachment{}).
+			Clauses(clause.Locking{Strength: "UPDATE"}).
+			Select("COALESCE(SUM(size + thumbnail_size), 0)").
+			Where("user_id = ?", a.UserID).
+			Scan(&used).
+			Error
+		if err != nil {
+			return err
+		}
+		if used+a.StoredSize() > quota {
+			return domain.ErrQuotaExceeded
+		}
+
+		if err := tx.Create(attachment).Error; err != nil {
+			return err
+		}
+		a.ID = attachment.ID
+		return nil
+	})
+}
+
+func (m *AttachmentRepository) GetByID(ctx context.Context, id int64) (domain.Attachment, error) {
+	var attachment model.Attachment
+	err := m.DB.WithContext(ctx).First(&attachment, "id = ?", id).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return domain.Attachment{}, domain.ErrNotFound
+	}
+	if err != nil {
+		return domain.Attachment{}, err
+	}
+	return attachment.ToDomain(), nil
+}
+
+func (m *AttachmentRepository) GetByKey(ctx context.Context, key string) (domain.Attachment, error) {
+	var attachment model.Attachment
+	err := m.DB.WithContext(ctx).
+		Where("storage_key = ? OR thumbnail_key = ?", key, key).
+		First(&attachment).
+		Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return domain.Attachment{}, domain.ErrNotFound
+	}
+	if err != nil {
+		return domain.Attachment{}, err
+	}
+	return attachment.ToDomain(), nil
+}
+
+func (m *AttachmentRepository) FetchByArticle(ctx context.Context, articleID int64) ([]domain.Attachment, error) {
+	var attachments []model.Attachment
+	err := m.DB.WithContext(ctx).
+		Where("article_id = ?", articleID).
+		Order("id").
+		Find(&attachments).
+		Error
+	if err != nil {
+		return nil, err
+	}
+	res := make([]domain.Attachment, len(attachments))
+	for i := range attachments {
+		res[i] = attachments[i].ToDomain()
+	}
+	return res, nil
+}
+
+func (m *AttachmentRepository) Link(ctx context.Context, userID, articleID int64, ids []int64) (int64, error) {
+	if len(ids) == 0 {
+		return 0, nil
+	}
+	result := m.DB.WithContext(ctx).
+		Model(&model.Attachment{}).
+		Where("id IN ? AND user_id = ?", ids, userID).
+		Update("article_id", articleID)
+	return result.RowsAffected, result.Error
+}
+
+func (m *AttachmentRepository) Usage(ctx context.Context, userID int64) (int64, error) {
+	var used int64
+	err := m.DB.WithContext(ctx).
+		Model(&model.Attachment{}).
+		Select("COALESCE(SUM(size + thumbnail_size), 0)").
+		Where("user_id = ?", userID).
+		Scan(&used).
+		Error
+	return used, err
+}
+
+func (m *AttachmentRepository) FetchOrphans(ctx context.Context, before time.Time, limit int) ([]domain.Attachment, error) {
+	var attachments []model.Attachment
+	err := m.DB.WithContext(ctx).
+		Select("attachment.*").
+		Joins("LEFT JOIN article ON article.id = attachment.article_id").
+		Where("attachment.created_at < ? AND article.id IS NULL", before).
+		Order("attachment.id").
+		Limit(limit).
+		Find(&attachments).
+		Error
+	if err != nil {
+		return nil, err
+	}
+	res := make([]domain.Attachment, len(attachments))
+	for i := range attachments {
+		res[i] = attachments[i].ToDomain()
+	}
+	return res, nil
+}
+
+func (m *AttachmentRepository) Delete(ctx context.Context, ids []int64) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	return m.DB.WithContext(ctx).Delete(&model.Attachment{}, ids).Error
+}
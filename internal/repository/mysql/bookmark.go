@@ -0,0 +1,248 @@
+package mysql
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/bxcodec/go-clean-arch/domain"
+	"github.com/bxcodec/go-clean-arch/internal/repository"
+	"github.com/bxcodec/go-clean-arch/internal/repository/mysql/model"
+)
+
+const bookmarkColumns = "bookmark.*, article.id AS article_ref_id, article.title AS article_title, " +
+	"article.status AS article_status, article.visibility AS article_visibility, article.user_id AS article_user_id, " +
+	"article.created_at AS article_created_at, article.updated_at AS article_updated_at"
+
+type BookmarkRepository struct {
+	DB *gorm.DB
+}
+
+// NewBookmarkRepository will create an object that represent the domain.BookmarkRepository interface
+func NewBookmarkRepository(db *gorm.DB) *BookmarkRepository {
+	return &BookmarkRepository{db}
+}
+
+func (m *BookmarkRepository) Save(ctx context.Context, b *domain.Bookmark) error {
+	bookmark := model.NewBookmarkFromDomain(b)
+	return m.DB.WithContext(ctx).Clauses(clause.OnConflict{
+		DoUpdates: clause.AssignmentColumns([]string{"list_id", "position", "updated_at"}),
+	}).Create(bookmark).Error
+}
+
+func (m *BookmarkRepository) Get(ctx context.Context, userID, articleID int64) (domain.Bookmark, error) {
+	var row model.BookmarkWithArticle
+	err := m.joined(ctx).
+		Where("bookmark.user_id = ? AND bookmark.article_id = ?", userID, articleID).
+		Take(&row).
+		Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return domain.Bookmark{}, domain.ErrNotFound
+	}
+	if err != nil {
+		return domain.Bookmark{}, err
+	}
+	return row.ToDomain(), nil
+}
+
+func (m *BookmarkRepository) Delete(ctx context.Context, userID, articleID int64) error {
+	result := m.DB.WithContext(ctx).
+		Where("user_id = ? AND article_id = ?", userID, articleID).
+		Delete(&model.Bookmark{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return domain.ErrNotFound
+	}
+	return nil
+}
+
+// SetRead marks the bookmark read at readAt, a zero readAt marks it unread
+func (m *BookmarkRepository) SetRead(ctx context.Context, userID, articleID int64, readAt time.Time) error {
+	var value any
+	if !readAt.IsZero() {
+		value = readAt
+	}
+	result := m.DB.WithContext(ctx).
+		Model(&model.Bookmark{}).
+		Where("user_id = ? AND article_id = ?", userID, articleID).
+		Updates(map[string]any{"read_at": value, "updated_at": time.Now()})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return domain.ErrNotFound
+	}
+	return nil
+}
+
+func (m *BookmarkRepository) Fetch(ctx context.Context, userID int64, filter domain.BookmarkFilter, cursor string, num int64) (res []domain.Bookmark, nextCursor string, err error) {
+	query := m.filtered(ctx, filter).Where("bookmark.user_id = ?", userID)
+	if cursor != "" {
+		decodedCursor, err := repository.DecodeCursor(cursor)
+		if err != nil {
+			return nil, "", domain.ErrBadParamInput
+		}
+		query = query.Where("bookmark.created_at < ?", decodedCursor)
+	}
+
+	repository.PageVerify(&num)
+	res, err = m.find(query.Order("bookmark.created_at DESC").Limit(int(num)))
+	if err != nil {
+		return nil, "", err
+	}
+	if len(res) == int(num) {
+		nextCursor = repository.EncodeCursor(res[len(res)-1].CreatedAt)
+	}
+	return res, nextCursor, nil
+}
+
+// FetchList pages by position, the cursor is the position of the last returned bookmark
+func (m *BookmarkRepository) FetchList(ctx context.Context, listID int64, filter domain.BookmarkFilter, cursor string, num int64) (res []domain.Bookmark, nextCursor string, err error) {
+	query := m.filtered(ctx, filter).Where("bookmark.list_id = ?", listID)
+	if cursor != "" {
+		position, err := strconv.ParseInt(cursor, 10, 64)
+		if err != nil {
+			return nil, "", domain.ErrBadParamInput
+		}
+		query = query.Where("bookmark.position > ?", position)
+	}
+
+	repository.PageVerify(&num)
+	res, err = m.find(query.Order("bookmark.position").Limit(int(num)))
+	if err != nil {
+		return nil, "", err
+	}
+	if len(res) == int(num) {
+		nextCursor = strconv.FormatInt(res[len(res)-1].Position, 10)
+	}
+	return res, nextCursor, nil
+}
+
+func (m *BookmarkRepository) NextPosition(ctx context.Context, listID int64) (int64, error) {
+	var last *int64
+	err := m.DB.WithContext(ctx).
+		Model(&model.Bookmark{}).
+		Where("list_id = ?", listID).
+		Select("MAX(position)").
+		Scan(&last).
+		Error
+	if err != nil || last == nil {
+		return 1, err
+	}
+	return *last + 1, nil
+}
+
+func (m *BookmarkRepository) Reorder(ctx context.Context, listID int64, articleIDs []int64) error {
+	return m.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		for i, articleID := range articleIDs {
+			err := tx.Model(&model.Bookmark{}).
+				Where("list_id = ? AND article_id = ?", listID, articleID).
+				UpdateColumn("position", i+1).
+				Error
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func (m *BookmarkRepository) ListArticleIDs(ctx context.Context, listID int64) ([]int64, error) {
+	var ids []int64
+	err := m.DB.WithContext(ctx).
+		Model(&model.Bookmark{}).
+		Where("list_id = ?", listID).
+		Order("position").
+		Pluck("article_id", &ids).
+		Error
+	return ids, err
+}
+
+func (m *BookmarkRepository) joined(ctx context.Context) *gorm.DB {
+	return m.DB.WithContext(ctx).
+		Table("bookmark").
+		Select(bookmarkColumns).
+		Joins("LEFT JOIN article ON article.id = bookmark.article_id")
+}
+
+func (m *BookmarkRepository) filtered(ctx context.Context, filter domain.BookmarkFilter) *gorm.DB {
+	query := m.joined(ctx)
+	if filter.UnreadOnly {
+		query = query.Where("bookmark.read_at IS NULL")
+	}
+	return query
+}
+
+func (m *BookmarkRepository) find(query *gorm.DB) ([]domain.Bookmark, error) {
+	var rows []model.BookmarkWithArticle
+	if err := query.Find(&rows).Error; err != nil {
+		return nil, err
+	}
+	res := make([]domain.Bookmark, len(rows))
+	for i := range rows {
+		res[i] = rows[i].ToDomain()
+	}
+	return res, nil
+}
+
+func (m *BookmarkRepository) StoreList(ctx context.Context, l *domain.ReadingList) error {
+	list := model.NewReadingListFromDomain(l)
+	if err := m.DB.WithContext(ctx).Create(list).Error; err != nil {
+		return err
+	}
+	l.ID = list.ID
+	l.CreatedAt = list.CreatedAt
+	l.UpdatedAt = list.UpdatedAt
+	return nil
+}
+
+func (m *BookmarkRepository) GetList(ctx context.Context, id int64) (domain.ReadingList, error) {
+	var list model.ReadingList
+	err := m.DB.WithContext(ctx).First(&list, "id = ?", id).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return domain.ReadingList{}, domain.ErrNotFound
+	}
+	if err != nil {
+		return domain.ReadingList{}, err
+	}
+	return list.ToDomain(), nil
+}
+
+func (m *BookmarkRepository) FetchLists(ctx context.Context, userID int64) ([]domain.ReadingList, error) {
+	var lists []model.ReadingList
+	if err := m.DB.WithContext(ctx).Where("user_id = ?", userID).Order("name").Find(&lists).Error; err != nil {
+		return nil, err
+	}
+	res := make([]domain.ReadingList, len(lists))
+	for i := range lists {
+		res[i] = lists[i].ToDomain()
+	}
+	return res, nil
+}
+
+func (m *BookmarkRepository) UpdateList(ctx context.Context, l *domain.ReadingList) error {
+	return m.DB.WithContext(ctx).
+		Model(&model.ReadingList{}).
+		Where("id = ?", l.ID).
+		Updates(map[string]any{"name": l.Name, "updated_at": l.UpdatedAt}).
+		Error
+}
+
+func (m *BookmarkRepository) DeleteList(ctx context.Context, id int64) error {
+	return m.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.Model(&model.Bookmark{}).
+			Where("list_id = ?", id).
+			Updates(map[string]any{"list_id": 0, "position": 0}).
+			Error
+		if err != nil {
+			return err
+		}
+		return tx.Delete(&model.ReadingList{}, id).Error
+	})
+}
@@ -0,0 +1,81 @@
+package mysql
+
+import (
+	"context"
+	"errors"
+
+	"gorm.io/gorm"
+
+	"github.com/bxcodec/go-clean-arch/domain"
+	"github.com/bxcodec/go-clean-arch/internal/repository/mysql/model"
+)
+
+type CategoryRepository struct {
+	DB *gorm.DB
+}
+
+// NewCategoryRepository will create an object that represent the domain.CategoryRepository interface
+func NewCategoryRepository(db *gorm.DB) *CategoryRepository {
+	return &CategoryRepository{db}
+}
+
+func (m *CategoryRepository) GetByTag(ctx context.Context, tag string) (domain.Category, error) {
+	var category model.Category
+	err := m.DB.WithContext(ctx).First(&category, "tag = ?", tag).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return domain.Category{}, domain.ErrNotFound
+	}
+	if err != nil {
+		return domain.Category{}, err
+	}
+	return category.ToDomain(), nil
+}
+
+func (m *CategoryRepository) FetchByTags(ctx context.Context, tags []string) ([]domain.Category, error) {
+	if len(tags) == 0 {
+		return nil, nil
+	}
+	var categories []model.Category
+	if err := m.DB.WithContext(ctx).Where("tag IN ?", tags).Find(&categories).Error; err != nil {
+		return nil, err
+	}
+	res := make([]domain.Category, len(categories))
+	for i := range categories {
+		res[i] = categories[i].ToDomain()
+	}
+	return res, nil
+}
+
+func (m *CategoryRepository) Store(ctx context.Context, c *domain.Category) error {
+	category := model.NewCategoryFromDomain(c)
+	if err := m.DB.WithContext(ctx).Create(category).Error; err != nil {
+		return err
+	}
+	c.ID = category.ID
+	c.CreatedAt = category.CreatedAt
+	c.UpdatedAt = category.UpdatedAt
+	return nil
+}
+
+func (m *CategoryRepository) FetchByArticles(ctx context.Context, articleIDs []int64) (map[int64][]domain.Category, error) {
+	res := make(map[int64][]domain.Category, len(articleIDs))
+	if len(articleIDs) == 0 {
+		return res, nil
+	}
+	var rows []model.ArticleCategory
+	err := m.DB.WithContext(ctx).
+		Table("article_category").
+		Select("category.*, article_category.article_id").
+		Joins("JOIN category ON category.id = article_category.category_id").
+		Where("article_category.article_id IN ?", articleIDs).
+		Order("category.name").
+		Scan(&rows).
+		Error
+	if err != nil {
+		return nil, err
+	}
+	for i := range rows {
+		res[rows[i].ArticleID] = append(res[rows[i].ArticleID], rows[i].ToDomain())
+	}
+	return res, nil
+}
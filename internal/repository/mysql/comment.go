@@ -0,0 +1,135 @@
+package mysql
+
+import (
+	"context"
+	"errors"
+
+	"gorm.io/gorm"
+
+	"github.com/bxcodec/go-clean-arch/domain"
+	"github.com/bxcodec/go-clean-arch/internal/repository"
+	"github.com/bxcodec/go-clean-arch/internal/repository/mysql/model"
+)
+
+// maxReplies bounds the replies loaded for one page of threads
+const maxReplies = 1000
+
+type CommentRepository struct {
+	DB *gorm.DB
+}
+
+// NewCommentRepository will create an object that represent the domain.CommentRepository interface
+func NewCommentRepository(db *gorm.DB) *CommentRepository {
+	return &CommentRepository{db}
+}
+
+func (m *CommentRepository) Store(ctx context.Context, c *domain.Comment) error {
+	comment := model.NewCommentFromDomain(c)
+	return m.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(comment).Error; err != nil {
+			return err
+		}
+		c.ID = comment.ID
+		c.CreatedAt = comment.CreatedAt
+		c.UpdatedAt = comment.UpdatedAt
+		return recountComments(tx, c.ArticleID)
+	})
+}
+
+func (m *CommentRepository) GetByID(ctx context.Context, id int64) (domain.Comment, error) {
+	var comment model.Comment
+	err := m.DB.WithContext(ctx).First(&comment, "id = ?", id).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return domain.Comment{}, domain.ErrNotFound
+	}
+	if err != nil {
+		return domain.Comment{}, err
+	}
+	return comment.ToDomain(), nil
+}
+
+func (m *CommentRepository) Update(ctx context.Context, c *domain.Comment) error {
+	return m.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&model.Comment{}).Where("id = ?", c.ID).Updates(map[string]any{
+			"content":    c.Content,
+			"status":     string(c.Status),
+			"deleted":    c.Deleted,
+			"updated_at": c.UpdatedAt,
+		})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return domain.ErrNotFound
+		}
+		return recountComments(tx, c.ArticleID)
+	})
+}
+
+func (m *CommentRepository) FetchThreads(ctx context.Context, articleID int64, cursor string, num int64) ([]domain.Comment, string, error) {
+	query := m.DB.WithContext(ctx).
+		Where("article_id = ? AND parent_id = 0 AND status = ?", articleID, domain.CommentApproved)
+	return m.fetchPage(query, cursor, num)
+}
+
+func (m *CommentRepository) FetchByStatus(ctx context.Context, articleID int64, status domain.CommentStatus, cursor string, num int64) ([]domain.Comment, string, error) {
+	query := m.DB.WithContext(ctx).
+		Where("article_id = ? AND status = ? AND deleted = ?", articleID, status, false)
+	return m.fetchPage(query, cursor, num)
+}
+
+func (m *CommentRepository) fetchPage(query *gorm.DB, cursor string, num int64) (res []domain.Comment, nextCursor string, err error) {
+	decodedCursor, err := repository.DecodeCursor(cursor)
+	if err != nil && cursor != "" {
+		return nil, "", domain.ErrBadParamInput
+	}
+
+	repository.PageVerify(&num)
+	var comments []model.Comment
+	err = query.
+		Where("created_at > ?", decodedCursor).
+		Order("created_at").
+		Limit(int(num)).
+		Find(&comments).
+		Error
+	if err != nil {
+		return nil, "", err
+	}
+
+	for i := range comments {
+		res = append(res, comments[i].ToDomain())
+	}
+	if len(res) == int(num) {
+		nextCursor = repository.EncodeCursor(res[len(res)-1].CreatedAt)
+	}
+	return res, nextCursor, nil
+}
+
+func (m *CommentRepository) FetchReplies(ctx context.Context, rootIDs []int64) ([]domain.Comment, error) {
+	if len(rootIDs) == 0 {
+		return nil, nil
+	}
+	var comments []model.Comment
+	err := m.DB.WithContext(ctx).
+		Where("root_id IN ? AND parent_id <> 0 AND status = ?", rootIDs, domain.CommentApproved).
+		Order("created_at").
+		Limit(maxReplies).
+		Find(&comments).
+		Error
+	if err != nil {
+		return nil, err
+	}
+	res := make([]domain.Comment, len(comments))
+	for i := range comments {
+		res[i] = comments[i].ToDomain()
+	}
+	return res, nil
+}
+
+// recountComments refreshes the denormalized comment count of the article from its visible comments
+func recountComments(tx *gorm.DB, articleID int64) error {
+	return tx.Exec(
+		"UPDATE article SET comment_count = (SELECT COUNT(*) FROM comment WHERE article_id = ? AND status = ? AND deleted = ?) WHERE id = ?",
+		articleID, domain.CommentApproved, false, articleID,
+	).Error
+}
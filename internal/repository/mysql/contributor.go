@@ -0,0 +1,100 @@
+package mysql
+
+import (
+	"context"
+	"slices"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/bxcodec/go-clean-arch/domain"
+	"github.com/bxcodec/go-clean-arch/internal/repository/mysql/model"
+)
+
+const contributorUserColumns = "user.name AS user_name, user.username AS user_username, " +
+	"user.created_at AS user_created_at, user.updated_at AS user_updated_at"
+
+type ContributorRepository struct {
+	DB *gorm.DB
+}
+
+// NewContributorRepository will create an object that represent the domain.ContributorRepository interface
+func NewContributorRepository(db *gorm.DB) *ContributorRepository {
+	return &ContributorRepository{db}
+}
+
+func (m *ContributorRepository) FetchByArticles(ctx context.Context, articleIDs []int64) (map[int64][]domain.Contributor, error) {
+	res := make(map[int64][]domain.Contributor, len(articleIDs))
+	if len(articleIDs) == 0 {
+		return res, nil
+	}
+	authors := m.DB.
+		Table("article").
+		Select("article.id AS article_id, ? AS role, article.user_id, "+contributorUserColumns, string(domain.ContributorAuthor)).
+		Joins("LEFT JOIN user ON user.id = article.user_id").
+		Where("article.id IN ?", articleIDs)
+	others := m.DB.
+		Table("article_contributor").
+		Select("article_contributor.article_id, article_contributor.role, article_contributor.user_id, "+contributorUserColumns).
+		Joins("LEFT JOIN user ON user.id = article_contributor.user_id").
+		Where("article_contributor.article_id IN ?", articleIDs)
+
+	var rows []model.ContributorWithUser
+	if err := m.DB.WithContext(ctx).Raw("(?) UNION ALL (?)", authors, others).Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+	for i := range rows {
+		res[rows[i].ArticleID] = append(res[rows[i].ArticleID], rows[i].ToDomain())
+	}
+	for id := range res {
+		slices.SortStableFunc(res[id], func(a, b domain.Contributor) int {
+			return a.Role.Rank() - b.Role.Rank()
+		})
+	}
+	return res, nil
+}
+
+func (m *ContributorRepository) Role(ctx context.Context, articleID, userID int64) (domain.ContributorRole, error) {
+	return contributorRole(m.DB.WithContext(ctx), articleID, userID)
+}
+
+// contributorRole returns the role of the user on the article, empty when they
+// do not contribute to it. It is shared with the article writes that check the
+// role inside their transaction.
+func contributorRole(db *gorm.DB, articleID, userID int64) (domain.ContributorRole, error) {
+	var roles []string
+	err := db.Raw(
+		"(SELECT ? AS role FROM article WHERE id = ? AND user_id = ?) UNION ALL "+
+			"(SELECT role FROM article_contributor WHERE article_id = ? AND user_id = ?)",
+		string(domain.ContributorAuthor), articleID, userID, articleID, userID,
+	).Scan(&roles).Error
+	if err != nil || len(roles) == 0 {
+		return "", err
+	}
+	return domain.ContributorRole(roles[0]), nil
+}
+
+func (m *ContributorRepository) Save(ctx context.Context, articleID, userID int64, role domain.ContributorRole) error {
+	return m.DB.WithContext(ctx).Clauses(clause.OnConflict{
+		DoUpdates: clause.AssignmentColumns([]string{"role"}),
+	}).Create(&model.ArticleContributor{
+		ArticleID: articleID,
+		UserID:    userID,
+		Role:      string(role),
+		CreatedAt: time.Now(),
+	}).Error
+}
+
+func (m *ContributorRepository) Remove(ctx context.Context, articleID, userID int64) error {
+	result := m.DB.WithContext(ctx).
+		Where("article_id = ? AND user_id = ?", articleID, userID).
+		Delete(&model.ArticleContributor{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return domain.ErrNotFound
+	}
+	return nil
+}
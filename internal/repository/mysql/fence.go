@@ -0,0 +1,44 @@
+package mysql
+
+import (
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/bxcodec/go-clean-arch/domain"
+	"github.com/bxcodec/go-clean-arch/internal/repository/mysql/model"
+)
+
+const leaderFenceName = "leader"
+
+// checkFence rejects a leader-only write made with a token older than the newest
+// one seen so far and records token otherwise. It must run inside the transaction
+// of the write: the fence row stays locked until it commits, so a stale leader
+// cannot slip a write in after a newer one.
+func checkFence(tx *gorm.DB, token int64) error {
+	if token <= 0 {
+		return domain.ErrStaleLeader
+	}
+
+	// make sure the row exists so that it can be locked
+	err := tx.Clauses(clause.Insert{Modifier: "IGNORE"}).
+		Create(&model.LeaderFence{Name: leaderFenceName}).
+		Error
+	if err != nil {
+		return err
+	}
+
+	var fence model.LeaderFence
+	err = tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		First(&fence, "name = ?", leaderFenceName).
+		Error
+	if err != nil {
+		return err
+	}
+	if token < fence.Token {
+		return domain.ErrStaleLeader
+	}
+	if token == fence.Token {
+		return nil
+	}
+	return tx.Model(&fence).UpdateColumn("token", token).Error
+}
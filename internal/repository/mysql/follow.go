@@ -0,0 +1,153 @@
+package mysql
+
+import (
+	"context"
+	"errors"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/bxcodec/go-clean-arch/domain"
+	"github.com/bxcodec/go-clean-arch/internal/repository"
+	"github.com/bxcodec/go-clean-arch/internal/repository/mysql/model"
+)
+
+type FollowRepository struct {
+	DB *gorm.DB
+}
+
+// NewFollowRepository will create an object that represent the domain.FollowRepository interface
+func NewFollowRepository(db *gorm.DB) *FollowRepository {
+	return &FollowRepository{db}
+}
+
+func (m *FollowRepository) Follow(ctx context.Context, f *domain.Follow) (bool, error) {
+	var added bool
+	err := m.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Clauses(clause.Insert{Modifier: "IGNORE"}).Create(model.NewFollowFromDomain(f))
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return nil
+		}
+		added = true
+		return updateFollowStats(tx, f.FollowerID, f.FolloweeID, 1)
+	})
+	return added, err
+}
+
+func (m *FollowRepository) Unfollow(ctx context.Context, followerID, followeeID int64) (bool, error) {
+	var removed bool
+	err := m.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Where("follower_id = ? AND followee_id = ?", followerID, followeeID).Delete(&model.Follow{})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return nil
+		}
+		removed = true
+		return updateFollowStats(tx, followerID, followeeID, -1)
+	})
+	return removed, err
+}
+
+func updateFollowStats(tx *gorm.DB, followerID, followeeID, delta int64) error {
+	rows := []model.FollowStats{
+		{UserID: followerID, Following: delta},
+		{UserID: followeeID, Followers: delta},
+	}
+	return tx.Clauses(clause.OnConflict{
+		DoUpdates: clause.Assignments(map[string]any{
+			"followers": gorm.Expr("followers + VALUES(followers)"),
+			"following": gorm.Expr("following + VALUES(following)"),
+		}),
+	}).Create(&rows).Error
+}
+
+func (m *FollowRepository) FetchFollowers(ctx context.Context, userID int64, cursor string, num int64) ([]domain.Follow, string, error) {
+	return m.fetch(ctx, "followee_id", "follower_id", userID, cursor, num)
+}
+
+func (m *FollowRepository) FetchFollowing(ctx context.Context, userID int64, cursor string, num int64) ([]domain.Follow, string, error) {
+	return m.fetch(ctx, "follower_id", "followee_id", userID, cursor, num)
+}
+
+// fetch pages the edges where column equals userID, other is the column
+// holding the user on the other side of the edge
+func (m *FollowRepository) fetch(ctx context.Context, column, other string, userID int64, cursor string, num int64) (res []domain.Follow, nextCursor string, err error) {
+	query := m.DB.WithContext(ctx).
+		Table("follow").
+		Select("follow.*, user.id AS user_ref_id, user.name AS user_name, user.username AS user_username, user.created_at AS user_created_at").
+		Joins("JOIN user ON user.id = follow."+other).
+		Where("follow."+column+" = ?", userID)
+	if cursor != "" {
+		decodedCursor, err := repository.DecodeCursor(cursor)
+		if err != nil {
+			return nil, "", domain.ErrBadParamInput
+		}
+		query = query.Where("follow.created_at < ?", decodedCursor)
+	}
+
+	repository.PageVerify(&num)
+	var rows []model.FollowWithUser
+	if err := query.Order("follow.created_at DESC").Limit(int(num)).Find(&rows).Error; err != nil {
+		return nil, "", err
+	}
+	res = make([]domain.Follow, len(rows))
+	for i := range rows {
+		res[i] = rows[i].ToDomain()
+	}
+	if len(res) == int(num) {
+		nextCursor = repository.EncodeCursor(res[len(res)-1].CreatedAt)
+	}
+	return res, nextCursor, nil
+}
+
+func (m *FollowRepository) FollowingIDs(ctx context.Context, userID int64) ([]int64, error) {
+	var ids []int64
+	err := m.DB.WithContext(ctx).
+		Model(&model.Follow{}).
+		Where("follower_id = ?", userID).
+		Pluck("followee_id", &ids).
+		Error
+	return ids, err
+}
+
+func (m *FollowRepository) FollowerIDs(ctx context.Context, userID, afterID int64, limit int) ([]int64, error) {
+	var ids []int64
+	err := m.DB.WithContext(ctx).
+		Model(&model.Follow{}).
+		Where("followee_id = ? AND follower_id > ?", userID, afterID).
+		Order("follower_id").
+		Limit(limit).
+		Pluck("follower_id", &ids).
+		Error
+	return ids, err
+}
+
+func (m *FollowRepository) Stats(ctx context.Context, userID int64) (domain.FollowStats, error) {
+	var stats model.FollowStats
+	err := m.DB.WithContext(ctx).First(&stats, "user_id = ?", userID).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return domain.FollowStats{}, nil
+	}
+	if err != nil {
+		return domain.FollowStats{}, err
+	}
+	return domain.FollowStats{Followers: stats.Followers, Following: stats.Following}, nil
+}
+
+func (m *FollowRepository) FilterPopular(ctx context.Context, userIDs []int64, minFollowers int64) ([]int64, error) {
+	if len(userIDs) == 0 {
+		return nil, nil
+	}
+	var ids []int64
+	err := m.DB.WithContext(ctx).
+		Model(&model.FollowStats{}).
+		Where("user_id IN ? AND followers > ?", userIDs, minFollowers).
+		Pluck("user_id", &ids).
+		Error
+	return ids, err
+}
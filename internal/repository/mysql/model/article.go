@@ -1,47 +1,130 @@
 package model
 
 import (
+	"encoding/json"
 	"time"
 
 	"github.com/bxcodec/go-clean-arch/domain"
 )
 
 type Article struct {
-	ID        int64     `gorm:"primaryKey;autoIncrement"`
-	Title     string    `gorm:"type:varchar(45);not null"`
-	Content   string    `gorm:"type:longtext;not null"`
-	UserID    int64     `gorm:"column:user_id;default:0"`
-	Views     int64     `gorm:"default:0"`
-	UpdatedAt time.Time `gorm:"type:datetime"`
-	CreatedAt time.Time `gorm:"type:datetime"`
+	ID      int64  `gorm:"primaryKey;autoIncrement"`
+	Title   string `gorm:"type:varchar(45);not null"`
+	Content string `gorm:"type:longtext;not null"`
+	// rows written before formats existed hold HTML and no rendering
+	ContentFormat  string `gorm:"column:content_format;type:varchar(16);not null;default:html"`
+	ContentHTML    string `gorm:"column:content_html;type:longtext"`
+	Excerpt        string `gorm:"type:varchar(1024);not null;default:''"`
+	WordCount      int64  `gorm:"column:word_count;not null;default:0"`
+	ReadingMinutes int64  `gorm:"column:reading_minutes;not null;default:0"`
+	// TOC is NULL until the metadata of the article is computed
+	TOC []byte `gorm:"column:toc;type:json"`
+	// Fingerprint is NULL until the article is rendered and stays NULL for articles without words
+	Fingerprint *uint64 `gorm:"column:fingerprint;type:bigint unsigned"`
+	Status      string  `gorm:"type:varchar(16);not null;default:published"`
+	Visibility  string  `gorm:"type:varchar(16);not null;default:public"`
+	UserID      int64   `gorm:"column:user_id;default:0"`
+	Views       int64   `gorm:"default:0"`
+	// CommentCount is maintained by the comment repository, article updates never touch it
+	CommentCount int64     `gorm:"column:comment_count;default:0;->"`
+	UpdatedAt    time.Time `gorm:"type:datetime"`
+	CreatedAt    time.Time `gorm:"type:datetime"`
 }
 
 func (Article) TableName() string {
 	return "article"
 }
 
+type TOCEntry struct {
+	Level int    `json:"level"`
+	ID    string `json:"id"`
+	Text  string `json:"text"`
+}
+
+func tocToDomain(raw []byte) []domain.TOCEntry {
+	var entries []TOCEntry
+	if len(raw) == 0 || json.Unmarshal(raw, &entries) != nil {
+		return nil
+	}
+	res := make([]domain.TOCEntry, len(entries))
+	for i, e := range entries {
+		res[i] = domain.TOCEntry{Level: e.Level, ID: e.ID, Text: e.Text}
+	}
+	return res
+}
+
+// NewTOCFromDomain encodes a table of contents, nil stays NULL
+func NewTOCFromDomain(toc []domain.TOCEntry) []byte {
+	if toc == nil {
+		return nil
+	}
+	entries := make([]TOCEntry, len(toc))
+	for i, e := range toc {
+		entries[i] = TOCEntry{Level: e.Level, ID: e.ID, Text: e.Text}
+	}
+	raw, _ := json.Marshal(entries)
+	return raw
+}
+
 func (m *Article) ToDomain() domain.Article {
 	return domain.Article{
-		ID:        m.ID,
-		Title:     m.Title,
-		Content:   m.Content,
-		UpdatedAt: m.UpdatedAt,
-		CreatedAt: m.CreatedAt,
+		ID:             m.ID,
+		Title:          m.Title,
+		Content:        m.Content,
+		ContentFormat:  domain.ContentFormat(m.ContentFormat),
+		ContentHTML:    m.ContentHTML,
+		Excerpt:        m.Excerpt,
+		WordCount:      m.WordCount,
+		ReadingMinutes: m.ReadingMinutes,
+		TOC:            tocToDomain(m.TOC),
+		Fingerprint:    deref(m.Fingerprint),
+		Status:         domain.ArticleStatus(m.Status),
+		Visibility:     domain.ArticleVisibility(m.Visibility),
+		UpdatedAt:      m.UpdatedAt,
+		CreatedAt:      m.CreatedAt,
 		User: domain.User{
 			ID: m.UserID,
 		},
-		Views: m.Views,
+		Views:        m.Views,
+		CommentCount: m.CommentCount,
 	}
 }
 
 func NewArticleFromDomain(a *domain.Article) *Article {
 	return &Article{
-		ID:        a.ID,
-		Title:     a.Title,
-		Content:   a.Content,
-		UserID:    a.User.ID,
-		UpdatedAt: a.UpdatedAt,
-		CreatedAt: a.CreatedAt,
-		Views:     a.Views,
+		ID:             a.ID,
+		Title:          a.Title,
+		Content:        a.Content,
+		ContentFormat:  string(a.ContentFormat),
+		ContentHTML:    a.ContentHTML,
+		Excerpt:        a.Excerpt,
+		WordCount:      a.WordCount,
+		ReadingMinutes: a.ReadingMinutes,
+		TOC:            NewTOCFromDomain(a.TOC),
+		Fingerprint:    NewFingerprintFromDomain(a.Fingerprint),
+		Status:         string(a.Status),
+		Visibility:     string(a.Visibility),
+		UserID:         a.User.ID,
+		UpdatedAt:      a.UpdatedAt,
+		CreatedAt:      a.CreatedAt,
+		Views:          a.Views,
+	}
+}
+
+// NewFingerprintFromDomain keeps the zero fingerprint of articles without words NULL
+func NewFingerprintFromDomain(fingerprint uint64) *uint64 {
+	if fingerprint == 0 {
+		return nil
 	}
+	return &fingerprint
+}
+
+// SimilarArticle is an article with the distance of its fingerprint to another one
+type SimilarArticle struct {
+	Article  `gorm:"embedded"`
+	Distance int
+}
+
+func (m *SimilarArticle) ToDomain() domain.SimilarArticle {
+	return domain.SimilarArticle{Article: m.Article.ToDomain(), Distance: m.Distance}
 }
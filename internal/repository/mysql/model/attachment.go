@@ -0,0 +1,65 @@
+package model
+
+import (
+	"time"
+
+	"github.com/bxcodec/go-clean-arch/domain"
+)
+
+type Attachment struct {
+	ID     int64 `gorm:"primaryKey;autoIncrement"`
+	UserID int64 `gorm:"column:user_id;not null;index"`
+	// ArticleID is NULL until the attachment is linked
+	ArticleID    *int64 `gorm:"column:article_id;index"`
+	Key          string `gorm:"column:storage_key;type:varchar(255);not null;uniqueIndex"`
+	ThumbnailKey string `gorm:"column:thumbnail_key;type:varchar(255);not null;default:'';index"`
+	Filename     string `gorm:"type:varchar(255);not null"`
+	ContentType  string `gorm:"column:content_type;type:varchar(100);not null"`
+	Size         int64  `gorm:"not null"`
+	// ThumbnailSize is counted against the quota with Size
+	ThumbnailSize int64     `gorm:"column:thumbnail_size;not null;default:0"`
+	Width         int       `gorm:"not null;default:0"`
+	Height        int       `gorm:"not null;default:0"`
+	CreatedAt     time.Time `gorm:"type:datetime;index"`
+}
+
+func (Attachment) TableName() string {
+	return "attachment"
+}
+
+func (m *Attachment) ToDomain() domain.Attachment {
+	return domain.Attachment{
+		ID:            m.ID,
+		UserID:        m.UserID,
+		ArticleID:     deref(m.ArticleID),
+		Key:           m.Key,
+		ThumbnailKey:  m.ThumbnailKey,
+		Filename:      m.Filename,
+		ContentType:   m.ContentType,
+		Size:          m.Size,
+		ThumbnailSize: m.ThumbnailSize,
+		Width:         m.Width,
+		Height:        m.Height,
+		CreatedAt:     m.CreatedAt,
+	}
+}
+
+func NewAttachmentFromDomain(a *domain.Attachment) *Attachment {
+	m := &Attachment{
+		ID:            a.ID,
+		UserID:        a.UserID,
+		Key:           a.Key,
+		ThumbnailKey:  a.ThumbnailKey,
+		Filename:      a.Filename,
+		ContentType:   a.ContentType,
+		Size:          a.Size,
+		ThumbnailSize: a.ThumbnailSize,
+		Width:         a.Width,
+		Height:        a.Height,
+		CreatedAt:     a.CreatedAt,
+	}
+	if a.ArticleID != 0 {
+		m.ArticleID = &a.ArticleID
+	}
+	return m
+}
@@ -0,0 +1,122 @@
+package model
+
+import (
+	"time"
+
+	"github.com/bxcodec/go-clean-arch/domain"
+)
+
+type ReadingList struct {
+	ID        int64     `gorm:"primaryKey;autoIncrement"`
+	UserID    int64     `gorm:"column:user_id;not null;index"`
+	Name      string    `gorm:"type:varchar(100);not null"`
+	CreatedAt time.Time `gorm:"type:datetime"`
+	UpdatedAt time.Time `gorm:"type:datetime"`
+}
+
+func (ReadingList) TableName() string {
+	return "reading_list"
+}
+
+func (m *ReadingList) ToDomain() domain.ReadingList {
+	return domain.ReadingList{
+		ID:        m.ID,
+		UserID:    m.UserID,
+		Name:      m.Name,
+		CreatedAt: m.CreatedAt,
+		UpdatedAt: m.UpdatedAt,
+	}
+}
+
+func NewReadingListFromDomain(l *domain.ReadingList) *ReadingList {
+	return &ReadingList{
+		ID:        l.ID,
+		UserID:    l.UserID,
+		Name:      l.Name,
+		CreatedAt: l.CreatedAt,
+		UpdatedAt: l.UpdatedAt,
+	}
+}
+
+type Bookmark struct {
+	UserID    int64      `gorm:"column:user_id;primaryKey"`
+	ArticleID int64      `gorm:"column:article_id;primaryKey"`
+	ListID    int64      `gorm:"column:list_id;not null;default:0;index:idx_bookmark_list"`
+	Position  int64      `gorm:"not null;default:0;index:idx_bookmark_list"`
+	ReadAt    *time.Time `gorm:"type:datetime"`
+	CreatedAt time.Time  `gorm:"type:datetime"`
+	UpdatedAt time.Time  `gorm:"type:datetime"`
+}
+
+func (Bookmark) TableName() string {
+	return "bookmark"
+}
+
+func (m *Bookmark) ToDomain() domain.Bookmark {
+	b := domain.Bookmark{
+		UserID:    m.UserID,
+		ArticleID: m.ArticleID,
+		ListID:    m.ListID,
+		Position:  m.Position,
+		CreatedAt: m.CreatedAt,
+		UpdatedAt: m.UpdatedAt,
+	}
+	if m.ReadAt != nil {
+		b.ReadAt = *m.ReadAt
+	}
+	return b
+}
+
+func NewBookmarkFromDomain(b *domain.Bookmark) *Bookmark {
+	m := &Bookmark{
+		UserID:    b.UserID,
+		ArticleID: b.ArticleID,
+		ListID:    b.ListID,
+		Position:  b.Position,
+		CreatedAt: b.CreatedAt,
+		UpdatedAt: b.UpdatedAt,
+	}
+	if !b.ReadAt.IsZero() {
+		readAt := b.ReadAt
+		m.ReadAt = &readAt
+	}
+	return m
+}
+
+// BookmarkWithArticle is a bookmark joined with its article, the article
+// columns are empty when the article was deleted
+type BookmarkWithArticle struct {
+	Bookmark          `gorm:"embedded"`
+	ArticleRefID      *int64
+	ArticleTitle      *string
+	ArticleStatus     *string
+	ArticleVisibility *string
+	ArticleUserID     *int64
+	ArticleCreatedAt  *time.Time
+	ArticleUpdatedAt  *time.Time
+}
+
+func (m *BookmarkWithArticle) ToDomain() domain.Bookmark {
+	b := m.Bookmark.ToDomain()
+	if m.ArticleRefID == nil {
+		return b
+	}
+	b.Article = domain.Article{
+		ID:         *m.ArticleRefID,
+		Title:      deref(m.ArticleTitle),
+		Status:     domain.ArticleStatus(deref(m.ArticleStatus)),
+		Visibility: domain.ArticleVisibility(deref(m.ArticleVisibility)),
+		User:       domain.User{ID: deref(m.ArticleUserID)},
+		CreatedAt:  deref(m.ArticleCreatedAt),
+		UpdatedAt:  deref(m.ArticleUpdatedAt),
+	}
+	return b
+}
+
+func deref[T any](v *T) T {
+	var zero T
+	if v == nil {
+		return zero
+	}
+	return *v
+}
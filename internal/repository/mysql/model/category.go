@@ -0,0 +1,56 @@
+package model
+
+import (
+	"time"
+
+	"github.com/bxcodec/go-clean-arch/domain"
+)
+
+type Category struct {
+	ID        int64     `gorm:"primaryKey;autoIncrement"`
+	Name      string    `gorm:"type:varchar(45);not null"`
+	Tag       string    `gorm:"type:varchar(45);not null"`
+	CreatedAt time.Time `gorm:"type:datetime"`
+	UpdatedAt time.Time `gorm:"type:datetime"`
+}
+
+func (Category) TableName() string {
+	return "category"
+}
+
+func (m *Category) ToDomain() domain.Category {
+	return domain.Category{
+		ID:        m.ID,
+		Name:      m.Name,
+		Tag:       m.Tag,
+		CreatedAt: m.CreatedAt,
+		UpdatedAt: m.UpdatedAt,
+	}
+}
+
+func NewCategoryFromDomain(c *domain.Category) *Category {
+	return &Category{
+		ID:        c.ID,
+		Name:      c.Name,
+		Tag:       c.Tag,
+		CreatedAt: c.CreatedAt,
+		UpdatedAt: c.UpdatedAt,
+	}
+}
+
+// ArticleCategoryLink tags an article with a category
+type ArticleCategoryLink struct {
+	ID         int64 `gorm:"primaryKey;autoIncrement"`
+	ArticleID  int64 `gorm:"column:article_id;not null;uniqueIndex:composite"`
+	CategoryID int64 `gorm:"column:category_id;not null;uniqueIndex:composite"`
+}
+
+func (ArticleCategoryLink) TableName() string {
+	return "article_category"
+}
+
+// ArticleCategory is a category joined with one of its articles
+type ArticleCategory struct {
+	Category  `gorm:"embedded"`
+	ArticleID int64
+}
@@ -0,0 +1,56 @@
+package model
+
+import (
+	"time"
+
+	"github.com/bxcodec/go-clean-arch/domain"
+)
+
+type Comment struct {
+	ID        int64     `gorm:"primaryKey;autoIncrement"`
+	ArticleID int64     `gorm:"column:article_id;not null;index:idx_comment_article"`
+	ParentID  int64     `gorm:"column:parent_id;not null;default:0"`
+	RootID    int64     `gorm:"column:root_id;not null;default:0;index"`
+	UserID    int64     `gorm:"column:user_id;not null"`
+	Content   string    `gorm:"type:text;not null"`
+	Status    string    `gorm:"type:varchar(16);not null;index:idx_comment_article"`
+	Deleted   bool      `gorm:"not null;default:false"`
+	CreatedAt time.Time `gorm:"type:datetime;index:idx_comment_article"`
+	UpdatedAt time.Time `gorm:"type:datetime"`
+}
+
+func (Comment) TableName() string {
+	return "comment"
+}
+
+func (m *Comment) ToDomain() domain.Comment {
+	return domain.Comment{
+		ID:        m.ID,
+		ArticleID: m.ArticleID,
+		ParentID:  m.ParentID,
+		RootID:    m.RootID,
+		User: domain.User{
+			ID: m.UserID,
+		},
+		Content:   m.Content,
+		Status:    domain.CommentStatus(m.Status),
+		Deleted:   m.Deleted,
+		CreatedAt: m.CreatedAt,
+		UpdatedAt: m.UpdatedAt,
+	}
+}
+
+func NewCommentFromDomain(c *domain.Comment) *Comment {
+	return &Comment{
+		ID:        c.ID,
+		ArticleID: c.ArticleID,
+		ParentID:  c.ParentID,
+		RootID:    c.RootID,
+		UserID:    c.User.ID,
+		Content:   c.Content,
+		Status:    string(c.Status),
+		Deleted:   c.Deleted,
+		CreatedAt: c.CreatedAt,
+		UpdatedAt: c.UpdatedAt,
+	}
+}
@@ -0,0 +1,43 @@
+package model
+
+import (
+	"time"
+
+	"github.com/bxcodec/go-clean-arch/domain"
+)
+
+// ArticleContributor is a contributor besides the author, who is stored on the article itself
+type ArticleContributor struct {
+	ArticleID int64     `gorm:"column:article_id;primaryKey"`
+	UserID    int64     `gorm:"column:user_id;primaryKey;index"`
+	Role      string    `gorm:"type:varchar(16);not null"`
+	CreatedAt time.Time `gorm:"type:datetime"`
+}
+
+func (ArticleContributor) TableName() string {
+	return "article_contributor"
+}
+
+// ContributorWithUser is a contributor of an article joined with its user
+type ContributorWithUser struct {
+	ArticleID     int64
+	Role          string
+	UserID        int64
+	UserName      *string
+	UserUsername  *string
+	UserCreatedAt *time.Time
+	UserUpdatedAt *time.Time
+}
+
+func (m *ContributorWithUser) ToDomain() domain.Contributor {
+	return domain.Contributor{
+		Role: domain.ContributorRole(m.Role),
+		User: domain.User{
+			ID:        m.UserID,
+			Name:      deref(m.UserName),
+			Username:  deref(m.UserUsername),
+			CreatedAt: deref(m.UserCreatedAt),
+			UpdatedAt: deref(m.UserUpdatedAt),
+		},
+	}
+}
@@ -0,0 +1,65 @@
+package model
+
+import (
+	"time"
+
+	"github.com/bxcodec/go-clean-arch/domain"
+)
+
+type Follow struct {
+	FollowerID int64     `gorm:"column:follower_id;primaryKey;index:idx_follow_follower_created"`
+	FolloweeID int64     `gorm:"column:followee_id;primaryKey;index:idx_follow_followee"`
+	CreatedAt  time.Time `gorm:"type:datetime;index:idx_follow_followee;index:idx_follow_follower_created"`
+}
+
+func (Follow) TableName() string {
+	return "follow"
+}
+
+func (m *Follow) ToDomain() domain.Follow {
+	return domain.Follow{
+		FollowerID: m.FollowerID,
+		FolloweeID: m.FolloweeID,
+		CreatedAt:  m.CreatedAt,
+	}
+}
+
+func NewFollowFromDomain(f *domain.Follow) *Follow {
+	return &Follow{
+		FollowerID: f.FollowerID,
+		FolloweeID: f.FolloweeID,
+		CreatedAt:  f.CreatedAt,
+	}
+}
+
+// FollowStats keeps the follower and following counts of a user so that the
+// feed can tell popular authors apart without counting the follow table
+type FollowStats struct {
+	UserID    int64 `gorm:"column:user_id;primaryKey"`
+	Followers int64 `gorm:"not null;default:0"`
+	Following int64 `gorm:"not null;default:0"`
+}
+
+func (FollowStats) TableName() string {
+	return "follow_stats"
+}
+
+// FollowWithUser is a follow edge joined with the user on the other side
+type FollowWithUser struct {
+	Follow        `gorm:"embedded"`
+	UserRefID     int64
+	UserName      string
+	UserUsername  string
+	UserCreatedAt time.Time
+}
+
+func (m *FollowWithUser) ToDomain() domain.Follow {
+	f := m.Follow.ToDomain()
+	f.User = domain.User{
+		ID:        m.UserRefID,
+		Name:      m.UserName,
+		Username:  m.UserUsername,
+		CreatedAt: m.UserCreatedAt,
+	}
+	return f
+}
@@ -0,0 +1,11 @@
+package model
+
+// LeaderFence remembers the highest fencing token a leader-only write was made with
+type LeaderFence struct {
+	Name  string `gorm:"primaryKey;type:varchar(64)"`
+	Token int64  `gorm:"not null;default:0"`
+}
+
+func (LeaderFence) TableName() string {
+	return "leader_fence"
+}
@@ -0,0 +1,107 @@
+package model
+
+import (
+	"time"
+
+	"github.com/bxcodec/go-clean-arch/domain"
+)
+
+type Notification struct {
+	ID       int64  `gorm:"primaryKey;autoIncrement"`
+	UserID   int64  `gorm:"column:user_id;not null;uniqueIndex:idx_notification_unread;index:idx_notification_user_updated"`
+	Type     string `gorm:"type:varchar(20);not null"`
+	GroupKey string `gorm:"type:varchar(64);not null"`
+	// UnreadKey is the group key while the notification is unread, so that a
+	// group has at most one unread notification
+	UnreadKey  *string    `gorm:"type:varchar(64);uniqueIndex:idx_notification_unread"`
+	ArticleID  int64      `gorm:"column:article_id;not null;default:0"`
+	CommentID  int64      `gorm:"column:comment_id;not null;default:0"`
+	ActorID    int64      `gorm:"column:actor_id;not null"`
+	ActorCount int64      `gorm:"not null;default:0"`
+	ReadAt     *time.Time `gorm:"type:datetime;index"`
+	CreatedAt  time.Time  `gorm:"type:datetime;index"`
+	UpdatedAt  time.Time  `gorm:"type:datetime;index:idx_notification_user_updated"`
+}
+
+func (Notification) TableName() string {
+	return "notification"
+}
+
+func (m *Notification) ToDomain() domain.Notification {
+	n := domain.Notification{
+		ID:         m.ID,
+		UserID:     m.UserID,
+		Type:       domain.NotificationType(m.Type),
+		GroupKey:   m.GroupKey,
+		ArticleID:  m.ArticleID,
+		CommentID:  m.CommentID,
+		Actor:      domain.User{ID: m.ActorID},
+		ActorCount: m.ActorCount,
+		CreatedAt:  m.CreatedAt,
+		UpdatedAt:  m.UpdatedAt,
+	}
+	if m.ReadAt != nil {
+		n.ReadAt = *m.ReadAt
+	}
+	return n
+}
+
+func NewNotificationFromDomain(n *domain.Notification) *Notification {
+	m := &Notification{
+		ID:         n.ID,
+		UserID:     n.UserID,
+		Type:       string(n.Type),
+		GroupKey:   n.GroupKey,
+		ArticleID:  n.ArticleID,
+		CommentID:  n.CommentID,
+		ActorID:    n.Actor.ID,
+		ActorCount: n.ActorCount,
+		CreatedAt:  n.CreatedAt,
+		UpdatedAt:  n.UpdatedAt,
+	}
+	if n.Read() {
+		readAt := n.ReadAt
+		m.ReadAt = &readAt
+	} else {
+		key := n.GroupKey
+		m.UnreadKey = &key
+	}
+	return m
+}
+
+// NotificationWithDetails is a notification joined with its latest actor and its article
+type NotificationWithDetails struct {
+	Notification  `gorm:"embedded"`
+	ActorName     *string
+	ActorUsername *string
+	ArticleTitle  *string
+}
+
+func (m *NotificationWithDetails) ToDomain() domain.Notification {
+	n := m.Notification.ToDomain()
+	n.Actor.Name = deref(m.ActorName)
+	n.Actor.Username = deref(m.ActorUsername)
+	n.ArticleTitle = deref(m.ArticleTitle)
+	return n
+}
+
+// NotificationActor records the distinct actors of an aggregated notification
+type NotificationActor struct {
+	NotificationID int64     `gorm:"column:notification_id;primaryKey"`
+	ActorID        int64     `gorm:"column:actor_id;primaryKey"`
+	CreatedAt      time.Time `gorm:"type:datetime"`
+}
+
+func (NotificationActor) TableName() string {
+	return "notification_actor"
+}
+
+type NotificationPreference struct {
+	UserID  int64  `gorm:"column:user_id;primaryKey"`
+	Type    string `gorm:"type:varchar(20);primaryKey"`
+	Enabled bool   `gorm:"not null"`
+}
+
+func (NotificationPreference) TableName() string {
+	return "notification_preference"
+}
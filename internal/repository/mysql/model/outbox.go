@@ -0,0 +1,63 @@
+package model
+
+import (
+	"encoding/json"
+	"strings"
+	"time"
+
+	"github.com/bxcodec/go-clean-arch/domain"
+)
+
+type OutboxEvent struct {
+	ID          int64  `gorm:"primaryKey;autoIncrement"`
+	AggregateID int64  `gorm:"column:aggregate_id;not null;index"`
+	EventType   string `gorm:"type:varchar(32);not null"`
+	Payload     string `gorm:"type:longtext;not null"`
+	Attempts    int    `gorm:"default:0"`
+	LastError   string `gorm:"type:text"`
+	// DeliveredTo is the comma separated names of the sinks that accepted the event
+	DeliveredTo string `gorm:"type:varchar(255)"`
+	// PreviousTags is the comma separated tags of the article before the change
+	PreviousTags string     `gorm:"type:text"`
+	RetryAt      *time.Time `gorm:"type:datetime"`
+	CreatedAt    time.Time  `gorm:"type:datetime"`
+	PublishedAt  *time.Time `gorm:"type:datetime;index"`
+	DeadAt       *time.Time `gorm:"type:datetime"`
+}
+
+func (OutboxEvent) TableName() string {
+	return "article_outbox"
+}
+
+func (m *OutboxEvent) ToDomain() (domain.ArticleEvent, error) {
+	event := domain.ArticleEvent{
+		ID:         m.ID,
+		Type:       domain.ArticleEventType(m.EventType),
+		ArticleID:  m.AggregateID,
+		OccurredAt: m.CreatedAt,
+		Attempts:   m.Attempts,
+		RetryAt:    deref(m.RetryAt),
+	}
+	if m.DeliveredTo != "" {
+		event.Delivered = strings.Split(m.DeliveredTo, ",")
+	}
+	if m.PreviousTags != "" {
+		event.PreviousTags = strings.Split(m.PreviousTags, ",")
+	}
+	err := json.Unmarshal([]byte(m.Payload), &event.Article)
+	return event, err
+}
+
+func NewOutboxEvent(eventType domain.ArticleEventType, a *domain.Article, previousTags []string, at time.Time) (*OutboxEvent, error) {
+	payload, err := json.Marshal(a)
+	if err != nil {
+		return nil, err
+	}
+	return &OutboxEvent{
+		AggregateID:  a.ID,
+		EventType:    string(eventType),
+		Payload:      string(payload),
+		PreviousTags: strings.Join(previousTags, ","),
+		CreatedAt:    at,
+	}, nil
+}
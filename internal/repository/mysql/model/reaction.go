@@ -0,0 +1,48 @@
+package model
+
+import (
+	"time"
+
+	"github.com/bxcodec/go-clean-arch/domain"
+)
+
+type Reaction struct {
+	ArticleID int64     `gorm:"column:article_id;primaryKey"`
+	UserID    int64     `gorm:"column:user_id;primaryKey"`
+	Kind      string    `gorm:"type:varchar(32);primaryKey"`
+	CreatedAt time.Time `gorm:"type:datetime"`
+}
+
+func (Reaction) TableName() string {
+	return "article_reaction"
+}
+
+func NewReactionFromDomain(r *domain.Reaction) *Reaction {
+	return &Reaction{
+		ArticleID: r.ArticleID,
+		UserID:    r.UserID,
+		Kind:      r.Kind,
+		CreatedAt: r.CreatedAt,
+	}
+}
+
+// ReactionCount is the synced number of reactions of one kind to an article
+type ReactionCount struct {
+	ArticleID int64  `gorm:"column:article_id;primaryKey"`
+	Kind      string `gorm:"type:varchar(32);primaryKey"`
+	Count     int64  `gorm:"not null;default:0"`
+}
+
+func (ReactionCount) TableName() string {
+	return "article_reaction_count"
+}
+
+// ReactionBatch records a reaction batch that has already been applied to the counts
+type ReactionBatch struct {
+	ID        string    `gorm:"primaryKey;type:varchar(64)"`
+	AppliedAt time.Time `gorm:"type:datetime;index"`
+}
+
+func (ReactionBatch) TableName() string {
+	return "article_reaction_batch"
+}
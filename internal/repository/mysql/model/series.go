@@ -0,0 +1,73 @@
+package model
+
+import (
+	"time"
+
+	"github.com/bxcodec/go-clean-arch/domain"
+)
+
+type Series struct {
+	ID          int64     `gorm:"primaryKey;autoIncrement"`
+	UserID      int64     `gorm:"column:user_id;not null;index"`
+	Title       string    `gorm:"type:varchar(100);not null"`
+	Description string    `gorm:"type:text;not null"`
+	CoverURL    string    `gorm:"column:cover_url;type:varchar(255);not null;default:''"`
+	CoverAlt    string    `gorm:"column:cover_alt;type:varchar(255);not null;default:''"`
+	CreatedAt   time.Time `gorm:"type:datetime;index"`
+	UpdatedAt   time.Time `gorm:"type:datetime"`
+}
+
+func (Series) TableName() string {
+	return "series"
+}
+
+func (m *Series) ToDomain() domain.Series {
+	return domain.Series{
+		ID:          m.ID,
+		User:        domain.User{ID: m.UserID},
+		Title:       m.Title,
+		Description: m.Description,
+		CoverURL:    m.CoverURL,
+		CoverAlt:    m.CoverAlt,
+		CreatedAt:   m.CreatedAt,
+		UpdatedAt:   m.UpdatedAt,
+	}
+}
+
+func NewSeriesFromDomain(s *domain.Series) *Series {
+	return &Series{
+		ID:          s.ID,
+		UserID:      s.User.ID,
+		Title:       s.Title,
+		Description: s.Description,
+		CoverURL:    s.CoverURL,
+		CoverAlt:    s.CoverAlt,
+		CreatedAt:   s.CreatedAt,
+		UpdatedAt:   s.UpdatedAt,
+	}
+}
+
+// SeriesWithStats is a series with the totals of its published articles
+type SeriesWithStats struct {
+	Series       `gorm:"embedded"`
+	ArticleCount int64
+	WordCount    int64
+}
+
+func (m *SeriesWithStats) ToDomain() domain.Series {
+	s := m.Series.ToDomain()
+	s.ArticleCount = m.ArticleCount
+	s.WordCount = m.WordCount
+	return s
+}
+
+// SeriesArticle places an article in a series, an article is in at most one series
+type SeriesArticle struct {
+	SeriesID  int64 `gorm:"column:series_id;primaryKey"`
+	ArticleID int64 `gorm:"column:article_id;primaryKey;uniqueIndex"`
+	Position  int64 `gorm:"not null"`
+}
+
+func (SeriesArticle) TableName() string {
+	return "series_article"
+}
@@ -0,0 +1,20 @@
+package model
+
+import (
+	"time"
+
+	"github.com/bxcodec/go-clean-arch/domain"
+)
+
+type SitemapEntry struct {
+	ID      int64
+	LastMod *time.Time
+}
+
+func SitemapEntriesToDomain(rows []SitemapEntry) []domain.SitemapEntry {
+	res := make([]domain.SitemapEntry, len(rows))
+	for i, row := range rows {
+		res[i] = domain.SitemapEntry{ID: row.ID, LastMod: deref(row.LastMod)}
+	}
+	return res
+}
@@ -0,0 +1,13 @@
+package model
+
+import "time"
+
+// ViewBatch records a view batch that has already been applied to the article table
+type ViewBatch struct {
+	ID        string    `gorm:"primaryKey;type:varchar(64)"`
+	AppliedAt time.Time `gorm:"type:datetime;index"`
+}
+
+func (ViewBatch) TableName() string {
+	return "article_view_batch"
+}
@@ -0,0 +1,141 @@
+package model
+
+import (
+	"strings"
+	"time"
+
+	"github.com/bxcodec/go-clean-arch/domain"
+)
+
+type WebhookSubscription struct {
+	ID        int64     `gorm:"primaryKey;autoIncrement"`
+	UserID    int64     `gorm:"column:user_id;not null;index"`
+	URL       string    `gorm:"column:url;type:varchar(2048);not null"`
+	Secret    string    `gorm:"type:varchar(128);not null"`
+	Events    string    `gorm:"type:varchar(255);not null;default:''"`
+	Active    bool      `gorm:"not null;default:true"`
+	CreatedAt time.Time `gorm:"type:datetime"`
+	UpdatedAt time.Time `gorm:"type:datetime"`
+}
+
+func (WebhookSubscription) TableName() string {
+	return "webhook_subscription"
+}
+
+func (m *WebhookSubscription) ToDomain() domain.WebhookSubscription {
+	var events []domain.ArticleEventType
+	if m.Events != "" {
+		for _, e := range strings.Split(m.Events, ",") {
+			events = append(events, domain.ArticleEventType(e))
+		}
+	}
+	return domain.WebhookSubscription{
+		ID:        m.ID,
+		UserID:    m.UserID,
+		URL:       m.URL,
+		Secret:    m.Secret,
+		Events:    events,
+		Active:    m.Active,
+		CreatedAt: m.CreatedAt,
+		UpdatedAt: m.UpdatedAt,
+	}
+}
+
+func NewWebhookSubscriptionFromDomain(s *domain.WebhookSubscription) *WebhookSubscription {
+	events := make([]string, len(s.Events))
+	for i, e := range s.Events {
+		events[i] = string(e)
+	}
+	return &WebhookSubscription{
+		ID:        s.ID,
+		UserID:    s.UserID,
+		URL:       s.URL,
+		Secret:    s.Secret,
+		Events:    strings.Join(events, ","),
+		Active:    s.Active,
+		CreatedAt: s.CreatedAt,
+		UpdatedAt: s.UpdatedAt,
+	}
+}
+
+type WebhookDelivery struct {
+	ID             string    `gorm:"primaryKey;type:varchar(32)"`
+	SubscriptionID int64     `gorm:"column:subscription_id;not null;uniqueIndex:idx_webhook_delivery_event"`
+	EventID        int64     `gorm:"column:event_id;not null;uniqueIndex:idx_webhook_delivery_event"`
+	EventType      string    `gorm:"type:varchar(32);not null"`
+	Payload        string    `gorm:"type:longtext;not null"`
+	Status         string    `gorm:"type:varchar(16);not null;index:idx_webhook_delivery_due"`
+	Attempts       int       `gorm:"not null;default:0"`
+	NextAttemptAt  time.Time `gorm:"type:datetime;index:idx_webhook_delivery_due"`
+	CreatedAt      time.Time `gorm:"type:datetime"`
+	UpdatedAt      time.Time `gorm:"type:datetime"`
+}
+
+func (WebhookDelivery) TableName() string {
+	return "webhook_delivery"
+}
+
+func (m *WebhookDelivery) ToDomain() domain.WebhookDelivery {
+	return domain.WebhookDelivery{
+		ID:             m.ID,
+		SubscriptionID: m.SubscriptionID,
+		EventID:        m.EventID,
+		EventType:      domain.ArticleEventType(m.EventType),
+		Payload:        m.Payload,
+		Status:         domain.WebhookDeliveryStatus(m.Status),
+		Attempts:       m.Attempts,
+		NextAttemptAt:  m.NextAttemptAt,
+		CreatedAt:      m.CreatedAt,
+		UpdatedAt:      m.UpdatedAt,
+	}
+}
+
+func NewWebhookDeliveryFromDomain(d *domain.WebhookDelivery) *WebhookDelivery {
+	return &WebhookDelivery{
+		ID:             d.ID,
+		SubscriptionID: d.SubscriptionID,
+		EventID:        d.EventID,
+		EventType:      string(d.EventType),
+		Payload:        d.Payload,
+		Status:         string(d.Status),
+		Attempts:       d.Attempts,
+		NextAttemptAt:  d.NextAttemptAt,
+		CreatedAt:      d.CreatedAt,
+		UpdatedAt:      d.UpdatedAt,
+	}
+}
+
+type WebhookAttempt struct {
+	ID          int64     `gorm:"primaryKey;autoIncrement"`
+	DeliveryID  string    `gorm:"column:delivery_id;type:varchar(32);not null;index"`
+	StatusCode  int       `gorm:"not null;default:0"`
+	Error       string    `gorm:"type:text"`
+	DurationMs  int64     `gorm:"column:duration_ms;not null;default:0"`
+	AttemptedAt time.Time `gorm:"type:datetime"`
+}
+
+func (WebhookAttempt) TableName() string {
+	return "webhook_attempt"
+}
+
+func (m *WebhookAttempt) ToDomain() domain.WebhookAttempt {
+	return domain.WebhookAttempt{
+		ID:          m.ID,
+		DeliveryID:  m.DeliveryID,
+		StatusCode:  m.StatusCode,
+		Error:       m.Error,
+		Duration:    time.Duration(m.DurationMs) * time.Millisecond,
+		AttemptedAt: m.AttemptedAt,
+	}
+}
+
+func NewWebhookAttemptFromDomain(a *domain.WebhookAttempt) *WebhookAttempt {
+	return &WebhookAttempt{
+		ID:          a.ID,
+		DeliveryID:  a.DeliveryID,
+		StatusCode:  a.StatusCode,
+		Error:       a.Error,
+		DurationMs:  a.Duration.Milliseconds(),
+		AttemptedAt: a.AttemptedAt,
+	}
+}
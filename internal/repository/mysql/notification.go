@@ -0,0 +1,227 @@
+package mysql
+
+import (
+	"context"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/bxcodec/go-clean-arch/domain"
+	"github.com/bxcodec/go-clean-arch/internal/repository"
+	"github.com/bxcodec/go-clean-arch/internal/repository/mysql/model"
+)
+
+const notificationColumns = "notification.*, user.name AS actor_name, user.username AS actor_username, article.title AS article_title"
+
+type NotificationRepository struct {
+	DB *gorm.DB
+}
+
+// NewNotificationRepository will create an object that represent the domain.NotificationRepository interface
+func NewNotificationRepository(db *gorm.DB) *NotificationRepository {
+	return &NotificationRepository{db}
+}
+
+func (m *NotificationRepository) Record(ctx context.Context, n *domain.Notification, actorID int64) error {
+	return m.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		row := model.NewNotificationFromDomain(n)
+		row.ActorID = actorID
+		row.ActorCount = 0
+		if err := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(row).Error; err != nil {
+			return err
+		}
+		if row.ID == 0 {
+			// the group already has an unread notification
+			err := tx.Model(&model.Notification{}).
+				Where("user_id = ? AND unread_key = ?", n.UserID, n.GroupKey).
+				Select("id").
+				Scan(&row.ID).
+				Error
+			if err != nil {
+				return err
+			}
+		}
+
+		result := tx.Clauses(clause.Insert{Modifier: "IGNORE"}).Create(&model.NotificationActor{
+			NotificationID: row.ID,
+			ActorID:        actorID,
+			CreatedAt:      n.UpdatedAt,
+		})
+		if result.Error != nil {
+			return result.Error
+		}
+		n.ID = row.ID
+		if result.RowsAffected == 0 {
+			// the actor is already counted
+			return nil
+		}
+		return tx.Model(&model.Notification{}).
+			Where("id = ?", row.ID).
+			Updates(map[string]any{
+				"actor_id":    actorID,
+				"actor_count": gorm.Expr("actor_count + 1"),
+				"updated_at":  n.UpdatedAt,
+			}).
+			Error
+	})
+}
+
+func (m *NotificationRepository) RecordMany(ctx context.Context, notifications []domain.Notification) error {
+	if len(notifications) == 0 {
+		return nil
+	}
+	rows := make([]*model.Notification, len(notifications))
+	for i := range notifications {
+		rows[i] = model.NewNotificationFromDomain(&notifications[i])
+	}
+	return m.DB.WithContext(ctx).Clauses(clause.Insert{Modifier: "IGNORE"}).Create(rows).Error
+}
+
+func (m *NotificationRepository) Fetch(ctx context.Context, userID int64, unreadOnly bool, cursor string, num int64) (res []domain.Notification, nextCursor string, err error) {
+	query := m.DB.WithContext(ctx).
+		Table("notification").
+		Select(notificationColumns).
+		Joins("LEFT JOIN user ON user.id = notification.actor_id").
+		Joins("LEFT JOIN article ON article.id = notification.article_id").
+		Where("notification.user_id = ?", userID)
+	if unreadOnly {
+		query = query.Where("notification.read_at IS NULL")
+	}
+	if cursor != "" {
+		// aggregation bumps updated_at, so ties are broken on the id
+		updatedAt, id, err := repository.DecodeKeyCursor(cursor)
+		if err != nil {
+			return nil, "", domain.ErrBadParamInput
+		}
+		query = query.Where("notification.updated_at < ? OR (notification.updated_at = ? AND notification.id < ?)", updatedAt, updatedAt, id)
+	}
+
+	repository.PageVerify(&num)
+	var rows []model.NotificationWithDetails
+	err = query.Order("notification.updated_at DESC, notification.id DESC").Limit(int(num)).Find(&rows).Error
+	if err != nil {
+		return nil, "", err
+	}
+	res = make([]domain.Notification, len(rows))
+	for i := range rows {
+		res[i] = rows[i].ToDomain()
+	}
+	if len(res) == int(num) {
+		last := res[len(res)-1]
+		nextCursor = repository.EncodeKeyCursor(last.UpdatedAt, last.ID)
+	}
+	return res, nextCursor, nil
+}
+
+func (m *NotificationRepository) CountUnread(ctx context.Context, userID int64) (int64, error) {
+	var count int64
+	err := m.DB.WithContext(ctx).
+		Model(&model.Notification{}).
+		Where("user_id = ? AND read_at IS NULL", userID).
+		Count(&count).
+		Error
+	return count, err
+}
+
+func (m *NotificationRepository) MarkRead(ctx context.Context, userID, id int64, readAt time.Time) error {
+	result := m.unread(ctx, userID).Where("id = ?", id).Updates(readUpdates(readAt))
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected > 0 {
+		return nil
+	}
+	// marking a read notification again is a no-op
+	var count int64
+	err := m.DB.WithContext(ctx).
+		Model(&model.Notification{}).
+		Where("id = ? AND user_id = ?", id, userID).
+		Count(&count).
+		Error
+	if err != nil {
+		return err
+	}
+	if count == 0 {
+		return domain.ErrNotFound
+	}
+	return nil
+}
+
+func (m *NotificationRepository) MarkAllRead(ctx context.Context, userID int64, readAt time.Time) (int64, error) {
+	result := m.unread(ctx, userID).Updates(readUpdates(readAt))
+	return result.RowsAffected, result.Error
+}
+
+func (m *NotificationRepository) unread(ctx context.Context, userID int64) *gorm.DB {
+	return m.DB.WithContext(ctx).
+		Model(&model.Notification{}).
+		Where("user_id = ? AND read_at IS NULL", userID)
+}
+
+func readUpdates(readAt time.Time) map[string]any {
+	// clearing the unread key lets the next activity of the group start a new notification
+	return map[string]any{"read_at": readAt, "unread_key": nil}
+}
+
+func (m *NotificationRepository) Purge(ctx context.Context, readBefore, before time.Time, limit int) (int64, error) {
+	var ids []int64
+	err := m.DB.WithContext(ctx).
+		Model(&model.Notification{}).
+		Where("read_at < ? OR created_at < ?", readBefore, before).
+		Limit(limit).
+		Pluck("id", &ids).
+		Error
+	if err != nil || len(ids) == 0 {
+		return 0, err
+	}
+
+	var purged int64
+	err = m.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("notification_id IN ?", ids).Delete(&model.NotificationActor{}).Error; err != nil {
+			return err
+		}
+		result := tx.Where("id IN ?", ids).Delete(&model.Notification{})
+		purged = result.RowsAffected
+		return result.Error
+	})
+	return purged, err
+}
+
+func (m *NotificationRepository) Preferences(ctx context.Context, userID int64) (map[domain.NotificationType]bool, error) {
+	var rows []model.NotificationPreference
+	if err := m.DB.WithContext(ctx).Where("user_id = ?", userID).Find(&rows).Error; err != nil {
+		return nil, err
+	}
+	res := make(map[domain.NotificationType]bool, len(rows))
+	for _, row := range rows {
+		res[domain.NotificationType(row.Type)] = row.Enabled
+	}
+	return res, nil
+}
+
+func (m *NotificationRepository) SetPreferences(ctx context.Context, userID int64, prefs map[domain.NotificationType]bool) error {
+	if len(prefs) == 0 {
+		return nil
+	}
+	rows := make([]model.NotificationPreference, 0, len(prefs))
+	for t, enabled := range prefs {
+		rows = append(rows, model.NotificationPreference{UserID: userID, Type: string(t), Enabled: enabled})
+	}
+	return m.DB.WithContext(ctx).Clauses(clause.OnConflict{
+		DoUpdates: clause.AssignmentColumns([]string{"enabled"}),
+	}).Create(&rows).Error
+}
+
+func (m *NotificationRepository) Muted(ctx context.Context, t domain.NotificationType, userIDs []int64) ([]int64, error) {
+	if len(userIDs) == 0 {
+		return nil, nil
+	}
+	var ids []int64
+	err := m.DB.WithContext(ctx).
+		Model(&model.NotificationPreference{}).
+		Where("type = ? AND enabled = ? AND user_id IN ?", string(t), false, userIDs).
+		Pluck("user_id", &ids).
+		Error
+	return ids, err
+}
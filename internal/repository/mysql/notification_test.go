@@ -0,0 +1,144 @@
+package mysql_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	gormMysql "gorm.io/driver/mysql"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+
+	"github.com/bxcodec/go-clean-arch/domain"
+	"github.com/bxcodec/go-clean-arch/internal/repository"
+	mysqlRepo "github.com/bxcodec/go-clean-arch/internal/repository/mysql"
+)
+
+func newMockDB(t *testing.T) (*gorm.DB, sqlmock.Sqlmock) {
+	sqlDB, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	t.Cleanup(func() { sqlDB.Close() })
+	db, err := gorm.Open(gormMysql.New(gormMysql.Config{Conn: sqlDB, SkipInitializeWithVersion: true}), &gorm.Config{
+		Logger: logger.Discard,
+	})
+	require.NoError(t, err)
+	return db, mock
+}
+
+func newCommentNotification(at time.Time) *domain.Notification {
+	return &domain.Notification{
+		UserID:    1,
+		Type:      domain.NotificationType("comment"),
+		GroupKey:  "comment:7",
+		ArticleID: 7,
+		Actor:     domain.User{ID: 2},
+		CreatedAt: at,
+		UpdatedAt: at,
+	}
+}
+
+func TestRecordStartsNotification(t *testing.T) {
+	db, mock := newMockDB(t)
+	repo := mysqlRepo.NewNotificationRepository(db)
+	now := time.Now()
+
+	mock.ExpectBegin()
+	mock.ExpectExec("INSERT INTO `notification` .+ ON DUPLICATE KEY UPDATE").WillReturnResult(sqlmock.NewResult(5, 1))
+	mock.ExpectExec("INSERT IGNORE INTO `notification_actor`").
+		WithArgs(int64(5), int64(2), now).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("UPDATE `notification` SET `actor_count`=actor_count \\+ 1,`actor_id`=\\?,`updated_at`=\\? WHERE id = \\?").
+		WithArgs(int64(2), now, int64(5)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	n := newCommentNotification(now)
+	require.NoError(t, repo.Record(context.Background(), n, 2))
+	assert.Equal(t, int64(5), n.ID)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRecordAggregatesIntoUnreadNotification(t *testing.T) {
+	db, mock := newMockDB(t)
+	repo := mysqlRepo.NewNotificationRepository(db)
+	now := time.Now()
+
+	// the group already has an unread notification, the new actor joins it
+	// and moves it to the top
+	mock.ExpectBegin()
+	mock.ExpectExec("INSERT INTO `notification` .+ ON DUPLICATE KEY UPDATE").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery("SELECT `id` FROM `notification` WHERE user_id = \\? AND unread_key = \\?").
+		WithArgs(int64(1), "comment:7").
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(5))
+	mock.ExpectExec("INSERT IGNORE INTO `notification_actor`").
+		WithArgs(int64(5), int64(3), now).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("UPDATE `notification` SET `actor_count`=actor_count \\+ 1,`actor_id`=\\?,`updated_at`=\\? WHERE id = \\?").
+		WithArgs(int64(3), now, int64(5)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	n := newCommentNotification(now)
+	n.Actor.ID = 3
+	require.NoError(t, repo.Record(context.Background(), n, 3))
+	assert.Equal(t, int64(5), n.ID)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRecordCountsActorOnce(t *testing.T) {
+	db, mock := newMockDB(t)
+	repo := mysqlRepo.NewNotificationRepository(db)
+	now := time.Now()
+
+	// the actor is already part of the unread notification, nothing changes
+	mock.ExpectBegin()
+	mock.ExpectExec("INSERT INTO `notification` .+ ON DUPLICATE KEY UPDATE").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery("SELECT `id` FROM `notification`").
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(5))
+	mock.ExpectExec("INSERT IGNORE INTO `notification_actor`").
+		WithArgs(int64(5), int64(2), now).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectCommit()
+
+	n := newCommentNotification(now)
+	require.NoError(t, repo.Record(context.Background(), n, 2))
+	assert.Equal(t, int64(5), n.ID)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestFetchNotificationsPagesOnUpdatedAtAndID(t *testing.T) {
+	db, mock := newMockDB(t)
+	repo := mysqlRepo.NewNotificationRepository(db)
+	updated := time.Date(2026, 3, 1, 10, 0, 0, 0, time.UTC)
+	columns := []string{"id", "user_id", "type", "group_key", "actor_id", "actor_count", "created_at", "updated_at"}
+
+	// both notifications were bumped in the same second
+	mock.ExpectQuery("WHERE notification.user_id = \\? ORDER BY notification.updated_at DESC, notification.id DESC LIMIT \\?").
+		WithArgs(int64(1), 2).
+		WillReturnRows(sqlmock.NewRows(columns).
+			AddRow(9, 1, "comment", "comment:7", 2, 3, updated, updated).
+			AddRow(4, 1, "comment", "comment:8", 2, 1, updated, updated))
+
+	res, cursor, err := repo.Fetch(context.Background(), 1, false, "", 2)
+	require.NoError(t, err)
+	require.Len(t, res, 2)
+	assert.Equal(t, repository.EncodeKeyCursor(updated, 4), cursor)
+
+	mock.ExpectQuery("WHERE notification.user_id = \\? AND \\(notification.updated_at < \\? OR \\(notification.updated_at = \\? AND notification.id < \\?\\)\\) ORDER BY").
+		WithArgs(int64(1), updated, updated, int64(4), 2).
+		WillReturnRows(sqlmock.NewRows(columns).
+			AddRow(3, 1, "comment", "comment:9", 2, 1, updated, updated))
+
+	res, cursor, err = repo.Fetch(context.Background(), 1, false, cursor, 2)
+	require.NoError(t, err)
+	require.Len(t, res, 1)
+	assert.Equal(t, int64(3), res[0].ID)
+	assert.Empty(t, cursor)
+
+	_, _, err = repo.Fetch(context.Background(), 1, false, repository.EncodeCursor(updated), 2)
+	assert.ErrorIs(t, err, domain.ErrBadParamInput)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,104 @@
+package mysql
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/bxcodec/go-clean-arch/domain"
+	"github.com/bxcodec/go-clean-arch/internal/repository/mysql/model"
+)
+
+type OutboxRepository struct {
+	DB *gorm.DB
+}
+
+// NewOutboxRepository will create an object that represent the domain.OutboxRepository interface
+func NewOutboxRepository(db *gorm.DB) *OutboxRepository {
+	return &OutboxRepository{db}
+}
+
+// waitingForRetry matches the events queued behind an earlier event of their
+// article whose retry is not due
+const waitingForRetry = "EXISTS (SELECT 1 FROM article_outbox AS head WHERE head.aggregate_id = article_outbox.aggregate_id " +
+	"AND head.id < article_outbox.id AND head.published_at IS NULL AND head.dead_at IS NULL AND head.retry_at > ?)"
+
+func (m *OutboxRepository) FetchPending(ctx context.Context, limit int) ([]domain.ArticleEvent, error) {
+	var rows []model.OutboxEvent
+	now := time.Now()
+	err := m.DB.WithContext(ctx).
+		Where("published_at IS NULL AND dead_at IS NULL").
+		Where("retry_at IS NULL OR retry_at <= ?", now).
+		Where("NOT "+waitingForRetry, now).
+		Order("id").
+		Limit(limit).
+		Find(&rows).
+		Error
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([]domain.ArticleEvent, 0, len(rows))
+	for i := range rows {
+		event, err := rows[i].ToDomain()
+		if err != nil {
+			return nil, err
+		}
+		res = append(res, event)
+	}
+	return res, nil
+}
+
+func (m *OutboxRepository) MarkPublished(ctx context.Context, ids []int64, fencingToken int64) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	return m.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := checkFence(tx, fencingToken); err != nil {
+			return err
+		}
+		return tx.Model(&model.OutboxEvent{}).
+			Where("id IN ?", ids).
+			UpdateColumn("published_at", time.Now()).
+			Error
+	})
+}
+
+func (m *OutboxRepository) MarkFailed(ctx context.Context, event domain.ArticleEvent, reason string, fencingToken int64) error {
+	return m.fail(ctx, event.ID, fencingToken, map[string]any{
+		"attempts":     gorm.Expr("attempts + 1"),
+		"last_error":   reason,
+		"delivered_to": strings.Join(event.Delivered, ","),
+		"retry_at":     event.RetryAt,
+	})
+}
+
+func (m *OutboxRepository) DeadLetter(ctx context.Context, event domain.ArticleEvent, reason string, fencingToken int64) error {
+	return m.fail(ctx, event.ID, fencingToken, map[string]any{
+		"attempts":     gorm.Expr("attempts + 1"),
+		"last_error":   reason,
+		"delivered_to": strings.Join(event.Delivered, ","),
+		"dead_at":      time.Now(),
+	})
+}
+
+func (m *OutboxRepository) fail(ctx context.Context, id int64, fencingToken int64, columns map[string]any) error {
+	return m.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := checkFence(tx, fencingToken); err != nil {
+			return err
+		}
+		return tx.Model(&model.OutboxEvent{}).
+			Where("id = ?", id).
+			UpdateColumns(columns).
+			Error
+	})
+}
+
+func (m *OutboxRepository) Purge(ctx context.Context, before time.Time) error {
+	return m.DB.WithContext(ctx).
+		Where("published_at IS NOT NULL AND published_at < ?", before).
+		Delete(&model.OutboxEvent{}).
+		Error
+}
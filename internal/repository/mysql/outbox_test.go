@@ -0,0 +1,32 @@
+package mysql_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	mysqlRepo "github.com/bxcodec/go-clean-arch/internal/repository/mysql"
+)
+
+func TestFetchPendingSkipsEventsWaitingForRetry(t *testing.T) {
+	db, mock := newMockDB(t)
+	repo := mysqlRepo.NewOutboxRepository(db)
+
+	mock.ExpectQuery("SELECT \\* FROM `article_outbox` WHERE \\(published_at IS NULL AND dead_at IS NULL\\) "+
+		"AND \\(retry_at IS NULL OR retry_at <= \\?\\) "+
+		"AND \\(NOT EXISTS \\(SELECT 1 FROM article_outbox AS head WHERE head.aggregate_id = article_outbox.aggregate_id "+
+		"AND head.id < article_outbox.id AND head.published_at IS NULL AND head.dead_at IS NULL AND head.retry_at > \\?\\)\\) "+
+		"ORDER BY id LIMIT \\?").
+		WithArgs(sqlmock.AnyArg(), sqlmock.AnyArg(), 100).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "aggregate_id", "event_type", "payload"}).
+			AddRow(int64(3), int64(20), "article.updated", `{"id":20}`))
+
+	events, err := repo.FetchPending(context.Background(), 100)
+	require.NoError(t, err)
+	require.Len(t, events, 1)
+	assert.Equal(t, int64(20), events[0].ArticleID)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
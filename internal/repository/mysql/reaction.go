@@ -0,0 +1,187 @@
+package mysql
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/bxcodec/go-clean-arch/domain"
+	"github.com/bxcodec/go-clean-arch/internal/repository/mysql/model"
+)
+
+type ReactionRepository struct {
+	DB *gorm.DB
+}
+
+// NewReactionRepository will create an object that represent the domain.ReactionRepository interface
+func NewReactionRepository(db *gorm.DB) *ReactionRepository {
+	return &ReactionRepository{db}
+}
+
+func (m *ReactionRepository) Add(ctx context.Context, r *domain.Reaction) (bool, error) {
+	result := m.DB.WithContext(ctx).
+		Clauses(clause.Insert{Modifier: "IGNORE"}).
+		Create(model.NewReactionFromDomain(r))
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
+func (m *ReactionRepository) Remove(ctx context.Context, r *domain.Reaction) (bool, error) {
+	result := m.DB.WithContext(ctx).
+		Where("article_id = ? AND user_id = ? AND kind = ?", r.ArticleID, r.UserID, r.Kind).
+		Delete(&model.Reaction{})
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
+func (m *ReactionRepository) ListKinds(ctx context.Context, articleID, userID int64) ([]string, error) {
+	var kinds []string
+	err := m.DB.WithContext(ctx).
+		Model(&model.Reaction{}).
+		Where("article_id = ? AND user_id = ?", articleID, userID).
+		Order("created_at").
+		Pluck("kind", &kinds).
+		Error
+	return kinds, err
+}
+
+func (m *ReactionRepository) Counts(ctx context.Context, articleID int64) (map[string]int64, error) {
+	var counts []model.ReactionCount
+	if err := m.DB.WithContext(ctx).Where("article_id = ?", articleID).Find(&counts).Error; err != nil {
+		return nil, err
+	}
+	res := make(map[string]int64, len(counts))
+	for _, c := range counts {
+		res[c.Kind] = c.Count
+	}
+	return res, nil
+}
+
+// AddCounts applies a whole reaction batch in a single transaction. Like view
+// batches, batches with an id are recorded so a redelivered batch is skipped.
+func (m *ReactionRepository) AddCounts(ctx context.Context, batch domain.ReactionBatch) error {
+	if len(batch.Counts) == 0 {
+		return nil
+	}
+
+	rows := make([]model.ReactionCount, 0, len(batch.Counts))
+	for key, delta := range batch.Counts {
+		rows = append(rows, model.ReactionCount{ArticleID: key.ArticleID, Kind: key.Kind, Count: delta})
+	}
+	// keep a stable lock order between concurrent batches
+	sort.Slice(rows, func(i, j int) bool {
+		if rows[i].ArticleID != rows[j].ArticleID {
+			return rows[i].ArticleID < rows[j].ArticleID
+		}
+		return rows[i].Kind < rows[j].Kind
+	})
+
+	err := m.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if batch.FencingToken != 0 {
+			if err := checkFence(tx, batch.FencingToken); err != nil {
+				return err
+			}
+		}
+		if batch.ID != "" {
+			result := tx.Clauses(clause.Insert{Modifier: "IGNORE"}).
+				Create(&model.ReactionBatch{ID: batch.ID, AppliedAt: time.Now()})
+			if result.Error != nil {
+				return result.Error
+			}
+			if result.RowsAffected == 0 {
+				// already applied by a previous run
+				return nil
+			}
+		}
+
+		return tx.Clauses(clause.OnConflict{
+			DoUpdates: clause.Assignments(map[string]any{"count": gorm.Expr("count + VALUES(count)")}),
+		}).Create(&rows).Error
+	})
+	if err != nil {
+		return fmt.Errorf("failed to add reaction counts: %w", err)
+	}
+	return nil
+}
+
+func (m *ReactionRepository) PurgeBatches(ctx context.Context, before time.Time) error {
+	return m.DB.WithContext(ctx).Where("applied_at < ?", before).Delete(&model.ReactionBatch{}).Error
+}
+
+func (m *ReactionRepository) Recount(ctx context.Context, afterArticleID int64, limit int) (map[domain.ReactionKey]int64, error) {
+	var articleIDs []int64
+	err := m.DB.WithContext(ctx).Raw(
+		"SELECT article_id FROM ("+
+			"(SELECT DISTINCT article_id FROM article_reaction WHERE article_id > ? ORDER BY article_id LIMIT ?) UNION "+
+			"(SELECT DISTINCT article_id FROM article_reaction_count WHERE article_id > ? ORDER BY article_id LIMIT ?)"+
+			") ids ORDER BY article_id LIMIT ?",
+		afterArticleID, limit, afterArticleID, limit, limit,
+	).Scan(&articleIDs).Error
+	if err != nil || len(articleIDs) == 0 {
+		return nil, err
+	}
+
+	res := make(map[domain.ReactionKey]int64)
+	var stored []model.ReactionCount
+	err = m.DB.WithContext(ctx).Where("article_id IN ?", articleIDs).Find(&stored).Error
+	if err != nil {
+		return nil, err
+	}
+	for _, c := range stored {
+		res[domain.ReactionKey{ArticleID: c.ArticleID, Kind: c.Kind}] = 0
+	}
+
+	var counted []model.ReactionCount
+	err = m.DB.WithContext(ctx).
+		Model(&model.Reaction{}).
+		Select("article_id, kind, COUNT(*) AS count").
+		Where("article_id IN ?", articleIDs).
+		Group("article_id, kind").
+		Scan(&counted).
+		Error
+	if err != nil {
+		return nil, err
+	}
+	for _, c := range counted {
+		res[domain.ReactionKey{ArticleID: c.ArticleID, Kind: c.Kind}] = c.Count
+	}
+	return res, nil
+}
+
+func (m *ReactionRepository) SetCounts(ctx context.Context, counts map[domain.ReactionKey]int64, fencingToken int64) error {
+	if len(counts) == 0 {
+		return nil
+	}
+	rows := make([]model.ReactionCount, 0, len(counts))
+	for key, count := range counts {
+		rows = append(rows, model.ReactionCount{ArticleID: key.ArticleID, Kind: key.Kind, Count: count})
+	}
+	// keep a stable lock order with AddCounts
+	sort.Slice(rows, func(i, j int) bool {
+		if rows[i].ArticleID != rows[j].ArticleID {
+			return rows[i].ArticleID < rows[j].ArticleID
+		}
+		return rows[i].Kind < rows[j].Kind
+	})
+
+	err := m.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := checkFence(tx, fencingToken); err != nil {
+			return err
+		}
+		return tx.Clauses(clause.OnConflict{
+			DoUpdates: clause.AssignmentColumns([]string{"count"}),
+		}).Create(&rows).Error
+	})
+	if err != nil {
+		return fmt.Errorf("failed to set reaction counts: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,190 @@
+package mysql
+
+import (
+	"context"
+	"errors"
+
+	"gorm.io/gorm"
+
+	"github.com/bxcodec/go-clean-arch/domain"
+	"github.com/bxcodec/go-clean-arch/internal/repository"
+	"github.com/bxcodec/go-clean-arch/internal/repository/mysql/model"
+)
+
+// seriesArticleColumns loads the articles of a series without their content
+const seriesArticleColumns = "article.id, article.title, article.excerpt, article.word_count, article.reading_minutes, " +
+	"article.status, article.visibility, article.user_id, article.views, article.comment_count, article.created_at, article.updated_at"
+
+type SeriesRepository struct {
+	DB *gorm.DB
+}
+
+// NewSeriesRepository will create an object that represent the domain.SeriesRepository interface
+func NewSeriesRepository(db *gorm.DB) *SeriesRepository {
+	return &SeriesRepository{db}
+}
+
+func (m *SeriesRepository) Store(ctx context.Context, s *domain.Series) error {
+	series := model.NewSeriesFromDomain(s)
+	if err := m.DB.WithContext(ctx).Create(series).Error; err != nil {
+		return err
+	}
+	s.ID = series.ID
+	return nil
+}
+
+func (m *SeriesRepository) GetByID(ctx context.Context, id int64) (domain.Series, error) {
+	var row model.SeriesWithStats
+	err := m.withStats(ctx).Where("series.id = ?", id).Take(&row).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return domain.Series{}, domain.ErrNotFound
+	}
+	if err != nil {
+		return domain.Series{}, err
+	}
+	return row.ToDomain(), nil
+}
+
+func (m *SeriesRepository) Update(ctx context.Context, s *domain.Series) error {
+	return m.DB.WithContext(ctx).
+		Model(&model.Series{}).
+		Where("id = ?", s.ID).
+		Updates(map[string]any{
+			"title":       s.Title,
+			"description": s.Description,
+			"cover_url":   s.CoverURL,
+			"cover_alt":   s.CoverAlt,
+			"updated_at":  s.UpdatedAt,
+		}).
+		Error
+}
+
+func (m *SeriesRepository) Delete(ctx context.Context, id int64) error {
+	return m.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("series_id = ?", id).Delete(&model.SeriesArticle{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&model.Series{}, id).Error
+	})
+}
+
+func (m *SeriesRepository) Fetch(ctx context.Context, authorID int64, cursor string, num int64) (res []domain.Series, nextCursor string, err error) {
+	query := m.withStats(ctx)
+	if authorID != 0 {
+		query = query.Where("series.user_id = ?", authorID)
+	}
+	if cursor != "" {
+		decodedCursor, err := repository.DecodeCursor(cursor)
+		if err != nil {
+			return nil, "", domain.ErrBadParamInput
+		}
+		query = query.Where("series.created_at < ?", decodedCursor)
+	}
+
+	repository.PageVerify(&num)
+	var rows []model.SeriesWithStats
+	if err := query.Order("series.created_at DESC").Limit(int(num)).Find(&rows).Error; err != nil {
+		return nil, "", err
+	}
+	res = make([]domain.Series, len(rows))
+	for i := range rows {
+		res[i] = rows[i].ToDomain()
+	}
+	if len(res) == int(num) {
+		nextCursor = repository.EncodeCursor(res[len(res)-1].CreatedAt)
+	}
+	return res, nextCursor, nil
+}
+
+// withStats selects series with the totals of their listed articles
+func (m *SeriesRepository) withStats(ctx context.Context) *gorm.DB {
+	stats := m.DB.
+		Table("series_article").
+		Select("series_article.series_id, COUNT(*) AS article_count, SUM(article.word_count) AS word_count").
+		Joins("JOIN article ON article.id = series_article.article_id").
+		Where("article.status = ? AND article.visibility = ?", domain.ArticleStatusPublished, domain.ArticleVisibilityPublic).
+		Group("series_article.series_id")
+	return m.DB.WithContext(ctx).
+		Table("series").
+		Select("series.*, COALESCE(stats.article_count, 0) AS article_count, COALESCE(stats.word_count, 0) AS word_count").
+		Joins("LEFT JOIN (?) AS stats ON stats.series_id = series.id", stats)
+}
+
+func (m *SeriesRepository) Articles(ctx context.Context, seriesID int64, listedOnly bool) ([]domain.Article, error) {
+	query := m.DB.WithContext(ctx).
+		Table("series_article").
+		Select(seriesArticleColumns).
+		Joins("JOIN article ON article.id = series_article.article_id").
+		Where("series_article.series_id = ?", seriesID)
+	if listedOnly {
+		query = query.Where("article.status = ? AND article.visibility = ?", domain.ArticleStatusPublished, domain.ArticleVisibilityPublic)
+	}
+	var articles []model.Article
+	if err := query.Order("series_article.position").Find(&articles).Error; err != nil {
+		return nil, err
+	}
+	res := make([]domain.Article, len(articles))
+	for i := range articles {
+		res[i] = articles[i].ToDomain()
+	}
+	return res, nil
+}
+
+func (m *SeriesRepository) SetArticles(ctx context.Context, seriesID int64, articleIDs []int64) error {
+	return m.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if len(articleIDs) > 0 {
+			var taken int64
+			err := tx.Model(&model.SeriesArticle{}).
+				Where("article_id IN ? AND series_id <> ?", articleIDs, seriesID).
+				Count(&taken).
+				Error
+			if err != nil {
+				return err
+			}
+			if taken > 0 {
+				return domain.ErrConflict
+			}
+		}
+		if err := tx.Where("series_id = ?", seriesID).Delete(&model.SeriesArticle{}).Error; err != nil {
+			return err
+		}
+		if len(articleIDs) == 0 {
+			return nil
+		}
+		rows := make([]model.SeriesArticle, len(articleIDs))
+		for i, id := range articleIDs {
+			rows[i] = model.SeriesArticle{SeriesID: seriesID, ArticleID: id, Position: int64(i + 1)}
+		}
+		return tx.Create(&rows).Error
+	})
+}
+
+func (m *SeriesRepository) GetByArticle(ctx context.Context, articleID int64) (domain.Series, error) {
+	var seriesID int64
+	err := m.DB.WithContext(ctx).
+		Model(&model.SeriesArticle{}).
+		Where("article_id = ?", articleID).
+		Select("series_id").
+		Scan(&seriesID).
+		Error
+	if err != nil {
+		return domain.Series{}, err
+	}
+	if seriesID == 0 {
+		return domain.Series{}, domain.ErrNotFound
+	}
+
+	var series model.Series
+	if err := m.DB.WithContext(ctx).First(&series, "id = ?", seriesID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return domain.Series{}, domain.ErrNotFound
+		}
+		return domain.Series{}, err
+	}
+	res := series.ToDomain()
+	res.Articles, err = m.Articles(ctx, seriesID, false)
+	if err != nil {
+		return domain.Series{}, err
+	}
+	return res, nil
+}
@@ -0,0 +1,65 @@
+package mysql
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+
+	"github.com/bxcodec/go-clean-arch/domain"
+	"github.com/bxcodec/go-clean-arch/internal/repository/mysql/model"
+)
+
+// articleLastMod is the last change of an article, rows written before updates
+// were tracked only have a creation time
+const articleLastMod = "GREATEST(article.created_at, COALESCE(article.updated_at, article.created_at))"
+
+type SitemapRepository struct {
+	DB *gorm.DB
+}
+
+// NewSitemapRepository will create an object that represent the domain.SitemapRepository interface
+func NewSitemapRepository(db *gorm.DB) *SitemapRepository {
+	return &SitemapRepository{db}
+}
+
+func (m *SitemapRepository) ArticleEntries(ctx context.Context, fromID, toID int64) ([]domain.SitemapEntry, error) {
+	var rows []model.SitemapEntry
+	err := m.DB.WithContext(ctx).
+		Table("article").
+		Select("article.id, "+articleLastMod+" AS last_mod").
+		Where("id BETWEEN ? AND ? AND status = ? AND visibility = ?",
+			fromID, toID, domain.ArticleStatusPublished, domain.ArticleVisibilityPublic).
+		Order("id").
+		Scan(&rows).
+		Error
+	if err != nil {
+		return nil, err
+	}
+	return model.SitemapEntriesToDomain(rows), nil
+}
+
+func (m *SitemapRepository) AuthorEntries(ctx context.Context, fromID, toID int64) ([]domain.SitemapEntry, error) {
+	var rows []model.SitemapEntry
+	err := m.DB.WithContext(ctx).
+		Table("article").
+		Select("article.user_id AS id, GREATEST(MAX("+articleLastMod+"), COALESCE(user.updated_at, MAX("+articleLastMod+"))) AS last_mod").
+		Joins("JOIN user ON user.id = article.user_id").
+		Where("article.user_id BETWEEN ? AND ? AND article.status = ? AND article.visibility = ?",
+			fromID, toID, domain.ArticleStatusPublished, domain.ArticleVisibilityPublic).
+		Group("article.user_id, user.updated_at").
+		Order("article.user_id").
+		Scan(&rows).
+		Error
+	if err != nil {
+		return nil, err
+	}
+	return model.SitemapEntriesToDomain(rows), nil
+}
+
+func (m *SitemapRepository) MaxIDs(ctx context.Context) (articleID, userID int64, err error) {
+	err = m.DB.WithContext(ctx).
+		Raw("SELECT (SELECT COALESCE(MAX(id), 0) FROM article), (SELECT COALESCE(MAX(id), 0) FROM user)").
+		Row().
+		Scan(&articleID, &userID)
+	return
+}
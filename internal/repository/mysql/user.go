@@ -2,6 +2,7 @@ package mysql
 
 import (
 	"context"
+	"errors"
 
 	"github.com/bxcodec/go-clean-arch/domain"
 	"github.com/bxcodec/go-clean-arch/internal/repository/mysql/model"
@@ -21,7 +22,11 @@ func NewUserRepository(db *gorm.DB) *UserRepository {
 
 func (m *UserRepository) GetByID(ctx context.Context, id int64) (domain.User, error) {
 	var user model.User
-	if err := m.DB.WithContext(ctx).First(&user, "id = ?", id).Error; err != nil {
+	err := m.DB.WithContext(ctx).First(&user, "id = ?", id).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return domain.User{}, domain.ErrNotFound
+	}
+	if err != nil {
 		return domain.User{}, err
 	}
 
@@ -50,7 +55,11 @@ func (m *UserRepository) Update(ctx context.Context, a *domain.User) error {
 
 func (m *UserRepository) GetByUsername(ctx context.Context, username string) (domain.User, error) {
 	var user model.User
-	if err := m.DB.WithContext(ctx).First(&user, "username = ?", username).Error; err != nil {
+	err := m.DB.WithContext(ctx).First(&user, "username = ?", username).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return domain.User{}, domain.ErrNotFound
+	}
+	if err != nil {
 		return domain.User{}, err
 	}
 
@@ -0,0 +1,191 @@
+package mysql
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/bxcodec/go-clean-arch/domain"
+	"github.com/bxcodec/go-clean-arch/internal/repository"
+	"github.com/bxcodec/go-clean-arch/internal/repository/mysql/model"
+)
+
+type WebhookRepository struct {
+	DB *gorm.DB
+}
+
+// NewWebhookRepository will create an object that represent the domain.WebhookRepository interface
+func NewWebhookRepository(db *gorm.DB) *WebhookRepository {
+	return &WebhookRepository{db}
+}
+
+func (m *WebhookRepository) StoreSubscription(ctx context.Context, s *domain.WebhookSubscription) error {
+	sub := model.NewWebhookSubscriptionFromDomain(s)
+	if err := m.DB.WithContext(ctx).Create(sub).Error; err != nil {
+		return err
+	}
+	s.ID = sub.ID
+	s.CreatedAt = sub.CreatedAt
+	s.UpdatedAt = sub.UpdatedAt
+	return nil
+}
+
+func (m *WebhookRepository) GetSubscription(ctx context.Context, id int64) (domain.WebhookSubscription, error) {
+	var sub model.WebhookSubscription
+	err := m.DB.WithContext(ctx).First(&sub, "id = ?", id).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return domain.WebhookSubscription{}, domain.ErrNotFound
+	}
+	if err != nil {
+		return domain.WebhookSubscription{}, err
+	}
+	return sub.ToDomain(), nil
+}
+
+func (m *WebhookRepository) ListSubscriptions(ctx context.Context, userID int64) ([]domain.WebhookSubscription, error) {
+	return m.listSubscriptions(m.DB.WithContext(ctx).Where("user_id = ?", userID))
+}
+
+func (m *WebhookRepository) ListActiveSubscriptions(ctx context.Context) ([]domain.WebhookSubscription, error) {
+	return m.listSubscriptions(m.DB.WithContext(ctx).Where("active = ?", true))
+}
+
+func (m *WebhookRepository) listSubscriptions(query *gorm.DB) ([]domain.WebhookSubscription, error) {
+	var subs []model.WebhookSubscription
+	if err := query.Order("id").Find(&subs).Error; err != nil {
+		return nil, err
+	}
+	res := make([]domain.WebhookSubscription, len(subs))
+	for i := range subs {
+		res[i] = subs[i].ToDomain()
+	}
+	return res, nil
+}
+
+func (m *WebhookRepository) UpdateSubscription(ctx context.Context, s *domain.WebhookSubscription) error {
+	sub := model.NewWebhookSubscriptionFromDomain(s)
+	// Select all columns so that clearing the filter or deactivating is persisted
+	result := m.DB.WithContext(ctx).Model(sub).
+		Select("url", "events", "active", "updated_at").
+		Updates(sub)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return domain.ErrNotFound
+	}
+	return nil
+}
+
+func (m *WebhookRepository) DeleteSubscription(ctx context.Context, id int64) error {
+	result := m.DB.WithContext(ctx).Delete(&model.WebhookSubscription{}, id)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return domain.ErrNotFound
+	}
+	return nil
+}
+
+func (m *WebhookRepository) StoreDeliveries(ctx context.Context, ds []domain.WebhookDelivery) error {
+	if len(ds) == 0 {
+		return nil
+	}
+	rows := make([]*model.WebhookDelivery, len(ds))
+	for i := range ds {
+		rows[i] = model.NewWebhookDeliveryFromDomain(&ds[i])
+	}
+	return m.DB.WithContext(ctx).
+		Clauses(clause.Insert{Modifier: "IGNORE"}).
+		Create(rows).
+		Error
+}
+
+func (m *WebhookRepository) GetDelivery(ctx context.Context, id string) (domain.WebhookDelivery, error) {
+	var d model.WebhookDelivery
+	err := m.DB.WithContext(ctx).First(&d, "id = ?", id).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return domain.WebhookDelivery{}, domain.ErrNotFound
+	}
+	if err != nil {
+		return domain.WebhookDelivery{}, err
+	}
+	return d.ToDomain(), nil
+}
+
+func (m *WebhookRepository) ListDeliveries(ctx context.Context, subscriptionID int64, num int64) ([]domain.WebhookDelivery, error) {
+	repository.PageVerify(&num)
+	var rows []model.WebhookDelivery
+	err := m.DB.WithContext(ctx).
+		Where("subscription_id = ?", subscriptionID).
+		Order("created_at DESC").
+		Limit(int(num)).
+		Find(&rows).
+		Error
+	if err != nil {
+		return nil, err
+	}
+	res := make([]domain.WebhookDelivery, len(rows))
+	for i := range rows {
+		res[i] = rows[i].ToDomain()
+	}
+	return res, nil
+}
+
+func (m *WebhookRepository) FetchDueDeliveries(ctx context.Context, now time.Time, limit int) ([]domain.WebhookDelivery, error) {
+	var rows []model.WebhookDelivery
+	err := m.DB.WithContext(ctx).
+		Where("status = ? AND next_attempt_at <= ?", domain.WebhookDeliveryPending, now).
+		Order("next_attempt_at").
+		Limit(limit).
+		Find(&rows).
+		Error
+	if err != nil {
+		return nil, err
+	}
+	res := make([]domain.WebhookDelivery, len(rows))
+	for i := range rows {
+		res[i] = rows[i].ToDomain()
+	}
+	return res, nil
+}
+
+func (m *WebhookRepository) UpdateDelivery(ctx context.Context, d *domain.WebhookDelivery) error {
+	row := model.NewWebhookDeliveryFromDomain(d)
+	result := m.DB.WithContext(ctx).Model(row).
+		Select("status", "attempts", "next_attempt_at", "updated_at").
+		Updates(row)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return domain.ErrNotFound
+	}
+	return nil
+}
+
+func (m *WebhookRepository) StoreAttempt(ctx context.Context, a *domain.WebhookAttempt) error {
+	row := model.NewWebhookAttemptFromDomain(a)
+	if err := m.DB.WithContext(ctx).Create(row).Error; err != nil {
+		return err
+	}
+	a.ID = row.ID
+	return nil
+}
+
+func (m *WebhookRepository) ListAttempts(ctx context.Context, deliveryID string) ([]domain.WebhookAttempt, error) {
+	var rows []model.WebhookAttempt
+	err := m.DB.WithContext(ctx).Where("delivery_id = ?", deliveryID).Order("id").Find(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+	res := make([]domain.WebhookAttempt, len(rows))
+	for i := range rows {
+		res[i] = rows[i].ToDomain()
+	}
+	return res, nil
+}
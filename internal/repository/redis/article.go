@@ -2,6 +2,8 @@ package redis
 
 import (
 	"context"
+	"crypto/rand"
+	"encoding/hex"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -15,8 +17,24 @@ import (
 const (
 	KeyViewsBuffer     = "article:views:buffer"
 	KeyViewsProcessing = "article:views:processing"
+	KeyViewsBatch      = "article:views:processing:batch"
+
+	// BatchAttemptsTTL forgets the failed attempts of batches that were synced since
+	BatchAttemptsTTL = 24 * time.Hour
+	// DeadLetterTTL is how long a batch that could not be synced is kept for inspection
+	DeadLetterTTL = 30 * 24 * time.Hour
 )
 
+// ViewsAttemptsKey counts the failed syncs of a view batch
+func ViewsAttemptsKey(batchID string) string {
+	return "article:views:attempts:" + batchID
+}
+
+// ViewsDeadLetterKey holds a view batch that failed too many times
+func ViewsDeadLetterKey(batchID string) string {
+	return "article:views:deadletter:" + batchID
+}
+
 type ArticleCache struct {
 	client *redis.Client
 }
@@ -53,30 +71,133 @@ func (c *ArticleCache) Incr(ctx context.Context, id int64) (int64, error) {
 	return c.client.HIncrBy(ctx, KeyViewsBuffer, strconv.FormatInt(id, 10), 1).Result()
 }
 
-func (c *ArticleCache) FetchAndResetViews(ctx context.Context) (map[int64]int64, error) {
-	result := make(map[int64]int64)
-	err := c.client.Rename(ctx, KeyViewsBuffer, KeyViewsProcessing).Err()
+// fetchBatchScript moves the buffer into the processing hash unless a previous
+// batch is still pending there, and returns the batch id followed by the hash.
+// It is shared by every buffered counter (views, reactions).
+var fetchBatchScript = redis.NewScript(`
+if redis.call('EXISTS', KEYS[2]) == 0 then
+	if redis.call('EXISTS', KEYS[1]) == 0 then
+		return {}
+	end
+	redis.call('RENAME', KEYS[1], KEYS[2])
+	redis.call('DEL', KEYS[3])
+end
+local batch = redis.call('GET', KEYS[3])
+if not batch then
+	batch = ARGV[1]
+	redis.call('SET', KEYS[3], batch)
+end
+local res = redis.call('HGETALL', KEYS[2])
+table.insert(res, 1, batch)
+return res
+`)
+
+// ackBatchScript removes the acknowledged fields from the processing hash
+// and drops the batch id once the whole batch has been acknowledged.
+var ackBatchScript = redis.NewScript(`
+if redis.call('GET', KEYS[2]) ~= ARGV[1] then
+	return 0
+end
+for i = 2, #ARGV do
+	redis.call('HDEL', KEYS[1], ARGV[i])
+end
+if redis.call('EXISTS', KEYS[1]) == 0 then
+	redis.call('DEL', KEYS[2])
+end
+return 1
+`)
+
+// failBatchScript counts a failed sync of the pending batch and, once it
+// reached the attempt limit, renames the processing hash to the dead letter
+// key and drops the batch id so that the next fetch rotates the buffer.
+// KEYS: processing hash, batch id, attempts, dead letter.
+// ARGV: batch id, max attempts, attempts ttl, dead letter ttl, in seconds.
+// It is shared by every buffered counter (views, reactions).
+var failBatchScript = redis.NewScript(`
+if redis.call('GET', KEYS[2]) ~= ARGV[1] then
+	return 0
+end
+local attempts = redis.call('INCR', KEYS[3])
+redis.call('EXPIRE', KEYS[3], ARGV[3])
+if attempts < tonumber(ARGV[2]) then
+	return 0
+end
+if redis.call('EXISTS', KEYS[1]) == 1 then
+	redis.call('RENAME', KEYS[1], KEYS[4])
+	redis.call('EXPIRE', KEYS[4], ARGV[4])
+end
+redis.call('DEL', KEYS[2], KEYS[3])
+return 1
+`)
+
+func failBatch(ctx context.Context, client *redis.Client, keys []string, batchID string, maxAttempts int) (bool, error) {
+	args := []any{batchID, maxAttempts, int64(BatchAttemptsTTL.Seconds()), int64(DeadLetterTTL.Seconds())}
+	res, err := failBatchScript.Run(ctx, client, keys, args...).Int()
+	return res == 1, err
+}
+
+// FetchViews returns the pending view batch. A batch left behind by a crashed
+// sync is returned again with its original id before the buffer is rotated.
+func (c *ArticleCache) FetchViews(ctx context.Context) (domain.ViewBatch, error) {
+	batch := domain.ViewBatch{Views: make(map[int64]int64)}
+	newID, err := newBatchID()
 	if err != nil {
-		return result, err
+		return batch, err
 	}
 
-	data, err := c.client.HGetAll(ctx, KeyViewsProcessing).Result()
+	keys := []string{KeyViewsBuffer, KeyViewsProcessing, KeyViewsBatch}
+	data, err := fetchBatchScript.Run(ctx, c.client, keys, newID).StringSlice()
 	if err != nil {
 		if errors.Is(err, redis.Nil) {
-			return result, nil
+			return batch, nil
 		}
-		return result, err
+		return batch, err
 	}
+	if len(data) == 0 {
+		return batch, nil
+	}
+
+	batch.ID = data[0]
+	for i := 1; i+1 < len(data); i += 2 {
+		id, err := strconv.ParseInt(data[i], 10, 64)
+		if err != nil {
+			continue
+		}
+		views, err := strconv.ParseInt(data[i+1], 10, 64)
+		if err != nil {
+			continue
+		}
+		batch.Views[id] = views
+	}
+	return batch, nil
+}
 
-	for idStr, viewsStr := range data {
-		id, _ := strconv.ParseInt(idStr, 10, 64)
-		views, _ := strconv.ParseInt(viewsStr, 10, 64)
-		result[id] = views
+// AckViews removes the given articles from the pending batch once their views
+// have been persisted.
+func (c *ArticleCache) AckViews(ctx context.Context, batchID string, ids []int64) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	args := make([]any, 0, len(ids)+1)
+	args = append(args, batchID)
+	for _, id := range ids {
+		args = append(args, strconv.FormatInt(id, 10))
 	}
+	keys := []string{KeyViewsProcessing, KeyViewsBatch}
+	return ackBatchScript.Run(ctx, c.client, keys, args...).Err()
+}
 
-	c.client.Del(ctx, KeyViewsProcessing)
+func (c *ArticleCache) FailViews(ctx context.Context, batchID string, maxAttempts int) (bool, error) {
+	keys := []string{KeyViewsProcessing, KeyViewsBatch, ViewsAttemptsKey(batchID), ViewsDeadLetterKey(batchID)}
+	return failBatch(ctx, c.client, keys, batchID, maxAttempts)
+}
 
-	return result, nil
+func newBatchID() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
 }
 
 func (c *ArticleCache) Del(ctx context.Context, id int64) (err error) {
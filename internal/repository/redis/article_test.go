@@ -72,3 +72,75 @@ func TestSet(t *testing.T) {
 		assert.Error(t, err)
 	})
 }
+
+func TestFetchViews(t *testing.T) {
+	db, mock := redismock.NewClientMock()
+	cache := redisRepo.NewArticleCache(db)
+	keys := []string{redisRepo.KeyViewsBuffer, redisRepo.KeyViewsProcessing, redisRepo.KeyViewsBatch}
+
+	t.Run("pending batch", func(t *testing.T) {
+		mock.Regexp().ExpectEvalSha(".+", keys, ".+").SetVal([]any{"batch-1", "1", "3", "2", "5"})
+
+		batch, err := cache.FetchViews(context.Background())
+
+		assert.NoError(t, err)
+		assert.Equal(t, "batch-1", batch.ID)
+		assert.Equal(t, map[int64]int64{1: 3, 2: 5}, batch.Views)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("empty buffer", func(t *testing.T) {
+		mock.Regexp().ExpectEvalSha(".+", keys, ".+").SetVal([]any{})
+
+		batch, err := cache.FetchViews(context.Background())
+
+		assert.NoError(t, err)
+		assert.Empty(t, batch.ID)
+		assert.Empty(t, batch.Views)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("redis error", func(t *testing.T) {
+		mock.Regexp().ExpectEvalSha(".+", keys, ".+").SetErr(assert.AnError)
+
+		_, err := cache.FetchViews(context.Background())
+
+		assert.Error(t, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestAckViews(t *testing.T) {
+	db, mock := redismock.NewClientMock()
+	cache := redisRepo.NewArticleCache(db)
+	keys := []string{redisRepo.KeyViewsProcessing, redisRepo.KeyViewsBatch}
+
+	mock.Regexp().ExpectEvalSha(".+", keys, "batch-1", "1", "2").SetVal(int64(1))
+
+	err := cache.AckViews(context.Background(), "batch-1", []int64{1, 2})
+
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestFailViews(t *testing.T) {
+	db, mock := redismock.NewClientMock()
+	cache := redisRepo.NewArticleCache(db)
+	keys := []string{
+		redisRepo.KeyViewsProcessing, redisRepo.KeyViewsBatch,
+		redisRepo.ViewsAttemptsKey("batch-1"), redisRepo.ViewsDeadLetterKey("batch-1"),
+	}
+	args := []any{"batch-1", 3, int64(redisRepo.BatchAttemptsTTL.Seconds()), int64(redisRepo.DeadLetterTTL.Seconds())}
+
+	mock.Regexp().ExpectEvalSha(".+", keys, args...).SetVal(int64(0))
+	mock.Regexp().ExpectEvalSha(".+", keys, args...).SetVal(int64(1))
+
+	dead, err := cache.FailViews(context.Background(), "batch-1", 3)
+	assert.NoError(t, err)
+	assert.False(t, dead)
+
+	dead, err = cache.FailViews(context.Background(), "batch-1", 3)
+	assert.NoError(t, err)
+	assert.True(t, dead)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,114 @@
+package redis
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+
+	"github.com/bxcodec/go-clean-arch/domain"
+)
+
+const (
+	// FeedTimelineSize is the number of entries kept per timeline
+	FeedTimelineSize = 500
+	// FeedTimelineTTL drops the timelines of users that stopped reading their feed,
+	// they are rebuilt on the next read
+	FeedTimelineTTL = 7 * 24 * time.Hour
+
+	// feedSentinel marks a timeline as built even when it holds no article
+	feedSentinel = "0"
+)
+
+// pushFeedScript adds the entries to the timelines that exist and trims them.
+// ARGV: max length, then score and member pairs.
+var pushFeedScript = redis.NewScript(`
+local max = tonumber(ARGV[1])
+for _, key in ipairs(KEYS) do
+	if redis.call('EXISTS', key) == 1 then
+		for i = 2, #ARGV, 2 do
+			redis.call('ZADD', key, ARGV[i], ARGV[i + 1])
+		end
+		redis.call('ZREMRANGEBYRANK', key, 0, -(max + 2))
+	end
+end
+return 1
+`)
+
+// FeedCache keeps the home feed timelines, one sorted set of article ids per
+// user scored by the creation time of the article in milliseconds
+type FeedCache struct {
+	client *redis.Client
+}
+
+func NewFeedCache(client *redis.Client) *FeedCache {
+	return &FeedCache{
+		client,
+	}
+}
+
+func FeedKey(userID int64) string {
+	return "feed:" + strconv.FormatInt(userID, 10)
+}
+
+func (c *FeedCache) Push(ctx context.Context, userIDs []int64, entries []domain.FeedEntry) error {
+	if len(userIDs) == 0 || len(entries) == 0 {
+		return nil
+	}
+	keys := make([]string, len(userIDs))
+	for i, id := range userIDs {
+		keys[i] = FeedKey(id)
+	}
+	args := make([]any, 0, 1+2*len(entries))
+	args = append(args, FeedTimelineSize)
+	for _, e := range entries {
+		args = append(args, e.CreatedAt.UnixMilli(), e.ArticleID)
+	}
+	return pushFeedScript.Run(ctx, c.client, keys, args...).Err()
+}
+
+func (c *FeedCache) Rebuild(ctx context.Context, userID int64, entries []domain.FeedEntry) error {
+	key := FeedKey(userID)
+	members := make([]redis.Z, 0, len(entries)+1)
+	members = append(members, redis.Z{Score: 0, Member: feedSentinel})
+	for _, e := range entries {
+		members = append(members, redis.Z{Score: float64(e.CreatedAt.UnixMilli()), Member: e.ArticleID})
+	}
+	pipe := c.client.TxPipeline()
+	pipe.Del(ctx, key)
+	pipe.ZAdd(ctx, key, members...)
+	pipe.ZRemRangeByRank(ctx, key, 0, -(FeedTimelineSize + 2))
+	pipe.Expire(ctx, key, FeedTimelineTTL)
+	_, err := pipe.Exec(ctx)
+	return err
+}
+
+func (c *FeedCache) Fetch(ctx context.Context, userID int64, before time.Time, num int64) ([]domain.FeedEntry, bool, error) {
+	key := FeedKey(userID)
+	pipe := c.client.Pipeline()
+	exists := pipe.Expire(ctx, key, FeedTimelineTTL)
+	// the sentinel has score 0 and is excluded by the open lower bound
+	entries := pipe.ZRevRangeByScoreWithScores(ctx, key, &redis.ZRangeBy{
+		Max:   "(" + strconv.FormatInt(before.UnixMilli(), 10),
+		Min:   "(0",
+		Count: num,
+	})
+	if _, err := pipe.Exec(ctx); err != nil {
+		return nil, false, err
+	}
+	if !exists.Val() {
+		return nil, false, nil
+	}
+
+	res := make([]domain.FeedEntry, 0, len(entries.Val()))
+	for _, z := range entries.Val() {
+		member, _ := z.Member.(string)
+		id, err := strconv.ParseInt(member, 10, 64)
+		if err != nil {
+			continue
+		}
+		res = append(res, domain.FeedEntry{ArticleID: id, CreatedAt: time.UnixMilli(int64(z.Score))})
+	}
+	return res, true, nil
+}
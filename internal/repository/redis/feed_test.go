@@ -0,0 +1,62 @@
+package redis_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/go-redis/redismock/v9"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/bxcodec/go-clean-arch/domain"
+	redisRepo "github.com/bxcodec/go-clean-arch/internal/repository/redis"
+)
+
+func TestFeedFetch(t *testing.T) {
+	db, mock := redismock.NewClientMock()
+	cache := redisRepo.NewFeedCache(db)
+	before := time.UnixMilli(5000)
+
+	mock.ExpectExpire(redisRepo.FeedKey(7), redisRepo.FeedTimelineTTL).SetVal(true)
+	mock.ExpectZRevRangeByScoreWithScores(redisRepo.FeedKey(7), &redis.ZRangeBy{Max: "(5000", Min: "(0", Count: 10}).
+		SetVal([]redis.Z{{Score: 4000, Member: "3"}, {Score: 2000, Member: "1"}})
+
+	entries, ok, err := cache.Fetch(context.Background(), 7, before, 10)
+
+	assert.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, []domain.FeedEntry{
+		{ArticleID: 3, CreatedAt: time.UnixMilli(4000)},
+		{ArticleID: 1, CreatedAt: time.UnixMilli(2000)},
+	}, entries)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestFeedFetchMissingTimeline(t *testing.T) {
+	db, mock := redismock.NewClientMock()
+	cache := redisRepo.NewFeedCache(db)
+
+	mock.ExpectExpire(redisRepo.FeedKey(7), redisRepo.FeedTimelineTTL).SetVal(false)
+	mock.ExpectZRevRangeByScoreWithScores(redisRepo.FeedKey(7), &redis.ZRangeBy{Max: "(5000", Min: "(0", Count: 10}).SetVal(nil)
+
+	entries, ok, err := cache.Fetch(context.Background(), 7, time.UnixMilli(5000), 10)
+
+	assert.NoError(t, err)
+	assert.False(t, ok)
+	assert.Empty(t, entries)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestFeedPush(t *testing.T) {
+	db, mock := redismock.NewClientMock()
+	cache := redisRepo.NewFeedCache(db)
+	keys := []string{redisRepo.FeedKey(1), redisRepo.FeedKey(2)}
+
+	mock.Regexp().ExpectEvalSha(".+", keys, redisRepo.FeedTimelineSize, int64(3000), int64(9)).SetVal(int64(1))
+
+	err := cache.Push(context.Background(), []int64{1, 2}, []domain.FeedEntry{{ArticleID: 9, CreatedAt: time.UnixMilli(3000)}})
+
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
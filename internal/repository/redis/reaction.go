@@ -0,0 +1,162 @@
+package redis
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"strings"
+
+	"github.com/redis/go-redis/v9"
+
+	"github.com/bxcodec/go-clean-arch/domain"
+)
+
+const (
+	KeyReactionsBuffer     = "article:reactions:buffer"
+	KeyReactionsProcessing = "article:reactions:processing"
+	KeyReactionsBatch      = "article:reactions:processing:batch"
+)
+
+// ReactionsAttemptsKey counts the failed syncs of a reaction batch
+func ReactionsAttemptsKey(batchID string) string {
+	return "article:reactions:attempts:" + batchID
+}
+
+// ReactionsDeadLetterKey holds a reaction batch that failed too many times
+func ReactionsDeadLetterKey(batchID string) string {
+	return "article:reactions:deadletter:" + batchID
+}
+
+// ReactionCache buffers reaction count deltas until they are synced to the database
+type ReactionCache struct {
+	client *redis.Client
+}
+
+func NewReactionCache(client *redis.Client) *ReactionCache {
+	return &ReactionCache{
+		client,
+	}
+}
+
+func reactionField(key domain.ReactionKey) string {
+	return strconv.FormatInt(key.ArticleID, 10) + ":" + key.Kind
+}
+
+func parseReactionField(field string) (domain.ReactionKey, bool) {
+	id, kind, ok := strings.Cut(field, ":")
+	if !ok {
+		return domain.ReactionKey{}, false
+	}
+	articleID, err := strconv.ParseInt(id, 10, 64)
+	if err != nil {
+		return domain.ReactionKey{}, false
+	}
+	return domain.ReactionKey{ArticleID: articleID, Kind: kind}, true
+}
+
+func (c *ReactionCache) Incr(ctx context.Context, key domain.ReactionKey, delta int64) error {
+	return c.client.HIncrBy(ctx, KeyReactionsBuffer, reactionField(key), delta).Err()
+}
+
+// Pending sums the deltas still in the buffer and in a batch being synced
+func (c *ReactionCache) Pending(ctx context.Context, articleID int64, kinds []string) (map[string]int64, error) {
+	keys := make([]domain.ReactionKey, len(kinds))
+	for i, kind := range kinds {
+		keys[i] = domain.ReactionKey{ArticleID: articleID, Kind: kind}
+	}
+	pending, err := c.PendingKeys(ctx, keys)
+	if err != nil {
+		return nil, err
+	}
+	res := make(map[string]int64, len(kinds))
+	for key, n := range pending {
+		res[key.Kind] = n
+	}
+	return res, nil
+}
+
+func (c *ReactionCache) PendingKeys(ctx context.Context, keys []domain.ReactionKey) (map[domain.ReactionKey]int64, error) {
+	res := make(map[domain.ReactionKey]int64, len(keys))
+	if len(keys) == 0 {
+		return res, nil
+	}
+	fields := make([]string, len(keys))
+	for i, key := range keys {
+		fields[i] = reactionField(key)
+	}
+
+	pipe := c.client.Pipeline()
+	buffered := pipe.HMGet(ctx, KeyReactionsBuffer, fields...)
+	processing := pipe.HMGet(ctx, KeyReactionsProcessing, fields...)
+	if _, err := pipe.Exec(ctx); err != nil {
+		return nil, err
+	}
+	for _, values := range [][]any{buffered.Val(), processing.Val()} {
+		for i, v := range values {
+			s, ok := v.(string)
+			if !ok {
+				continue
+			}
+			if n, err := strconv.ParseInt(s, 10, 64); err == nil {
+				res[keys[i]] += n
+			}
+		}
+	}
+	return res, nil
+}
+
+// FetchReactions returns the pending reaction batch, see FetchViews
+func (c *ReactionCache) FetchReactions(ctx context.Context) (domain.ReactionBatch, error) {
+	batch := domain.ReactionBatch{Counts: make(map[domain.ReactionKey]int64)}
+	newID, err := newBatchID()
+	if err != nil {
+		return batch, err
+	}
+
+	keys := []string{KeyReactionsBuffer, KeyReactionsProcessing, KeyReactionsBatch}
+	data, err := fetchBatchScript.Run(ctx, c.client, keys, newID).StringSlice()
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			return batch, nil
+		}
+		return batch, err
+	}
+	if len(data) == 0 {
+		return batch, nil
+	}
+
+	batch.ID = data[0]
+	for i := 1; i+1 < len(data); i += 2 {
+		key, ok := parseReactionField(data[i])
+		if !ok {
+			continue
+		}
+		delta, err := strconv.ParseInt(data[i+1], 10, 64)
+		if err != nil {
+			continue
+		}
+		batch.Counts[key] = delta
+	}
+	return batch, nil
+}
+
+// AckReactions removes the given counters from the pending batch once they have been persisted
+func (c *ReactionCache) AckReactions(ctx context.Context, batchID string, keys []domain.ReactionKey) error {
+	if len(keys) == 0 {
+		return nil
+	}
+	args := make([]any, 0, len(keys)+1)
+	args = append(args, batchID)
+	for _, key := range keys {
+		args = append(args, reactionField(key))
+	}
+	return ackBatchScript.Run(ctx, c.client, []string{KeyReactionsProcessing, KeyReactionsBatch}, args...).Err()
+}
+
+func (c *ReactionCache) FailReactions(ctx context.Context, batchID string, maxAttempts int) (bool, error) {
+	keys := []string{
+		KeyReactionsProcessing, KeyReactionsBatch,
+		ReactionsAttemptsKey(batchID), ReactionsDeadLetterKey(batchID),
+	}
+	return failBatch(ctx, c.client, keys, batchID, maxAttempts)
+}
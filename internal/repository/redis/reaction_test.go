@@ -0,0 +1,78 @@
+package redis_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/go-redis/redismock/v9"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/bxcodec/go-clean-arch/domain"
+	redisRepo "github.com/bxcodec/go-clean-arch/internal/repository/redis"
+)
+
+func TestFetchReactions(t *testing.T) {
+	db, mock := redismock.NewClientMock()
+	cache := redisRepo.NewReactionCache(db)
+	keys := []string{redisRepo.KeyReactionsBuffer, redisRepo.KeyReactionsProcessing, redisRepo.KeyReactionsBatch}
+
+	mock.Regexp().ExpectEvalSha(".+", keys, ".+").SetVal([]any{"batch-1", "1:like", "3", "2:wow", "-1", "bad", "4"})
+
+	batch, err := cache.FetchReactions(context.Background())
+
+	assert.NoError(t, err)
+	assert.Equal(t, "batch-1", batch.ID)
+	assert.Equal(t, map[domain.ReactionKey]int64{
+		{ArticleID: 1, Kind: "like"}: 3,
+		{ArticleID: 2, Kind: "wow"}:  -1,
+	}, batch.Counts)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestPendingReactions(t *testing.T) {
+	db, mock := redismock.NewClientMock()
+	cache := redisRepo.NewReactionCache(db)
+
+	mock.ExpectHMGet(redisRepo.KeyReactionsBuffer, "1:like", "1:wow").SetVal([]any{"2", nil})
+	mock.ExpectHMGet(redisRepo.KeyReactionsProcessing, "1:like", "1:wow").SetVal([]any{"1", "-1"})
+
+	pending, err := cache.Pending(context.Background(), 1, []string{"like", "wow"})
+
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]int64{"like": 3, "wow": -1}, pending)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestPendingReactionKeys(t *testing.T) {
+	db, mock := redismock.NewClientMock()
+	cache := redisRepo.NewReactionCache(db)
+	like := domain.ReactionKey{ArticleID: 1, Kind: "like"}
+	wow := domain.ReactionKey{ArticleID: 2, Kind: "wow"}
+
+	mock.ExpectHMGet(redisRepo.KeyReactionsBuffer, "1:like", "2:wow").SetVal([]any{nil, "2"})
+	mock.ExpectHMGet(redisRepo.KeyReactionsProcessing, "1:like", "2:wow").SetVal([]any{"1", nil})
+
+	pending, err := cache.PendingKeys(context.Background(), []domain.ReactionKey{like, wow})
+
+	assert.NoError(t, err)
+	assert.Equal(t, map[domain.ReactionKey]int64{like: 1, wow: 2}, pending)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestFailReactions(t *testing.T) {
+	db, mock := redismock.NewClientMock()
+	cache := redisRepo.NewReactionCache(db)
+	keys := []string{
+		redisRepo.KeyReactionsProcessing, redisRepo.KeyReactionsBatch,
+		redisRepo.ReactionsAttemptsKey("batch-1"), redisRepo.ReactionsDeadLetterKey("batch-1"),
+	}
+	args := []any{"batch-1", 3, int64(redisRepo.BatchAttemptsTTL.Seconds()), int64(redisRepo.DeadLetterTTL.Seconds())}
+
+	mock.Regexp().ExpectEvalSha(".+", keys, args...).SetVal(int64(1))
+
+	dead, err := cache.FailReactions(context.Background(), "batch-1", 3)
+
+	assert.NoError(t, err)
+	assert.True(t, dead)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
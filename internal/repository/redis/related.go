@@ -0,0 +1,177 @@
+package redis
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+
+	"github.com/bxcodec/go-clean-arch/domain"
+)
+
+const (
+	// KeyRelatedDirty is the set of articles waiting for their recommendations
+	KeyRelatedDirty  = "related:dirty"
+	KeyRelatedCursor = "related:cursor"
+
+	// RelatedHistorySize is the number of recent articles of a reader that a
+	// new read counts as co-viewed with
+	RelatedHistorySize = 20
+	// RelatedCoViewTTL forgets the co-views of articles nobody reads anymore
+	// and the history of readers who left
+	RelatedCoViewTTL = 30 * 24 * time.Hour
+	// RelatedTTL drops the recommendations of deleted articles, the worker
+	// refreshes the others well before
+	RelatedTTL = 7 * 24 * time.Hour
+)
+
+func RelatedKey(articleID int64) string {
+	return fmt.Sprintf("related:%d", articleID)
+}
+
+func RelatedCoViewsKey(articleID int64) string {
+	return fmt.Sprintf("related:coviews:%d", articleID)
+}
+
+func RelatedHistoryKey(userID int64) string {
+	return fmt.Sprintf("related:history:%d", userID)
+}
+
+// RelatedCache keeps per article the recommendations as JSON and the co-view
+// counts as a sorted set, and per reader the list of their recent reads
+type RelatedCache struct {
+	client *redis.Client
+}
+
+func NewRelatedCache(client *redis.Client) *RelatedCache {
+	return &RelatedCache{
+		client,
+	}
+}
+
+// RecordCoView counts a read as a co-view with every recent read of the
+// reader. Reading again an article of the history only moves it to the front,
+// so reloads are not counted.
+func (c *RelatedCache) RecordCoView(ctx context.Context, userID, articleID int64) error {
+	historyKey := RelatedHistoryKey(userID)
+	history, err := c.client.LRange(ctx, historyKey, 0, -1).Result()
+	if err != nil {
+		return err
+	}
+	member := strconv.FormatInt(articleID, 10)
+	seen := false
+	for _, other := range history {
+		if other == member {
+			seen = true
+			break
+		}
+	}
+
+	pipe := c.client.TxPipeline()
+	if !seen {
+		for _, other := range history {
+			otherID, err := strconv.ParseInt(other, 10, 64)
+			if err != nil {
+				continue
+			}
+			pipe.ZIncrBy(ctx, RelatedCoViewsKey(articleID), 1, other)
+			pipe.ZIncrBy(ctx, RelatedCoViewsKey(otherID), 1, member)
+			pipe.Expire(ctx, RelatedCoViewsKey(otherID), RelatedCoViewTTL)
+		}
+		if len(history) > 0 {
+			pipe.Expire(ctx, RelatedCoViewsKey(articleID), RelatedCoViewTTL)
+		}
+	}
+	pipe.LRem(ctx, historyKey, 0, member)
+	pipe.LPush(ctx, historyKey, member)
+	pipe.LTrim(ctx, historyKey, 0, RelatedHistorySize-1)
+	pipe.Expire(ctx, historyKey, RelatedCoViewTTL)
+	_, err = pipe.Exec(ctx)
+	return err
+}
+
+func (c *RelatedCache) CoViews(ctx context.Context, articleID int64, limit int) (map[int64]float64, error) {
+	members, err := c.client.ZRevRangeWithScores(ctx, RelatedCoViewsKey(articleID), 0, int64(limit)-1).Result()
+	if err != nil {
+		return nil, err
+	}
+	res := make(map[int64]float64, len(members))
+	for _, m := range members {
+		member, _ := m.Member.(string)
+		id, err := strconv.ParseInt(member, 10, 64)
+		if err != nil {
+			continue
+		}
+		res[id] = m.Score
+	}
+	return res, nil
+}
+
+func (c *RelatedCache) Get(ctx context.Context, articleID int64) ([]domain.RelatedScore, error) {
+	data, err := c.client.Get(ctx, RelatedKey(articleID)).Bytes()
+	if err != nil {
+		return nil, err
+	}
+	var res []domain.RelatedScore
+	if err := json.Unmarshal(data, &res); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+func (c *RelatedCache) Set(ctx context.Context, articleID int64, related []domain.RelatedScore) error {
+	if related == nil {
+		// an article without recommendations is computed all the same
+		related = []domain.RelatedScore{}
+	}
+	data, err := json.Marshal(related)
+	if err != nil {
+		return err
+	}
+	return c.client.Set(ctx, RelatedKey(articleID), data, RelatedTTL).Err()
+}
+
+func (c *RelatedCache) Del(ctx context.Context, articleID int64) error {
+	return c.client.Del(ctx, RelatedKey(articleID), RelatedCoViewsKey(articleID)).Err()
+}
+
+func (c *RelatedCache) MarkDirty(ctx context.Context, articleIDs ...int64) error {
+	if len(articleIDs) == 0 {
+		return nil
+	}
+	members := make([]any, len(articleIDs))
+	for i, id := range articleIDs {
+		members[i] = strconv.FormatInt(id, 10)
+	}
+	return c.client.SAdd(ctx, KeyRelatedDirty, members...).Err()
+}
+
+func (c *RelatedCache) PopDirty(ctx context.Context, limit int) ([]int64, error) {
+	members, err := c.client.SPopN(ctx, KeyRelatedDirty, int64(limit)).Result()
+	if err != nil {
+		return nil, err
+	}
+	res := make([]int64, 0, len(members))
+	for _, m := range members {
+		if id, err := strconv.ParseInt(m, 10, 64); err == nil {
+			res = append(res, id)
+		}
+	}
+	return res, nil
+}
+
+func (c *RelatedCache) Cursor(ctx context.Context) (int64, error) {
+	cursor, err := c.client.Get(ctx, KeyRelatedCursor).Int64()
+	if errors.Is(err, redis.Nil) {
+		return 0, nil
+	}
+	return cursor, err
+}
+
+func (c *RelatedCache) SaveCursor(ctx context.Context, articleID int64) error {
+	return c.client.Set(ctx, KeyRelatedCursor, articleID, 0).Err()
+}
@@ -0,0 +1,68 @@
+package redis_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/go-redis/redismock/v9"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/bxcodec/go-clean-arch/domain"
+	redisRepo "github.com/bxcodec/go-clean-arch/internal/repository/redis"
+)
+
+func expectHistoryPush(mock redismock.ClientMock, userID int64, member string) {
+	key := redisRepo.RelatedHistoryKey(userID)
+	mock.ExpectLRem(key, 0, member).SetVal(0)
+	mock.ExpectLPush(key, member).SetVal(1)
+	mock.ExpectLTrim(key, 0, redisRepo.RelatedHistorySize-1).SetVal("OK")
+	mock.ExpectExpire(key, redisRepo.RelatedCoViewTTL).SetVal(true)
+}
+
+func TestRelatedRecordCoView(t *testing.T) {
+	db, mock := redismock.NewClientMock()
+	cache := redisRepo.NewRelatedCache(db)
+
+	mock.ExpectLRange(redisRepo.RelatedHistoryKey(7), 0, -1).SetVal([]string{"2"})
+	mock.ExpectTxPipeline()
+	mock.ExpectZIncrBy(redisRepo.RelatedCoViewsKey(3), 1, "2").SetVal(1)
+	mock.ExpectZIncrBy(redisRepo.RelatedCoViewsKey(2), 1, "3").SetVal(1)
+	mock.ExpectExpire(redisRepo.RelatedCoViewsKey(2), redisRepo.RelatedCoViewTTL).SetVal(true)
+	mock.ExpectExpire(redisRepo.RelatedCoViewsKey(3), redisRepo.RelatedCoViewTTL).SetVal(true)
+	expectHistoryPush(mock, 7, "3")
+	mock.ExpectTxPipelineExec()
+
+	assert.NoError(t, cache.RecordCoView(context.Background(), 7, 3))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRelatedRecordCoViewReread(t *testing.T) {
+	db, mock := redismock.NewClientMock()
+	cache := redisRepo.NewRelatedCache(db)
+
+	// reading an article of the history again counts nothing
+	mock.ExpectLRange(redisRepo.RelatedHistoryKey(7), 0, -1).SetVal([]string{"2", "3"})
+	mock.ExpectTxPipeline()
+	expectHistoryPush(mock, 7, "3")
+	mock.ExpectTxPipelineExec()
+
+	assert.NoError(t, cache.RecordCoView(context.Background(), 7, 3))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRelatedGet(t *testing.T) {
+	db, mock := redismock.NewClientMock()
+	cache := redisRepo.NewRelatedCache(db)
+
+	mock.ExpectGet(redisRepo.RelatedKey(3)).SetVal(`[{"id":2,"score":0.5}]`)
+	mock.ExpectGet(redisRepo.RelatedKey(4)).RedisNil()
+
+	res, err := cache.Get(context.Background(), 3)
+	assert.NoError(t, err)
+	assert.Equal(t, []domain.RelatedScore{{ArticleID: 2, Score: 0.5}}, res)
+
+	_, err = cache.Get(context.Background(), 4)
+	assert.ErrorIs(t, err, redis.Nil)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
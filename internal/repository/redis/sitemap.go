@@ -0,0 +1,112 @@
+package redis
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+
+	"github.com/bxcodec/go-clean-arch/domain"
+)
+
+const (
+	KeySitemapIndex = "sitemap:index"
+	// KeySitemapChunks maps the name of every stored chunk to its last change in unix seconds
+	KeySitemapChunks = "sitemap:chunks"
+	// KeySitemapDirty is the set of chunks waiting to be regenerated
+	KeySitemapDirty = "sitemap:dirty"
+)
+
+// SitemapCache keeps the sitemap files without expiry, the sitemap worker
+// replaces them as the pages change
+type SitemapCache struct {
+	client *redis.Client
+}
+
+func NewSitemapCache(client *redis.Client) *SitemapCache {
+	return &SitemapCache{
+		client,
+	}
+}
+
+func sitemapChunkKey(chunk domain.SitemapChunk) string {
+	return "sitemap:chunk:" + chunk.Name()
+}
+
+func (c *SitemapCache) Index(ctx context.Context) ([]byte, error) {
+	data, err := c.client.Get(ctx, KeySitemapIndex).Bytes()
+	if errors.Is(err, redis.Nil) {
+		return nil, redis.Nil
+	}
+	return data, err
+}
+
+func (c *SitemapCache) SaveIndex(ctx context.Context, body []byte) error {
+	return c.client.Set(ctx, KeySitemapIndex, body, 0).Err()
+}
+
+func (c *SitemapCache) Chunk(ctx context.Context, chunk domain.SitemapChunk) ([]byte, error) {
+	data, err := c.client.Get(ctx, sitemapChunkKey(chunk)).Bytes()
+	if errors.Is(err, redis.Nil) {
+		return nil, redis.Nil
+	}
+	return data, err
+}
+
+func (c *SitemapCache) SaveChunk(ctx context.Context, chunk domain.SitemapChunk, body []byte, lastMod time.Time) error {
+	_, err := c.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		if body == nil {
+			pipe.Del(ctx, sitemapChunkKey(chunk))
+			pipe.HDel(ctx, KeySitemapChunks, chunk.Name())
+			return nil
+		}
+		pipe.Set(ctx, sitemapChunkKey(chunk), body, 0)
+		pipe.HSet(ctx, KeySitemapChunks, chunk.Name(), lastMod.Unix())
+		return nil
+	})
+	return err
+}
+
+func (c *SitemapCache) Chunks(ctx context.Context) (map[domain.SitemapChunk]time.Time, error) {
+	fields, err := c.client.HGetAll(ctx, KeySitemapChunks).Result()
+	if err != nil {
+		return nil, err
+	}
+	res := make(map[domain.SitemapChunk]time.Time, len(fields))
+	for name, value := range fields {
+		chunk, ok := domain.ParseSitemapChunk(name)
+		if !ok {
+			continue
+		}
+		unix, _ := strconv.ParseInt(value, 10, 64)
+		res[chunk] = time.Unix(unix, 0)
+	}
+	return res, nil
+}
+
+func (c *SitemapCache) MarkDirty(ctx context.Context, chunks ...domain.SitemapChunk) error {
+	if len(chunks) == 0 {
+		return nil
+	}
+	names := make([]any, len(chunks))
+	for i, chunk := range chunks {
+		names[i] = chunk.Name()
+	}
+	return c.client.SAdd(ctx, KeySitemapDirty, names...).Err()
+}
+
+func (c *SitemapCache) PopDirty(ctx context.Context, limit int) ([]domain.SitemapChunk, error) {
+	names, err := c.client.SPopN(ctx, KeySitemapDirty, int64(limit)).Result()
+	if err != nil {
+		return nil, err
+	}
+	res := make([]domain.SitemapChunk, 0, len(names))
+	for _, name := range names {
+		if chunk, ok := domain.ParseSitemapChunk(name); ok {
+			res = append(res, chunk)
+		}
+	}
+	return res, nil
+}
@@ -0,0 +1,67 @@
+package redis
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/bxcodec/go-clean-arch/domain"
+	"github.com/redis/go-redis/v9"
+)
+
+// syndicationTTL bounds how long a feed survives when an invalidation is lost
+const syndicationTTL = time.Hour
+
+type SyndicationCache struct {
+	client *redis.Client
+}
+
+func NewSyndicationCache(client *redis.Client) *SyndicationCache {
+	return &SyndicationCache{
+		client,
+	}
+}
+
+func syndicationKey(scope domain.SyndicationScope, format domain.SyndicationFormat) string {
+	switch {
+	case scope.AuthorID != 0:
+		return fmt.Sprintf("syndication:author:%d:%s", scope.AuthorID, format)
+	case scope.Tag != "":
+		return fmt.Sprintf("syndication:tag:%s:%s", scope.Tag, format)
+	}
+	return fmt.Sprintf("syndication:all:%s", format)
+}
+
+func (c *SyndicationCache) Get(ctx context.Context, scope domain.SyndicationScope, format domain.SyndicationFormat) (res domain.SyndicationDocument, err error) {
+	data, err := c.client.Get(ctx, syndicationKey(scope, format)).Bytes()
+	if errors.Is(err, redis.Nil) {
+		return domain.SyndicationDocument{}, redis.Nil
+	} else if err != nil {
+		return domain.SyndicationDocument{}, err
+	}
+	err = json.Unmarshal(data, &res)
+	return
+}
+
+func (c *SyndicationCache) Set(ctx context.Context, scope domain.SyndicationScope, format domain.SyndicationFormat, doc domain.SyndicationDocument) error {
+	data, err := json.Marshal(doc)
+	if err != nil {
+		return err
+	}
+	return c.client.Set(ctx, syndicationKey(scope, format), data, syndicationTTL).Err()
+}
+
+func (c *SyndicationCache) Invalidate(ctx context.Context, scopes ...domain.SyndicationScope) error {
+	if len(scopes) == 0 {
+		return nil
+	}
+	keys := make([]string, 0, len(scopes)*len(domain.SyndicationFormats))
+	for _, scope := range scopes {
+		for _, format := range domain.SyndicationFormats {
+			keys = append(keys, syndicationKey(scope, format))
+		}
+	}
+	return c.client.Del(ctx, keys...).Err()
+}
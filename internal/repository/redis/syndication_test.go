@@ -0,0 +1,60 @@
+package redis_test
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/bxcodec/go-clean-arch/domain"
+	redisRepo "github.com/bxcodec/go-clean-arch/internal/repository/redis"
+	"github.com/go-redis/redismock/v9"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSyndicationCacheGet(t *testing.T) {
+	db, mock := redismock.NewClientMock()
+	cache := redisRepo.NewSyndicationCache(db)
+
+	t.Run("success", func(t *testing.T) {
+		doc := domain.SyndicationDocument{
+			Body:         []byte("<rss/>"),
+			ContentType:  "application/rss+xml; charset=utf-8",
+			ETag:         `"abc"`,
+			LastModified: time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC),
+		}
+		data, _ := json.Marshal(doc)
+		mock.ExpectGet("syndication:author:7:rss").SetVal(string(data))
+
+		res, err := cache.Get(context.Background(), domain.SyndicationScope{AuthorID: 7}, domain.SyndicationRSS)
+
+		assert.NoError(t, err)
+		assert.Equal(t, doc, res)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		mock.ExpectGet("syndication:all:atom").RedisNil()
+
+		_, err := cache.Get(context.Background(), domain.SyndicationScope{}, domain.SyndicationAtom)
+
+		assert.ErrorIs(t, err, redis.Nil)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestSyndicationCacheInvalidate(t *testing.T) {
+	db, mock := redismock.NewClientMock()
+	cache := redisRepo.NewSyndicationCache(db)
+
+	mock.ExpectDel(
+		"syndication:all:rss", "syndication:all:atom", "syndication:all:json",
+		"syndication:tag:food:rss", "syndication:tag:food:atom", "syndication:tag:food:json",
+	).SetVal(2)
+
+	err := cache.Invalidate(context.Background(), domain.SyndicationScope{}, domain.SyndicationScope{Tag: "food"})
+
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
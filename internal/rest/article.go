@@ -19,13 +19,13 @@ type ResponseError struct {
 
 //go:generate mockery --name ArticleService
 type ArticleService interface {
-	Fetch(ctx context.Context, cursor string, num int64) ([]domain.Article, string, error)
-	GetByID(ctx context.Context, id int64) (domain.Article, error)
+	Fetch(ctx context.Context, cursor string, num int64, withContent bool) ([]domain.Article, string, error)
+	GetByID(ctx context.Context, id int64, viewer domain.Viewer) (domain.Article, error)
 	Update(ctx context.Context, ar *domain.Article) error
 	AddViews(ctx context.Context, id int64, newViews int64) error
 	GetByTitle(ctx context.Context, title string) (domain.Article, error)
 	Store(context.Context, *domain.Article) error
-	Delete(ctx context.Context, id int64) error
+	Delete(ctx context.Context, userID, id int64) error
 }
 
 // ArticleHandler  represent the httphandler for article
@@ -41,7 +41,8 @@ func NewArticleHandler(svc ArticleService) *ArticleHandler {
 	}
 }
 
-// GetByID will get article by given id
+// GetByID will get article by given id, the share query param carries the
+// token of a share link
 func (a *ArticleHandler) GetByID(c *gin.Context) {
 	idP, err := strconv.Atoi(c.Param("id"))
 	if err != nil {
@@ -51,7 +52,12 @@ func (a *ArticleHandler) GetByID(c *gin.Context) {
 	id := int64(idP)
 	ctx := c.Request.Context()
 
-	art, err := a.Service.GetByID(ctx, id)
+	viewer := domain.Viewer{ShareToken: c.Query("share")}
+	if userID, exists := c.Get("user_id"); exists {
+		viewer.UserID = userID.(int64)
+	}
+
+	art, err := a.Service.GetByID(ctx, id, viewer)
 	if err != nil {
 		c.JSON(getStatusCode(err), ResponseError{Message: err.Error()})
 		return
@@ -60,7 +66,8 @@ func (a *ArticleHandler) GetByID(c *gin.Context) {
 	c.JSON(http.StatusOK, response.NewArticleFromDomain(&art))
 }
 
-// FetchArticle will fetch the articles based on given params
+// FetchArticle will fetch the articles based on given params, as summaries
+// unless the content query param asks for their content
 func (a *ArticleHandler) FetchArticle(c *gin.Context) {
 	numS := c.Query("num")
 	num, err := strconv.Atoi(numS)
@@ -70,18 +77,27 @@ func (a *ArticleHandler) FetchArticle(c *gin.Context) {
 	}
 
 	cursor := c.Query("cursor")
+	withContent, _ := strconv.ParseBool(c.Query("content"))
 	ctx := c.Request.Context()
 
-	listAr, nextCursor, err := a.Service.Fetch(ctx, cursor, int64(num))
+	listAr, nextCursor, err := a.Service.Fetch(ctx, cursor, int64(num), withContent)
 	if err != nil {
 		c.JSON(getStatusCode(err), ResponseError{Message: err.Error()})
 		return
 	}
-	res := make([]response.Article, len(listAr))
+	c.Header(`X-cursor`, nextCursor)
+	if withContent {
+		res := make([]response.Article, len(listAr))
+		for i := range listAr {
+			res[i] = response.NewArticleFromDomain(&listAr[i])
+		}
+		c.JSON(http.StatusOK, res)
+		return
+	}
+	res := make([]response.ArticleSummary, len(listAr))
 	for i := range listAr {
-		res[i] = response.NewArticleFromDomain(&listAr[i])
+		res[i] = response.NewArticleSummaryFromDomain(&listAr[i])
 	}
-	c.Header(`X-cursor`, nextCursor)
 	c.JSON(http.StatusOK, res)
 }
 
@@ -103,13 +119,45 @@ func (a *ArticleHandler) Store(c *gin.Context) {
 
 	ctx := c.Request.Context()
 	if err := a.Service.Store(ctx, &article); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		c.JSON(getStatusCode(err), gin.H{"error": err.Error()})
 		return
 	}
 
 	c.JSON(http.StatusCreated, response.NewArticleFromDomain(&article))
 }
 
+// Update will update the article by given param and request body
+func (a *ArticleHandler) Update(c *gin.Context) {
+	idP, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, ResponseError{Message: domain.ErrNotFound.Error()})
+		return
+	}
+
+	var req request.Article
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		return
+	}
+	article := req.ToDomain()
+	article.ID = int64(idP)
+	article.User.ID = userID.(int64)
+
+	ctx := c.Request.Context()
+	if err := a.Service.Update(ctx, &article); err != nil {
+		c.JSON(getStatusCode(err), ResponseError{Message: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, response.NewArticleFromDomain(&article))
+}
+
 // Delete will delete the article by given param
 func (a *ArticleHandler) Delete(c *gin.Context) {
 	idP, err := strconv.Atoi(c.Param("id"))
@@ -118,8 +166,12 @@ func (a *ArticleHandler) Delete(c *gin.Context) {
 		return
 	}
 	id := int64(idP)
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
 
-	if err := a.Service.Delete(c.Request.Context(), id); err != nil {
+	if err := a.Service.Delete(c.Request.Context(), userID, id); err != nil {
 		c.JSON(getStatusCode(err), ResponseError{err.Error()})
 		return
 	}
@@ -139,8 +191,18 @@ func getStatusCode(err error) int {
 		return http.StatusInternalServerError
 	case domain.ErrNotFound:
 		return http.StatusNotFound
-	case domain.ErrConflict:
+	case domain.ErrConflict, domain.ErrDuplicateContent:
 		return http.StatusConflict
+	case domain.ErrBadParamInput:
+		return http.StatusBadRequest
+	case domain.ErrUnauthorized:
+		return http.StatusUnauthorized
+	case domain.ErrForbidden:
+		return http.StatusForbidden
+	case domain.ErrTooLarge, domain.ErrQuotaExceeded:
+		return http.StatusRequestEntityTooLarge
+	case domain.ErrUnsupportedMediaType:
+		return http.StatusUnsupportedMediaType
 	default:
 		return http.StatusInternalServerError
 	}
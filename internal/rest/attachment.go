@@ -0,0 +1,179 @@
+package rest
+
+import (
+	"context"
+	"errors"
+	"io"
+	"mime"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/bxcodec/go-clean-arch/domain"
+	"github.com/bxcodec/go-clean-arch/internal/rest/request"
+	"github.com/bxcodec/go-clean-arch/internal/rest/response"
+)
+
+type AttachmentService interface {
+	Upload(ctx context.Context, userID int64, filename string, r io.Reader) (domain.Attachment, error)
+	Open(ctx context.Context, userID int64, key string) (domain.AttachmentFile, error)
+	Usage(ctx context.Context, userID int64) (used, quota int64, err error)
+	Link(ctx context.Context, userID, articleID int64, ids []int64) ([]domain.Attachment, error)
+	Attachments(ctx context.Context, userID, articleID int64) ([]domain.Attachment, error)
+	Delete(ctx context.Context, userID, id int64) error
+}
+
+// AttachmentHandler represent the httphandler for uploaded files
+type AttachmentHandler struct {
+	Service AttachmentService
+}
+
+func NewAttachmentHandler(svc AttachmentService) *AttachmentHandler {
+	return &AttachmentHandler{
+		Service: svc,
+	}
+}
+
+// Upload will store the file part of a multipart request. The body is
+// streamed, the service enforces the size limit while reading it.
+func (h *AttachmentHandler) Upload(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+	reader, err := c.Request.MultipartReader()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	for {
+		part, err := reader.NextPart()
+		if errors.Is(err, io.EOF) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "missing file part"})
+			return
+		}
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		if part.FormName() != "file" {
+			part.Close()
+			continue
+		}
+
+		attachment, err := h.Service.Upload(c.Request.Context(), userID, part.FileName(), part)
+		part.Close()
+		if err != nil {
+			c.JSON(getStatusCode(err), ResponseError{Message: err.Error()})
+			return
+		}
+		c.JSON(http.StatusCreated, response.NewAttachmentFromDomain(&attachment))
+		return
+	}
+}
+
+// Serve will stream a stored file or thumbnail the current user may read.
+// Keys are random and never reused, so files of listed articles are cached
+// for good while the others are kept out of shared caches.
+func (h *AttachmentHandler) Serve(c *gin.Context) {
+	key := strings.TrimPrefix(c.Param("key"), "/")
+	var userID int64
+	if id, exists := c.Get("user_id"); exists {
+		userID = id.(int64)
+	}
+
+	file, err := h.Service.Open(c.Request.Context(), userID, key)
+	if err != nil {
+		c.JSON(getStatusCode(err), ResponseError{Message: err.Error()})
+		return
+	}
+	defer file.Content.Close()
+
+	disposition := "attachment"
+	if strings.HasPrefix(file.ContentType, "image/") {
+		disposition = "inline"
+	}
+	c.Header("Content-Disposition", mime.FormatMediaType(disposition, map[string]string{"filename": file.Attachment.Filename}))
+	c.Header("X-Content-Type-Options", "nosniff")
+	if file.Public {
+		c.Header("Cache-Control", "public, max-age=31536000, immutable")
+	} else {
+		c.Header("Cache-Control", "private, no-store")
+	}
+	c.DataFromReader(http.StatusOK, -1, file.ContentType, file.Content, nil)
+}
+
+// Usage will get the storage used by the current user and their quota
+func (h *AttachmentHandler) Usage(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+	used, quota, err := h.Service.Usage(c.Request.Context(), userID)
+	if err != nil {
+		c.JSON(getStatusCode(err), ResponseError{Message: err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, response.AttachmentUsage{Used: used, Quota: quota})
+}
+
+// Link will link uploaded attachments of the current user to an article
+func (h *AttachmentHandler) Link(c *gin.Context) {
+	articleID, ok := paramID(c, "id")
+	if !ok {
+		return
+	}
+	var req request.AttachmentLink
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	attachments, err := h.Service.Link(c.Request.Context(), userID, articleID, req.AttachmentIDs)
+	if err != nil {
+		c.JSON(getStatusCode(err), ResponseError{Message: err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, response.NewAttachmentsFromDomain(attachments))
+}
+
+// Fetch will list the attachments of an article
+func (h *AttachmentHandler) Fetch(c *gin.Context) {
+	articleID, ok := paramID(c, "id")
+	if !ok {
+		return
+	}
+	var userID int64
+	if id, exists := c.Get("user_id"); exists {
+		userID = id.(int64)
+	}
+
+	attachments, err := h.Service.Attachments(c.Request.Context(), userID, articleID)
+	if err != nil {
+		c.JSON(getStatusCode(err), ResponseError{Message: err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, response.NewAttachmentsFromDomain(attachments))
+}
+
+// Delete will delete an attachment of the current user with its files
+func (h *AttachmentHandler) Delete(c *gin.Context) {
+	id, ok := paramID(c, "id")
+	if !ok {
+		return
+	}
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+	if err := h.Service.Delete(c.Request.Context(), userID, id); err != nil {
+		c.JSON(getStatusCode(err), ResponseError{Message: err.Error()})
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
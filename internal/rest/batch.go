@@ -0,0 +1,48 @@
+package rest
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/bxcodec/go-clean-arch/domain"
+	"github.com/bxcodec/go-clean-arch/internal/rest/request"
+	"github.com/bxcodec/go-clean-arch/internal/rest/response"
+)
+
+type BatchService interface {
+	Batch(ctx context.Context, userID int64, ops []domain.BatchOperation, atomic bool) ([]domain.BatchResult, error)
+}
+
+// BatchHandler represent the httphandler for batch operations on articles
+type BatchHandler struct {
+	Service BatchService
+}
+
+func NewBatchHandler(svc BatchService) *BatchHandler {
+	return &BatchHandler{
+		Service: svc,
+	}
+}
+
+// Apply will apply operations on several articles and report the result of
+// each, the batch answers 200 even when some of its operations fail
+func (h *BatchHandler) Apply(c *gin.Context) {
+	var req request.Batch
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	results, err := h.Service.Batch(c.Request.Context(), userID, req.ToDomain(), req.Atomic)
+	if err != nil {
+		c.JSON(getStatusCode(err), ResponseError{Message: err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, response.NewBatchFromDomain(req.Atomic, results, getStatusCode))
+}
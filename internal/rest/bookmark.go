@@ -0,0 +1,259 @@
+package rest
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/bxcodec/go-clean-arch/domain"
+	"github.com/bxcodec/go-clean-arch/internal/rest/request"
+	"github.com/bxcodec/go-clean-arch/internal/rest/response"
+)
+
+type BookmarkService interface {
+	Bookmark(ctx context.Context, b *domain.Bookmark) error
+	Unbookmark(ctx context.Context, userID, articleID int64) error
+	MarkRead(ctx context.Context, userID, articleID int64, read bool) (domain.Bookmark, error)
+	Fetch(ctx context.Context, userID int64, filter domain.BookmarkFilter, cursor string, num int64) ([]domain.Bookmark, string, error)
+	FetchList(ctx context.Context, userID, listID int64, filter domain.BookmarkFilter, cursor string, num int64) ([]domain.Bookmark, string, error)
+	CreateList(ctx context.Context, l *domain.ReadingList) error
+	FetchLists(ctx context.Context, userID int64) ([]domain.ReadingList, error)
+	RenameList(ctx context.Context, l *domain.ReadingList) error
+	DeleteList(ctx context.Context, userID, listID int64) error
+	Reorder(ctx context.Context, userID, listID int64, articleIDs []int64) error
+}
+
+// BookmarkHandler represent the httphandler for bookmarks and reading lists
+type BookmarkHandler struct {
+	Service BookmarkService
+}
+
+func NewBookmarkHandler(svc BookmarkService) *BookmarkHandler {
+	return &BookmarkHandler{
+		Service: svc,
+	}
+}
+
+// Fetch will list the bookmarks of the current user, newest first
+func (h *BookmarkHandler) Fetch(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	bookmarks, nextCursor, err := h.Service.Fetch(c.Request.Context(), userID, bookmarkFilter(c), c.Query("cursor"), queryNum(c))
+	if err != nil {
+		c.JSON(getStatusCode(err), ResponseError{Message: err.Error()})
+		return
+	}
+	c.Header(`X-cursor`, nextCursor)
+	c.JSON(http.StatusOK, newBookmarksResponse(bookmarks))
+}
+
+// Store will bookmark an article, optionally into a reading list
+func (h *BookmarkHandler) Store(c *gin.Context) {
+	articleID, ok := paramID(c, "id")
+	if !ok {
+		return
+	}
+	var req request.Bookmark
+	// the body is optional
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+	}
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	bookmark := domain.Bookmark{UserID: userID, ArticleID: articleID, ListID: req.ListID}
+	if err := h.Service.Bookmark(c.Request.Context(), &bookmark); err != nil {
+		c.JSON(getStatusCode(err), ResponseError{Message: err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, response.NewBookmarkFromDomain(&bookmark))
+}
+
+// Delete will remove a bookmark of the current user
+func (h *BookmarkHandler) Delete(c *gin.Context) {
+	articleID, ok := paramID(c, "id")
+	if !ok {
+		return
+	}
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	if err := h.Service.Unbookmark(c.Request.Context(), userID, articleID); err != nil {
+		c.JSON(getStatusCode(err), ResponseError{Message: err.Error()})
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// MarkRead will mark a bookmark read or unread
+func (h *BookmarkHandler) MarkRead(c *gin.Context) {
+	articleID, ok := paramID(c, "id")
+	if !ok {
+		return
+	}
+	var req request.BookmarkRead
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	bookmark, err := h.Service.MarkRead(c.Request.Context(), userID, articleID, req.Read)
+	if err != nil {
+		c.JSON(getStatusCode(err), ResponseError{Message: err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, response.NewBookmarkFromDomain(&bookmark))
+}
+
+// FetchLists will list the reading lists of the current user
+func (h *BookmarkHandler) FetchLists(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	lists, err := h.Service.FetchLists(c.Request.Context(), userID)
+	if err != nil {
+		c.JSON(getStatusCode(err), ResponseError{Message: err.Error()})
+		return
+	}
+	res := make([]response.ReadingList, len(lists))
+	for i := range lists {
+		res[i] = response.NewReadingListFromDomain(&lists[i])
+	}
+	c.JSON(http.StatusOK, res)
+}
+
+// StoreList will create a reading list
+func (h *BookmarkHandler) StoreList(c *gin.Context) {
+	var req request.ReadingList
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	list := domain.ReadingList{UserID: userID, Name: req.Name}
+	if err := h.Service.CreateList(c.Request.Context(), &list); err != nil {
+		c.JSON(getStatusCode(err), ResponseError{Message: err.Error()})
+		return
+	}
+	c.JSON(http.StatusCreated, response.NewReadingListFromDomain(&list))
+}
+
+// UpdateList will rename a reading list
+func (h *BookmarkHandler) UpdateList(c *gin.Context) {
+	id, ok := paramID(c, "id")
+	if !ok {
+		return
+	}
+	var req request.ReadingList
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	list := domain.ReadingList{ID: id, UserID: userID, Name: req.Name}
+	if err := h.Service.RenameList(c.Request.Context(), &list); err != nil {
+		c.JSON(getStatusCode(err), ResponseError{Message: err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, response.NewReadingListFromDomain(&list))
+}
+
+// DeleteList will delete a reading list, its bookmarks are kept
+func (h *BookmarkHandler) DeleteList(c *gin.Context) {
+	id, ok := paramID(c, "id")
+	if !ok {
+		return
+	}
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	if err := h.Service.DeleteList(c.Request.Context(), userID, id); err != nil {
+		c.JSON(getStatusCode(err), ResponseError{Message: err.Error()})
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// FetchList will list the bookmarks of a reading list in list order
+func (h *BookmarkHandler) FetchList(c *gin.Context) {
+	id, ok := paramID(c, "id")
+	if !ok {
+		return
+	}
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	bookmarks, nextCursor, err := h.Service.FetchList(c.Request.Context(), userID, id, bookmarkFilter(c), c.Query("cursor"), queryNum(c))
+	if err != nil {
+		c.JSON(getStatusCode(err), ResponseError{Message: err.Error()})
+		return
+	}
+	c.Header(`X-cursor`, nextCursor)
+	c.JSON(http.StatusOK, newBookmarksResponse(bookmarks))
+}
+
+// Reorder will put the bookmarks of a reading list in the given order
+func (h *BookmarkHandler) Reorder(c *gin.Context) {
+	id, ok := paramID(c, "id")
+	if !ok {
+		return
+	}
+	var req request.ReadingListOrder
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	if err := h.Service.Reorder(c.Request.Context(), userID, id, req.ArticleIDs); err != nil {
+		c.JSON(getStatusCode(err), ResponseError{Message: err.Error()})
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+func bookmarkFilter(c *gin.Context) domain.BookmarkFilter {
+	unread, _ := strconv.ParseBool(c.Query("unread"))
+	return domain.BookmarkFilter{UnreadOnly: unread}
+}
+
+func newBookmarksResponse(bookmarks []domain.Bookmark) []response.Bookmark {
+	res := make([]response.Bookmark, len(bookmarks))
+	for i := range bookmarks {
+		res[i] = response.NewBookmarkFromDomain(&bookmarks[i])
+	}
+	return res
+}
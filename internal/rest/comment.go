@@ -0,0 +1,169 @@
+package rest
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/bxcodec/go-clean-arch/domain"
+	"github.com/bxcodec/go-clean-arch/internal/rest/request"
+	"github.com/bxcodec/go-clean-arch/internal/rest/response"
+)
+
+type CommentService interface {
+	Fetch(ctx context.Context, articleID int64, cursor string, num int64) ([]domain.Comment, string, error)
+	FetchForModeration(ctx context.Context, userID, articleID int64, status domain.CommentStatus, cursor string, num int64) ([]domain.Comment, string, error)
+	Store(ctx context.Context, c *domain.Comment) error
+	Update(ctx context.Context, c *domain.Comment) error
+	Delete(ctx context.Context, userID, id int64) error
+	Moderate(ctx context.Context, userID, id int64, status domain.CommentStatus) (domain.Comment, error)
+}
+
+// CommentHandler represent the httphandler for article comments
+type CommentHandler struct {
+	Service CommentService
+}
+
+func NewCommentHandler(svc CommentService) *CommentHandler {
+	return &CommentHandler{
+		Service: svc,
+	}
+}
+
+// Fetch will list the approved comment threads of an article
+func (h *CommentHandler) Fetch(c *gin.Context) {
+	articleID, ok := paramID(c, "id")
+	if !ok {
+		return
+	}
+
+	comments, nextCursor, err := h.Service.Fetch(c.Request.Context(), articleID, c.Query("cursor"), queryNum(c))
+	if err != nil {
+		c.JSON(getStatusCode(err), ResponseError{Message: err.Error()})
+		return
+	}
+	c.Header(`X-cursor`, nextCursor)
+	c.JSON(http.StatusOK, newCommentsResponse(comments))
+}
+
+// FetchForModeration will list the comments of an article in the requested state, pending by default
+func (h *CommentHandler) FetchForModeration(c *gin.Context) {
+	articleID, ok := paramID(c, "id")
+	if !ok {
+		return
+	}
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+	status := domain.CommentStatus(c.DefaultQuery("status", string(domain.CommentPending)))
+
+	comments, nextCursor, err := h.Service.FetchForModeration(c.Request.Context(), userID, articleID, status, c.Query("cursor"), queryNum(c))
+	if err != nil {
+		c.JSON(getStatusCode(err), ResponseError{Message: err.Error()})
+		return
+	}
+	c.Header(`X-cursor`, nextCursor)
+	c.JSON(http.StatusOK, newCommentsResponse(comments))
+}
+
+// Store will add a comment or a reply to an article
+func (h *CommentHandler) Store(c *gin.Context) {
+	articleID, ok := paramID(c, "id")
+	if !ok {
+		return
+	}
+	var req request.Comment
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	comment := req.ToDomain()
+	comment.ArticleID = articleID
+	comment.User.ID = userID
+	if err := h.Service.Store(c.Request.Context(), &comment); err != nil {
+		c.JSON(getStatusCode(err), ResponseError{Message: err.Error()})
+		return
+	}
+	c.JSON(http.StatusCreated, response.NewCommentFromDomain(&comment))
+}
+
+// Update will edit a comment of the current user
+func (h *CommentHandler) Update(c *gin.Context) {
+	id, ok := paramID(c, "id")
+	if !ok {
+		return
+	}
+	var req request.CommentEdit
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	comment := domain.Comment{ID: id, Content: req.Content, User: domain.User{ID: userID}}
+	if err := h.Service.Update(c.Request.Context(), &comment); err != nil {
+		c.JSON(getStatusCode(err), ResponseError{Message: err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, response.NewCommentFromDomain(&comment))
+}
+
+// Delete will delete a comment of the current user
+func (h *CommentHandler) Delete(c *gin.Context) {
+	id, ok := paramID(c, "id")
+	if !ok {
+		return
+	}
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	if err := h.Service.Delete(c.Request.Context(), userID, id); err != nil {
+		c.JSON(getStatusCode(err), ResponseError{Message: err.Error()})
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// Moderate will move a comment to another moderation state
+func (h *CommentHandler) Moderate(c *gin.Context) {
+	id, ok := paramID(c, "id")
+	if !ok {
+		return
+	}
+	var req request.CommentModeration
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	comment, err := h.Service.Moderate(c.Request.Context(), userID, id, domain.CommentStatus(req.Status))
+	if err != nil {
+		c.JSON(getStatusCode(err), ResponseError{Message: err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, response.NewCommentFromDomain(&comment))
+}
+
+func newCommentsResponse(comments []domain.Comment) []response.Comment {
+	res := make([]response.Comment, len(comments))
+	for i := range comments {
+		res[i] = response.NewCommentFromDomain(&comments[i])
+	}
+	return res
+}
@@ -0,0 +1,99 @@
+package rest
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/bxcodec/go-clean-arch/domain"
+	"github.com/bxcodec/go-clean-arch/internal/rest/request"
+	"github.com/bxcodec/go-clean-arch/internal/rest/response"
+)
+
+type ContributorService interface {
+	Contributors(ctx context.Context, articleID int64, viewer domain.Viewer) ([]domain.Contributor, error)
+	SetContributor(ctx context.Context, actorID, articleID, userID int64, role domain.ContributorRole) ([]domain.Contributor, error)
+	RemoveContributor(ctx context.Context, actorID, articleID, userID int64) error
+}
+
+// ContributorHandler represent the httphandler for article contributors
+type ContributorHandler struct {
+	Service ContributorService
+}
+
+func NewContributorHandler(svc ContributorService) *ContributorHandler {
+	return &ContributorHandler{
+		Service: svc,
+	}
+}
+
+// Fetch will list the contributors of an article, the author first. Like the
+// article itself they are hidden unless the viewer may read it.
+func (h *ContributorHandler) Fetch(c *gin.Context) {
+	articleID, ok := paramID(c, "id")
+	if !ok {
+		return
+	}
+	viewer := domain.Viewer{ShareToken: c.Query("share")}
+	if userID, exists := c.Get("user_id"); exists {
+		viewer.UserID = userID.(int64)
+	}
+
+	contributors, err := h.Service.Contributors(c.Request.Context(), articleID, viewer)
+	if err != nil {
+		c.JSON(getStatusCode(err), ResponseError{Message: err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, response.NewContributorsFromDomain(contributors))
+}
+
+// Store will add a contributor to an article or change its role
+func (h *ContributorHandler) Store(c *gin.Context) {
+	articleID, ok := paramID(c, "id")
+	if !ok {
+		return
+	}
+	userID, ok := paramID(c, "userID")
+	if !ok {
+		return
+	}
+	var req request.Contributor
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	actorID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	contributors, err := h.Service.SetContributor(c.Request.Context(), actorID, articleID, userID, domain.ContributorRole(req.Role))
+	if err != nil {
+		c.JSON(getStatusCode(err), ResponseError{Message: err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, response.NewContributorsFromDomain(contributors))
+}
+
+// Delete will remove a contributor from an article
+func (h *ContributorHandler) Delete(c *gin.Context) {
+	articleID, ok := paramID(c, "id")
+	if !ok {
+		return
+	}
+	userID, ok := paramID(c, "userID")
+	if !ok {
+		return
+	}
+	actorID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	if err := h.Service.RemoveContributor(c.Request.Context(), actorID, articleID, userID); err != nil {
+		c.JSON(getStatusCode(err), ResponseError{Message: err.Error()})
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
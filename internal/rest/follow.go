@@ -0,0 +1,115 @@
+package rest
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/bxcodec/go-clean-arch/domain"
+	"github.com/bxcodec/go-clean-arch/internal/rest/response"
+)
+
+type FeedService interface {
+	Follow(ctx context.Context, followerID, followeeID int64) error
+	Unfollow(ctx context.Context, followerID, followeeID int64) error
+	FetchFollowers(ctx context.Context, userID int64, cursor string, num int64) ([]domain.Follow, string, error)
+	FetchFollowing(ctx context.Context, userID int64, cursor string, num int64) ([]domain.Follow, string, error)
+	Feed(ctx context.Context, userID int64, cursor string, num int64) ([]domain.Article, string, error)
+}
+
+// FeedHandler represent the httphandler for follows and the home feed
+type FeedHandler struct {
+	Service FeedService
+}
+
+func NewFeedHandler(svc FeedService) *FeedHandler {
+	return &FeedHandler{
+		Service: svc,
+	}
+}
+
+// Follow will make the current user follow a user
+func (h *FeedHandler) Follow(c *gin.Context) {
+	id, ok := paramID(c, "id")
+	if !ok {
+		return
+	}
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	if err := h.Service.Follow(c.Request.Context(), userID, id); err != nil {
+		c.JSON(getStatusCode(err), ResponseError{Message: err.Error()})
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// Unfollow will make the current user stop following a user
+func (h *FeedHandler) Unfollow(c *gin.Context) {
+	id, ok := paramID(c, "id")
+	if !ok {
+		return
+	}
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	if err := h.Service.Unfollow(c.Request.Context(), userID, id); err != nil {
+		c.JSON(getStatusCode(err), ResponseError{Message: err.Error()})
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// Followers will list the followers of a user, newest first
+func (h *FeedHandler) Followers(c *gin.Context) {
+	h.listFollows(c, h.Service.FetchFollowers)
+}
+
+// Following will list the users a user follows, newest first
+func (h *FeedHandler) Following(c *gin.Context) {
+	h.listFollows(c, h.Service.FetchFollowing)
+}
+
+func (h *FeedHandler) listFollows(c *gin.Context, fetch func(context.Context, int64, string, int64) ([]domain.Follow, string, error)) {
+	id, ok := paramID(c, "id")
+	if !ok {
+		return
+	}
+
+	follows, nextCursor, err := fetch(c.Request.Context(), id, c.Query("cursor"), queryNum(c))
+	if err != nil {
+		c.JSON(getStatusCode(err), ResponseError{Message: err.Error()})
+		return
+	}
+	res := make([]response.Follow, len(follows))
+	for i := range follows {
+		res[i] = response.NewFollowFromDomain(&follows[i])
+	}
+	c.Header(`X-cursor`, nextCursor)
+	c.JSON(http.StatusOK, res)
+}
+
+// Feed will list the recent articles of the authors the current user follows
+func (h *FeedHandler) Feed(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	articles, nextCursor, err := h.Service.Feed(c.Request.Context(), userID, c.Query("cursor"), queryNum(c))
+	if err != nil {
+		c.JSON(getStatusCode(err), ResponseError{Message: err.Error()})
+		return
+	}
+	res := make([]response.ArticleSummary, len(articles))
+	for i := range articles {
+		res[i] = response.NewArticleSummaryFromDomain(&articles[i])
+	}
+	c.Header(`X-cursor`, nextCursor)
+	c.JSON(http.StatusOK, res)
+}
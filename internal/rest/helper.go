@@ -0,0 +1,38 @@
+package rest
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/bxcodec/go-clean-arch/domain"
+	"github.com/gin-gonic/gin"
+)
+
+// currentUserID returns the id set by the auth middleware, it answers 401 when missing
+func currentUserID(c *gin.Context) (int64, bool) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		return 0, false
+	}
+	return userID.(int64), true
+}
+
+// paramID parses a numeric path param, it answers 404 when it is not a number
+func paramID(c *gin.Context, name string) (int64, bool) {
+	id, err := strconv.ParseInt(c.Param(name), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusNotFound, ResponseError{Message: domain.ErrNotFound.Error()})
+		return 0, false
+	}
+	return id, true
+}
+
+// queryNum parses the page size query param, falling back to the default page size
+func queryNum(c *gin.Context) int64 {
+	num, err := strconv.ParseInt(c.Query("num"), 10, 64)
+	if err != nil || num == 0 {
+		return defaultNum
+	}
+	return num
+}
@@ -0,0 +1,23 @@
+package middleware
+
+import (
+	"net/http"
+	"slices"
+
+	"github.com/gin-gonic/gin"
+)
+
+// AdminMiddleware lets through the users authenticated by AuthMiddleware whose
+// id is one of adminIDs
+func AdminMiddleware(adminIDs []int64) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, _ := c.Get("user_id")
+		id, ok := userID.(int64)
+		if !ok || !slices.Contains(adminIDs, id) {
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "Admin access required"})
+			return
+		}
+
+		c.Next()
+	}
+}
@@ -0,0 +1,41 @@
+package middleware_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/bxcodec/go-clean-arch/internal/rest/middleware"
+)
+
+func TestAdminMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	for name, tc := range map[string]struct {
+		userID any
+		code   int
+	}{
+		"admin":     {int64(1), http.StatusOK},
+		"not admin": {int64(2), http.StatusForbidden},
+		"anonymous": {nil, http.StatusForbidden},
+	} {
+		t.Run(name, func(t *testing.T) {
+			r := gin.New()
+			r.Use(func(c *gin.Context) {
+				if tc.userID != nil {
+					c.Set("user_id", tc.userID)
+				}
+			}, middleware.AdminMiddleware([]int64{1}))
+			r.GET("/", func(c *gin.Context) {
+				c.Status(http.StatusOK)
+			})
+
+			rec := httptest.NewRecorder()
+			r.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+
+			assert.Equal(t, tc.code, rec.Code)
+		})
+	}
+}
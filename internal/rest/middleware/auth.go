@@ -1,6 +1,7 @@
 package middleware
 
 import (
+	"errors"
 	"net/http"
 	"strings"
 
@@ -8,42 +9,66 @@ import (
 	"github.com/golang-jwt/jwt/v5"
 )
 
+var (
+	errMissingAuthorization = errors.New("Authorization header is required")
+	errInvalidAuthorization = errors.New("Invalid authorization format")
+	errInvalidToken         = errors.New("Invalid token")
+)
+
 // AuthMiddleware is a Gin middleware for JWT authentication
 func AuthMiddleware(secret string) gin.HandlerFunc {
 	return func(c *gin.Context) {
-		authHeader := c.GetHeader("Authorization")
-		if authHeader == "" {
-			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Authorization header is required"})
+		if err := authenticate(c, secret); err != nil {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
 			return
 		}
 
-		parts := strings.Split(authHeader, " ")
-		if len(parts) != 2 || parts[0] != "Bearer" {
-			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Invalid authorization format"})
-			return
-		}
-		tokenString := parts[1]
+		c.Next()
+	}
+}
 
-		token, err := jwt.Parse(tokenString, func(token *jwt.Token) (any, error) {
+// OptionalAuthMiddleware sets the user of a valid token like AuthMiddleware but
+// lets anonymous requests and invalid tokens through without a user
+func OptionalAuthMiddleware(secret string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		_ = authenticate(c, secret)
+		c.Next()
+	}
+}
 
-			if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-				return nil, jwt.ErrTokenMalformed
-			}
+func authenticate(c *gin.Context, secret string) error {
+	authHeader := c.GetHeader("Authorization")
+	if authHeader == "" {
+		return errMissingAuthorization
+	}
 
-			return []byte(secret), nil
-		})
+	parts := strings.Split(authHeader, " ")
+	if len(parts) != 2 || parts[0] != "Bearer" {
+		return errInvalidAuthorization
+	}
+	tokenString := parts[1]
 
-		if err != nil || !token.Valid {
-			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Invalid token"})
-			return
-		}
+	token, err := jwt.Parse(tokenString, func(token *jwt.Token) (any, error) {
 
-		if claims, ok := token.Claims.(jwt.MapClaims); ok {
-			if userID, ok := claims["user_id"].(float64); ok {
-				c.Set("user_id", int64(userID))
-			}
+		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
+			return nil, jwt.ErrTokenMalformed
 		}
 
-		c.Next()
+		return []byte(secret), nil
+	})
+
+	if err != nil || !token.Valid {
+		return errInvalidToken
+	}
+
+	if claims, ok := token.Claims.(jwt.MapClaims); ok {
+		// tokens issued for one purpose, like a password reset, do not sign in
+		if _, ok := claims["purpose"]; ok {
+			return errInvalidToken
+		}
+		if userID, ok := claims["user_id"].(float64); ok {
+			c.Set("user_id", int64(userID))
+		}
 	}
+	return nil
 }
@@ -0,0 +1,44 @@
+package middleware_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bxcodec/go-clean-arch/internal/rest/middleware"
+)
+
+func TestAuthMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	for name, tc := range map[string]struct {
+		claims jwt.MapClaims
+		code   int
+	}{
+		"login token": {jwt.MapClaims{"user_id": 7}, http.StatusOK},
+		"reset token": {jwt.MapClaims{"user_id": 7, "purpose": "password_reset"}, http.StatusUnauthorized},
+	} {
+		t.Run(name, func(t *testing.T) {
+			token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, tc.claims).SignedString([]byte("secret"))
+			require.NoError(t, err)
+
+			r := gin.New()
+			r.Use(middleware.AuthMiddleware("secret"))
+			r.GET("/", func(c *gin.Context) {
+				assert.Equal(t, int64(7), c.GetInt64("user_id"))
+				c.Status(http.StatusOK)
+			})
+
+			rec := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			req.Header.Set("Authorization", "Bearer "+token)
+			r.ServeHTTP(rec, req)
+
+			assert.Equal(t, tc.code, rec.Code)
+		})
+	}
+}
@@ -7,9 +7,19 @@ import (
 	"github.com/gin-gonic/gin"
 )
 
-// SetRequestContextWithTimeout will set the request context with timeout for every incoming HTTP Request
-func SetRequestContextWithTimeout(d time.Duration) gin.HandlerFunc {
+// SetRequestContextWithTimeout will set the request context with timeout for every incoming HTTP Request.
+// Requests matching one of the exempt route paths, such as long lived streams, keep their original context.
+func SetRequestContextWithTimeout(d time.Duration, exempt ...string) gin.HandlerFunc {
+	skip := make(map[string]bool, len(exempt))
+	for _, path := range exempt {
+		skip[path] = true
+	}
 	return func(c *gin.Context) {
+		if skip[c.FullPath()] {
+			c.Next()
+			return
+		}
+
 		ctx, cancel := context.WithTimeout(c.Request.Context(), d)
 		defer cancel()
 
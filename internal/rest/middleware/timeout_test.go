@@ -0,0 +1,37 @@
+package middleware_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/bxcodec/go-clean-arch/internal/rest/middleware"
+)
+
+func TestSetRequestContextWithTimeout(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(middleware.SetRequestContextWithTimeout(time.Minute, "/stream/:id"))
+
+	deadline := func(c *gin.Context) {
+		if _, ok := c.Request.Context().Deadline(); ok {
+			c.Status(http.StatusOK)
+			return
+		}
+		c.Status(http.StatusNoContent)
+	}
+	r.GET("/articles", deadline)
+	r.GET("/stream/:id", deadline)
+
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/articles", nil))
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	rec = httptest.NewRecorder()
+	r.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/stream/1", nil))
+	assert.Equal(t, http.StatusNoContent, rec.Code)
+}
@@ -14,17 +14,17 @@ type ArticleService struct {
 	mock.Mock
 }
 
-// Delete provides a mock function with given fields: ctx, id
-func (_m *ArticleService) Delete(ctx context.Context, id int64) error {
-	ret := _m.Called(ctx, id)
+// Delete provides a mock function with given fields: ctx, userID, id
+func (_m *ArticleService) Delete(ctx context.Context, userID int64, id int64) error {
+	ret := _m.Called(ctx, userID, id)
 
 	if len(ret) == 0 {
 		panic("no return value specified for Delete")
 	}
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(context.Context, int64) error); ok {
-		r0 = rf(ctx, id)
+	if rf, ok := ret.Get(0).(func(context.Context, int64, int64) error); ok {
+		r0 = rf(ctx, userID, id)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -32,9 +32,9 @@ func (_m *ArticleService) Delete(ctx context.Context, id int64) error {
 	return r0
 }
 
-// Fetch provides a mock function with given fields: ctx, cursor, num
-func (_m *ArticleService) Fetch(ctx context.Context, cursor string, num int64) ([]domain.Article, string, error) {
-	ret := _m.Called(ctx, cursor, num)
+// Fetch provides a mock function with given fields: ctx, cursor, num, withContent
+func (_m *ArticleService) Fetch(ctx context.Context, cursor string, num int64, withContent bool) ([]domain.Article, string, error) {
+	ret := _m.Called(ctx, cursor, num, withContent)
 
 	if len(ret) == 0 {
 		panic("no return value specified for Fetch")
@@ -43,25 +43,25 @@ func (_m *ArticleService) Fetch(ctx context.Context, cursor string, num int64) (
 	var r0 []domain.Article
 	var r1 string
 	var r2 error
-	if rf, ok := ret.Get(0).(func(context.Context, string, int64) ([]domain.Article, string, error)); ok {
-		return rf(ctx, cursor, num)
+	if rf, ok := ret.Get(0).(func(context.Context, string, int64, bool) ([]domain.Article, string, error)); ok {
+		return rf(ctx, cursor, num, withContent)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, string, int64) []domain.Article); ok {
-		r0 = rf(ctx, cursor, num)
+	if rf, ok := ret.Get(0).(func(context.Context, string, int64, bool) []domain.Article); ok {
+		r0 = rf(ctx, cursor, num, withContent)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).([]domain.Article)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context, string, int64) string); ok {
-		r1 = rf(ctx, cursor, num)
+	if rf, ok := ret.Get(1).(func(context.Context, string, int64, bool) string); ok {
+		r1 = rf(ctx, cursor, num, withContent)
 	} else {
 		r1 = ret.Get(1).(string)
 	}
 
-	if rf, ok := ret.Get(2).(func(context.Context, string, int64) error); ok {
-		r2 = rf(ctx, cursor, num)
+	if rf, ok := ret.Get(2).(func(context.Context, string, int64, bool) error); ok {
+		r2 = rf(ctx, cursor, num, withContent)
 	} else {
 		r2 = ret.Error(2)
 	}
@@ -69,9 +69,9 @@ func (_m *ArticleService) Fetch(ctx context.Context, cursor string, num int64) (
 	return r0, r1, r2
 }
 
-// GetByID provides a mock function with given fields: ctx, id
-func (_m *ArticleService) GetByID(ctx context.Context, id int64) (domain.Article, error) {
-	ret := _m.Called(ctx, id)
+// GetByID provides a mock function with given fields: ctx, id, viewer
+func (_m *ArticleService) GetByID(ctx context.Context, id int64, viewer domain.Viewer) (domain.Article, error) {
+	ret := _m.Called(ctx, id, viewer)
 
 	if len(ret) == 0 {
 		panic("no return value specified for GetByID")
@@ -79,17 +79,17 @@ func (_m *ArticleService) GetByID(ctx context.Context, id int64) (domain.Article
 
 	var r0 domain.Article
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, int64) (domain.Article, error)); ok {
-		return rf(ctx, id)
+	if rf, ok := ret.Get(0).(func(context.Context, int64, domain.Viewer) (domain.Article, error)); ok {
+		return rf(ctx, id, viewer)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, int64) domain.Article); ok {
-		r0 = rf(ctx, id)
+	if rf, ok := ret.Get(0).(func(context.Context, int64, domain.Viewer) domain.Article); ok {
+		r0 = rf(ctx, id, viewer)
 	} else {
 		r0 = ret.Get(0).(domain.Article)
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context, int64) error); ok {
-		r1 = rf(ctx, id)
+	if rf, ok := ret.Get(1).(func(context.Context, int64, domain.Viewer) error); ok {
+		r1 = rf(ctx, id, viewer)
 	} else {
 		r1 = ret.Error(1)
 	}
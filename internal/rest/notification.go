@@ -0,0 +1,148 @@
+package rest
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/bxcodec/go-clean-arch/domain"
+	"github.com/bxcodec/go-clean-arch/internal/rest/request"
+	"github.com/bxcodec/go-clean-arch/internal/rest/response"
+)
+
+type NotificationService interface {
+	Fetch(ctx context.Context, userID int64, unreadOnly bool, cursor string, num int64) ([]domain.Notification, string, error)
+	CountUnread(ctx context.Context, userID int64) (int64, error)
+	MarkRead(ctx context.Context, userID, id int64) error
+	MarkAllRead(ctx context.Context, userID int64) (int64, error)
+	Preferences(ctx context.Context, userID int64) (map[domain.NotificationType]bool, error)
+	SetPreferences(ctx context.Context, userID int64, prefs map[domain.NotificationType]bool) (map[domain.NotificationType]bool, error)
+}
+
+// NotificationHandler represent the httphandler for the notification inbox
+type NotificationHandler struct {
+	Service NotificationService
+}
+
+func NewNotificationHandler(svc NotificationService) *NotificationHandler {
+	return &NotificationHandler{
+		Service: svc,
+	}
+}
+
+// Fetch will list the notifications of the current user, the unread count is
+// returned in the X-unread-count header
+func (h *NotificationHandler) Fetch(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+	unreadOnly, _ := strconv.ParseBool(c.Query("unread"))
+
+	ctx := c.Request.Context()
+	notifications, nextCursor, err := h.Service.Fetch(ctx, userID, unreadOnly, c.Query("cursor"), queryNum(c))
+	if err != nil {
+		c.JSON(getStatusCode(err), ResponseError{Message: err.Error()})
+		return
+	}
+	unread, err := h.Service.CountUnread(ctx, userID)
+	if err != nil {
+		c.JSON(getStatusCode(err), ResponseError{Message: err.Error()})
+		return
+	}
+	res := make([]response.Notification, len(notifications))
+	for i := range notifications {
+		res[i] = response.NewNotificationFromDomain(&notifications[i])
+	}
+	c.Header(`X-cursor`, nextCursor)
+	c.Header(`X-unread-count`, strconv.FormatInt(unread, 10))
+	c.JSON(http.StatusOK, res)
+}
+
+// UnreadCount will return the number of unread notifications of the current user
+func (h *NotificationHandler) UnreadCount(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	unread, err := h.Service.CountUnread(c.Request.Context(), userID)
+	if err != nil {
+		c.JSON(getStatusCode(err), ResponseError{Message: err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, response.UnreadNotifications{Unread: unread})
+}
+
+// MarkRead will mark a notification of the current user read
+func (h *NotificationHandler) MarkRead(c *gin.Context) {
+	id, ok := paramID(c, "id")
+	if !ok {
+		return
+	}
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	if err := h.Service.MarkRead(c.Request.Context(), userID, id); err != nil {
+		c.JSON(getStatusCode(err), ResponseError{Message: err.Error()})
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// MarkAllRead will mark every notification of the current user read
+func (h *NotificationHandler) MarkAllRead(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	if _, err := h.Service.MarkAllRead(c.Request.Context(), userID); err != nil {
+		c.JSON(getStatusCode(err), ResponseError{Message: err.Error()})
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// Preferences will return which notification types the current user receives
+func (h *NotificationHandler) Preferences(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	prefs, err := h.Service.Preferences(c.Request.Context(), userID)
+	if err != nil {
+		c.JSON(getStatusCode(err), ResponseError{Message: err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, prefs)
+}
+
+// UpdatePreferences will enable or disable notification types for the current user
+func (h *NotificationHandler) UpdatePreferences(c *gin.Context) {
+	var req request.NotificationPreferences
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	prefs := make(map[domain.NotificationType]bool, len(req))
+	for t, enabled := range req {
+		prefs[domain.NotificationType(t)] = enabled
+	}
+	res, err := h.Service.SetPreferences(c.Request.Context(), userID, prefs)
+	if err != nil {
+		c.JSON(getStatusCode(err), ResponseError{Message: err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, res)
+}
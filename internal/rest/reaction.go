@@ -0,0 +1,77 @@
+package rest
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/bxcodec/go-clean-arch/domain"
+	"github.com/bxcodec/go-clean-arch/internal/rest/response"
+)
+
+type ReactionService interface {
+	React(ctx context.Context, r *domain.Reaction) (domain.ReactionSummary, error)
+	Unreact(ctx context.Context, r *domain.Reaction) (domain.ReactionSummary, error)
+	Summary(ctx context.Context, articleID, userID int64) (domain.ReactionSummary, error)
+}
+
+// ReactionHandler represent the httphandler for article reactions
+type ReactionHandler struct {
+	Service ReactionService
+}
+
+func NewReactionHandler(svc ReactionService) *ReactionHandler {
+	return &ReactionHandler{
+		Service: svc,
+	}
+}
+
+// Summary will get the reaction counts of an article, with the reactions of the
+// current user when the request is authenticated
+func (h *ReactionHandler) Summary(c *gin.Context) {
+	articleID, ok := paramID(c, "id")
+	if !ok {
+		return
+	}
+	var userID int64
+	if id, exists := c.Get("user_id"); exists {
+		userID = id.(int64)
+	}
+
+	summary, err := h.Service.Summary(c.Request.Context(), articleID, userID)
+	if err != nil {
+		c.JSON(getStatusCode(err), ResponseError{Message: err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, response.NewReactionSummaryFromDomain(&summary))
+}
+
+// React will add a reaction of the current user to an article
+func (h *ReactionHandler) React(c *gin.Context) {
+	h.change(c, h.Service.React)
+}
+
+// Unreact will remove a reaction of the current user from an article
+func (h *ReactionHandler) Unreact(c *gin.Context) {
+	h.change(c, h.Service.Unreact)
+}
+
+func (h *ReactionHandler) change(c *gin.Context, apply func(context.Context, *domain.Reaction) (domain.ReactionSummary, error)) {
+	articleID, ok := paramID(c, "id")
+	if !ok {
+		return
+	}
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	reaction := domain.Reaction{ArticleID: articleID, UserID: userID, Kind: c.Param("kind")}
+	summary, err := apply(c.Request.Context(), &reaction)
+	if err != nil {
+		c.JSON(getStatusCode(err), ResponseError{Message: err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, response.NewReactionSummaryFromDomain(&summary))
+}
@@ -0,0 +1,41 @@
+package rest
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/bxcodec/go-clean-arch/domain"
+	"github.com/bxcodec/go-clean-arch/internal/rest/response"
+)
+
+type RelatedService interface {
+	Related(ctx context.Context, articleID int64, num int64) ([]domain.RelatedArticle, error)
+}
+
+// RelatedHandler represent the httphandler for related articles
+type RelatedHandler struct {
+	Service RelatedService
+}
+
+func NewRelatedHandler(svc RelatedService) *RelatedHandler {
+	return &RelatedHandler{
+		Service: svc,
+	}
+}
+
+// Fetch will list the articles recommended next to an article, the best first
+func (h *RelatedHandler) Fetch(c *gin.Context) {
+	articleID, ok := paramID(c, "id")
+	if !ok {
+		return
+	}
+
+	related, err := h.Service.Related(c.Request.Context(), articleID, queryNum(c))
+	if err != nil {
+		c.JSON(getStatusCode(err), ResponseError{Message: err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, response.NewRelatedArticlesFromDomain(related))
+}
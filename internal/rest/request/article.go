@@ -8,12 +8,21 @@ import (
 type Article struct {
 	Title   string `json:"title" binding:"required"`
 	Content string `json:"content" binding:"required"`
+	// ContentFormat is either "markdown" or "html", it defaults to "markdown"
+	ContentFormat string `json:"content_format" binding:"omitempty,oneof=markdown html"`
+	// Status is either "draft" or "published", it defaults to "published"
+	Status string `json:"status" binding:"omitempty,oneof=draft published"`
+	// Visibility is "public", "unlisted" or "private", it defaults to "public"
+	Visibility string `json:"visibility" binding:"omitempty,oneof=public unlisted private"`
 }
 
 // ToDomain: Request -> Domain
 func (r *Article) ToDomain() domain.Article {
 	return domain.Article{
-		Title:   r.Title,
-		Content: r.Content,
+		Title:         r.Title,
+		Content:       r.Content,
+		ContentFormat: domain.ContentFormat(r.ContentFormat),
+		Status:        domain.ArticleStatus(r.Status),
+		Visibility:    domain.ArticleVisibility(r.Visibility),
 	}
 }
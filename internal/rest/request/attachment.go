@@ -0,0 +1,6 @@
+package request
+
+// AttachmentLink is the request payload for linking uploaded attachments to an article
+type AttachmentLink struct {
+	AttachmentIDs []int64 `json:"attachment_ids" binding:"required,min=1,max=100"`
+}
@@ -0,0 +1,47 @@
+package request
+
+import (
+	"github.com/bxcodec/go-clean-arch/domain"
+)
+
+// Batch is the request payload for applying operations on several articles
+type Batch struct {
+	// Atomic applies either every operation or none of them
+	Atomic     bool             `json:"atomic"`
+	Operations []BatchOperation `json:"operations" binding:"required,min=1,max=100,dive"`
+}
+
+// BatchOperation is one operation of a batch, the fields other than the action
+// and the article id only apply to updates where empty fields are left alone
+type BatchOperation struct {
+	// Action is "update", "delete" or "publish"
+	Action        string `json:"action" binding:"required,oneof=update delete publish"`
+	ArticleID     int64  `json:"article_id" binding:"required"`
+	Title         string `json:"title"`
+	Content       string `json:"content"`
+	ContentFormat string `json:"content_format" binding:"omitempty,oneof=markdown html"`
+	Status        string `json:"status" binding:"omitempty,oneof=draft published"`
+	Visibility    string `json:"visibility" binding:"omitempty,oneof=public unlisted private"`
+	// Tags replaces the tags of the article when present, an empty list removes them
+	Tags []string `json:"tags"`
+}
+
+// ToDomain: Request -> Domain
+func (r *Batch) ToDomain() []domain.BatchOperation {
+	res := make([]domain.BatchOperation, len(r.Operations))
+	for i, op := range r.Operations {
+		res[i] = domain.BatchOperation{
+			Action:    domain.BatchAction(op.Action),
+			ArticleID: op.ArticleID,
+			Changes: domain.Article{
+				Title:         op.Title,
+				Content:       op.Content,
+				ContentFormat: domain.ContentFormat(op.ContentFormat),
+				Status:        domain.ArticleStatus(op.Status),
+				Visibility:    domain.ArticleVisibility(op.Visibility),
+			},
+			Tags: op.Tags,
+		}
+	}
+	return res
+}
@@ -0,0 +1,22 @@
+package request
+
+// Bookmark is the request payload for bookmarking an article
+type Bookmark struct {
+	// ListID is the reading list to put the bookmark in, it is empty to keep it out of lists
+	ListID int64 `json:"list_id"`
+}
+
+// BookmarkRead is the request payload for marking a bookmark read or unread
+type BookmarkRead struct {
+	Read bool `json:"read"`
+}
+
+// ReadingList is the request payload for creating or renaming a reading list
+type ReadingList struct {
+	Name string `json:"name" binding:"required,max=100"`
+}
+
+// ReadingListOrder is the request payload for reordering a reading list
+type ReadingListOrder struct {
+	ArticleIDs []int64 `json:"article_ids" binding:"required"`
+}
@@ -0,0 +1,27 @@
+package request
+
+import "github.com/bxcodec/go-clean-arch/domain"
+
+// Comment is the request payload for creating a comment or a reply
+type Comment struct {
+	Content string `json:"content" binding:"required"`
+	// ParentID is the comment being replied to, it is empty for top level comments
+	ParentID int64 `json:"parent_id"`
+}
+
+func (r *Comment) ToDomain() domain.Comment {
+	return domain.Comment{
+		Content:  r.Content,
+		ParentID: r.ParentID,
+	}
+}
+
+// CommentEdit is the request payload for editing a comment
+type CommentEdit struct {
+	Content string `json:"content" binding:"required"`
+}
+
+// CommentModeration is the request payload for moving a comment to another moderation state
+type CommentModeration struct {
+	Status string `json:"status" binding:"required,oneof=pending approved rejected spam"`
+}
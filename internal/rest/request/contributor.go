@@ -0,0 +1,7 @@
+package request
+
+// Contributor is the request payload for adding a contributor to an article or changing its role
+type Contributor struct {
+	// Role is one of "co-author", "editor" or "reviewer"
+	Role string `json:"role" binding:"required,oneof=co-author editor reviewer"`
+}
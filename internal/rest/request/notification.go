@@ -0,0 +1,5 @@
+package request
+
+// NotificationPreferences is the request payload for enabling or disabling
+// notification types, types left out keep their current setting
+type NotificationPreferences map[string]bool
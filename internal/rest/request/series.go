@@ -0,0 +1,26 @@
+package request
+
+import "github.com/bxcodec/go-clean-arch/domain"
+
+// Series is the request payload for creating or updating a series
+type Series struct {
+	Title       string `json:"title" binding:"required,max=100"`
+	Description string `json:"description" binding:"max=2000"`
+	CoverURL    string `json:"cover_url" binding:"omitempty,url,max=255"`
+	CoverAlt    string `json:"cover_alt" binding:"max=255"`
+}
+
+// ToDomain: Request -> Domain
+func (r *Series) ToDomain() domain.Series {
+	return domain.Series{
+		Title:       r.Title,
+		Description: r.Description,
+		CoverURL:    r.CoverURL,
+		CoverAlt:    r.CoverAlt,
+	}
+}
+
+// SeriesArticles is the request payload for setting the articles of a series in order
+type SeriesArticles struct {
+	ArticleIDs []int64 `json:"article_ids" binding:"required"`
+}
@@ -0,0 +1,7 @@
+package request
+
+// ShareLink is the request payload for creating a share link
+type ShareLink struct {
+	// ExpiresIn is the lifetime of the link in seconds, it defaults to 7 days and is at most 30 days
+	ExpiresIn int64 `json:"expires_in" binding:"omitempty,min=1"`
+}
@@ -15,3 +15,8 @@ func (a *User) ToDomain() domain.User {
 		Password: a.Password,
 	}
 }
+
+type PasswordReset struct {
+	Token    string `json:"token" binding:"required"`
+	Password string `json:"password" binding:"required"`
+}
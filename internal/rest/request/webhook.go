@@ -0,0 +1,30 @@
+package request
+
+import "github.com/bxcodec/go-clean-arch/domain"
+
+// WebhookSubscription is the request payload for creating or updating a webhook subscription
+type WebhookSubscription struct {
+	URL string `json:"url" binding:"required,url"`
+	// Secret is only read on creation, one is generated when it is empty
+	Secret string   `json:"secret"`
+	Events []string `json:"events"`
+	// Active defaults to true
+	Active *bool `json:"active"`
+}
+
+func (r *WebhookSubscription) ToDomain() domain.WebhookSubscription {
+	events := make([]domain.ArticleEventType, len(r.Events))
+	for i, e := range r.Events {
+		events[i] = domain.ArticleEventType(e)
+	}
+	active := true
+	if r.Active != nil {
+		active = *r.Active
+	}
+	return domain.WebhookSubscription{
+		URL:    r.URL,
+		Secret: r.Secret,
+		Events: events,
+		Active: active,
+	}
+}
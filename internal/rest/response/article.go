@@ -4,25 +4,97 @@ import (
 	"github.com/bxcodec/go-clean-arch/domain"
 )
 
+// ArticleSummary is the representation of an article in listings, without its content
+type ArticleSummary struct {
+	ID             int64         `json:"id"`
+	Title          string        `json:"title"`
+	Excerpt        string        `json:"excerpt"`
+	WordCount      int64         `json:"word_count"`
+	ReadingMinutes int64         `json:"reading_minutes"`
+	Status         string        `json:"status"`
+	Visibility     string        `json:"visibility"`
+	UserName       string        `json:"user_name"`
+	UpdatedAt      string        `json:"updated_at"`
+	CreatedAt      string        `json:"created_at"`
+	Views          int64         `json:"views"`
+	CommentCount   int64         `json:"comment_count"`
+	Contributors   []Contributor `json:"contributors"`
+}
+
 type Article struct {
-	ID        int64  `json:"id"`
-	Title     string `json:"title"`
-	Content   string `json:"content"`
-	UserName  string `json:"user_name"`
-	UpdatedAt string `json:"updated_at"`
-	CreatedAt string `json:"created_at"`
-	Views     int64  `json:"views"`
+	ArticleSummary
+	// Content is the sanitized HTML of the article
+	Content       string     `json:"content"`
+	ContentFormat string     `json:"content_format"`
+	TOC           []TOCEntry `json:"toc"`
+	// Series is only set on single article reads of articles in a series
+	Series *SeriesNavigation `json:"series,omitempty"`
+	// SimilarContent lists the near duplicates found when the content was stored or updated
+	SimilarContent []SimilarArticle `json:"similar_content,omitempty"`
+}
+
+type TOCEntry struct {
+	Level int    `json:"level"`
+	ID    string `json:"id"`
+	Text  string `json:"text"`
+}
+
+func NewArticleSummaryFromDomain(a *domain.Article) ArticleSummary {
+	visibility := a.Visibility
+	if visibility == "" {
+		visibility = domain.ArticleVisibilityPublic
+	}
+	return ArticleSummary{
+		ID:             a.ID,
+		Title:          a.Title,
+		Excerpt:        a.Excerpt,
+		WordCount:      a.WordCount,
+		ReadingMinutes: a.ReadingMinutes,
+		Status:         string(a.Status),
+		Visibility:     string(visibility),
+		UserName:       a.User.Name,
+		UpdatedAt:      a.UpdatedAt.Format("2006-01-02 15:04:05"),
+		CreatedAt:      a.CreatedAt.Format("2006-01-02 15:04:05"),
+		Views:          a.Views,
+		CommentCount:   a.CommentCount,
+		Contributors:   NewContributorsFromDomain(a.Contributors),
+	}
 }
 
 // FromDomain: Domain -> Response
 func NewArticleFromDomain(a *domain.Article) Article {
+	toc := make([]TOCEntry, len(a.TOC))
+	for i, e := range a.TOC {
+		toc[i] = TOCEntry{Level: e.Level, ID: e.ID, Text: e.Text}
+	}
 	return Article{
-		ID:        a.ID,
-		Title:     a.Title,
-		Content:   a.Content,
-		UserName:  a.User.Name,
-		UpdatedAt: a.UpdatedAt.Format("2006-01-02 15:04:05"),
-		CreatedAt: a.CreatedAt.Format("2006-01-02 15:04:05"),
-		Views:     a.Views,
+		ArticleSummary: NewArticleSummaryFromDomain(a),
+		Content:        a.ContentHTML,
+		ContentFormat:  string(a.ContentFormat),
+		TOC:            toc,
+		Series:         NewSeriesNavigationFromDomain(a.Series),
+		SimilarContent: NewSimilarArticlesFromDomain(a.SimilarContent),
+	}
+}
+
+// ArticleSource is the content of an article as written, it is only returned
+// to the users who may edit the article and must never be rendered as is
+type ArticleSource struct {
+	ID            int64  `json:"id"`
+	Source        string `json:"source"`
+	ContentFormat string `json:"content_format"`
+	UpdatedAt     string `json:"updated_at"`
+}
+
+func NewArticleSourceFromDomain(a *domain.Article) ArticleSource {
+	format := a.ContentFormat
+	if !format.Valid() {
+		format = domain.ContentFormatHTML
+	}
+	return ArticleSource{
+		ID:            a.ID,
+		Source:        a.Content,
+		ContentFormat: string(format),
+		UpdatedAt:     a.UpdatedAt.Format("2006-01-02 15:04:05"),
 	}
 }
@@ -0,0 +1,48 @@
+package response
+
+import "github.com/bxcodec/go-clean-arch/domain"
+
+type Attachment struct {
+	ID          int64  `json:"id"`
+	ArticleID   int64  `json:"article_id,omitempty"`
+	Filename    string `json:"filename"`
+	ContentType string `json:"content_type"`
+	Size        int64  `json:"size"`
+	Width       int    `json:"width,omitempty"`
+	Height      int    `json:"height,omitempty"`
+	// URL and ThumbnailURL are paths relative to the API root
+	URL          string `json:"url"`
+	ThumbnailURL string `json:"thumbnail_url,omitempty"`
+	CreatedAt    string `json:"created_at"`
+}
+
+func NewAttachmentFromDomain(a *domain.Attachment) Attachment {
+	res := Attachment{
+		ID:          a.ID,
+		ArticleID:   a.ArticleID,
+		Filename:    a.Filename,
+		ContentType: a.ContentType,
+		Size:        a.Size,
+		Width:       a.Width,
+		Height:      a.Height,
+		URL:         "/files/" + a.Key,
+		CreatedAt:   a.CreatedAt.Format("2006-01-02 15:04:05"),
+	}
+	if a.ThumbnailKey != "" {
+		res.ThumbnailURL = "/files/" + a.ThumbnailKey
+	}
+	return res
+}
+
+func NewAttachmentsFromDomain(attachments []domain.Attachment) []Attachment {
+	res := make([]Attachment, len(attachments))
+	for i := range attachments {
+		res[i] = NewAttachmentFromDomain(&attachments[i])
+	}
+	return res
+}
+
+type AttachmentUsage struct {
+	Used  int64 `json:"used"`
+	Quota int64 `json:"quota"`
+}
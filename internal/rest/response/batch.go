@@ -0,0 +1,48 @@
+package response
+
+import (
+	"net/http"
+
+	"github.com/bxcodec/go-clean-arch/domain"
+)
+
+type BatchResult struct {
+	ArticleID int64  `json:"article_id"`
+	Action    string `json:"action"`
+	Applied   bool   `json:"applied"`
+	// Status is the HTTP status the operation would have answered on its own
+	Status int    `json:"status"`
+	Error  string `json:"error,omitempty"`
+}
+
+type Batch struct {
+	Atomic bool `json:"atomic"`
+	// Applied tells whether every operation was applied
+	Applied bool          `json:"applied"`
+	Results []BatchResult `json:"results"`
+}
+
+// NewBatchFromDomain builds the report of a batch, statusOf maps the error of
+// an operation to its status
+func NewBatchFromDomain(atomic bool, results []domain.BatchResult, statusOf func(error) int) Batch {
+	res := Batch{Atomic: atomic, Applied: true, Results: make([]BatchResult, len(results))}
+	for i, r := range results {
+		item := BatchResult{
+			ArticleID: r.ArticleID,
+			Action:    string(r.Action),
+			Applied:   r.Applied,
+			Status:    statusOf(r.Err),
+		}
+		switch {
+		case r.Err != nil:
+			item.Error = r.Err.Error()
+		case !r.Applied:
+			// the operation was valid but another one cancelled the batch
+			item.Status = http.StatusFailedDependency
+			item.Error = "rolled back"
+		}
+		res.Applied = res.Applied && r.Applied
+		res.Results[i] = item
+	}
+	return res
+}
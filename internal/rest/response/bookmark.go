@@ -0,0 +1,59 @@
+package response
+
+import "github.com/bxcodec/go-clean-arch/domain"
+
+type BookmarkArticle struct {
+	ID        int64  `json:"id"`
+	Title     string `json:"title"`
+	CreatedAt string `json:"created_at"`
+}
+
+type Bookmark struct {
+	ArticleID int64  `json:"article_id"`
+	ListID    int64  `json:"list_id,omitempty"`
+	Position  int64  `json:"position,omitempty"`
+	Read      bool   `json:"read"`
+	ReadAt    string `json:"read_at,omitempty"`
+	// Available is false once the article is deleted or unpublished, Article is then empty
+	Available bool             `json:"available"`
+	Article   *BookmarkArticle `json:"article"`
+	CreatedAt string           `json:"created_at"`
+}
+
+func NewBookmarkFromDomain(b *domain.Bookmark) Bookmark {
+	res := Bookmark{
+		ArticleID: b.ArticleID,
+		ListID:    b.ListID,
+		Position:  b.Position,
+		Read:      !b.ReadAt.IsZero(),
+		Available: b.Available(),
+		CreatedAt: b.CreatedAt.Format("2006-01-02 15:04:05"),
+	}
+	if res.Read {
+		res.ReadAt = b.ReadAt.Format("2006-01-02 15:04:05")
+	}
+	if res.Available {
+		res.Article = &BookmarkArticle{
+			ID:        b.Article.ID,
+			Title:     b.Article.Title,
+			CreatedAt: b.Article.CreatedAt.Format("2006-01-02 15:04:05"),
+		}
+	}
+	return res
+}
+
+type ReadingList struct {
+	ID        int64  `json:"id"`
+	Name      string `json:"name"`
+	CreatedAt string `json:"created_at"`
+	UpdatedAt string `json:"updated_at"`
+}
+
+func NewReadingListFromDomain(l *domain.ReadingList) ReadingList {
+	return ReadingList{
+		ID:        l.ID,
+		Name:      l.Name,
+		CreatedAt: l.CreatedAt.Format("2006-01-02 15:04:05"),
+		UpdatedAt: l.UpdatedAt.Format("2006-01-02 15:04:05"),
+	}
+}
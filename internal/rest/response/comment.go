@@ -0,0 +1,41 @@
+package response
+
+import "github.com/bxcodec/go-clean-arch/domain"
+
+type Comment struct {
+	ID        int64     `json:"id"`
+	ArticleID int64     `json:"article_id"`
+	ParentID  int64     `json:"parent_id,omitempty"`
+	UserName  string    `json:"user_name"`
+	Content   string    `json:"content"`
+	Status    string    `json:"status"`
+	Deleted   bool      `json:"deleted"`
+	Replies   []Comment `json:"replies"`
+	CreatedAt string    `json:"created_at"`
+	UpdatedAt string    `json:"updated_at"`
+}
+
+// NewCommentFromDomain converts the comment together with its nested replies
+func NewCommentFromDomain(c *domain.Comment) Comment {
+	replies := make([]Comment, len(c.Replies))
+	for i := range c.Replies {
+		replies[i] = NewCommentFromDomain(&c.Replies[i])
+	}
+	res := Comment{
+		ID:        c.ID,
+		ArticleID: c.ArticleID,
+		ParentID:  c.ParentID,
+		UserName:  c.User.Name,
+		Content:   c.Content,
+		Status:    string(c.Status),
+		Deleted:   c.Deleted,
+		Replies:   replies,
+		CreatedAt: c.CreatedAt.Format("2006-01-02 15:04:05"),
+		UpdatedAt: c.UpdatedAt.Format("2006-01-02 15:04:05"),
+	}
+	if c.Deleted {
+		// the author is hidden together with the content
+		res.UserName = ""
+	}
+	return res
+}
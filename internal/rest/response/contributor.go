@@ -0,0 +1,23 @@
+package response
+
+import "github.com/bxcodec/go-clean-arch/domain"
+
+type Contributor struct {
+	ID       int64  `json:"id"`
+	Name     string `json:"name"`
+	Username string `json:"username"`
+	Role     string `json:"role"`
+}
+
+func NewContributorsFromDomain(contributors []domain.Contributor) []Contributor {
+	res := make([]Contributor, len(contributors))
+	for i, c := range contributors {
+		res[i] = Contributor{
+			ID:       c.User.ID,
+			Name:     c.User.Name,
+			Username: c.User.Username,
+			Role:     string(c.Role),
+		}
+	}
+	return res
+}
@@ -0,0 +1,19 @@
+package response
+
+import "github.com/bxcodec/go-clean-arch/domain"
+
+type Follow struct {
+	ID         int64  `json:"id"`
+	Name       string `json:"name"`
+	Username   string `json:"username"`
+	FollowedAt string `json:"followed_at"`
+}
+
+func NewFollowFromDomain(f *domain.Follow) Follow {
+	return Follow{
+		ID:         f.User.ID,
+		Name:       f.User.Name,
+		Username:   f.User.Username,
+		FollowedAt: f.CreatedAt.Format("2006-01-02 15:04:05"),
+	}
+}
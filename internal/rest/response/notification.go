@@ -0,0 +1,79 @@
+package response
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/bxcodec/go-clean-arch/domain"
+)
+
+type NotificationActor struct {
+	ID       int64  `json:"id"`
+	Name     string `json:"name"`
+	Username string `json:"username"`
+}
+
+type Notification struct {
+	ID        int64  `json:"id"`
+	Type      string `json:"type"`
+	Message   string `json:"message"`
+	ArticleID int64  `json:"article_id,omitempty"`
+	CommentID int64  `json:"comment_id,omitempty"`
+	// Actor is the latest of ActorCount users whose activity is aggregated here
+	Actor      NotificationActor `json:"actor"`
+	ActorCount int64             `json:"actor_count"`
+	Read       bool              `json:"read"`
+	CreatedAt  string            `json:"created_at"`
+	UpdatedAt  string            `json:"updated_at"`
+}
+
+func NewNotificationFromDomain(n *domain.Notification) Notification {
+	return Notification{
+		ID:        n.ID,
+		Type:      string(n.Type),
+		Message:   notificationMessage(n),
+		ArticleID: n.ArticleID,
+		CommentID: n.CommentID,
+		Actor: NotificationActor{
+			ID:       n.Actor.ID,
+			Name:     n.Actor.Name,
+			Username: n.Actor.Username,
+		},
+		ActorCount: n.ActorCount,
+		Read:       n.Read(),
+		CreatedAt:  n.CreatedAt.Format("2006-01-02 15:04:05"),
+		UpdatedAt:  n.UpdatedAt.Format("2006-01-02 15:04:05"),
+	}
+}
+
+// notificationMessage renders e.g. "Alice and 4 others reacted to your article "Go""
+func notificationMessage(n *domain.Notification) string {
+	actors := n.Actor.Name
+	if actors == "" {
+		actors = "Someone"
+	}
+	switch {
+	case n.ActorCount == 2:
+		actors += " and 1 other"
+	case n.ActorCount > 2:
+		actors += " and " + strconv.FormatInt(n.ActorCount-1, 10) + " others"
+	}
+
+	switch n.Type {
+	case domain.NotificationComment:
+		return fmt.Sprintf("%s commented on your article %q", actors, n.ArticleTitle)
+	case domain.NotificationReply:
+		return fmt.Sprintf("%s replied to your comment on %q", actors, n.ArticleTitle)
+	case domain.NotificationReaction:
+		return fmt.Sprintf("%s reacted to your article %q", actors, n.ArticleTitle)
+	case domain.NotificationFollow:
+		return actors + " started following you"
+	case domain.NotificationArticle:
+		return fmt.Sprintf("%s published %q", actors, n.ArticleTitle)
+	}
+	return ""
+}
+
+type UnreadNotifications struct {
+	Unread int64 `json:"unread"`
+}
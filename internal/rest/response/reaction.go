@@ -0,0 +1,18 @@
+package response
+
+import "github.com/bxcodec/go-clean-arch/domain"
+
+type ReactionSummary struct {
+	ArticleID int64            `json:"article_id"`
+	Counts    map[string]int64 `json:"counts"`
+	// Mine lists the kinds the current user reacted with, it is empty for anonymous requests
+	Mine []string `json:"mine"`
+}
+
+func NewReactionSummaryFromDomain(s *domain.ReactionSummary) ReactionSummary {
+	return ReactionSummary{
+		ArticleID: s.ArticleID,
+		Counts:    s.Counts,
+		Mine:      s.Mine,
+	}
+}
@@ -0,0 +1,19 @@
+package response
+
+import "github.com/bxcodec/go-clean-arch/domain"
+
+type RelatedArticle struct {
+	ArticleSummary
+	Score float64 `json:"score"`
+}
+
+func NewRelatedArticlesFromDomain(related []domain.RelatedArticle) []RelatedArticle {
+	res := make([]RelatedArticle, len(related))
+	for i := range related {
+		res[i] = RelatedArticle{
+			ArticleSummary: NewArticleSummaryFromDomain(&related[i].Article),
+			Score:          related[i].Score,
+		}
+	}
+	return res
+}
@@ -0,0 +1,89 @@
+package response
+
+import "github.com/bxcodec/go-clean-arch/domain"
+
+type SeriesLink struct {
+	ID    int64  `json:"id"`
+	Title string `json:"title"`
+}
+
+type SeriesNavigation struct {
+	ID       int64       `json:"id"`
+	Title    string      `json:"title"`
+	Position int         `json:"position"`
+	Total    int         `json:"total"`
+	Prev     *SeriesLink `json:"prev"`
+	Next     *SeriesLink `json:"next"`
+}
+
+func NewSeriesNavigationFromDomain(n *domain.SeriesNavigation) *SeriesNavigation {
+	if n == nil {
+		return nil
+	}
+	res := &SeriesNavigation{
+		ID:       n.SeriesID,
+		Title:    n.Title,
+		Position: n.Position,
+		Total:    n.Total,
+	}
+	if n.Prev != nil {
+		res.Prev = &SeriesLink{ID: n.Prev.ID, Title: n.Prev.Title}
+	}
+	if n.Next != nil {
+		res.Next = &SeriesLink{ID: n.Next.ID, Title: n.Next.Title}
+	}
+	return res
+}
+
+type SeriesCover struct {
+	URL string `json:"url"`
+	Alt string `json:"alt"`
+}
+
+type SeriesArticle struct {
+	ID             int64  `json:"id"`
+	Title          string `json:"title"`
+	Excerpt        string `json:"excerpt"`
+	ReadingMinutes int64  `json:"reading_minutes"`
+	CreatedAt      string `json:"created_at"`
+}
+
+type Series struct {
+	ID           int64        `json:"id"`
+	Title        string       `json:"title"`
+	Description  string       `json:"description"`
+	Cover        *SeriesCover `json:"cover"`
+	UserName     string       `json:"user_name"`
+	ArticleCount int64        `json:"article_count"`
+	// ReadingMinutes is the total reading time of the published articles
+	ReadingMinutes int64           `json:"reading_minutes"`
+	Articles       []SeriesArticle `json:"articles,omitempty"`
+	CreatedAt      string          `json:"created_at"`
+	UpdatedAt      string          `json:"updated_at"`
+}
+
+func NewSeriesFromDomain(s *domain.Series) Series {
+	res := Series{
+		ID:             s.ID,
+		Title:          s.Title,
+		Description:    s.Description,
+		UserName:       s.User.Name,
+		ArticleCount:   s.ArticleCount,
+		ReadingMinutes: s.ReadingMinutes(),
+		CreatedAt:      s.CreatedAt.Format("2006-01-02 15:04:05"),
+		UpdatedAt:      s.UpdatedAt.Format("2006-01-02 15:04:05"),
+	}
+	if s.CoverURL != "" {
+		res.Cover = &SeriesCover{URL: s.CoverURL, Alt: s.CoverAlt}
+	}
+	for _, a := range s.Articles {
+		res.Articles = append(res.Articles, SeriesArticle{
+			ID:             a.ID,
+			Title:          a.Title,
+			Excerpt:        a.Excerpt,
+			ReadingMinutes: a.ReadingMinutes,
+			CreatedAt:      a.CreatedAt.Format("2006-01-02 15:04:05"),
+		})
+	}
+	return res
+}
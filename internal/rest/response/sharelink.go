@@ -0,0 +1,22 @@
+package response
+
+import (
+	"fmt"
+
+	"github.com/bxcodec/go-clean-arch/domain"
+)
+
+type ShareLink struct {
+	Token string `json:"token"`
+	// URL is the path of the shared article, relative to the API root
+	URL       string `json:"url"`
+	ExpiresAt string `json:"expires_at"`
+}
+
+func NewShareLinkFromDomain(l domain.ShareLink) ShareLink {
+	return ShareLink{
+		Token:     l.Token,
+		URL:       fmt.Sprintf("/articles/%d?share=%s", l.ArticleID, l.Token),
+		ExpiresAt: l.ExpiresAt.Format("2006-01-02 15:04:05"),
+	}
+}
@@ -0,0 +1,41 @@
+package response
+
+import "github.com/bxcodec/go-clean-arch/domain"
+
+// SimilarArticle is a near duplicate, hidden ones only tell that a duplicate exists
+type SimilarArticle struct {
+	ID         int64  `json:"id,omitempty"`
+	Title      string `json:"title,omitempty"`
+	Status     string `json:"status,omitempty"`
+	Visibility string `json:"visibility,omitempty"`
+	UserID     int64  `json:"user_id,omitempty"`
+	UserName   string `json:"user_name,omitempty"`
+	// Similarity goes from 0 to 1 for identical texts
+	Similarity float64 `json:"similarity"`
+	UpdatedAt  string  `json:"updated_at,omitempty"`
+	Hidden     bool    `json:"hidden,omitempty"`
+}
+
+func NewSimilarArticlesFromDomain(similar []domain.SimilarArticle) []SimilarArticle {
+	if similar == nil {
+		return nil
+	}
+	res := make([]SimilarArticle, len(similar))
+	for i, s := range similar {
+		if s.Hidden {
+			res[i] = SimilarArticle{Similarity: s.Similarity(), Hidden: true}
+			continue
+		}
+		res[i] = SimilarArticle{
+			ID:         s.Article.ID,
+			Title:      s.Article.Title,
+			Status:     string(s.Article.Status),
+			Visibility: string(s.Article.Visibility),
+			UserID:     s.Article.User.ID,
+			UserName:   s.Article.User.Name,
+			Similarity: s.Similarity(),
+			UpdatedAt:  s.Article.UpdatedAt.Format("2006-01-02 15:04:05"),
+		}
+	}
+	return res
+}
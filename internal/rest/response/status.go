@@ -0,0 +1,66 @@
+package response
+
+import "github.com/bxcodec/go-clean-arch/domain"
+
+type LeaderStatus struct {
+	ReplicaID    string `json:"replica_id"`
+	IsLeader     bool   `json:"is_leader"`
+	Leader       string `json:"leader"`
+	FencingToken int64  `json:"fencing_token"`
+	LeaderSince  string `json:"leader_since,omitempty"`
+}
+
+func NewLeaderStatusFromDomain(s *domain.LeaderStatus) LeaderStatus {
+	res := LeaderStatus{
+		ReplicaID:    s.ReplicaID,
+		IsLeader:     s.IsLeader,
+		Leader:       s.Leader,
+		FencingToken: s.FencingToken,
+	}
+	if !s.LeaderSince.IsZero() {
+		res.LeaderSince = s.LeaderSince.Format("2006-01-02 15:04:05")
+	}
+	return res
+}
+
+type JobRun struct {
+	StartedAt  string `json:"started_at"`
+	DurationMs int64  `json:"duration_ms"`
+	Error      string `json:"error,omitempty"`
+}
+
+type ScheduledJob struct {
+	Name     string   `json:"name"`
+	Schedule string   `json:"schedule"`
+	Mode     string   `json:"mode"`
+	Running  bool     `json:"running"`
+	NextRun  string   `json:"next_run,omitempty"`
+	Runs     int64    `json:"runs"`
+	Failures int64    `json:"failures"`
+	Panics   int64    `json:"panics"`
+	History  []JobRun `json:"history"`
+}
+
+func NewScheduledJobFromDomain(j *domain.ScheduledJob) ScheduledJob {
+	res := ScheduledJob{
+		Name:     j.Name,
+		Schedule: j.Schedule,
+		Mode:     j.Mode,
+		Running:  j.Running,
+		Runs:     j.Runs,
+		Failures: j.Failures,
+		Panics:   j.Panics,
+		History:  make([]JobRun, len(j.History)),
+	}
+	if !j.NextRun.IsZero() {
+		res.NextRun = j.NextRun.Format("2006-01-02 15:04:05")
+	}
+	for i, run := range j.History {
+		res.History[i] = JobRun{
+			StartedAt:  run.StartedAt.Format("2006-01-02 15:04:05"),
+			DurationMs: run.Duration.Milliseconds(),
+			Error:      run.Err,
+		}
+	}
+	return res
+}
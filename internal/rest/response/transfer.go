@@ -0,0 +1,91 @@
+package response
+
+import (
+	"fmt"
+
+	"github.com/bxcodec/go-clean-arch/domain"
+)
+
+type ImportRow struct {
+	Row       int    `json:"row"`
+	SourceID  int64  `json:"source_id,omitempty"`
+	SourceURL string `json:"source_url,omitempty"`
+	Title     string `json:"title"`
+	Slug      string `json:"slug"`
+	Result    string `json:"result"`
+	ArticleID int64  `json:"article_id,omitempty"`
+	// URL is the path of the article, relative to the API root
+	URL   string `json:"url,omitempty"`
+	Error string `json:"error,omitempty"`
+}
+
+type ImportAuthor struct {
+	SourceID int64  `json:"source_id,omitempty"`
+	Username string `json:"username"`
+	UserID   int64  `json:"user_id,omitempty"`
+	Created  bool   `json:"created"`
+	Error    string `json:"error,omitempty"`
+}
+
+type ImportCategory struct {
+	SourceID   int64  `json:"source_id,omitempty"`
+	Tag        string `json:"tag"`
+	CategoryID int64  `json:"category_id,omitempty"`
+	Created    bool   `json:"created"`
+	Error      string `json:"error,omitempty"`
+}
+
+type ImportReport struct {
+	DryRun  bool        `json:"dry_run"`
+	Created int         `json:"created"`
+	Skipped int         `json:"skipped"`
+	Failed  int         `json:"failed"`
+	Rows    []ImportRow `json:"rows"`
+	// Authors and Categories are only reported by the WordPress import
+	Authors    []ImportAuthor   `json:"authors,omitempty"`
+	Categories []ImportCategory `json:"categories,omitempty"`
+}
+
+func NewImportReportFromDomain(r *domain.ImportReport) ImportReport {
+	res := ImportReport{
+		DryRun:  r.DryRun,
+		Created: r.Created,
+		Skipped: r.Skipped,
+		Failed:  r.Failed,
+		Rows:    make([]ImportRow, len(r.Rows)),
+	}
+	for i, row := range r.Rows {
+		res.Rows[i] = ImportRow{
+			Row:       row.Row,
+			SourceID:  row.SourceID,
+			SourceURL: row.SourceURL,
+			Title:     row.Title,
+			Slug:      row.Slug,
+			Result:    string(row.Result),
+			ArticleID: row.ArticleID,
+			Error:     row.Error,
+		}
+		if row.ArticleID != 0 {
+			res.Rows[i].URL = fmt.Sprintf("/articles/%d", row.ArticleID)
+		}
+	}
+	for _, a := range r.Authors {
+		res.Authors = append(res.Authors, ImportAuthor{
+			SourceID: a.SourceID,
+			Username: a.Username,
+			UserID:   a.UserID,
+			Created:  a.Created,
+			Error:    a.Error,
+		})
+	}
+	for _, c := range r.Categories {
+		res.Categories = append(res.Categories, ImportCategory{
+			SourceID:   c.SourceID,
+			Tag:        c.Tag,
+			CategoryID: c.CategoryID,
+			Created:    c.Created,
+			Error:      c.Error,
+		})
+	}
+	return res
+}
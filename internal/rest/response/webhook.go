@@ -0,0 +1,72 @@
+package response
+
+import "github.com/bxcodec/go-clean-arch/domain"
+
+type WebhookSubscription struct {
+	ID        int64    `json:"id"`
+	URL       string   `json:"url"`
+	Secret    string   `json:"secret,omitempty"`
+	Events    []string `json:"events"`
+	Active    bool     `json:"active"`
+	CreatedAt string   `json:"created_at"`
+	UpdatedAt string   `json:"updated_at"`
+}
+
+// NewWebhookSubscriptionFromDomain hides the secret, it is only returned once on creation
+func NewWebhookSubscriptionFromDomain(s *domain.WebhookSubscription) WebhookSubscription {
+	events := make([]string, len(s.Events))
+	for i, e := range s.Events {
+		events[i] = string(e)
+	}
+	return WebhookSubscription{
+		ID:        s.ID,
+		URL:       s.URL,
+		Events:    events,
+		Active:    s.Active,
+		CreatedAt: s.CreatedAt.Format("2006-01-02 15:04:05"),
+		UpdatedAt: s.UpdatedAt.Format("2006-01-02 15:04:05"),
+	}
+}
+
+type WebhookAttempt struct {
+	StatusCode  int    `json:"status_code"`
+	Error       string `json:"error,omitempty"`
+	DurationMs  int64  `json:"duration_ms"`
+	AttemptedAt string `json:"attempted_at"`
+}
+
+type WebhookDelivery struct {
+	ID             string           `json:"id"`
+	SubscriptionID int64            `json:"subscription_id"`
+	EventID        int64            `json:"event_id"`
+	EventType      string           `json:"event_type"`
+	Status         string           `json:"status"`
+	Attempts       int              `json:"attempts"`
+	NextAttemptAt  string           `json:"next_attempt_at,omitempty"`
+	CreatedAt      string           `json:"created_at"`
+	History        []WebhookAttempt `json:"history,omitempty"`
+}
+
+func NewWebhookDeliveryFromDomain(d *domain.WebhookDelivery, attempts []domain.WebhookAttempt) WebhookDelivery {
+	res := WebhookDelivery{
+		ID:             d.ID,
+		SubscriptionID: d.SubscriptionID,
+		EventID:        d.EventID,
+		EventType:      string(d.EventType),
+		Status:         string(d.Status),
+		Attempts:       d.Attempts,
+		CreatedAt:      d.CreatedAt.Format("2006-01-02 15:04:05"),
+	}
+	if d.Status == domain.WebhookDeliveryPending {
+		res.NextAttemptAt = d.NextAttemptAt.Format("2006-01-02 15:04:05")
+	}
+	for _, a := range attempts {
+		res.History = append(res.History, WebhookAttempt{
+			StatusCode:  a.StatusCode,
+			Error:       a.Error,
+			DurationMs:  a.Duration.Milliseconds(),
+			AttemptedAt: a.AttemptedAt.Format("2006-01-02 15:04:05"),
+		})
+	}
+	return res
+}
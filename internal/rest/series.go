@@ -0,0 +1,162 @@
+package rest
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/bxcodec/go-clean-arch/domain"
+	"github.com/bxcodec/go-clean-arch/internal/rest/request"
+	"github.com/bxcodec/go-clean-arch/internal/rest/response"
+)
+
+type SeriesService interface {
+	Fetch(ctx context.Context, authorID int64, cursor string, num int64) ([]domain.Series, string, error)
+	GetByID(ctx context.Context, id int64) (domain.Series, error)
+	Store(ctx context.Context, s *domain.Series) error
+	Update(ctx context.Context, s *domain.Series) error
+	Delete(ctx context.Context, userID, id int64) error
+	SetArticles(ctx context.Context, userID, id int64, articleIDs []int64) (domain.Series, error)
+}
+
+// SeriesHandler represent the httphandler for article series
+type SeriesHandler struct {
+	Service SeriesService
+}
+
+func NewSeriesHandler(svc SeriesService) *SeriesHandler {
+	return &SeriesHandler{
+		Service: svc,
+	}
+}
+
+// Fetch will list the series, newest first, of one author with the author query param
+func (h *SeriesHandler) Fetch(c *gin.Context) {
+	var authorID int64
+	if author := c.Query("author"); author != "" {
+		id, err := strconv.ParseInt(author, 10, 64)
+		if err != nil || id <= 0 {
+			c.JSON(http.StatusBadRequest, ResponseError{Message: domain.ErrBadParamInput.Error()})
+			return
+		}
+		authorID = id
+	}
+
+	list, nextCursor, err := h.Service.Fetch(c.Request.Context(), authorID, c.Query("cursor"), queryNum(c))
+	if err != nil {
+		c.JSON(getStatusCode(err), ResponseError{Message: err.Error()})
+		return
+	}
+	res := make([]response.Series, len(list))
+	for i := range list {
+		res[i] = response.NewSeriesFromDomain(&list[i])
+	}
+	c.Header(`X-cursor`, nextCursor)
+	c.JSON(http.StatusOK, res)
+}
+
+// GetByID will get a series with its published articles in order
+func (h *SeriesHandler) GetByID(c *gin.Context) {
+	id, ok := paramID(c, "id")
+	if !ok {
+		return
+	}
+
+	series, err := h.Service.GetByID(c.Request.Context(), id)
+	if err != nil {
+		c.JSON(getStatusCode(err), ResponseError{Message: err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, response.NewSeriesFromDomain(&series))
+}
+
+// Store will create a series for the current user
+func (h *SeriesHandler) Store(c *gin.Context) {
+	var req request.Series
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	series := req.ToDomain()
+	series.User.ID = userID
+	if err := h.Service.Store(c.Request.Context(), &series); err != nil {
+		c.JSON(getStatusCode(err), ResponseError{Message: err.Error()})
+		return
+	}
+	c.JSON(http.StatusCreated, response.NewSeriesFromDomain(&series))
+}
+
+// Update will change the metadata of a series of the current user
+func (h *SeriesHandler) Update(c *gin.Context) {
+	id, ok := paramID(c, "id")
+	if !ok {
+		return
+	}
+	var req request.Series
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	series := req.ToDomain()
+	series.ID = id
+	series.User.ID = userID
+	if err := h.Service.Update(c.Request.Context(), &series); err != nil {
+		c.JSON(getStatusCode(err), ResponseError{Message: err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, response.NewSeriesFromDomain(&series))
+}
+
+// Delete will delete a series of the current user, its articles are kept
+func (h *SeriesHandler) Delete(c *gin.Context) {
+	id, ok := paramID(c, "id")
+	if !ok {
+		return
+	}
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	if err := h.Service.Delete(c.Request.Context(), userID, id); err != nil {
+		c.JSON(getStatusCode(err), ResponseError{Message: err.Error()})
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// SetArticles will replace the articles of a series of the current user, in the given order
+func (h *SeriesHandler) SetArticles(c *gin.Context) {
+	id, ok := paramID(c, "id")
+	if !ok {
+		return
+	}
+	var req request.SeriesArticles
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	series, err := h.Service.SetArticles(c.Request.Context(), userID, id, req.ArticleIDs)
+	if err != nil {
+		c.JSON(getStatusCode(err), ResponseError{Message: err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, response.NewSeriesFromDomain(&series))
+}
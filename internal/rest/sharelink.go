@@ -0,0 +1,55 @@
+package rest
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/bxcodec/go-clean-arch/domain"
+	"github.com/bxcodec/go-clean-arch/internal/rest/request"
+	"github.com/bxcodec/go-clean-arch/internal/rest/response"
+)
+
+type ShareLinkService interface {
+	CreateShareLink(ctx context.Context, userID, articleID int64, ttl time.Duration) (domain.ShareLink, error)
+}
+
+// ShareLinkHandler represent the httphandler for article share links
+type ShareLinkHandler struct {
+	Service ShareLinkService
+}
+
+func NewShareLinkHandler(svc ShareLinkService) *ShareLinkHandler {
+	return &ShareLinkHandler{
+		Service: svc,
+	}
+}
+
+// Store will create a share link granting read access to an article until it expires
+func (h *ShareLinkHandler) Store(c *gin.Context) {
+	articleID, ok := paramID(c, "id")
+	if !ok {
+		return
+	}
+	var req request.ShareLink
+	// the body is optional
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+	}
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	link, err := h.Service.CreateShareLink(c.Request.Context(), userID, articleID, time.Duration(req.ExpiresIn)*time.Second)
+	if err != nil {
+		c.JSON(getStatusCode(err), ResponseError{Message: err.Error()})
+		return
+	}
+	c.JSON(http.StatusCreated, response.NewShareLinkFromDomain(link))
+}
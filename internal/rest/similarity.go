@@ -0,0 +1,50 @@
+package rest
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/bxcodec/go-clean-arch/domain"
+	"github.com/bxcodec/go-clean-arch/internal/rest/response"
+)
+
+type SimilarContentService interface {
+	SimilarContent(ctx context.Context, userID, articleID int64) ([]domain.SimilarArticle, error)
+}
+
+// SimilarContentHandler represent the httphandler for near duplicates of articles
+type SimilarContentHandler struct {
+	Service SimilarContentService
+}
+
+func NewSimilarContentHandler(svc SimilarContentService) *SimilarContentHandler {
+	return &SimilarContentHandler{
+		Service: svc,
+	}
+}
+
+// Fetch will list the articles whose content is a near duplicate of an
+// article, for the users who may edit it
+func (h *SimilarContentHandler) Fetch(c *gin.Context) {
+	articleID, ok := paramID(c, "id")
+	if !ok {
+		return
+	}
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	similar, err := h.Service.SimilarContent(c.Request.Context(), userID, articleID)
+	if err != nil {
+		c.JSON(getStatusCode(err), ResponseError{Message: err.Error()})
+		return
+	}
+	res := response.NewSimilarArticlesFromDomain(similar)
+	if res == nil {
+		res = []response.SimilarArticle{}
+	}
+	c.JSON(http.StatusOK, res)
+}
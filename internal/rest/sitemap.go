@@ -0,0 +1,53 @@
+package rest
+
+import (
+	"context"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/bxcodec/go-clean-arch/domain"
+)
+
+type SitemapService interface {
+	Index(ctx context.Context) ([]byte, error)
+	Chunk(ctx context.Context, name string) ([]byte, error)
+}
+
+// SitemapHandler represent the httphandler for the sitemaps
+type SitemapHandler struct {
+	Service SitemapService
+}
+
+func NewSitemapHandler(svc SitemapService) *SitemapHandler {
+	return &SitemapHandler{
+		Service: svc,
+	}
+}
+
+// Index will serve the sitemap index listing every sitemap file
+func (h *SitemapHandler) Index(c *gin.Context) {
+	body, err := h.Service.Index(c.Request.Context())
+	h.serve(c, body, err)
+}
+
+// Chunk will serve one sitemap file, named like articles-0.xml
+func (h *SitemapHandler) Chunk(c *gin.Context) {
+	name, ok := strings.CutSuffix(c.Param("file"), ".xml")
+	if !ok {
+		c.JSON(http.StatusNotFound, ResponseError{Message: domain.ErrNotFound.Error()})
+		return
+	}
+	body, err := h.Service.Chunk(c.Request.Context(), name)
+	h.serve(c, body, err)
+}
+
+func (h *SitemapHandler) serve(c *gin.Context, body []byte, err error) {
+	if err != nil {
+		c.JSON(getStatusCode(err), ResponseError{Message: err.Error()})
+		return
+	}
+	c.Header("Cache-Control", "public, max-age=3600")
+	c.Data(http.StatusOK, "application/xml; charset=utf-8", body)
+}
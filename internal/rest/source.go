@@ -0,0 +1,45 @@
+package rest
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/bxcodec/go-clean-arch/domain"
+	"github.com/bxcodec/go-clean-arch/internal/rest/response"
+)
+
+type ArticleSourceService interface {
+	Source(ctx context.Context, userID, articleID int64) (domain.Article, error)
+}
+
+// ArticleSourceHandler represent the httphandler for the source of articles
+type ArticleSourceHandler struct {
+	Service ArticleSourceService
+}
+
+func NewArticleSourceHandler(svc ArticleSourceService) *ArticleSourceHandler {
+	return &ArticleSourceHandler{
+		Service: svc,
+	}
+}
+
+// Fetch will get the source of an article as written, for the users who may edit it
+func (h *ArticleSourceHandler) Fetch(c *gin.Context) {
+	articleID, ok := paramID(c, "id")
+	if !ok {
+		return
+	}
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	ar, err := h.Service.Source(c.Request.Context(), userID, articleID)
+	if err != nil {
+		c.JSON(getStatusCode(err), ResponseError{Message: err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, response.NewArticleSourceFromDomain(&ar))
+}
@@ -0,0 +1,52 @@
+package rest
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/bxcodec/go-clean-arch/domain"
+	"github.com/bxcodec/go-clean-arch/internal/rest/response"
+	"github.com/gin-gonic/gin"
+)
+
+type LeaderElector interface {
+	Status(ctx context.Context) (domain.LeaderStatus, error)
+}
+
+type JobScheduler interface {
+	Jobs() []domain.ScheduledJob
+}
+
+// StatusHandler represent the httphandler for operational status
+type StatusHandler struct {
+	Elector   LeaderElector
+	Scheduler JobScheduler
+}
+
+func NewStatusHandler(elector LeaderElector, scheduler JobScheduler) *StatusHandler {
+	return &StatusHandler{
+		Elector:   elector,
+		Scheduler: scheduler,
+	}
+}
+
+// Leader will report whether this replica is the leader and who holds the lease
+func (h *StatusHandler) Leader(c *gin.Context) {
+	status, err := h.Elector.Status(c.Request.Context())
+	if err != nil {
+		c.JSON(getStatusCode(err), ResponseError{Message: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, response.NewLeaderStatusFromDomain(&status))
+}
+
+// Jobs will list the background jobs of this replica with their run history
+func (h *StatusHandler) Jobs(c *gin.Context) {
+	jobs := h.Scheduler.Jobs()
+	res := make([]response.ScheduledJob, len(jobs))
+	for i := range jobs {
+		res[i] = response.NewScheduledJobFromDomain(&jobs[i])
+	}
+	c.JSON(http.StatusOK, res)
+}
@@ -0,0 +1,124 @@
+package rest
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/bxcodec/go-clean-arch/domain"
+)
+
+const (
+	streamHeartbeat = 15 * time.Second
+	streamRetryMs   = 3000
+
+	// streamEventReset tells a resuming client that events were lost and it should reload the list
+	streamEventReset = "reset"
+)
+
+type ArticleStream interface {
+	Subscribe() (<-chan domain.StreamEvent, func())
+	Replay(ctx context.Context, after int64) ([]domain.StreamEvent, bool, error)
+}
+
+// StreamHandler serves the live article stream as Server-Sent Events
+type StreamHandler struct {
+	Stream ArticleStream
+}
+
+func NewStreamHandler(s ArticleStream) *StreamHandler {
+	return &StreamHandler{
+		Stream: s,
+	}
+}
+
+// Articles streams article changes and view deltas. Clients resume with the
+// Last-Event-ID header, or the last_event_id query param where headers can not be set.
+func (s *StreamHandler) Articles(c *gin.Context) {
+	lastID, err := lastEventID(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ResponseError{Message: domain.ErrBadParamInput.Error()})
+		return
+	}
+	ctx := c.Request.Context()
+
+	// subscribe before replaying so nothing published in between is missed
+	events, unsubscribe := s.Stream.Subscribe()
+	defer unsubscribe()
+
+	var replay []domain.StreamEvent
+	complete := true
+	if lastID > 0 {
+		replay, complete, err = s.Stream.Replay(ctx, lastID)
+		if err != nil {
+			c.JSON(getStatusCode(err), ResponseError{Message: err.Error()})
+			return
+		}
+	}
+
+	h := c.Writer.Header()
+	h.Set("Content-Type", "text/event-stream")
+	h.Set("Cache-Control", "no-cache")
+	h.Set("Connection", "keep-alive")
+	h.Set("X-Accel-Buffering", "no")
+	c.Status(http.StatusOK)
+
+	fmt.Fprintf(c.Writer, "retry: %d\n\n", streamRetryMs)
+	if !complete {
+		writeStreamEvent(c, domain.StreamEvent{Type: streamEventReset, Data: []byte("{}")})
+	}
+	for _, event := range replay {
+		writeStreamEvent(c, event)
+		lastID = event.ID
+	}
+	c.Writer.Flush()
+
+	heartbeat := time.NewTicker(streamHeartbeat)
+	defer heartbeat.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-heartbeat.C:
+			fmt.Fprint(c.Writer, ": ping\n\n")
+		case event, ok := <-events:
+			if !ok {
+				// dropped or shutting down, the client reconnects and resumes
+				return
+			}
+			if event.ID <= lastID {
+				continue
+			}
+			writeStreamEvent(c, event)
+			lastID = event.ID
+		}
+		c.Writer.Flush()
+	}
+}
+
+func lastEventID(c *gin.Context) (int64, error) {
+	raw := c.GetHeader("Last-Event-ID")
+	if raw == "" {
+		raw = c.Query("last_event_id")
+	}
+	if raw == "" {
+		return 0, nil
+	}
+	return strconv.ParseInt(raw, 10, 64)
+}
+
+func writeStreamEvent(c *gin.Context, event domain.StreamEvent) {
+	if event.ID > 0 {
+		fmt.Fprintf(c.Writer, "id: %d\n", event.ID)
+	}
+	fmt.Fprintf(c.Writer, "event: %s\n", event.Type)
+	for _, line := range strings.Split(string(event.Data), "\n") {
+		fmt.Fprintf(c.Writer, "data: %s\n", line)
+	}
+	fmt.Fprint(c.Writer, "\n")
+}
@@ -0,0 +1,79 @@
+package rest
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/bxcodec/go-clean-arch/domain"
+)
+
+type SyndicationService interface {
+	Feed(ctx context.Context, scope domain.SyndicationScope, format domain.SyndicationFormat) (domain.SyndicationDocument, error)
+}
+
+// SyndicationHandler represent the httphandler for the RSS, Atom and JSON feeds
+type SyndicationHandler struct {
+	Service SyndicationService
+}
+
+func NewSyndicationHandler(svc SyndicationService) *SyndicationHandler {
+	return &SyndicationHandler{
+		Service: svc,
+	}
+}
+
+// Feed serves the feed in format of the site, of the author in the id path
+// param or of the tag in the tag path param, answering 304 to conditional
+// requests of readers that already have it
+func (h *SyndicationHandler) Feed(format domain.SyndicationFormat) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var scope domain.SyndicationScope
+		if c.Param("id") != "" {
+			id, ok := paramID(c, "id")
+			if !ok {
+				return
+			}
+			scope.AuthorID = id
+		}
+		scope.Tag = c.Param("tag")
+
+		doc, err := h.Service.Feed(c.Request.Context(), scope, format)
+		if err != nil {
+			c.JSON(getStatusCode(err), ResponseError{Message: err.Error()})
+			return
+		}
+		c.Header("ETag", doc.ETag)
+		if !doc.LastModified.IsZero() {
+			c.Header("Last-Modified", doc.LastModified.UTC().Format(http.TimeFormat))
+		}
+		c.Header("Cache-Control", "public, max-age=300")
+		if notModified(c.Request, doc) {
+			c.Status(http.StatusNotModified)
+			return
+		}
+		c.Data(http.StatusOK, doc.ContentType, doc.Body)
+	}
+}
+
+// notModified evaluates If-None-Match, or If-Modified-Since when there is no
+// If-None-Match, against the document
+func notModified(r *http.Request, doc domain.SyndicationDocument) bool {
+	if inm := r.Header.Get("If-None-Match"); inm != "" {
+		for _, tag := range strings.Split(inm, ",") {
+			tag = strings.TrimPrefix(strings.TrimSpace(tag), "W/")
+			if tag == "*" || tag == doc.ETag {
+				return true
+			}
+		}
+		return false
+	}
+	if ims := r.Header.Get("If-Modified-Since"); ims != "" && !doc.LastModified.IsZero() {
+		since, err := http.ParseTime(ims)
+		return err == nil && !doc.LastModified.Truncate(time.Second).After(since)
+	}
+	return false
+}
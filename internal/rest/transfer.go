@@ -0,0 +1,103 @@
+package rest
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+
+	"github.com/bxcodec/go-clean-arch/domain"
+	"github.com/bxcodec/go-clean-arch/internal/rest/response"
+)
+
+// maxImportSize bounds the body of an import
+const maxImportSize = 512 << 20
+
+type TransferService interface {
+	Export(ctx context.Context, format domain.TransferFormat, w io.Writer) error
+	Import(ctx context.Context, format domain.TransferFormat, r io.Reader, opts domain.ImportOptions) (domain.ImportReport, error)
+}
+
+// TransferHandler represent the httphandler for the bulk export and import of articles
+type TransferHandler struct {
+	Service TransferService
+}
+
+func NewTransferHandler(svc TransferService) *TransferHandler {
+	return &TransferHandler{
+		Service: svc,
+	}
+}
+
+var transferFiles = map[domain.TransferFormat]struct {
+	name, contentType string
+}{
+	domain.TransferJSONL:    {"articles.jsonl", "application/jsonl"},
+	domain.TransferCSV:      {"articles.csv", "text/csv; charset=utf-8"},
+	domain.TransferMarkdown: {"articles.zip", "application/zip"},
+}
+
+// Export will stream every article with its author and tags in the format
+// query param: jsonl (default), csv or markdown
+func (h *TransferHandler) Export(c *gin.Context) {
+	format := domain.TransferFormat(c.DefaultQuery("format", string(domain.TransferJSONL)))
+	file, ok := transferFiles[format]
+	if !ok {
+		c.JSON(http.StatusBadRequest, ResponseError{Message: domain.ErrBadParamInput.Error()})
+		return
+	}
+
+	c.Header("Content-Type", file.contentType)
+	c.Header("Content-Disposition", `attachment; filename="`+file.name+`"`)
+	c.Status(http.StatusOK)
+	// the status is sent with the first bytes, a failure can only cut the stream short
+	if err := h.Service.Export(c.Request.Context(), format, c.Writer); err != nil {
+		logrus.Errorf("failed to export articles: %v", err)
+	}
+}
+
+// Import will import the articles of the body in the format query param, one
+// of the export formats or wxr for WordPress exports, and
+// report the outcome of every row. dry_run=true only validates, author_map
+// params (old:new) rename authors and default_author gets the unknown ones.
+func (h *TransferHandler) Import(c *gin.Context) {
+	format := domain.TransferFormat(c.DefaultQuery("format", string(domain.TransferJSONL)))
+	if !format.Valid() {
+		c.JSON(http.StatusBadRequest, ResponseError{Message: domain.ErrBadParamInput.Error()})
+		return
+	}
+	opts := domain.ImportOptions{
+		AuthorMap:     map[string]string{},
+		DefaultAuthor: c.Query("default_author"),
+	}
+	opts.DryRun, _ = strconv.ParseBool(c.Query("dry_run"))
+	for _, pair := range c.QueryArray("author_map") {
+		from, to, ok := strings.Cut(pair, ":")
+		if !ok || from == "" || to == "" {
+			c.JSON(http.StatusBadRequest, ResponseError{Message: domain.ErrBadParamInput.Error()})
+			return
+		}
+		opts.AuthorMap[from] = to
+	}
+
+	body := http.MaxBytesReader(c.Writer, c.Request.Body, maxImportSize)
+	report, err := h.Service.Import(c.Request.Context(), format, body, opts)
+	var tooLarge *http.MaxBytesError
+	switch {
+	case errors.As(err, &tooLarge):
+		c.JSON(http.StatusRequestEntityTooLarge, ResponseError{Message: domain.ErrTooLarge.Error()})
+		return
+	case errors.Is(err, domain.ErrBadParamInput):
+		c.JSON(http.StatusBadRequest, ResponseError{Message: err.Error()})
+		return
+	case err != nil:
+		c.JSON(getStatusCode(err), ResponseError{Message: err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, response.NewImportReportFromDomain(&report))
+}
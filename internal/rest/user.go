@@ -3,6 +3,7 @@ package rest
 import (
 	"context"
 	"net/http"
+	"time"
 
 	"github.com/bxcodec/go-clean-arch/domain"
 	"github.com/bxcodec/go-clean-arch/internal/rest/request"
@@ -13,6 +14,8 @@ type UserService interface {
 	Register(ctx context.Context, name, username, password string) error
 	Login(ctx context.Context, username, password string) (string, error)
 	EditPassword(ctx context.Context, id int64, oldPassword, newPassword string) error
+	PasswordResetToken(ctx context.Context, userID int64) (string, time.Time, error)
+	ResetPassword(ctx context.Context, token, newPassword string) error
 }
 
 type UserHandler struct {
@@ -63,3 +66,43 @@ func (h *UserHandler) Login(c *gin.Context) {
 
 	c.JSON(http.StatusOK, gin.H{"token": token})
 }
+
+// CreatePasswordReset will issue a password reset token for a user, for
+// administrators to hand to users who cannot sign in such as imported authors
+func (h *UserHandler) CreatePasswordReset(c *gin.Context) {
+	userID, ok := paramID(c, "id")
+	if !ok {
+		return
+	}
+
+	token, expiresAt, err := h.Service.PasswordResetToken(c.Request.Context(), userID)
+	if err != nil {
+		if err == domain.ErrUserNotFound {
+			c.JSON(http.StatusNotFound, ResponseError{Message: err.Error()})
+			return
+		}
+		c.JSON(getStatusCode(err), ResponseError{Message: err.Error()})
+		return
+	}
+	c.JSON(http.StatusCreated, gin.H{"token": token, "expires_at": expiresAt.Format(time.RFC3339)})
+}
+
+// ResetPassword will set a new password with a password reset token
+func (h *UserHandler) ResetPassword(c *gin.Context) {
+	var req request.PasswordReset
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	err := h.Service.ResetPassword(c.Request.Context(), req.Token, req.Password)
+	if err != nil {
+		if err == domain.ErrInvalidCredentials {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid or expired token"})
+			return
+		}
+		c.JSON(getStatusCode(err), ResponseError{Message: err.Error()})
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
@@ -0,0 +1,205 @@
+package rest
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+
+	"github.com/bxcodec/go-clean-arch/domain"
+	"github.com/bxcodec/go-clean-arch/internal/rest/request"
+	"github.com/bxcodec/go-clean-arch/internal/rest/response"
+	"github.com/gin-gonic/gin"
+)
+
+type WebhookService interface {
+	Subscribe(ctx context.Context, s *domain.WebhookSubscription) error
+	ListSubscriptions(ctx context.Context, userID int64) ([]domain.WebhookSubscription, error)
+	GetSubscription(ctx context.Context, userID, id int64) (domain.WebhookSubscription, error)
+	UpdateSubscription(ctx context.Context, s *domain.WebhookSubscription) error
+	Unsubscribe(ctx context.Context, userID, id int64) error
+	ListDeliveries(ctx context.Context, userID, subscriptionID int64, num int64) ([]domain.WebhookDelivery, error)
+	GetDelivery(ctx context.Context, userID, subscriptionID int64, id string) (domain.WebhookDelivery, []domain.WebhookAttempt, error)
+	Replay(ctx context.Context, userID, subscriptionID int64, id string) (domain.WebhookDelivery, error)
+}
+
+// WebhookHandler represent the httphandler for webhook subscriptions
+type WebhookHandler struct {
+	Service WebhookService
+}
+
+func NewWebhookHandler(svc WebhookService) *WebhookHandler {
+	return &WebhookHandler{
+		Service: svc,
+	}
+}
+
+// Store will subscribe the current user to article events
+func (h *WebhookHandler) Store(c *gin.Context) {
+	var req request.WebhookSubscription
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	sub := req.ToDomain()
+	sub.UserID = userID
+	if err := h.Service.Subscribe(c.Request.Context(), &sub); err != nil {
+		c.JSON(getStatusCode(err), ResponseError{Message: err.Error()})
+		return
+	}
+
+	res := response.NewWebhookSubscriptionFromDomain(&sub)
+	res.Secret = sub.Secret
+	c.JSON(http.StatusCreated, res)
+}
+
+// Fetch will list the subscriptions of the current user
+func (h *WebhookHandler) Fetch(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	subs, err := h.Service.ListSubscriptions(c.Request.Context(), userID)
+	if err != nil {
+		c.JSON(getStatusCode(err), ResponseError{Message: err.Error()})
+		return
+	}
+	res := make([]response.WebhookSubscription, len(subs))
+	for i := range subs {
+		res[i] = response.NewWebhookSubscriptionFromDomain(&subs[i])
+	}
+	c.JSON(http.StatusOK, res)
+}
+
+// GetByID will get a subscription of the current user by given id
+func (h *WebhookHandler) GetByID(c *gin.Context) {
+	id, ok := paramID(c, "id")
+	if !ok {
+		return
+	}
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	sub, err := h.Service.GetSubscription(c.Request.Context(), userID, id)
+	if err != nil {
+		c.JSON(getStatusCode(err), ResponseError{Message: err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, response.NewWebhookSubscriptionFromDomain(&sub))
+}
+
+// Update will replace the url, event filter and active flag of a subscription
+func (h *WebhookHandler) Update(c *gin.Context) {
+	id, ok := paramID(c, "id")
+	if !ok {
+		return
+	}
+	var req request.WebhookSubscription
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	sub := req.ToDomain()
+	sub.ID = id
+	sub.UserID = userID
+	if err := h.Service.UpdateSubscription(c.Request.Context(), &sub); err != nil {
+		c.JSON(getStatusCode(err), ResponseError{Message: err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, response.NewWebhookSubscriptionFromDomain(&sub))
+}
+
+// Delete will remove a subscription of the current user
+func (h *WebhookHandler) Delete(c *gin.Context) {
+	id, ok := paramID(c, "id")
+	if !ok {
+		return
+	}
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	if err := h.Service.Unsubscribe(c.Request.Context(), userID, id); err != nil {
+		c.JSON(getStatusCode(err), ResponseError{Message: err.Error()})
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// FetchDeliveries will list the latest deliveries of a subscription
+func (h *WebhookHandler) FetchDeliveries(c *gin.Context) {
+	id, ok := paramID(c, "id")
+	if !ok {
+		return
+	}
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+	num, err := strconv.Atoi(c.Query("num"))
+	if err != nil || num == 0 {
+		num = defaultNum
+	}
+
+	deliveries, err := h.Service.ListDeliveries(c.Request.Context(), userID, id, int64(num))
+	if err != nil {
+		c.JSON(getStatusCode(err), ResponseError{Message: err.Error()})
+		return
+	}
+	res := make([]response.WebhookDelivery, len(deliveries))
+	for i := range deliveries {
+		res[i] = response.NewWebhookDeliveryFromDomain(&deliveries[i], nil)
+	}
+	c.JSON(http.StatusOK, res)
+}
+
+// GetDelivery will get a delivery with all of its attempts
+func (h *WebhookHandler) GetDelivery(c *gin.Context) {
+	id, ok := paramID(c, "id")
+	if !ok {
+		return
+	}
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	d, attempts, err := h.Service.GetDelivery(c.Request.Context(), userID, id, c.Param("deliveryID"))
+	if err != nil {
+		c.JSON(getStatusCode(err), ResponseError{Message: err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, response.NewWebhookDeliveryFromDomain(&d, attempts))
+}
+
+// Replay will schedule a failed delivery to be sent again
+func (h *WebhookHandler) Replay(c *gin.Context) {
+	id, ok := paramID(c, "id")
+	if !ok {
+		return
+	}
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	d, err := h.Service.Replay(c.Request.Context(), userID, id, c.Param("deliveryID"))
+	if err != nil {
+		c.JSON(getStatusCode(err), ResponseError{Message: err.Error()})
+		return
+	}
+	c.JSON(http.StatusAccepted, response.NewWebhookDeliveryFromDomain(&d, nil))
+}
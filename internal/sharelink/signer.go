@@ -0,0 +1,63 @@
+package sharelink
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Signer issues share link tokens of the form payload.signature, where the
+// payload holds the article id and the expiry and the signature is an
+// HMAC-SHA256 of the payload. Tokens cannot be revoked before they expire.
+type Signer struct {
+	secret []byte
+	now    func() time.Time
+}
+
+func NewSigner(secret []byte) *Signer {
+	return &Signer{
+		secret: secret,
+		now:    time.Now,
+	}
+}
+
+// Sign implements domain.ShareSigner
+func (s *Signer) Sign(articleID int64, expiresAt time.Time) string {
+	payload := base64.RawURLEncoding.EncodeToString(
+		[]byte(strconv.FormatInt(articleID, 10) + ":" + strconv.FormatInt(expiresAt.Unix(), 10)))
+	return payload + "." + s.signature(payload)
+}
+
+// Verify implements domain.ShareSigner
+func (s *Signer) Verify(token string) (int64, bool) {
+	payload, signature, ok := strings.Cut(token, ".")
+	if !ok || !hmac.Equal([]byte(signature), []byte(s.signature(payload))) {
+		return 0, false
+	}
+	raw, err := base64.RawURLEncoding.DecodeString(payload)
+	if err != nil {
+		return 0, false
+	}
+	id, expiry, ok := strings.Cut(string(raw), ":")
+	if !ok {
+		return 0, false
+	}
+	articleID, err := strconv.ParseInt(id, 10, 64)
+	if err != nil {
+		return 0, false
+	}
+	expiresAt, err := strconv.ParseInt(expiry, 10, 64)
+	if err != nil || s.now().Unix() >= expiresAt {
+		return 0, false
+	}
+	return articleID, true
+}
+
+func (s *Signer) signature(payload string) string {
+	mac := hmac.New(sha256.New, s.secret)
+	mac.Write([]byte(payload))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
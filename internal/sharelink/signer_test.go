@@ -0,0 +1,28 @@
+package sharelink
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSignAndVerify(t *testing.T) {
+	now := time.Unix(1_700_000_000, 0)
+	signer := NewSigner([]byte("secret"))
+	signer.now = func() time.Time { return now }
+
+	token := signer.Sign(42, now.Add(time.Hour))
+	id, ok := signer.Verify(token)
+	assert.True(t, ok)
+	assert.Equal(t, int64(42), id)
+
+	// tampered, foreign and expired tokens are rejected
+	_, ok = signer.Verify(token + "x")
+	assert.False(t, ok)
+	_, ok = NewSigner([]byte("other")).Verify(token)
+	assert.False(t, ok)
+	signer.now = func() time.Time { return now.Add(2 * time.Hour) }
+	_, ok = signer.Verify(token)
+	assert.False(t, ok)
+}
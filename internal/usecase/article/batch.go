@@ -0,0 +1,180 @@
+package article
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/bxcodec/go-clean-arch/domain"
+)
+
+// Batch applies operations on articles on behalf of userID and reports the
+// outcome of each at its index. In atomic mode nothing is applied unless every
+// operation succeeds, otherwise each valid operation is applied on its own.
+func (a *Service) Batch(ctx context.Context, userID int64, ops []domain.BatchOperation, atomic bool) ([]domain.BatchResult, error) {
+	if len(ops) == 0 || len(ops) > domain.MaxBatchSize {
+		return nil, domain.ErrBadParamInput
+	}
+
+	results := make([]domain.BatchResult, len(ops))
+	changes := make([]domain.ArticleChange, len(ops))
+	seen := make(map[int64]bool, len(ops))
+	failed := false
+	for i, op := range ops {
+		results[i] = domain.BatchResult{ArticleID: op.ArticleID, Action: op.Action}
+		if seen[op.ArticleID] {
+			// later operations would depend on the outcome of earlier ones
+			results[i].Err = domain.ErrConflict
+		} else {
+			seen[op.ArticleID] = true
+			changes[i], results[i].Err = a.prepareChange(ctx, userID, op)
+			changes[i].UserID = userID
+		}
+		failed = failed || results[i].Err != nil
+	}
+
+	if atomic {
+		if failed {
+			return results, nil
+		}
+		err := a.articleRepo.ApplyChanges(ctx, changes)
+		var itemErr *domain.BatchItemError
+		if errors.As(err, &itemErr) {
+			results[itemErr.Index].Err = itemErr.Err
+			return results, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		for i := range results {
+			results[i].Applied = true
+		}
+	} else {
+		for i := range changes {
+			if results[i].Err != nil {
+				continue
+			}
+			err := a.articleRepo.ApplyChanges(ctx, changes[i:i+1])
+			var itemErr *domain.BatchItemError
+			if errors.As(err, &itemErr) {
+				err = itemErr.Err
+			}
+			results[i].Err = err
+			results[i].Applied = err == nil
+		}
+	}
+
+	for _, res := range results {
+		if !res.Applied {
+			continue
+		}
+		if res.Action == domain.BatchDelete {
+			a.invalidate(ctx, res.ArticleID)
+		} else {
+			a.refreshCache(ctx, res.ArticleID)
+		}
+	}
+	return results, nil
+}
+
+// prepareChange checks that userID may apply the operation and turns it into
+// the change to write. Deleting takes the author, the other actions an editor.
+func (a *Service) prepareChange(ctx context.Context, userID int64, op domain.BatchOperation) (domain.ArticleChange, error) {
+	if !op.Action.Valid() {
+		return domain.ArticleChange{}, domain.ErrBadParamInput
+	}
+	existedArticle, err := a.articleRepo.GetByID(ctx, op.ArticleID)
+	if err != nil {
+		return domain.ArticleChange{}, err
+	}
+	role, err := a.contribRepo.Role(ctx, op.ArticleID, userID)
+	if err != nil {
+		return domain.ArticleChange{}, err
+	}
+
+	switch op.Action {
+	case domain.BatchDelete:
+		if !role.CanManage() {
+			return domain.ArticleChange{}, domain.ErrForbidden
+		}
+		return domain.ArticleChange{Delete: true, Article: domain.Article{ID: op.ArticleID}}, nil
+	case domain.BatchPublish:
+		if !role.CanEdit() {
+			return domain.ArticleChange{}, domain.ErrForbidden
+		}
+		return domain.ArticleChange{Article: domain.Article{
+			ID:        op.ArticleID,
+			Status:    domain.ArticleStatusPublished,
+			User:      existedArticle.User,
+			UpdatedAt: time.Now(),
+		}}, nil
+	}
+
+	if !role.CanEdit() {
+		return domain.ArticleChange{}, domain.ErrForbidden
+	}
+	ar := op.Changes
+	if ar.Title == "" && ar.Content == "" && ar.ContentFormat == "" && ar.Status == "" && ar.Visibility == "" && op.Tags == nil {
+		return domain.ArticleChange{}, domain.ErrBadParamInput
+	}
+	if ar.Status != "" && !ar.Status.Valid() {
+		return domain.ArticleChange{}, domain.ErrBadParamInput
+	}
+	if ar.Visibility != "" && !ar.Visibility.Valid() {
+		return domain.ArticleChange{}, domain.ErrBadParamInput
+	}
+	if ar.ContentFormat != "" && !ar.ContentFormat.Valid() {
+		return domain.ArticleChange{}, domain.ErrBadParamInput
+	}
+	if ar.Content != "" || ar.ContentFormat != "" {
+		if ar.Content == "" {
+			ar.Content = existedArticle.Content
+		}
+		if ar.ContentFormat == "" {
+			ar.ContentFormat = existedArticle.ContentFormat
+		}
+		if err := a.render(&ar); err != nil {
+			return domain.ArticleChange{}, err
+		}
+		if _, err := a.checkDuplicates(ctx, ar.Fingerprint, op.ArticleID, userID); err != nil {
+			return domain.ArticleChange{}, err
+		}
+	}
+	ar.ID = op.ArticleID
+	ar.User = existedArticle.User
+	ar.UpdatedAt = time.Now()
+
+	change := domain.ArticleChange{Article: ar}
+	if op.Tags != nil {
+		change.SetTags = true
+		if change.CategoryIDs, err = a.categoryIDs(ctx, op.Tags); err != nil {
+			return domain.ArticleChange{}, err
+		}
+	}
+	return change, nil
+}
+
+// categoryIDs resolves tags to their categories, every tag must exist
+func (a *Service) categoryIDs(ctx context.Context, tags []string) ([]int64, error) {
+	categories, err := a.categoryRepo.FetchByTags(ctx, tags)
+	if err != nil {
+		return nil, err
+	}
+	ids := make(map[string]int64, len(categories))
+	for _, c := range categories {
+		ids[c.Tag] = c.ID
+	}
+	res := make([]int64, 0, len(tags))
+	added := make(map[int64]bool, len(tags))
+	for _, tag := range tags {
+		id, ok := ids[tag]
+		if !ok {
+			return nil, domain.ErrBadParamInput
+		}
+		if !added[id] {
+			added[id] = true
+			res = append(res, id)
+		}
+	}
+	return res, nil
+}
@@ -0,0 +1,178 @@
+package article
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bxcodec/go-clean-arch/domain"
+)
+
+type fakeArticleRepo struct {
+	domain.ArticleRepository
+	articles map[int64]domain.Article
+	// failAt makes the change for that article id fail
+	failAt  int64
+	applied []domain.ArticleChange
+	deleted []int64
+}
+
+func (f *fakeArticleRepo) GetByID(_ context.Context, id int64) (domain.Article, error) {
+	ar, ok := f.articles[id]
+	if !ok {
+		return domain.Article{}, domain.ErrNotFound
+	}
+	return ar, nil
+}
+
+func (f *fakeArticleRepo) ApplyChanges(_ context.Context, changes []domain.ArticleChange) error {
+	for i, c := range changes {
+		if c.Article.ID == f.failAt {
+			return &domain.BatchItemError{Index: i, Err: domain.ErrConflict}
+		}
+	}
+	f.applied = append(f.applied, changes...)
+	return nil
+}
+
+func (f *fakeArticleRepo) Fetch(context.Context, string, int64, bool) ([]domain.Article, string, error) {
+	res := make([]domain.Article, 0, len(f.articles))
+	for id := int64(1); id <= int64(len(f.articles)); id++ {
+		res = append(res, f.articles[id])
+	}
+	return res, "", nil
+}
+
+func (f *fakeArticleRepo) Update(_ context.Context, ar *domain.Article, _ int64) error {
+	stored := f.articles[ar.ID]
+	if ar.Visibility != "" {
+		stored.Visibility = ar.Visibility
+	}
+	f.articles[ar.ID] = stored
+	return nil
+}
+
+func (f *fakeArticleRepo) Delete(_ context.Context, id int64) error {
+	f.deleted = append(f.deleted, id)
+	return nil
+}
+
+type fakeContribRepo struct {
+	domain.ContributorRepository
+	roles        map[int64]domain.ContributorRole
+	contributors map[int64][]domain.Contributor
+}
+
+func (f *fakeContribRepo) Role(_ context.Context, articleID, _ int64) (domain.ContributorRole, error) {
+	return f.roles[articleID], nil
+}
+
+func (f *fakeContribRepo) FetchByArticles(_ context.Context, ids []int64) (map[int64][]domain.Contributor, error) {
+	res := make(map[int64][]domain.Contributor, len(ids))
+	for _, id := range ids {
+		res[id] = f.contributors[id]
+	}
+	return res, nil
+}
+
+type fakeCache struct {
+	domain.ArticleCache
+	deleted []int64
+}
+
+func (f *fakeCache) Del(_ context.Context, id int64) error {
+	f.deleted = append(f.deleted, id)
+	return nil
+}
+
+type fakeQueue struct {
+	domain.JobQueue
+	jobs int
+	err  error
+}
+
+func (f *fakeQueue) Enqueue(context.Context, string, []byte) error {
+	f.jobs++
+	return f.err
+}
+
+func newBatchService(repo *fakeArticleRepo, cache *fakeCache, queue *fakeQueue) *Service {
+	roles := &fakeContribRepo{roles: map[int64]domain.ContributorRole{
+		1: domain.ContributorAuthor,
+		2: domain.ContributorEditor,
+		3: domain.ContributorEditor,
+	}}
+	return NewService(repo, nil, cache, queue, nil, nil, nil, roles, nil, nil, nil, DuplicatePolicy{})
+}
+
+func testArticles() map[int64]domain.Article {
+	return map[int64]domain.Article{1: {ID: 1}, 2: {ID: 2}, 3: {ID: 3}, 4: {ID: 4}}
+}
+
+func TestBatchAuthorizesEachOperation(t *testing.T) {
+	repo := &fakeArticleRepo{articles: testArticles()}
+	cache, queue := &fakeCache{}, &fakeQueue{}
+	svc := newBatchService(repo, cache, queue)
+
+	results, err := svc.Batch(context.Background(), 7, []domain.BatchOperation{
+		{Action: domain.BatchDelete, ArticleID: 1},
+		{Action: domain.BatchPublish, ArticleID: 2},
+		{Action: domain.BatchDelete, ArticleID: 3},
+		{Action: domain.BatchPublish, ArticleID: 4},
+		{Action: domain.BatchPublish, ArticleID: 2},
+	}, false)
+	require.NoError(t, err)
+
+	assert.True(t, results[0].Applied)
+	assert.True(t, results[1].Applied)
+	assert.ErrorIs(t, results[2].Err, domain.ErrForbidden, "editors cannot delete")
+	assert.ErrorIs(t, results[3].Err, domain.ErrForbidden)
+	assert.ErrorIs(t, results[4].Err, domain.ErrConflict, "an article appears once per batch")
+	assert.Len(t, repo.applied, 2)
+	assert.Equal(t, []int64{1, 2}, cache.deleted, "every changed article leaves the cache at once")
+	assert.Equal(t, 1, queue.jobs)
+}
+
+func TestBatchAtomic(t *testing.T) {
+	ops := []domain.BatchOperation{
+		{Action: domain.BatchPublish, ArticleID: 1},
+		{Action: domain.BatchPublish, ArticleID: 2},
+	}
+
+	t.Run("invalid operation applies nothing", func(t *testing.T) {
+		repo := &fakeArticleRepo{articles: testArticles()}
+		svc := newBatchService(repo, &fakeCache{}, &fakeQueue{})
+
+		results, err := svc.Batch(context.Background(), 7, append(ops, domain.BatchOperation{Action: domain.BatchPublish, ArticleID: 9}), true)
+		require.NoError(t, err)
+		assert.False(t, results[0].Applied)
+		assert.NoError(t, results[0].Err)
+		assert.ErrorIs(t, results[2].Err, domain.ErrNotFound)
+		assert.Empty(t, repo.applied)
+	})
+
+	t.Run("failing write is reported on its operation", func(t *testing.T) {
+		repo := &fakeArticleRepo{articles: testArticles(), failAt: 2}
+		queue := &fakeQueue{}
+		svc := newBatchService(repo, &fakeCache{}, queue)
+
+		results, err := svc.Batch(context.Background(), 7, ops, true)
+		require.NoError(t, err)
+		assert.False(t, results[0].Applied)
+		assert.ErrorIs(t, results[1].Err, domain.ErrConflict)
+		assert.Zero(t, queue.jobs)
+	})
+
+	t.Run("every operation applied", func(t *testing.T) {
+		repo := &fakeArticleRepo{articles: testArticles()}
+		queue := &fakeQueue{}
+		svc := newBatchService(repo, &fakeCache{}, queue)
+
+		results, err := svc.Batch(context.Background(), 7, ops, true)
+		require.NoError(t, err)
+		assert.True(t, results[0].Applied && results[1].Applied)
+		assert.Equal(t, 2, queue.jobs)
+	})
+}
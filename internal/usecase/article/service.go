@@ -2,101 +2,152 @@ package article
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"time"
 
 	"github.com/redis/go-redis/v9"
 	"github.com/sirupsen/logrus"
-	"golang.org/x/sync/errgroup"
 
 	"github.com/bxcodec/go-clean-arch/domain"
 )
 
+// JobRefreshCache is the queue job type that reloads an article into the cache
+const JobRefreshCache = "article.cache.refresh"
+
+const (
+	// DefaultShareLinkTTL is the lifetime of a share link when none is requested
+	DefaultShareLinkTTL = 7 * 24 * time.Hour
+	// MaxShareLinkTTL bounds the lifetime of share links, they cannot be revoked
+	MaxShareLinkTTL = 30 * 24 * time.Hour
+)
+
 type Service struct {
 	articleRepo  domain.ArticleRepository
 	userRepo     domain.UserRepository
 	articleCache domain.ArticleCache
+	jobQueue     domain.JobQueue
+	viewRecorder domain.ViewRecorder
+	coViews      domain.CoViewRecorder
+	seriesRepo   domain.SeriesRepository
+	contribRepo  domain.ContributorRepository
+	shareSigner  domain.ShareSigner
+	renderer     domain.ContentRenderer
+	categoryRepo domain.CategoryRepository
+	duplicates   DuplicatePolicy
 }
 
 // NewService will create a new article service object
-func NewService(a domain.ArticleRepository, u domain.UserRepository, ac domain.ArticleCache) *Service {
+func NewService(a domain.ArticleRepository, u domain.UserRepository, ac domain.ArticleCache, q domain.JobQueue, vr domain.ViewRecorder, cv domain.CoViewRecorder, s domain.SeriesRepository, c domain.ContributorRepository, ss domain.ShareSigner, r domain.ContentRenderer, cr domain.CategoryRepository, dp DuplicatePolicy) *Service {
+	if dp.Threshold <= 0 || dp.Threshold > 1 {
+		dp.Threshold = DefaultSimilarityThreshold
+	}
 	return &Service{
 		articleRepo:  a,
 		userRepo:     u,
 		articleCache: ac,
+		jobQueue:     q,
+		viewRecorder: vr,
+		coViews:      cv,
+		seriesRepo:   s,
+		contribRepo:  c,
+		shareSigner:  ss,
+		renderer:     r,
+		categoryRepo: cr,
+		duplicates:   dp,
 	}
 }
 
-/*
-* In this function below, I'm using errgroup with the pipeline pattern
-* Look how this works in this package explanation
-* in godoc: https://godoc.org/golang.org/x/sync/errgroup#ex-Group--Pipeline
- */
-func (a *Service) fillUserDetails(ctx context.Context, data []domain.Article) ([]domain.Article, error) {
-	g, ctx := errgroup.WithContext(ctx)
-	// Get the User's id
-	mapUsers := map[int64]domain.User{}
-
-	for _, article := range data { //nolint
-		mapUsers[article.User.ID] = domain.User{}
-	}
-	// Using goroutine to fetch the User's detail
-	chanUser := make(chan domain.User)
-	for UserID := range mapUsers {
-		UserID := UserID
-		g.Go(func() error {
-			res, err := a.userRepo.GetByID(ctx, UserID)
-			if err != nil {
-				return err
-			}
-			chanUser <- res
-			return nil
-		})
-	}
+type refreshCachePayload struct {
+	ArticleID int64 `json:"article_id"`
+}
 
-	go func() {
-		defer close(chanUser)
-		err := g.Wait()
-		if err != nil {
-			logrus.Error(err)
-			return
-		}
+// enqueueRefreshCache schedules a cache reload
+func (a *Service) enqueueRefreshCache(ctx context.Context, id int64) {
+	payload, err := json.Marshal(refreshCachePayload{ArticleID: id})
+	if err != nil {
+		logrus.Warnf("failed to encode cache refresh job: %v", err)
+		return
+	}
+	if err := a.jobQueue.Enqueue(ctx, JobRefreshCache, payload); err != nil {
+		logrus.Warnf("failed to enqueue cache refresh for article %d: %v", id, err)
+	}
+}
 
-	}()
+// refreshCache drops the cached article right away, as reads decide access from
+// it, and schedules its reload. A failed reload only leaves the cache cold.
+func (a *Service) refreshCache(ctx context.Context, id int64) {
+	a.invalidate(ctx, id)
+	a.enqueueRefreshCache(ctx, id)
+}
 
-	for User := range chanUser {
-		if User != (domain.User{}) {
-			mapUsers[User.ID] = User
-		}
+// HandleRefreshCache reloads the article of a JobRefreshCache job from the database into the cache
+func (a *Service) HandleRefreshCache(ctx context.Context, job domain.Job) error {
+	var payload refreshCachePayload
+	if err := json.Unmarshal(job.Payload, &payload); err != nil {
+		// a malformed payload will never succeed, drop it
+		logrus.Errorf("invalid cache refresh job %s: %v", job.ID, err)
+		return nil
 	}
 
-	if err := g.Wait(); err != nil {
-		return nil, err
+	res, err := a.articleRepo.GetByID(ctx, payload.ArticleID)
+	if errors.Is(err, domain.ErrNotFound) {
+		return a.articleCache.Del(ctx, payload.ArticleID)
+	}
+	if err != nil {
+		return err
+	}
+	list := []domain.Article{res}
+	if err := a.fillContributors(ctx, list); err != nil {
+		return err
 	}
+	return a.articleCache.Set(ctx, &list[0])
+}
 
-	// merge the User's data
-	for index, item := range data { //nolint
-		if a, ok := mapUsers[item.User.ID]; ok {
-			data[index].User = a
+// fillContributors resolves the author and the other contributors of every
+// article with a single query
+func (a *Service) fillContributors(ctx context.Context, data []domain.Article) error {
+	ids := make([]int64, len(data))
+	for i := range data {
+		ids[i] = data[i].ID
+	}
+	contributors, err := a.contribRepo.FetchByArticles(ctx, ids)
+	if err != nil {
+		return err
+	}
+	for i := range data {
+		data[i].Contributors = contributors[data[i].ID]
+		for _, c := range data[i].Contributors {
+			if c.Role == domain.ContributorAuthor {
+				data[i].User = c.User
+			}
 		}
 	}
-	return data, nil
+	return nil
 }
 
-func (a *Service) Fetch(ctx context.Context, cursor string, num int64) (res []domain.Article, nextCursor string, err error) {
-	res, nextCursor, err = a.articleRepo.Fetch(ctx, cursor, num)
+// Fetch returns a page of the listed articles, with their content only when withContent is set
+func (a *Service) Fetch(ctx context.Context, cursor string, num int64, withContent bool) (res []domain.Article, nextCursor string, err error) {
+	res, nextCursor, err = a.articleRepo.Fetch(ctx, cursor, num, withContent)
 	if err != nil {
 		return nil, "", err
 	}
 
-	res, err = a.fillUserDetails(ctx, res)
-	if err != nil {
-		nextCursor = ""
+	if err = a.fillContributors(ctx, res); err != nil {
+		return nil, "", err
+	}
+	if withContent {
+		for i := range res {
+			a.renderMissing(&res[i])
+		}
 	}
 	return
 }
 
-func (a *Service) GetByID(ctx context.Context, id int64) (res domain.Article, err error) {
+// GetByID returns an article the viewer may read. Drafts and private articles
+// are reported as not found unless the viewer contributes to them or follows
+// a valid share link.
+func (a *Service) GetByID(ctx context.Context, id int64, viewer domain.Viewer) (res domain.Article, err error) {
 	res, err = a.articleCache.Get(ctx, id)
 
 	if err != nil {
@@ -108,12 +159,13 @@ func (a *Service) GetByID(ctx context.Context, id int64) (res domain.Article, er
 		if err != nil {
 			return domain.Article{}, err
 		}
+		a.renderMissing(&res)
 
-		resUser, err := a.userRepo.GetByID(ctx, res.User.ID)
-		if err != nil {
+		list := []domain.Article{res}
+		if err := a.fillContributors(ctx, list); err != nil {
 			return domain.Article{}, err
 		}
-		res.User = resUser
+		res = list[0]
 
 		go func(art domain.Article) {
 			if err := a.articleCache.Set(ctx, &art); err != nil {
@@ -122,18 +174,232 @@ func (a *Service) GetByID(ctx context.Context, id int64) (res domain.Article, er
 		}(res)
 	}
 
+	if !a.canRead(res, viewer) {
+		return domain.Article{}, domain.ErrNotFound
+	}
+
+	a.fillSeries(ctx, &res)
+
 	deltaViews, err := a.articleCache.Incr(ctx, id)
 	if err != nil {
 		return res, err
 	} else {
+		a.viewRecorder.RecordView(id)
+		a.recordCoView(ctx, viewer, res)
 		res.Views += deltaViews
 		return res, err
 	}
 }
 
+// recordCoView remembers what signed in readers read for the related articles,
+// reads of hidden articles are left out so they are never recommended
+func (a *Service) recordCoView(ctx context.Context, viewer domain.Viewer, ar domain.Article) {
+	if viewer.UserID == 0 || !ar.Listed() {
+		return
+	}
+	if err := a.coViews.RecordCoView(ctx, viewer.UserID, ar.ID); err != nil {
+		logrus.Warnf("failed to record co-view of article %d: %v", ar.ID, err)
+	}
+}
+
+func (a *Service) canRead(ar domain.Article, viewer domain.Viewer) bool {
+	if ar.Reachable() || ar.Contributes(viewer.UserID) {
+		return true
+	}
+	if viewer.ShareToken == "" {
+		return false
+	}
+	id, ok := a.shareSigner.Verify(viewer.ShareToken)
+	return ok && id == ar.ID
+}
+
+// CreateShareLink grants read access to an article to anyone holding the
+// link until it expires, only contributors allowed to edit the article share it
+func (a *Service) CreateShareLink(ctx context.Context, userID, articleID int64, ttl time.Duration) (domain.ShareLink, error) {
+	if ttl == 0 {
+		ttl = DefaultShareLinkTTL
+	}
+	if ttl < 0 || ttl > MaxShareLinkTTL {
+		return domain.ShareLink{}, domain.ErrBadParamInput
+	}
+	if _, err := a.articleRepo.GetByID(ctx, articleID); err != nil {
+		return domain.ShareLink{}, err
+	}
+	role, err := a.contribRepo.Role(ctx, articleID, userID)
+	if err != nil {
+		return domain.ShareLink{}, err
+	}
+	if !role.CanEdit() {
+		return domain.ShareLink{}, domain.ErrForbidden
+	}
+
+	expiresAt := time.Now().Add(ttl).Truncate(time.Second)
+	return domain.ShareLink{
+		ArticleID: articleID,
+		Token:     a.shareSigner.Sign(articleID, expiresAt),
+		ExpiresAt: expiresAt,
+	}, nil
+}
+
+// fillSeries adds the series navigation of the article, the article is still
+// served without it when the series cannot be loaded
+func (a *Service) fillSeries(ctx context.Context, ar *domain.Article) {
+	series, err := a.seriesRepo.GetByArticle(ctx, ar.ID)
+	if err != nil {
+		if !errors.Is(err, domain.ErrNotFound) {
+			logrus.Warnf("failed to load series of article %d: %v", ar.ID, err)
+		}
+		return
+	}
+	if nav, ok := series.Navigation(ar.ID); ok {
+		ar.Series = &nav
+	}
+}
+
+// render converts the source of an article into its sanitized HTML and
+// computes the metadata shown in listings
+func (a *Service) render(ar *domain.Article) error {
+	rendered, err := a.renderer.Render(ar.ContentFormat, ar.Content)
+	if err != nil {
+		return err
+	}
+	ar.ContentHTML = rendered.HTML
+	ar.Excerpt = rendered.Excerpt
+	ar.WordCount = rendered.WordCount
+	ar.ReadingMinutes = domain.ReadingMinutes(rendered.WordCount)
+	ar.TOC = rendered.TOC
+	ar.Fingerprint = rendered.Fingerprint
+	return nil
+}
+
+// renderMissing renders an article the backfill has not rendered yet so that
+// it is not served without content, the rendering is left for the backfill to store
+func (a *Service) renderMissing(ar *domain.Article) {
+	if ar.ContentHTML != "" || ar.Content == "" {
+		return
+	}
+	if !ar.ContentFormat.Valid() {
+		ar.ContentFormat = domain.ContentFormatHTML
+	}
+	if err := a.render(ar); err != nil {
+		logrus.Warnf("failed to render article %d: %v", ar.ID, err)
+	}
+}
+
+// Source returns an article with its source as written, for the users who may edit it
+func (a *Service) Source(ctx context.Context, userID, articleID int64) (domain.Article, error) {
+	ar, err := a.articleRepo.GetByID(ctx, articleID)
+	if err != nil {
+		return domain.Article{}, err
+	}
+	role, err := a.contribRepo.Role(ctx, articleID, userID)
+	if err != nil {
+		return domain.Article{}, err
+	}
+	if !role.CanEdit() {
+		return domain.Article{}, domain.ErrForbidden
+	}
+	return ar, nil
+}
+
+// RenderPending renders up to limit articles stored before rendering or their
+// metadata existed and reports how many it rendered. Sources without a format are HTML.
+func (a *Service) RenderPending(ctx context.Context, limit int) (int, error) {
+	var afterID int64
+	rendered := 0
+	for rendered < limit {
+		list, err := a.articleRepo.FetchUnrendered(ctx, afterID, limit-rendered)
+		if err != nil {
+			return rendered, err
+		}
+		for i := range list {
+			ar := &list[i]
+			afterID = ar.ID
+			if !ar.ContentFormat.Valid() {
+				ar.ContentFormat = domain.ContentFormatHTML
+			}
+			if err := a.render(ar); err != nil {
+				// leave it for the next run rather than blocking the others
+				logrus.Warnf("failed to render article %d: %v", ar.ID, err)
+				continue
+			}
+			if err := a.articleRepo.SaveRendering(ctx, ar); err != nil {
+				return rendered, err
+			}
+			a.invalidate(ctx, ar.ID)
+			rendered++
+		}
+		if len(list) == 0 {
+			break
+		}
+	}
+	return rendered, nil
+}
+
+// Update changes an article on behalf of ar.User, who must be a contributor
+// allowed to edit it. On success ar holds the full updated article.
 func (a *Service) Update(ctx context.Context, ar *domain.Article) (err error) {
+	if ar.Status != "" && !ar.Status.Valid() {
+		return domain.ErrBadParamInput
+	}
+	if ar.Visibility != "" && !ar.Visibility.Valid() {
+		return domain.ErrBadParamInput
+	}
+	if ar.ContentFormat != "" && !ar.ContentFormat.Valid() {
+		return domain.ErrBadParamInput
+	}
+	existedArticle, err := a.articleRepo.GetByID(ctx, ar.ID)
+	if err != nil {
+		return
+	}
+	// fail before rendering, the repository checks the role again under the row lock
+	editorID := ar.User.ID
+	role, err := a.contribRepo.Role(ctx, ar.ID, editorID)
+	if err != nil {
+		return
+	}
+	if !role.CanEdit() {
+		return domain.ErrForbidden
+	}
+
+	if ar.Content != "" || ar.ContentFormat != "" {
+		if ar.Content == "" {
+			ar.Content = existedArticle.Content
+		}
+		if ar.ContentFormat == "" {
+			ar.ContentFormat = existedArticle.ContentFormat
+		}
+		if err = a.render(ar); err != nil {
+			return
+		}
+	}
+	var similar []domain.SimilarArticle
+	if ar.Content != "" {
+		if similar, err = a.checkDuplicates(ctx, ar.Fingerprint, ar.ID, editorID); err != nil {
+			return
+		}
+	}
+
+	// the article stays with its author whoever edits it
+	ar.User = existedArticle.User
 	ar.UpdatedAt = time.Now()
-	return a.articleRepo.Update(ctx, ar)
+	err = a.articleRepo.Update(ctx, ar, editorID)
+	if err != nil {
+		return
+	}
+	a.refreshCache(ctx, ar.ID)
+
+	updated, err := a.articleRepo.GetByID(ctx, ar.ID)
+	if err != nil {
+		return
+	}
+	list := []domain.Article{updated}
+	if err = a.fillContributors(ctx, list); err != nil {
+		return
+	}
+	*ar = list[0]
+	ar.SimilarContent = similar
+	return
 }
 
 func (a *Service) GetByTitle(ctx context.Context, title string) (res domain.Article, err error) {
@@ -152,10 +418,37 @@ func (a *Service) GetByTitle(ctx context.Context, title string) (res domain.Arti
 }
 
 func (a *Service) Store(ctx context.Context, m *domain.Article) (err error) {
+	if m.Status == "" {
+		m.Status = domain.ArticleStatusPublished
+	}
+	if !m.Status.Valid() {
+		return domain.ErrBadParamInput
+	}
+	if m.Visibility == "" {
+		m.Visibility = domain.ArticleVisibilityPublic
+	}
+	if !m.Visibility.Valid() {
+		return domain.ErrBadParamInput
+	}
+	if m.ContentFormat == "" {
+		m.ContentFormat = domain.ContentFormatMarkdown
+	}
+	if !m.ContentFormat.Valid() {
+		return domain.ErrBadParamInput
+	}
+	if err = a.render(m); err != nil {
+		return
+	}
+
 	existedArticle, _ := a.GetByTitle(ctx, m.Title) // ignore if any error
-	if existedArticle != (domain.Article{}) {
+	if existedArticle.ID != 0 {
 		return domain.ErrConflict
 	}
+	similar, err := a.checkDuplicates(ctx, m.Fingerprint, 0, m.User.ID)
+	if err != nil {
+		return
+	}
+	m.SimilarContent = similar
 
 	err = a.articleRepo.Store(ctx, m)
 	if err != nil {
@@ -167,17 +460,22 @@ func (a *Service) Store(ctx context.Context, m *domain.Article) (err error) {
 	}
 	m.User.Name = userDetail.Name
 	m.User.Username = userDetail.Username
+	m.Contributors = []domain.Contributor{{User: m.User, Role: domain.ContributorAuthor}}
 	return
 }
 
-func (a *Service) Delete(ctx context.Context, id int64) (err error) {
+// Delete removes an article, only its author deletes it
+func (a *Service) Delete(ctx context.Context, userID, id int64) (err error) {
 	existedArticle, err := a.articleRepo.GetByID(ctx, id)
 	if err != nil {
 		return
 	}
-	if existedArticle == (domain.Article{}) {
+	if existedArticle.ID == 0 {
 		return domain.ErrNotFound
 	}
+	if err = a.canManage(ctx, id, userID); err != nil {
+		return
+	}
 	err = a.articleRepo.Delete(ctx, id)
 	if err != nil {
 		return
@@ -189,6 +487,84 @@ func (a *Service) Delete(ctx context.Context, id int64) (err error) {
 	return
 }
 
+// Contributors returns the contributors of an article the viewer may read, the author first
+func (a *Service) Contributors(ctx context.Context, articleID int64, viewer domain.Viewer) ([]domain.Contributor, error) {
+	article, err := a.articleRepo.GetByID(ctx, articleID)
+	if err != nil {
+		return nil, err
+	}
+	contributors, err := a.contribRepo.FetchByArticles(ctx, []int64{articleID})
+	if err != nil {
+		return nil, err
+	}
+	article.Contributors = contributors[articleID]
+	if !a.canRead(article, viewer) {
+		return nil, domain.ErrNotFound
+	}
+	return article.Contributors, nil
+}
+
+// SetContributor adds userID to the contributors of an article or changes
+// their role, only the author manages contributors
+func (a *Service) SetContributor(ctx context.Context, actorID, articleID, userID int64, role domain.ContributorRole) ([]domain.Contributor, error) {
+	if !role.Valid() {
+		return nil, domain.ErrBadParamInput
+	}
+	article, err := a.articleRepo.GetByID(ctx, articleID)
+	if err != nil {
+		return nil, err
+	}
+	if err := a.canManage(ctx, articleID, actorID); err != nil {
+		return nil, err
+	}
+	if userID == article.User.ID {
+		// the author keeps the author role
+		return nil, domain.ErrBadParamInput
+	}
+	if _, err := a.userRepo.GetByID(ctx, userID); err != nil {
+		return nil, err
+	}
+
+	if err := a.contribRepo.Save(ctx, articleID, userID, role); err != nil {
+		return nil, err
+	}
+	a.invalidate(ctx, articleID)
+	return a.Contributors(ctx, articleID, domain.Viewer{UserID: actorID})
+}
+
+// RemoveContributor removes userID from the contributors of an article, the
+// author removes anyone and contributors may remove themselves
+func (a *Service) RemoveContributor(ctx context.Context, actorID, articleID, userID int64) error {
+	if actorID != userID {
+		if err := a.canManage(ctx, articleID, actorID); err != nil {
+			return err
+		}
+	}
+	if err := a.contribRepo.Remove(ctx, articleID, userID); err != nil {
+		return err
+	}
+	a.invalidate(ctx, articleID)
+	return nil
+}
+
+func (a *Service) canManage(ctx context.Context, articleID, userID int64) error {
+	role, err := a.contribRepo.Role(ctx, articleID, userID)
+	if err != nil {
+		return err
+	}
+	if !role.CanManage() {
+		return domain.ErrForbidden
+	}
+	return nil
+}
+
+// invalidate drops the cached article so its contributors are reloaded
+func (a *Service) invalidate(ctx context.Context, id int64) {
+	if err := a.articleCache.Del(ctx, id); err != nil {
+		logrus.Warnf("failed to invalidate cached article %d: %v", id, err)
+	}
+}
+
 func (a *Service) AddViews(ctx context.Context, id int64, deltaViews int64) error {
-	return a.articleRepo.AddViews(ctx, id, deltaViews)
+	return a.articleRepo.AddViews(ctx, domain.ViewBatch{Views: map[int64]int64{id: deltaViews}})
 }
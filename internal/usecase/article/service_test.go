@@ -0,0 +1,120 @@
+package article
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bxcodec/go-clean-arch/domain"
+)
+
+func TestDeleteRequiresAuthor(t *testing.T) {
+	repo := &fakeArticleRepo{articles: testArticles()}
+	cache := &fakeCache{}
+	s := newBatchService(repo, cache, &fakeQueue{})
+
+	// editors edit the article but do not delete it
+	assert.ErrorIs(t, s.Delete(context.Background(), 9, 2), domain.ErrForbidden)
+	assert.ErrorIs(t, s.Delete(context.Background(), 9, 4), domain.ErrForbidden)
+	assert.ErrorIs(t, s.Delete(context.Background(), 9, 5), domain.ErrNotFound)
+	assert.Empty(t, repo.deleted)
+
+	require.NoError(t, s.Delete(context.Background(), 9, 1))
+	assert.Equal(t, []int64{1}, repo.deleted)
+	assert.Equal(t, []int64{1}, cache.deleted)
+}
+
+func TestUpdateDropsCachedArticle(t *testing.T) {
+	repo := &fakeArticleRepo{articles: testArticles()}
+	cache := &fakeCache{}
+	queue := &fakeQueue{err: errors.New("queue unavailable")}
+	s := newBatchService(repo, cache, queue)
+
+	// reads decide access from the cached copy, it must not outlive the change
+	ar := &domain.Article{ID: 2, User: domain.User{ID: 9}, Visibility: domain.ArticleVisibilityPrivate}
+	require.NoError(t, s.Update(context.Background(), ar))
+	assert.Equal(t, []int64{2}, cache.deleted)
+	assert.Equal(t, 1, queue.jobs)
+	assert.Equal(t, domain.ArticleVisibilityPrivate, repo.articles[2].Visibility)
+}
+
+func TestContributorsFollowReadAccess(t *testing.T) {
+	repo := &fakeArticleRepo{articles: map[int64]domain.Article{
+		1: {ID: 1, Status: domain.ArticleStatusPublished, Visibility: domain.ArticleVisibilityPublic},
+		2: {ID: 2, Status: domain.ArticleStatusDraft, Visibility: domain.ArticleVisibilityPublic},
+		3: {ID: 3, Status: domain.ArticleStatusPublished, Visibility: domain.ArticleVisibilityPrivate},
+	}}
+	contribs := &fakeContribRepo{contributors: map[int64][]domain.Contributor{
+		1: {{User: domain.User{ID: 7}, Role: domain.ContributorAuthor}},
+		2: {{User: domain.User{ID: 7}, Role: domain.ContributorAuthor}},
+		3: {{User: domain.User{ID: 7}, Role: domain.ContributorAuthor}, {User: domain.User{ID: 8}, Role: domain.ContributorEditor}},
+	}}
+	s := NewService(repo, nil, &fakeCache{}, &fakeQueue{}, nil, nil, nil, contribs, nil, nil, nil, DuplicatePolicy{})
+	ctx := context.Background()
+
+	res, err := s.Contributors(ctx, 1, domain.Viewer{})
+	require.NoError(t, err)
+	assert.Len(t, res, 1)
+
+	// drafts and private articles do not reveal who works on them
+	for _, id := range []int64{2, 3} {
+		_, err := s.Contributors(ctx, id, domain.Viewer{})
+		assert.ErrorIs(t, err, domain.ErrNotFound, "article %d", id)
+		_, err = s.Contributors(ctx, id, domain.Viewer{UserID: 9})
+		assert.ErrorIs(t, err, domain.ErrNotFound, "article %d", id)
+	}
+
+	res, err = s.Contributors(ctx, 3, domain.Viewer{UserID: 8})
+	require.NoError(t, err)
+	assert.Len(t, res, 2)
+}
+
+type fakeRenderer struct {
+	renders int
+}
+
+func (f *fakeRenderer) Render(format domain.ContentFormat, source string) (domain.RenderedContent, error) {
+	f.renders++
+	return domain.RenderedContent{HTML: "<p>" + source + "</p>", WordCount: 1}, nil
+}
+
+func TestFetchRendersUnrenderedArticles(t *testing.T) {
+	repo := &fakeArticleRepo{articles: map[int64]domain.Article{
+		// stored before rendering existed, the backfill has not reached it yet
+		1: {ID: 1, Content: "old"},
+		2: {ID: 2, Content: "new", ContentFormat: domain.ContentFormatMarkdown, ContentHTML: "<p>new</p>"},
+	}}
+	renderer := &fakeRenderer{}
+	s := NewService(repo, nil, &fakeCache{}, &fakeQueue{}, nil, nil, nil, &fakeContribRepo{}, nil, renderer, nil, DuplicatePolicy{})
+
+	res, _, err := s.Fetch(context.Background(), "", 10, true)
+	require.NoError(t, err)
+	require.Len(t, res, 2)
+	assert.Equal(t, "<p>old</p>", res[0].ContentHTML)
+	assert.Equal(t, domain.ContentFormatHTML, res[0].ContentFormat)
+	assert.Equal(t, "<p>new</p>", res[1].ContentHTML)
+	assert.Equal(t, 1, renderer.renders)
+
+	// summaries carry no content to render
+	_, _, err = s.Fetch(context.Background(), "", 10, false)
+	require.NoError(t, err)
+	assert.Equal(t, 1, renderer.renders)
+}
+
+func TestSourceRequiresEditRights(t *testing.T) {
+	articles := testArticles()
+	articles[1] = domain.Article{ID: 1, Content: "# draft"}
+	s := newBatchService(&fakeArticleRepo{articles: articles}, &fakeCache{}, &fakeQueue{})
+
+	ar, err := s.Source(context.Background(), 9, 1)
+	require.NoError(t, err)
+	assert.Equal(t, "# draft", ar.Content)
+
+	_, err = s.Source(context.Background(), 9, 4)
+	assert.ErrorIs(t, err, domain.ErrForbidden)
+	_, err = s.Source(context.Background(), 9, 5)
+	assert.ErrorIs(t, err, domain.ErrNotFound)
+}
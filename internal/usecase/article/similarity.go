@@ -0,0 +1,103 @@
+package article
+
+import (
+	"context"
+
+	"github.com/bxcodec/go-clean-arch/domain"
+)
+
+const (
+	// DefaultSimilarityThreshold is the similarity from which articles are
+	// near duplicates, 0.9 lets their fingerprints differ by 6 bits
+	DefaultSimilarityThreshold = 0.9
+	// maxSimilar bounds the near duplicates listed for an article
+	maxSimilar = 20
+)
+
+// DuplicatePolicy decides how near duplicates are treated on store and update
+type DuplicatePolicy struct {
+	// Threshold is the similarity from 0 to 1 from which content is a near
+	// duplicate, zero falls back to DefaultSimilarityThreshold
+	Threshold float64
+	// Reject refuses content that is a near duplicate of another article
+	// instead of only reporting it
+	Reject bool
+}
+
+// similar returns the near duplicates of a fingerprint other than the article excludeID
+func (a *Service) similar(ctx context.Context, fingerprint uint64, excludeID int64) ([]domain.SimilarArticle, error) {
+	if fingerprint == 0 {
+		return nil, nil
+	}
+	return a.articleRepo.FetchSimilar(ctx, fingerprint, domain.MaxFingerprintDistance(a.duplicates.Threshold), excludeID, maxSimilar)
+}
+
+// checkDuplicates returns the near duplicates of new content of the article
+// excludeID as userID may see them, failing with ErrDuplicateContent when the
+// policy rejects them
+func (a *Service) checkDuplicates(ctx context.Context, fingerprint uint64, excludeID, userID int64) ([]domain.SimilarArticle, error) {
+	similar, err := a.similar(ctx, fingerprint, excludeID)
+	if err != nil {
+		return nil, err
+	}
+	if a.duplicates.Reject && len(similar) > 0 {
+		return nil, domain.ErrDuplicateContent
+	}
+	return hideUnlisted(similar, userID), nil
+}
+
+// hideUnlisted keeps only the similarity of the near duplicates that are
+// neither listed nor written by userID, so drafts and private articles of
+// others are reported without revealing them
+func hideUnlisted(similar []domain.SimilarArticle, userID int64) []domain.SimilarArticle {
+	for i := range similar {
+		ar := &similar[i].Article
+		if ar.Listed() || ar.User.ID == userID {
+			continue
+		}
+		similar[i] = domain.SimilarArticle{Distance: similar[i].Distance, Hidden: true}
+	}
+	return similar
+}
+
+// SimilarContent returns the near duplicates of an article, closest first,
+// to the users who may edit it
+func (a *Service) SimilarContent(ctx context.Context, userID, articleID int64) ([]domain.SimilarArticle, error) {
+	ar, err := a.articleRepo.GetByID(ctx, articleID)
+	if err != nil {
+		return nil, err
+	}
+	role, err := a.contribRepo.Role(ctx, articleID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if !role.CanEdit() {
+		return nil, domain.ErrForbidden
+	}
+	similar, err := a.similar(ctx, ar.Fingerprint, articleID)
+	if err != nil {
+		return nil, err
+	}
+	return a.fillSimilarUsers(ctx, hideUnlisted(similar, userID))
+}
+
+// fillSimilarUsers loads the authors of near duplicates
+func (a *Service) fillSimilarUsers(ctx context.Context, similar []domain.SimilarArticle) ([]domain.SimilarArticle, error) {
+	users := map[int64]domain.User{}
+	for i := range similar {
+		if similar[i].Hidden {
+			continue
+		}
+		id := similar[i].Article.User.ID
+		u, ok := users[id]
+		if !ok {
+			var err error
+			if u, err = a.userRepo.GetByID(ctx, id); err != nil {
+				return nil, err
+			}
+			users[id] = u
+		}
+		similar[i].Article.User = u
+	}
+	return similar, nil
+}
@@ -0,0 +1,98 @@
+package article
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bxcodec/go-clean-arch/domain"
+)
+
+type fakeSimilarRepo struct {
+	fakeArticleRepo
+	maxDistance int
+}
+
+func (f *fakeSimilarRepo) FetchSimilar(_ context.Context, fingerprint uint64, maxDistance int, excludeID int64, _ int) ([]domain.SimilarArticle, error) {
+	f.maxDistance = maxDistance
+	var res []domain.SimilarArticle
+	for id, ar := range f.articles {
+		d := domain.FingerprintDistance(fingerprint, ar.Fingerprint)
+		if id != excludeID && ar.Fingerprint != 0 && d <= maxDistance {
+			res = append(res, domain.SimilarArticle{Article: ar, Distance: d})
+		}
+	}
+	return res, nil
+}
+
+type fakeUserRepo struct {
+	domain.UserRepository
+	users map[int64]domain.User
+}
+
+func (f *fakeUserRepo) GetByID(_ context.Context, id int64) (domain.User, error) {
+	u, ok := f.users[id]
+	if !ok {
+		return domain.User{}, domain.ErrNotFound
+	}
+	return u, nil
+}
+
+func TestCheckDuplicates(t *testing.T) {
+	repo := &fakeSimilarRepo{fakeArticleRepo: fakeArticleRepo{articles: map[int64]domain.Article{
+		1: {ID: 1, Fingerprint: 0b1111, Status: domain.ArticleStatusPublished},
+		2: {ID: 2, Fingerprint: 0b1111 << 40, Status: domain.ArticleStatusPublished},
+	}}}
+	svc := NewService(repo, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, DuplicatePolicy{})
+
+	similar, err := svc.checkDuplicates(context.Background(), 0b0111, 0, 5)
+	require.NoError(t, err)
+	assert.Equal(t, 6, repo.maxDistance, "the default threshold allows 6 bits")
+	require.Len(t, similar, 1)
+	assert.Equal(t, int64(1), similar[0].Article.ID)
+	assert.InDelta(t, 1-1.0/64, similar[0].Similarity(), 1e-9)
+
+	similar, err = svc.checkDuplicates(context.Background(), 0b1111, 1, 5)
+	require.NoError(t, err)
+	assert.Empty(t, similar, "an article is not a duplicate of itself")
+
+	svc.duplicates.Reject = true
+	_, err = svc.checkDuplicates(context.Background(), 0b0111, 0, 5)
+	assert.ErrorIs(t, err, domain.ErrDuplicateContent)
+	_, err = svc.checkDuplicates(context.Background(), 0, 0, 5)
+	assert.NoError(t, err, "content without words has no duplicates")
+}
+
+func TestSimilarContentHidesArticlesOfOthers(t *testing.T) {
+	author := domain.User{ID: 5, Name: "Ann"}
+	other := domain.User{ID: 6, Name: "Bob"}
+	repo := &fakeSimilarRepo{fakeArticleRepo: fakeArticleRepo{articles: map[int64]domain.Article{
+		1: {ID: 1, Fingerprint: 0b1111, User: author, Status: domain.ArticleStatusPublished},
+		2: {ID: 2, Fingerprint: 0b1110, User: other, Status: domain.ArticleStatusPublished},
+		3: {ID: 3, Fingerprint: 0b1101, User: other, Status: domain.ArticleStatusDraft, Title: "Secret draft"},
+		4: {ID: 4, Fingerprint: 0b1011, User: other, Status: domain.ArticleStatusPublished, Visibility: domain.ArticleVisibilityPrivate},
+		5: {ID: 5, Fingerprint: 0b0111, User: author, Status: domain.ArticleStatusDraft},
+	}}}
+	users := &fakeUserRepo{users: map[int64]domain.User{5: author, 6: other}}
+	contributors := &fakeContribRepo{roles: map[int64]domain.ContributorRole{1: domain.ContributorAuthor}}
+	svc := NewService(repo, users, nil, nil, nil, nil, nil, contributors, nil, nil, nil, DuplicatePolicy{})
+
+	similar, err := svc.SimilarContent(context.Background(), 5, 1)
+	require.NoError(t, err)
+	require.Len(t, similar, 4)
+	visible := map[int64]bool{}
+	hidden := 0
+	for _, s := range similar {
+		if s.Hidden {
+			hidden++
+			assert.Equal(t, domain.Article{}, s.Article, "hidden duplicates only keep their similarity")
+			assert.InDelta(t, 1-1.0/64, s.Similarity(), 1e-9)
+			continue
+		}
+		visible[s.Article.ID] = true
+	}
+	assert.Equal(t, map[int64]bool{2: true, 5: true}, visible, "listed articles and the own drafts are shown")
+	assert.Equal(t, 2, hidden)
+}
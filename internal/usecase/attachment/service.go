@@ -0,0 +1,360 @@
+package attachment
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"image"
+	_ "image/gif" // registers the gif decoder
+	"image/jpeg"
+	"image/png"
+	"io"
+	"net/http"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"golang.org/x/image/draw"
+	_ "golang.org/x/image/webp" // registers the webp decoder
+
+	"github.com/bxcodec/go-clean-arch/domain"
+)
+
+const (
+	// DefaultMaxSize is the size limit of a single upload
+	DefaultMaxSize = 10 << 20
+	// DefaultQuota is the total size of the attachments a user may keep
+	DefaultQuota = 500 << 20
+	// ThumbnailSize bounds the width and height of thumbnails
+	ThumbnailSize = 320
+	// OrphanGracePeriod is how long an upload may stay unlinked, giving authors
+	// time to save the article it belongs to
+	OrphanGracePeriod = 24 * time.Hour
+
+	// maxPixels keeps decoding of images with huge dimensions from exhausting memory
+	maxPixels         = 40_000_000
+	maxFilenameLength = 255
+)
+
+// allowedTypes maps the sniffed content types that are accepted to the
+// extension of their blobs
+var allowedTypes = map[string]string{
+	"image/jpeg":                ".jpg",
+	"image/png":                 ".png",
+	"image/gif":                 ".gif",
+	"image/webp":                ".webp",
+	"application/pdf":           ".pdf",
+	"text/plain; charset=utf-8": ".txt",
+}
+
+type Service struct {
+	attachmentRepo domain.AttachmentRepository
+	blobStore      domain.BlobStore
+	articleRepo    domain.ArticleRepository
+	contribRepo    domain.ContributorRepository
+	maxSize        int64
+	quota          int64
+}
+
+// NewService will create a new attachment service object, a zero maxSize or
+// quota falls back to the defaults
+func NewService(r domain.AttachmentRepository, b domain.BlobStore, a domain.ArticleRepository, c domain.ContributorRepository, maxSize, quota int64) *Service {
+	if maxSize <= 0 {
+		maxSize = DefaultMaxSize
+	}
+	if quota <= 0 {
+		quota = DefaultQuota
+	}
+	return &Service{
+		attachmentRepo: r,
+		blobStore:      b,
+		articleRepo:    a,
+		contribRepo:    c,
+		maxSize:        maxSize,
+		quota:          quota,
+	}
+}
+
+// Upload stores a file of userID. The type is sniffed from the content, the
+// name given by the client is only kept for display.
+func (s *Service) Upload(ctx context.Context, userID int64, filename string, r io.Reader) (domain.Attachment, error) {
+	data, err := io.ReadAll(io.LimitReader(r, s.maxSize+1))
+	if err != nil {
+		return domain.Attachment{}, err
+	}
+	if int64(len(data)) > s.maxSize {
+		return domain.Attachment{}, domain.ErrTooLarge
+	}
+	if len(data) == 0 {
+		return domain.Attachment{}, domain.ErrBadParamInput
+	}
+	contentType := http.DetectContentType(data)
+	ext, ok := allowedTypes[contentType]
+	if !ok {
+		return domain.Attachment{}, domain.ErrUnsupportedMediaType
+	}
+
+	name, err := randomHex(16)
+	if err != nil {
+		return domain.Attachment{}, err
+	}
+	prefix := fmt.Sprintf("attachments/%d/%s", userID, name)
+	a := domain.Attachment{
+		UserID:      userID,
+		Key:         prefix + ext,
+		Filename:    cleanFilename(filename, ext),
+		ContentType: contentType,
+		Size:        int64(len(data)),
+		CreatedAt:   time.Now(),
+	}
+
+	var thumb thumbnail
+	if a.IsImage() {
+		thumb, err = makeThumbnail(data)
+		if err != nil {
+			return domain.Attachment{}, err
+		}
+		a.Width, a.Height = thumb.width, thumb.height
+		a.ThumbnailKey = prefix + "_thumb" + thumb.ext
+		a.ThumbnailSize = int64(len(thumb.data))
+	}
+
+	// the row comes first so that a crash leaves an orphan the cleanup finds
+	if err := s.attachmentRepo.Store(ctx, &a, s.quota); err != nil {
+		return domain.Attachment{}, err
+	}
+	err = s.blobStore.Put(ctx, a.Key, bytes.NewReader(data), a.Size, a.ContentType)
+	if err == nil && a.ThumbnailKey != "" {
+		err = s.blobStore.Put(ctx, a.ThumbnailKey, bytes.NewReader(thumb.data), a.ThumbnailSize, thumb.contentType)
+	}
+	if err != nil {
+		if _, cleanupErr := s.remove(ctx, []domain.Attachment{a}); cleanupErr != nil {
+			logrus.Warnf("failed to remove attachment %d after a failed upload: %v", a.ID, cleanupErr)
+		}
+		return domain.Attachment{}, err
+	}
+	return a, nil
+}
+
+// cleanFilename keeps the base name given by the client, falling back to a
+// generic name with the sniffed extension
+func cleanFilename(filename, ext string) string {
+	name := path.Base(strings.ReplaceAll(filename, `\`, "/"))
+	if name == "." || name == "/" || name == "" {
+		return "file" + ext
+	}
+	if len(name) > maxFilenameLength {
+		name = name[:maxFilenameLength]
+	}
+	return name
+}
+
+type thumbnail struct {
+	data          []byte
+	contentType   string
+	ext           string
+	width, height int
+}
+
+// makeThumbnail scales an image to fit ThumbnailSize, PNG keeps the
+// transparency of PNG and GIF sources and everything else becomes JPEG
+func makeThumbnail(data []byte) (thumbnail, error) {
+	cfg, format, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil || cfg.Width == 0 || cfg.Height == 0 {
+		return thumbnail{}, domain.ErrUnsupportedMediaType
+	}
+	if cfg.Width*cfg.Height > maxPixels {
+		return thumbnail{}, domain.ErrTooLarge
+	}
+	src, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return thumbnail{}, domain.ErrUnsupportedMediaType
+	}
+
+	w, h := cfg.Width, cfg.Height
+	if w > ThumbnailSize || h > ThumbnailSize {
+		if w >= h {
+			w, h = ThumbnailSize, max(1, h*ThumbnailSize/w)
+		} else {
+			w, h = max(1, w*ThumbnailSize/h), ThumbnailSize
+		}
+	}
+	dst := image.NewRGBA(image.Rect(0, 0, w, h))
+	draw.CatmullRom.Scale(dst, dst.Bounds(), src, src.Bounds(), draw.Over, nil)
+
+	res := thumbnail{width: cfg.Width, height: cfg.Height}
+	var buf bytes.Buffer
+	if format == "png" || format == "gif" {
+		err = png.Encode(&buf, dst)
+		res.contentType, res.ext = "image/png", ".png"
+	} else {
+		err = jpeg.Encode(&buf, dst, &jpeg.Options{Quality: 80})
+		res.contentType, res.ext = "image/jpeg", ".jpg"
+	}
+	if err != nil {
+		return thumbnail{}, err
+	}
+	res.data = buf.Bytes()
+	return res, nil
+}
+
+// Open returns the file stored or thumbnailed under key when userID may read
+// it: files of a reachable article are readable by anyone, the others only by
+// the contributors of their article or, until it is linked, by their uploader.
+func (s *Service) Open(ctx context.Context, userID int64, key string) (domain.AttachmentFile, error) {
+	a, err := s.attachmentRepo.GetByKey(ctx, key)
+	if err != nil {
+		return domain.AttachmentFile{}, err
+	}
+	public, err := s.readable(ctx, userID, a)
+	if err != nil {
+		return domain.AttachmentFile{}, err
+	}
+	contentType := a.ContentType
+	if key == a.ThumbnailKey {
+		contentType = "image/jpeg"
+		if strings.HasSuffix(key, ".png") {
+			contentType = "image/png"
+		}
+	}
+	r, err := s.blobStore.Get(ctx, key)
+	if err != nil {
+		return domain.AttachmentFile{}, err
+	}
+	return domain.AttachmentFile{Attachment: a, Content: r, ContentType: contentType, Public: public}, nil
+}
+
+// readable fails with ErrNotFound when userID may not read the attachment and
+// reports whether its article is listed
+func (s *Service) readable(ctx context.Context, userID int64, a domain.Attachment) (bool, error) {
+	if a.ArticleID == 0 {
+		if a.UserID != userID {
+			return false, domain.ErrNotFound
+		}
+		return false, nil
+	}
+	article, err := s.articleRepo.GetByID(ctx, a.ArticleID)
+	if err != nil {
+		return false, err
+	}
+	if article.Reachable() {
+		return article.Listed(), nil
+	}
+	role, err := s.contribRepo.Role(ctx, a.ArticleID, userID)
+	if err != nil {
+		return false, err
+	}
+	if role == "" {
+		return false, domain.ErrNotFound
+	}
+	return false, nil
+}
+
+// Usage returns the storage used by a user and their quota
+func (s *Service) Usage(ctx context.Context, userID int64) (used, quota int64, err error) {
+	used, err = s.attachmentRepo.Usage(ctx, userID)
+	return used, s.quota, err
+}
+
+// Link links attachments of userID to an article the user may edit and
+// returns all the attachments of the article
+func (s *Service) Link(ctx context.Context, userID, articleID int64, ids []int64) ([]domain.Attachment, error) {
+	if len(ids) == 0 {
+		return nil, domain.ErrBadParamInput
+	}
+	if _, err := s.articleRepo.GetByID(ctx, articleID); err != nil {
+		return nil, err
+	}
+	role, err := s.contribRepo.Role(ctx, articleID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if !role.CanEdit() {
+		return nil, domain.ErrForbidden
+	}
+	if _, err := s.attachmentRepo.Link(ctx, userID, articleID, ids); err != nil {
+		return nil, err
+	}
+	return s.attachmentRepo.FetchByArticle(ctx, articleID)
+}
+
+// Attachments returns the attachments of an article readable by userID
+func (s *Service) Attachments(ctx context.Context, userID, articleID int64) ([]domain.Attachment, error) {
+	article, err := s.articleRepo.GetByID(ctx, articleID)
+	if err != nil {
+		return nil, err
+	}
+	if !article.Reachable() {
+		role, err := s.contribRepo.Role(ctx, articleID, userID)
+		if err != nil {
+			return nil, err
+		}
+		if role == "" {
+			return nil, domain.ErrNotFound
+		}
+	}
+	return s.attachmentRepo.FetchByArticle(ctx, articleID)
+}
+
+// Delete removes an attachment of userID with its blobs
+func (s *Service) Delete(ctx context.Context, userID, id int64) error {
+	a, err := s.attachmentRepo.GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	if a.UserID != userID {
+		return domain.ErrForbidden
+	}
+	if err := s.deleteBlobs(ctx, a); err != nil {
+		return err
+	}
+	return s.attachmentRepo.Delete(ctx, []int64{id})
+}
+
+// CleanupOrphans deletes up to limit attachments that were never linked or
+// whose article is gone and reports how many it deleted
+func (s *Service) CleanupOrphans(ctx context.Context, limit int) (int, error) {
+	orphans, err := s.attachmentRepo.FetchOrphans(ctx, time.Now().Add(-OrphanGracePeriod), limit)
+	if err != nil {
+		return 0, err
+	}
+	return s.remove(ctx, orphans)
+}
+
+// remove deletes the blobs then the rows of attachments, an attachment whose
+// blobs cannot be deleted keeps its row so that it is retried
+func (s *Service) remove(ctx context.Context, attachments []domain.Attachment) (int, error) {
+	ids := make([]int64, 0, len(attachments))
+	for _, a := range attachments {
+		if err := s.deleteBlobs(ctx, a); err != nil {
+			logrus.Warnf("failed to delete blobs of attachment %d: %v", a.ID, err)
+			continue
+		}
+		ids = append(ids, a.ID)
+	}
+	if err := s.attachmentRepo.Delete(ctx, ids); err != nil {
+		return 0, err
+	}
+	return len(ids), nil
+}
+
+func (s *Service) deleteBlobs(ctx context.Context, a domain.Attachment) error {
+	if err := s.blobStore.Delete(ctx, a.Key); err != nil {
+		return err
+	}
+	if a.ThumbnailKey != "" {
+		return s.blobStore.Delete(ctx, a.ThumbnailKey)
+	}
+	return nil
+}
+
+func randomHex(n int) (string, error) {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
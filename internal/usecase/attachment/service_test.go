@@ -0,0 +1,402 @@
+package attachment
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"image"
+	"image/png"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bxcodec/go-clean-arch/domain"
+)
+
+type fakeAttachmentRepo struct {
+	domain.AttachmentRepository
+	attachments map[int64]domain.Attachment
+	nextID      int64
+	linked      []int64
+	orphansFrom time.Time
+}
+
+func newFakeAttachmentRepo() *fakeAttachmentRepo {
+	return &fakeAttachmentRepo{attachments: map[int64]domain.Attachment{}}
+}
+
+func (f *fakeAttachmentRepo) Store(_ context.Context, a *domain.Attachment, quota int64) error {
+	var used int64
+	for _, stored := range f.attachments {
+		if stored.UserID == a.UserID {
+			used += stored.StoredSize()
+		}
+	}
+	if used+a.StoredSize() > quota {
+		return domain.ErrQuotaExceeded
+	}
+	f.nextID++
+	a.ID = f.nextID
+	f.attachments[a.ID] = *a
+	return nil
+}
+
+func (f *fakeAttachmentRepo) GetByKey(_ context.Context, key string) (domain.Attachment, error) {
+	for _, a := range f.attachments {
+		if a.Key == key || a.ThumbnailKey == key {
+			return a, nil
+		}
+	}
+	return domain.Attachment{}, domain.ErrNotFound
+}
+
+func (f *fakeAttachmentRepo) Link(_ context.Context, userID, articleID int64, ids []int64) (int64, error) {
+	var linked int64
+	for _, id := range ids {
+		a, ok := f.attachments[id]
+		if !ok || a.UserID != userID {
+			continue
+		}
+		a.ArticleID = articleID
+		f.attachments[id] = a
+		f.linked = append(f.linked, id)
+		linked++
+	}
+	return linked, nil
+}
+
+func (f *fakeAttachmentRepo) FetchByArticle(_ context.Context, articleID int64) ([]domain.Attachment, error) {
+	var res []domain.Attachment
+	for _, a := range f.attachments {
+		if a.ArticleID == articleID {
+			res = append(res, a)
+		}
+	}
+	return res, nil
+}
+
+func (f *fakeAttachmentRepo) FetchOrphans(_ context.Context, before time.Time, limit int) ([]domain.Attachment, error) {
+	f.orphansFrom = before
+	var res []domain.Attachment
+	for id := int64(1); id <= f.nextID && len(res) < limit; id++ {
+		a, ok := f.attachments[id]
+		if ok && a.ArticleID == 0 && a.CreatedAt.Before(before) {
+			res = append(res, a)
+		}
+	}
+	return res, nil
+}
+
+func (f *fakeAttachmentRepo) Delete(_ context.Context, ids []int64) error {
+	for _, id := range ids {
+		delete(f.attachments, id)
+	}
+	return nil
+}
+
+type fakeBlobStore struct {
+	domain.BlobStore
+	blobs   map[string][]byte
+	failPut bool
+	// failDelete makes deleting the blobs under these keys fail
+	failDelete map[string]bool
+}
+
+func newFakeBlobStore() *fakeBlobStore {
+	return &fakeBlobStore{blobs: map[string][]byte{}, failDelete: map[string]bool{}}
+}
+
+func (f *fakeBlobStore) Put(_ context.Context, key string, r io.Reader, size int64, _ string) error {
+	if f.failPut {
+		return errors.New("storage unavailable")
+	}
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return err
+	}
+	if int64(len(data)) != size {
+		return errors.New("size mismatch")
+	}
+	f.blobs[key] = data
+	return nil
+}
+
+func (f *fakeBlobStore) Get(_ context.Context, key string) (io.ReadCloser, error) {
+	data, ok := f.blobs[key]
+	if !ok {
+		return nil, domain.ErrNotFound
+	}
+	return io.NopCloser(bytes.NewReader(data)), nil
+}
+
+func (f *fakeBlobStore) Delete(_ context.Context, key string) error {
+	if f.failDelete[key] {
+		return errors.New("storage unavailable")
+	}
+	delete(f.blobs, key)
+	return nil
+}
+
+type fakeArticleRepo struct {
+	domain.ArticleRepository
+}
+
+// testArticles are a draft, a published and an unlisted article
+var testArticles = map[int64]domain.Article{
+	1: {ID: 1, Status: domain.ArticleStatusDraft},
+	2: {ID: 2, Status: domain.ArticleStatusPublished, Visibility: domain.ArticleVisibilityPublic},
+	3: {ID: 3, Status: domain.ArticleStatusPublished, Visibility: domain.ArticleVisibilityUnlisted},
+}
+
+func (fakeArticleRepo) GetByID(_ context.Context, id int64) (domain.Article, error) {
+	a, ok := testArticles[id]
+	if !ok {
+		return domain.Article{}, domain.ErrNotFound
+	}
+	return a, nil
+}
+
+type fakeContribRepo struct {
+	domain.ContributorRepository
+	roles map[int64]domain.ContributorRole
+}
+
+func (f *fakeContribRepo) Role(_ context.Context, _, userID int64) (domain.ContributorRole, error) {
+	return f.roles[userID], nil
+}
+
+func newTestService(maxSize, quota int64) (*Service, *fakeAttachmentRepo, *fakeBlobStore) {
+	repo := newFakeAttachmentRepo()
+	blobs := newFakeBlobStore()
+	roles := &fakeContribRepo{roles: map[int64]domain.ContributorRole{
+		1: domain.ContributorAuthor,
+		2: domain.ContributorEditor,
+		3: domain.ContributorCoAuthor,
+	}}
+	return NewService(repo, blobs, fakeArticleRepo{}, roles, maxSize, quota), repo, blobs
+}
+
+func testPNG(t *testing.T, w, h int) []byte {
+	var buf bytes.Buffer
+	require.NoError(t, png.Encode(&buf, image.NewRGBA(image.Rect(0, 0, w, h))))
+	return buf.Bytes()
+}
+
+func TestUploadSniffsContentType(t *testing.T) {
+	s, repo, blobs := newTestService(0, 0)
+	ctx := context.Background()
+
+	// the name given by the client does not decide the type
+	a, err := s.Upload(ctx, 1, "notes.exe", strings.NewReader("plain notes"))
+	require.NoError(t, err)
+	assert.Equal(t, "text/plain; charset=utf-8", a.ContentType)
+	assert.True(t, strings.HasSuffix(a.Key, ".txt"))
+	assert.Equal(t, "notes.exe", a.Filename)
+	assert.Empty(t, a.ThumbnailKey)
+	assert.Equal(t, []byte("plain notes"), blobs.blobs[a.Key])
+
+	img := testPNG(t, 640, 480)
+	a, err = s.Upload(ctx, 1, "photo.jpg", bytes.NewReader(img))
+	require.NoError(t, err)
+	assert.Equal(t, "image/png", a.ContentType)
+	assert.Equal(t, 640, a.Width)
+	assert.Equal(t, 480, a.Height)
+	assert.Equal(t, int64(len(img)), a.Size)
+	assert.Equal(t, int64(len(blobs.blobs[a.ThumbnailKey])), a.ThumbnailSize)
+	assert.NotZero(t, a.ThumbnailSize)
+	assert.Len(t, repo.attachments, 2)
+
+	for name, content := range map[string]string{
+		"page.png":   "<!DOCTYPE html><html><script>alert(1)</script></html>",
+		"image.svg":  `<?xml version="1.0"?><svg xmlns="http://www.w3.org/2000/svg"></svg>`,
+		"binary.pdf": "\x7fELF\x02\x01\x01",
+	} {
+		_, err := s.Upload(ctx, 1, name, strings.NewReader(content))
+		assert.ErrorIs(t, err, domain.ErrUnsupportedMediaType, name)
+	}
+	_, err = s.Upload(ctx, 1, "empty.txt", strings.NewReader(""))
+	assert.ErrorIs(t, err, domain.ErrBadParamInput)
+	assert.Len(t, repo.attachments, 2)
+	assert.Len(t, blobs.blobs, 3)
+}
+
+func TestUploadSizeLimit(t *testing.T) {
+	s, repo, _ := newTestService(16, 0)
+
+	_, err := s.Upload(context.Background(), 1, "a.txt", strings.NewReader(strings.Repeat("a", 16)))
+	require.NoError(t, err)
+	_, err = s.Upload(context.Background(), 1, "b.txt", strings.NewReader(strings.Repeat("b", 17)))
+	assert.ErrorIs(t, err, domain.ErrTooLarge)
+	assert.Len(t, repo.attachments, 1)
+}
+
+func TestUploadQuota(t *testing.T) {
+	img := testPNG(t, 640, 480)
+	// the image fits the quota but not with its thumbnail
+	s, repo, blobs := newTestService(0, int64(len(img))+10)
+	ctx := context.Background()
+
+	_, err := s.Upload(ctx, 1, "photo.png", bytes.NewReader(img))
+	assert.ErrorIs(t, err, domain.ErrQuotaExceeded)
+	assert.Empty(t, repo.attachments)
+	assert.Empty(t, blobs.blobs)
+
+	_, err = s.Upload(ctx, 1, "a.txt", strings.NewReader(strings.Repeat("a", len(img))))
+	require.NoError(t, err)
+	_, err = s.Upload(ctx, 1, "b.txt", strings.NewReader(strings.Repeat("b", 11)))
+	assert.ErrorIs(t, err, domain.ErrQuotaExceeded)
+
+	// the quota is per user
+	_, err = s.Upload(ctx, 2, "b.txt", strings.NewReader(strings.Repeat("b", 11)))
+	assert.NoError(t, err)
+}
+
+func TestUploadRemovesRowWhenStorageFails(t *testing.T) {
+	s, repo, blobs := newTestService(0, 0)
+	blobs.failPut = true
+
+	_, err := s.Upload(context.Background(), 1, "photo.png", bytes.NewReader(testPNG(t, 10, 10)))
+	assert.Error(t, err)
+	assert.Empty(t, repo.attachments)
+}
+
+func TestLinkRequiresEditRights(t *testing.T) {
+	s, repo, _ := newTestService(0, 0)
+	ctx := context.Background()
+	own, err := s.Upload(ctx, 2, "a.txt", strings.NewReader("a"))
+	require.NoError(t, err)
+	other, err := s.Upload(ctx, 4, "b.txt", strings.NewReader("b"))
+	require.NoError(t, err)
+
+	// a user without a role on the article cannot attach files to it
+	_, err = s.Link(ctx, 4, 1, []int64{other.ID})
+	assert.ErrorIs(t, err, domain.ErrForbidden)
+	_, err = s.Link(ctx, 2, 9, []int64{own.ID})
+	assert.ErrorIs(t, err, domain.ErrNotFound)
+	_, err = s.Link(ctx, 2, 1, nil)
+	assert.ErrorIs(t, err, domain.ErrBadParamInput)
+	assert.Empty(t, repo.linked)
+
+	// editors link their own uploads only
+	res, err := s.Link(ctx, 2, 1, []int64{own.ID, other.ID})
+	require.NoError(t, err)
+	require.Len(t, res, 1)
+	assert.Equal(t, own.ID, res[0].ID)
+	assert.Equal(t, []int64{own.ID}, repo.linked)
+}
+
+func TestOpenChecksArticleVisibility(t *testing.T) {
+	s, repo, _ := newTestService(0, 0)
+	ctx := context.Background()
+	upload := func(articleID int64) domain.Attachment {
+		a, err := s.Upload(ctx, 1, "a.txt", strings.NewReader("a"))
+		require.NoError(t, err)
+		_, err = repo.Link(ctx, 1, articleID, []int64{a.ID})
+		require.NoError(t, err)
+		return repo.attachments[a.ID]
+	}
+	unlinked, err := s.Upload(ctx, 1, "a.txt", strings.NewReader("a"))
+	require.NoError(t, err)
+	draft, published, unlisted := upload(1), upload(2), upload(3)
+
+	for _, tc := range []struct {
+		name    string
+		userID  int64
+		key     string
+		public  bool
+		wantErr error
+	}{
+		{name: "published article", userID: 0, key: published.Key, public: true},
+		{name: "unlisted article", userID: 0, key: unlisted.Key},
+		{name: "draft for a contributor", userID: 3, key: draft.Key},
+		{name: "draft for anyone else", userID: 4, key: draft.Key, wantErr: domain.ErrNotFound},
+		{name: "draft for a guest", userID: 0, key: draft.Key, wantErr: domain.ErrNotFound},
+		{name: "unlinked for its uploader", userID: 1, key: unlinked.Key},
+		{name: "unlinked for anyone else", userID: 2, key: unlinked.Key, wantErr: domain.ErrNotFound},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			file, err := s.Open(ctx, tc.userID, tc.key)
+			if tc.wantErr != nil {
+				assert.ErrorIs(t, err, tc.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			defer file.Content.Close()
+			assert.Equal(t, tc.public, file.Public)
+			data, err := io.ReadAll(file.Content)
+			require.NoError(t, err)
+			assert.Equal(t, "a", string(data))
+		})
+	}
+}
+
+func TestCleanupOrphans(t *testing.T) {
+	s, repo, blobs := newTestService(0, 0)
+	ctx := context.Background()
+
+	var uploaded []domain.Attachment
+	for i := 0; i < 3; i++ {
+		a, err := s.Upload(ctx, 1, "photo.png", bytes.NewReader(testPNG(t, 10, 10)))
+		require.NoError(t, err)
+		uploaded = append(uploaded, a)
+	}
+	_, err := s.Link(ctx, 1, 1, []int64{uploaded[2].ID})
+	require.NoError(t, err)
+	// uploads within the grace period are left alone
+	fresh, err := s.Upload(ctx, 1, "fresh.txt", strings.NewReader("fresh"))
+	require.NoError(t, err)
+	for _, a := range uploaded {
+		a := repo.attachments[a.ID]
+		a.CreatedAt = time.Now().Add(-OrphanGracePeriod - time.Hour)
+		repo.attachments[a.ID] = a
+	}
+	blobs.failDelete[uploaded[1].ThumbnailKey] = true
+
+	n, err := s.CleanupOrphans(ctx, 10)
+	require.NoError(t, err)
+	assert.Equal(t, 1, n)
+	assert.WithinDuration(t, time.Now().Add(-OrphanGracePeriod), repo.orphansFrom, time.Minute)
+
+	assert.NotContains(t, repo.attachments, uploaded[0].ID)
+	assert.NotContains(t, blobs.blobs, uploaded[0].Key)
+	assert.NotContains(t, blobs.blobs, uploaded[0].ThumbnailKey)
+	// an attachment whose blobs could not be deleted is retried on the next run
+	assert.Contains(t, repo.attachments, uploaded[1].ID)
+	assert.Contains(t, repo.attachments, uploaded[2].ID)
+	assert.Contains(t, repo.attachments, fresh.ID)
+
+	delete(blobs.failDelete, uploaded[1].ThumbnailKey)
+	n, err = s.CleanupOrphans(ctx, 10)
+	require.NoError(t, err)
+	assert.Equal(t, 1, n)
+	assert.NotContains(t, repo.attachments, uploaded[1].ID)
+}
+
+func TestMakeThumbnail(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, png.Encode(&buf, image.NewRGBA(image.Rect(0, 0, 800, 400))))
+
+	thumb, err := makeThumbnail(buf.Bytes())
+	require.NoError(t, err)
+	assert.Equal(t, 800, thumb.width)
+	assert.Equal(t, 400, thumb.height)
+	assert.Equal(t, "image/png", thumb.contentType)
+
+	cfg, err := png.DecodeConfig(bytes.NewReader(thumb.data))
+	require.NoError(t, err)
+	assert.Equal(t, ThumbnailSize, cfg.Width)
+	assert.Equal(t, ThumbnailSize/2, cfg.Height)
+
+	_, err = makeThumbnail([]byte("\x89PNG\r\n\x1a\nbroken"))
+	assert.ErrorIs(t, err, domain.ErrUnsupportedMediaType)
+}
+
+func TestCleanFilename(t *testing.T) {
+	assert.Equal(t, "photo.png", cleanFilename(`C:\Users\me\photo.png`, ".png"))
+	assert.Equal(t, "passwd", cleanFilename("../../etc/passwd", ".txt"))
+	assert.Equal(t, "file.pdf", cleanFilename("", ".pdf"))
+}
@@ -0,0 +1,196 @@
+package bookmark
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/bxcodec/go-clean-arch/domain"
+)
+
+const maxListNameLength = 100
+
+type Service struct {
+	bookmarkRepo domain.BookmarkRepository
+	articleRepo  domain.ArticleRepository
+}
+
+// NewService will create a new bookmark service object
+func NewService(b domain.BookmarkRepository, a domain.ArticleRepository) *Service {
+	return &Service{
+		bookmarkRepo: b,
+		articleRepo:  a,
+	}
+}
+
+// Bookmark saves an article for b.UserID, optionally into the reading list b.ListID.
+// Bookmarking an article again only moves it to the given list.
+func (s *Service) Bookmark(ctx context.Context, b *domain.Bookmark) error {
+	if b.ListID != 0 {
+		if _, err := s.ownList(ctx, b.UserID, b.ListID); err != nil {
+			return err
+		}
+	}
+
+	existing, err := s.bookmarkRepo.Get(ctx, b.UserID, b.ArticleID)
+	switch {
+	case errors.Is(err, domain.ErrNotFound):
+		article, err := s.articleRepo.GetByID(ctx, b.ArticleID)
+		if err != nil {
+			return err
+		}
+		if !article.Reachable() {
+			return domain.ErrNotFound
+		}
+		b.CreatedAt = time.Now()
+	case err != nil:
+		return err
+	case existing.ListID == b.ListID:
+		*b = existing
+		return nil
+	default:
+		b.CreatedAt = existing.CreatedAt
+	}
+
+	b.Position = 0
+	if b.ListID != 0 {
+		b.Position, err = s.bookmarkRepo.NextPosition(ctx, b.ListID)
+		if err != nil {
+			return err
+		}
+	}
+	b.UpdatedAt = time.Now()
+	if err := s.bookmarkRepo.Save(ctx, b); err != nil {
+		return err
+	}
+	saved, err := s.bookmarkRepo.Get(ctx, b.UserID, b.ArticleID)
+	if err != nil {
+		return err
+	}
+	*b = saved
+	return nil
+}
+
+// Unbookmark removes a bookmark, also when its article is gone
+func (s *Service) Unbookmark(ctx context.Context, userID, articleID int64) error {
+	return s.bookmarkRepo.Delete(ctx, userID, articleID)
+}
+
+// MarkRead marks a bookmark read or unread
+func (s *Service) MarkRead(ctx context.Context, userID, articleID int64, read bool) (domain.Bookmark, error) {
+	var readAt time.Time
+	if read {
+		readAt = time.Now()
+	}
+	if err := s.bookmarkRepo.SetRead(ctx, userID, articleID, readAt); err != nil {
+		return domain.Bookmark{}, err
+	}
+	return s.bookmarkRepo.Get(ctx, userID, articleID)
+}
+
+// Fetch returns a page of the bookmarks of a user, newest first. Bookmarks of
+// deleted or unpublished articles are kept and reported as unavailable.
+func (s *Service) Fetch(ctx context.Context, userID int64, filter domain.BookmarkFilter, cursor string, num int64) ([]domain.Bookmark, string, error) {
+	return s.bookmarkRepo.Fetch(ctx, userID, filter, cursor, num)
+}
+
+// FetchList returns a page of the bookmarks of a reading list of the user in list order
+func (s *Service) FetchList(ctx context.Context, userID, listID int64, filter domain.BookmarkFilter, cursor string, num int64) ([]domain.Bookmark, string, error) {
+	if _, err := s.ownList(ctx, userID, listID); err != nil {
+		return nil, "", err
+	}
+	return s.bookmarkRepo.FetchList(ctx, listID, filter, cursor, num)
+}
+
+func (s *Service) CreateList(ctx context.Context, l *domain.ReadingList) error {
+	name, err := cleanListName(l.Name)
+	if err != nil {
+		return err
+	}
+	now := time.Now()
+	l.Name = name
+	l.CreatedAt = now
+	l.UpdatedAt = now
+	return s.bookmarkRepo.StoreList(ctx, l)
+}
+
+func (s *Service) FetchLists(ctx context.Context, userID int64) ([]domain.ReadingList, error) {
+	return s.bookmarkRepo.FetchLists(ctx, userID)
+}
+
+// RenameList changes the name of a reading list owned by l.UserID
+func (s *Service) RenameList(ctx context.Context, l *domain.ReadingList) error {
+	name, err := cleanListName(l.Name)
+	if err != nil {
+		return err
+	}
+	existing, err := s.ownList(ctx, l.UserID, l.ID)
+	if err != nil {
+		return err
+	}
+	existing.Name = name
+	existing.UpdatedAt = time.Now()
+	if err := s.bookmarkRepo.UpdateList(ctx, &existing); err != nil {
+		return err
+	}
+	*l = existing
+	return nil
+}
+
+// DeleteList removes a reading list, its bookmarks are kept
+func (s *Service) DeleteList(ctx context.Context, userID, listID int64) error {
+	if _, err := s.ownList(ctx, userID, listID); err != nil {
+		return err
+	}
+	return s.bookmarkRepo.DeleteList(ctx, listID)
+}
+
+// Reorder puts the bookmarks of a reading list in the given order, articleIDs
+// must hold every article of the list exactly once
+func (s *Service) Reorder(ctx context.Context, userID, listID int64, articleIDs []int64) error {
+	if _, err := s.ownList(ctx, userID, listID); err != nil {
+		return err
+	}
+	current, err := s.bookmarkRepo.ListArticleIDs(ctx, listID)
+	if err != nil {
+		return err
+	}
+	if !samePermutation(current, articleIDs) {
+		return domain.ErrBadParamInput
+	}
+	return s.bookmarkRepo.Reorder(ctx, listID, articleIDs)
+}
+
+// ownList returns the reading list if it belongs to userID
+func (s *Service) ownList(ctx context.Context, userID, listID int64) (domain.ReadingList, error) {
+	list, err := s.bookmarkRepo.GetList(ctx, listID)
+	if err != nil {
+		return domain.ReadingList{}, err
+	}
+	if list.UserID != userID {
+		// do not reveal lists of other users
+		return domain.ReadingList{}, domain.ErrNotFound
+	}
+	return list, nil
+}
+
+func cleanListName(name string) (string, error) {
+	name = strings.TrimSpace(name)
+	if name == "" || len([]rune(name)) > maxListNameLength {
+		return "", domain.ErrBadParamInput
+	}
+	return name, nil
+}
+
+func samePermutation(a, b []int64) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	a = slices.Clone(a)
+	b = slices.Clone(b)
+	slices.Sort(a)
+	slices.Sort(b)
+	return slices.Equal(a, b)
+}
@@ -0,0 +1,226 @@
+package bookmark
+
+import (
+	"context"
+	"slices"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bxcodec/go-clean-arch/domain"
+)
+
+type bookmarkKey struct{ userID, articleID int64 }
+
+type fakeBookmarkRepo struct {
+	domain.BookmarkRepository
+	articles  *fakeArticleRepo
+	bookmarks map[bookmarkKey]domain.Bookmark
+	lists     map[int64]domain.ReadingList
+	order     map[int64][]int64
+	saves     int
+}
+
+func newFakeBookmarkRepo(articles *fakeArticleRepo) *fakeBookmarkRepo {
+	return &fakeBookmarkRepo{
+		articles:  articles,
+		bookmarks: map[bookmarkKey]domain.Bookmark{},
+		lists:     map[int64]domain.ReadingList{},
+		order:     map[int64][]int64{},
+	}
+}
+
+func (f *fakeBookmarkRepo) Save(_ context.Context, b *domain.Bookmark) error {
+	f.saves++
+	f.bookmarks[bookmarkKey{b.UserID, b.ArticleID}] = *b
+	return nil
+}
+
+// Get loads the bookmark with its article like the repository join, the
+// article stays empty once deleted
+func (f *fakeBookmarkRepo) Get(_ context.Context, userID, articleID int64) (domain.Bookmark, error) {
+	b, ok := f.bookmarks[bookmarkKey{userID, articleID}]
+	if !ok {
+		return domain.Bookmark{}, domain.ErrNotFound
+	}
+	b.Article = f.articles.articles[articleID]
+	return b, nil
+}
+
+func (f *fakeBookmarkRepo) Fetch(ctx context.Context, userID int64, _ domain.BookmarkFilter, _ string, _ int64) ([]domain.Bookmark, string, error) {
+	var res []domain.Bookmark
+	for key := range f.bookmarks {
+		if key.userID != userID {
+			continue
+		}
+		b, _ := f.Get(ctx, key.userID, key.articleID)
+		res = append(res, b)
+	}
+	slices.SortFunc(res, func(a, b domain.Bookmark) int { return int(a.ArticleID - b.ArticleID) })
+	return res, "", nil
+}
+
+func (f *fakeBookmarkRepo) NextPosition(_ context.Context, listID int64) (int64, error) {
+	var max int64
+	for _, b := range f.bookmarks {
+		if b.ListID == listID && b.Position > max {
+			max = b.Position
+		}
+	}
+	return max + 1, nil
+}
+
+func (f *fakeBookmarkRepo) GetList(_ context.Context, id int64) (domain.ReadingList, error) {
+	l, ok := f.lists[id]
+	if !ok {
+		return domain.ReadingList{}, domain.ErrNotFound
+	}
+	return l, nil
+}
+
+func (f *fakeBookmarkRepo) ListArticleIDs(_ context.Context, listID int64) ([]int64, error) {
+	return f.order[listID], nil
+}
+
+func (f *fakeBookmarkRepo) Reorder(_ context.Context, listID int64, articleIDs []int64) error {
+	f.order[listID] = articleIDs
+	return nil
+}
+
+type fakeArticleRepo struct {
+	domain.ArticleRepository
+	articles map[int64]domain.Article
+}
+
+func (f *fakeArticleRepo) GetByID(_ context.Context, id int64) (domain.Article, error) {
+	a, ok := f.articles[id]
+	if !ok {
+		return domain.Article{}, domain.ErrNotFound
+	}
+	return a, nil
+}
+
+func newTestService() (*Service, *fakeBookmarkRepo, *fakeArticleRepo) {
+	articles := &fakeArticleRepo{articles: map[int64]domain.Article{
+		1: {ID: 1, Status: domain.ArticleStatusPublished, Visibility: domain.ArticleVisibilityPublic},
+		2: {ID: 2, Status: domain.ArticleStatusPublished, Visibility: domain.ArticleVisibilityUnlisted},
+		3: {ID: 3, Status: domain.ArticleStatusDraft, Visibility: domain.ArticleVisibilityPublic},
+		4: {ID: 4, Status: domain.ArticleStatusPublished, Visibility: domain.ArticleVisibilityPrivate},
+	}}
+	bookmarks := newFakeBookmarkRepo(articles)
+	bookmarks.lists[10] = domain.ReadingList{ID: 10, UserID: 7, Name: "later"}
+	bookmarks.lists[11] = domain.ReadingList{ID: 11, UserID: 8, Name: "other"}
+	return NewService(bookmarks, articles), bookmarks, articles
+}
+
+func TestBookmarkReachableArticles(t *testing.T) {
+	s, repo, _ := newTestService()
+
+	for _, id := range []int64{1, 2} {
+		b := domain.Bookmark{UserID: 7, ArticleID: id}
+		require.NoError(t, s.Bookmark(context.Background(), &b))
+		assert.Equal(t, id, b.Article.ID)
+		assert.True(t, b.Available())
+		assert.False(t, b.CreatedAt.IsZero())
+	}
+	assert.Len(t, repo.bookmarks, 2)
+}
+
+func TestBookmarkUnreadableArticles(t *testing.T) {
+	s, repo, _ := newTestService()
+
+	// drafts, private and deleted articles cannot be bookmarked
+	for _, id := range []int64{3, 4, 99} {
+		b := domain.Bookmark{UserID: 7, ArticleID: id}
+		assert.ErrorIs(t, s.Bookmark(context.Background(), &b), domain.ErrNotFound, "article %d", id)
+	}
+	assert.Empty(t, repo.bookmarks)
+}
+
+func TestBookmarkAgainMovesToList(t *testing.T) {
+	s, repo, articles := newTestService()
+	ctx := context.Background()
+
+	b := domain.Bookmark{UserID: 7, ArticleID: 1}
+	require.NoError(t, s.Bookmark(ctx, &b))
+	created := b.CreatedAt
+
+	// bookmarking again into the same place is a no-op
+	again := domain.Bookmark{UserID: 7, ArticleID: 1}
+	require.NoError(t, s.Bookmark(ctx, &again))
+	assert.Equal(t, 1, repo.saves)
+
+	// moving an existing bookmark does not check the article again
+	delete(articles.articles, 1)
+	moved := domain.Bookmark{UserID: 7, ArticleID: 1, ListID: 10}
+	require.NoError(t, s.Bookmark(ctx, &moved))
+	assert.Equal(t, int64(10), moved.ListID)
+	assert.Equal(t, int64(1), moved.Position)
+	assert.Equal(t, created, moved.CreatedAt)
+	assert.False(t, moved.Available())
+
+	second := domain.Bookmark{UserID: 7, ArticleID: 2, ListID: 10}
+	require.NoError(t, s.Bookmark(ctx, &second))
+	assert.Equal(t, int64(2), second.Position)
+}
+
+func TestBookmarkIntoForeignList(t *testing.T) {
+	s, repo, _ := newTestService()
+
+	b := domain.Bookmark{UserID: 7, ArticleID: 1, ListID: 11}
+	assert.ErrorIs(t, s.Bookmark(context.Background(), &b), domain.ErrNotFound)
+	b = domain.Bookmark{UserID: 7, ArticleID: 1, ListID: 12}
+	assert.ErrorIs(t, s.Bookmark(context.Background(), &b), domain.ErrNotFound)
+	assert.Empty(t, repo.bookmarks)
+}
+
+func TestFetchKeepsUnavailableBookmarks(t *testing.T) {
+	s, _, articles := newTestService()
+	ctx := context.Background()
+
+	for _, id := range []int64{1, 2} {
+		b := domain.Bookmark{UserID: 7, ArticleID: id}
+		require.NoError(t, s.Bookmark(ctx, &b))
+	}
+	// article 1 is deleted and article 2 unpublished after being bookmarked
+	delete(articles.articles, 1)
+	a := articles.articles[2]
+	a.Status = domain.ArticleStatusDraft
+	articles.articles[2] = a
+
+	res, _, err := s.Fetch(ctx, 7, domain.BookmarkFilter{}, "", 10)
+	require.NoError(t, err)
+	require.Len(t, res, 2)
+	for _, b := range res {
+		assert.False(t, b.Available(), "article %d", b.ArticleID)
+	}
+	assert.Zero(t, res[0].Article.ID)
+	assert.Equal(t, int64(2), res[1].ArticleID)
+}
+
+func TestReorder(t *testing.T) {
+	s, repo, _ := newTestService()
+	ctx := context.Background()
+	repo.order[10] = []int64{1, 2, 3}
+	repo.order[11] = []int64{4}
+
+	require.NoError(t, s.Reorder(ctx, 7, 10, []int64{3, 1, 2}))
+	assert.Equal(t, []int64{3, 1, 2}, repo.order[10])
+
+	for _, ids := range [][]int64{{3, 1}, {3, 1, 2, 4}, {3, 1, 1}, {3, 1, 5}} {
+		assert.ErrorIs(t, s.Reorder(ctx, 7, 10, ids), domain.ErrBadParamInput, "%v", ids)
+	}
+	assert.Equal(t, []int64{3, 1, 2}, repo.order[10])
+
+	assert.ErrorIs(t, s.Reorder(ctx, 7, 11, []int64{4}), domain.ErrNotFound)
+	assert.ErrorIs(t, s.Reorder(ctx, 7, 12, nil), domain.ErrNotFound)
+}
+
+func TestSamePermutation(t *testing.T) {
+	assert.True(t, samePermutation([]int64{1, 2, 3}, []int64{3, 1, 2}))
+	assert.True(t, samePermutation(nil, []int64{}))
+	assert.False(t, samePermutation([]int64{1, 2, 3}, []int64{1, 2}))
+	assert.False(t, samePermutation([]int64{1, 2, 3}, []int64{1, 2, 2}))
+	assert.False(t, samePermutation([]int64{1, 2}, []int64{1, 4}))
+}
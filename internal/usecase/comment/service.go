@@ -0,0 +1,354 @@
+package comment
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/bxcodec/go-clean-arch/domain"
+)
+
+const maxContentLength = 5000
+
+type Service struct {
+	commentRepo     domain.CommentRepository
+	articleRepo     domain.ArticleRepository
+	userRepo        domain.UserRepository
+	articleCache    domain.ArticleCache
+	contribRepo     domain.ContributorRepository
+	notifier        domain.Notifier
+	requireApproval bool
+}
+
+// NewService will create a new comment service object. With requireApproval new
+// comments wait in the pending state until an editing contributor of the article approves them.
+func NewService(c domain.CommentRepository, a domain.ArticleRepository, u domain.UserRepository, ac domain.ArticleCache, cr domain.ContributorRepository, n domain.Notifier, requireApproval bool) *Service {
+	return &Service{
+		commentRepo:     c,
+		articleRepo:     a,
+		userRepo:        u,
+		articleCache:    ac,
+		contribRepo:     cr,
+		notifier:        n,
+		requireApproval: requireApproval,
+	}
+}
+
+// Fetch returns a page of threads of a published article with their replies nested
+func (s *Service) Fetch(ctx context.Context, articleID int64, cursor string, num int64) ([]domain.Comment, string, error) {
+	if _, err := s.publishedArticle(ctx, articleID); err != nil {
+		return nil, "", err
+	}
+	roots, nextCursor, err := s.commentRepo.FetchThreads(ctx, articleID, cursor, num)
+	if err != nil {
+		return nil, "", err
+	}
+	rootIDs := make([]int64, len(roots))
+	for i := range roots {
+		rootIDs[i] = roots[i].ID
+	}
+	replies, err := s.commentRepo.FetchReplies(ctx, rootIDs)
+	if err != nil {
+		return nil, "", err
+	}
+
+	threads := buildThreads(roots, replies)
+	if err := s.fillUsers(ctx, threads); err != nil {
+		return nil, "", err
+	}
+	return threads, nextCursor, nil
+}
+
+// FetchForModeration returns a page of comments in the given state, only
+// contributors allowed to edit the article may moderate them
+func (s *Service) FetchForModeration(ctx context.Context, userID, articleID int64, status domain.CommentStatus, cursor string, num int64) ([]domain.Comment, string, error) {
+	if !status.Valid() {
+		return nil, "", domain.ErrBadParamInput
+	}
+	if _, err := s.articleRepo.GetByID(ctx, articleID); err != nil {
+		return nil, "", err
+	}
+	if err := s.canModerate(ctx, articleID, userID); err != nil {
+		return nil, "", err
+	}
+	res, nextCursor, err := s.commentRepo.FetchByStatus(ctx, articleID, status, cursor, num)
+	if err != nil {
+		return nil, "", err
+	}
+	if err := s.fillUsers(ctx, res); err != nil {
+		return nil, "", err
+	}
+	return res, nextCursor, nil
+}
+
+// Store adds a comment or, when c.ParentID is set, a reply on behalf of c.User
+func (s *Service) Store(ctx context.Context, c *domain.Comment) error {
+	content, err := cleanContent(c.Content)
+	if err != nil {
+		return err
+	}
+	article, err := s.publishedArticle(ctx, c.ArticleID)
+	if err != nil {
+		return err
+	}
+
+	c.RootID = 0
+	var parent domain.Comment
+	if c.ParentID != 0 {
+		parent, err = s.commentRepo.GetByID(ctx, c.ParentID)
+		if err != nil {
+			return err
+		}
+		if parent.ArticleID != c.ArticleID || parent.Status != domain.CommentApproved || parent.Deleted {
+			return domain.ErrBadParamInput
+		}
+		c.RootID = parent.RootID
+		if c.RootID == 0 {
+			c.RootID = parent.ID
+		}
+	}
+
+	now := time.Now()
+	c.Content = content
+	c.Deleted = false
+	c.Status = domain.CommentApproved
+	if s.requireApproval {
+		role, err := s.contribRepo.Role(ctx, c.ArticleID, c.User.ID)
+		if err != nil {
+			return err
+		}
+		if !role.CanEdit() {
+			c.Status = domain.CommentPending
+		}
+	}
+	c.CreatedAt = now
+	c.UpdatedAt = now
+	if err := s.commentRepo.Store(ctx, c); err != nil {
+		return err
+	}
+	s.invalidateArticle(ctx, c.ArticleID)
+	if c.Status == domain.CommentApproved {
+		s.notify(ctx, article, parent, c)
+	}
+	return s.fillUser(ctx, c)
+}
+
+// Update changes the content of a comment, only its author may edit it
+func (s *Service) Update(ctx context.Context, c *domain.Comment) error {
+	content, err := cleanContent(c.Content)
+	if err != nil {
+		return err
+	}
+	existing, err := s.ownComment(ctx, c.User.ID, c.ID)
+	if err != nil {
+		return err
+	}
+
+	existing.Content = content
+	existing.UpdatedAt = time.Now()
+	if err := s.commentRepo.Update(ctx, &existing); err != nil {
+		return err
+	}
+	*c = existing
+	return s.fillUser(ctx, c)
+}
+
+// Delete removes the content of a comment, only its author may delete it.
+// The comment stays in its thread so replies to it are kept.
+func (s *Service) Delete(ctx context.Context, userID, id int64) error {
+	existing, err := s.ownComment(ctx, userID, id)
+	if err != nil {
+		return err
+	}
+	existing.Content = ""
+	existing.Deleted = true
+	existing.UpdatedAt = time.Now()
+	if err := s.commentRepo.Update(ctx, &existing); err != nil {
+		return err
+	}
+	s.invalidateArticle(ctx, existing.ArticleID)
+	return nil
+}
+
+// Moderate moves a comment to the given state, only contributors allowed to edit the article may moderate it
+func (s *Service) Moderate(ctx context.Context, userID, id int64, status domain.CommentStatus) (domain.Comment, error) {
+	if !status.Valid() {
+		return domain.Comment{}, domain.ErrBadParamInput
+	}
+	existing, err := s.commentRepo.GetByID(ctx, id)
+	if err != nil {
+		return domain.Comment{}, err
+	}
+	article, err := s.articleRepo.GetByID(ctx, existing.ArticleID)
+	if err != nil {
+		return domain.Comment{}, err
+	}
+	if err := s.canModerate(ctx, article.ID, userID); err != nil {
+		return domain.Comment{}, err
+	}
+	if existing.Deleted {
+		return domain.Comment{}, domain.ErrNotFound
+	}
+
+	approved := status == domain.CommentApproved && existing.Status != domain.CommentApproved
+	existing.Status = status
+	existing.UpdatedAt = time.Now()
+	if err := s.commentRepo.Update(ctx, &existing); err != nil {
+		return domain.Comment{}, err
+	}
+	s.invalidateArticle(ctx, existing.ArticleID)
+	if approved {
+		var parent domain.Comment
+		if existing.ParentID != 0 {
+			if parent, err = s.commentRepo.GetByID(ctx, existing.ParentID); err != nil {
+				logrus.Warnf("failed to load parent of comment %d: %v", existing.ID, err)
+			}
+		}
+		s.notify(ctx, article, parent, &existing)
+	}
+	if err := s.fillUser(ctx, &existing); err != nil {
+		return domain.Comment{}, err
+	}
+	return existing, nil
+}
+
+func (s *Service) canModerate(ctx context.Context, articleID, userID int64) error {
+	role, err := s.contribRepo.Role(ctx, articleID, userID)
+	if err != nil {
+		return err
+	}
+	if !role.CanEdit() {
+		return domain.ErrForbidden
+	}
+	return nil
+}
+
+func (s *Service) publishedArticle(ctx context.Context, id int64) (domain.Article, error) {
+	article, err := s.articleRepo.GetByID(ctx, id)
+	if err != nil {
+		return domain.Article{}, err
+	}
+	if !article.Reachable() {
+		return domain.Article{}, domain.ErrNotFound
+	}
+	return article, nil
+}
+
+func (s *Service) ownComment(ctx context.Context, userID, id int64) (domain.Comment, error) {
+	existing, err := s.commentRepo.GetByID(ctx, id)
+	if err != nil {
+		return domain.Comment{}, err
+	}
+	if existing.Deleted {
+		return domain.Comment{}, domain.ErrNotFound
+	}
+	if existing.User.ID != userID {
+		return domain.Comment{}, domain.ErrForbidden
+	}
+	return existing, nil
+}
+
+// notify tells the article author about a new visible comment and the parent
+// author about a reply, an author replied to on their own article is told once
+func (s *Service) notify(ctx context.Context, article domain.Article, parent domain.Comment, c *domain.Comment) {
+	if parent.ID != 0 {
+		s.notifier.Notify(ctx, domain.Activity{
+			Type:        domain.NotificationReply,
+			ActorID:     c.User.ID,
+			RecipientID: parent.User.ID,
+			ArticleID:   c.ArticleID,
+			CommentID:   parent.ID,
+			OccurredAt:  c.UpdatedAt,
+		})
+		if parent.User.ID == article.User.ID {
+			return
+		}
+	}
+	s.notifier.Notify(ctx, domain.Activity{
+		Type:        domain.NotificationComment,
+		ActorID:     c.User.ID,
+		RecipientID: article.User.ID,
+		ArticleID:   c.ArticleID,
+		OccurredAt:  c.UpdatedAt,
+	})
+}
+
+// invalidateArticle drops the cached article so its comment count is reloaded
+func (s *Service) invalidateArticle(ctx context.Context, articleID int64) {
+	if err := s.articleCache.Del(ctx, articleID); err != nil {
+		logrus.Warnf("failed to invalidate cached article %d: %v", articleID, err)
+	}
+}
+
+func (s *Service) fillUser(ctx context.Context, c *domain.Comment) error {
+	user, err := s.userRepo.GetByID(ctx, c.User.ID)
+	if err != nil {
+		return err
+	}
+	c.User = user
+	return nil
+}
+
+// fillUsers loads the author of every comment of the trees, each user once
+func (s *Service) fillUsers(ctx context.Context, comments []domain.Comment) error {
+	users := make(map[int64]domain.User)
+	var fill func(list []domain.Comment) error
+	fill = func(list []domain.Comment) error {
+		for i := range list {
+			id := list[i].User.ID
+			user, ok := users[id]
+			if !ok {
+				var err error
+				user, err = s.userRepo.GetByID(ctx, id)
+				if err != nil {
+					return err
+				}
+				users[id] = user
+			}
+			list[i].User = user
+			if err := fill(list[i].Replies); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	return fill(comments)
+}
+
+func cleanContent(content string) (string, error) {
+	content = strings.TrimSpace(content)
+	if content == "" || len([]rune(content)) > maxContentLength {
+		return "", domain.ErrBadParamInput
+	}
+	return content, nil
+}
+
+// buildThreads nests the replies under their parents. Replies whose parent is
+// not visible are dropped, so are deleted comments left without replies.
+func buildThreads(roots, replies []domain.Comment) []domain.Comment {
+	children := make(map[int64][]domain.Comment)
+	for _, r := range replies {
+		children[r.ParentID] = append(children[r.ParentID], r)
+	}
+
+	var attach func(c domain.Comment) (domain.Comment, bool)
+	attach = func(c domain.Comment) (domain.Comment, bool) {
+		c.Replies = nil
+		for _, child := range children[c.ID] {
+			if nested, ok := attach(child); ok {
+				c.Replies = append(c.Replies, nested)
+			}
+		}
+		return c, !c.Deleted || len(c.Replies) > 0
+	}
+
+	res := make([]domain.Comment, 0, len(roots))
+	for _, root := range roots {
+		if thread, ok := attach(root); ok {
+			res = append(res, thread)
+		}
+	}
+	return res
+}
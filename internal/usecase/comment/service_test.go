@@ -0,0 +1,49 @@
+package comment
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bxcodec/go-clean-arch/domain"
+)
+
+func TestBuildThreads(t *testing.T) {
+	roots := []domain.Comment{
+		{ID: 1},
+		{ID: 2, Deleted: true},
+		{ID: 3, Deleted: true},
+	}
+	replies := []domain.Comment{
+		{ID: 4, ParentID: 1, RootID: 1},
+		{ID: 5, ParentID: 4, RootID: 1},
+		{ID: 6, ParentID: 1, RootID: 1, Deleted: true},
+		{ID: 7, ParentID: 3, RootID: 3},
+		// the parent was rejected, so it is not among the replies
+		{ID: 8, ParentID: 9, RootID: 1},
+	}
+
+	threads := buildThreads(roots, replies)
+
+	require.Len(t, threads, 2)
+	assert.Equal(t, int64(1), threads[0].ID)
+	require.Len(t, threads[0].Replies, 1)
+	assert.Equal(t, int64(4), threads[0].Replies[0].ID)
+	require.Len(t, threads[0].Replies[0].Replies, 1)
+	assert.Equal(t, int64(5), threads[0].Replies[0].Replies[0].ID)
+
+	// a deleted comment stays while it has replies
+	assert.Equal(t, int64(3), threads[1].ID)
+	assert.True(t, threads[1].Deleted)
+	require.Len(t, threads[1].Replies, 1)
+}
+
+func TestCleanContent(t *testing.T) {
+	content, err := cleanContent("  hello  ")
+	assert.NoError(t, err)
+	assert.Equal(t, "hello", content)
+
+	_, err = cleanContent("   ")
+	assert.ErrorIs(t, err, domain.ErrBadParamInput)
+}
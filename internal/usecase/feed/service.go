@@ -0,0 +1,309 @@
+package feed
+
+import (
+	"cmp"
+	"context"
+	"slices"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/bxcodec/go-clean-arch/domain"
+	"github.com/bxcodec/go-clean-arch/internal/repository"
+)
+
+const (
+	// DefaultFanOutLimit is the follower count up to which new articles are
+	// pushed into the timelines of the followers, articles of more followed
+	// authors are pulled when the feed is read
+	DefaultFanOutLimit = 1000
+
+	// timelineSize is the number of articles loaded when a timeline is rebuilt
+	timelineSize = 500
+	// backfillSize is the number of recent articles of an author copied into a
+	// timeline on follow
+	backfillSize = 20
+	// fanOutBatch is the number of follower timelines updated per write
+	fanOutBatch = 500
+)
+
+type Service struct {
+	followRepo  domain.FollowRepository
+	articleRepo domain.ArticleRepository
+	userRepo    domain.UserRepository
+	feedCache   domain.FeedCache
+	notifier    domain.Notifier
+	fanOutLimit int64
+}
+
+// NewService will create a new feed service object, fanOutLimit is the
+// follower count up to which articles are pushed into follower timelines
+func NewService(f domain.FollowRepository, a domain.ArticleRepository, u domain.UserRepository, fc domain.FeedCache, n domain.Notifier, fanOutLimit int64) *Service {
+	if fanOutLimit < 0 {
+		fanOutLimit = DefaultFanOutLimit
+	}
+	return &Service{
+		followRepo:  f,
+		articleRepo: a,
+		userRepo:    u,
+		feedCache:   fc,
+		notifier:    n,
+		fanOutLimit: fanOutLimit,
+	}
+}
+
+// Follow makes followerID follow followeeID, following again is a no-op
+func (s *Service) Follow(ctx context.Context, followerID, followeeID int64) error {
+	if followerID == followeeID {
+		return domain.ErrBadParamInput
+	}
+	if _, err := s.userRepo.GetByID(ctx, followeeID); err != nil {
+		return err
+	}
+
+	now := time.Now()
+	added, err := s.followRepo.Follow(ctx, &domain.Follow{
+		FollowerID: followerID,
+		FolloweeID: followeeID,
+		CreatedAt:  now,
+	})
+	if err != nil || !added {
+		return err
+	}
+	s.notifier.Notify(ctx, domain.Activity{
+		Type:        domain.NotificationFollow,
+		ActorID:     followerID,
+		RecipientID: followeeID,
+		OccurredAt:  now,
+	})
+
+	// articles of popular authors are pulled on read, the others have to be
+	// copied into the timeline so the feed does not wait for the next article
+	stats, err := s.followRepo.Stats(ctx, followeeID)
+	if err != nil {
+		logrus.Warnf("feed backfill of user %d skipped: %v", followerID, err)
+		return nil
+	}
+	if stats.Followers > s.fanOutLimit {
+		return nil
+	}
+	articles, err := s.articleRepo.FetchByAuthors(ctx, []int64{followeeID}, time.Now(), backfillSize)
+	if err == nil {
+		err = s.feedCache.Push(ctx, []int64{followerID}, feedEntries(articles))
+	}
+	if err != nil {
+		logrus.Warnf("feed backfill of user %d failed: %v", followerID, err)
+	}
+	return nil
+}
+
+// Unfollow removes the follow edge, the articles already in the timeline are
+// filtered out when the feed is read
+func (s *Service) Unfollow(ctx context.Context, followerID, followeeID int64) error {
+	removed, err := s.followRepo.Unfollow(ctx, followerID, followeeID)
+	if err != nil {
+		return err
+	}
+	if !removed {
+		return domain.ErrNotFound
+	}
+	return nil
+}
+
+func (s *Service) FetchFollowers(ctx context.Context, userID int64, cursor string, num int64) ([]domain.Follow, string, error) {
+	if _, err := s.userRepo.GetByID(ctx, userID); err != nil {
+		return nil, "", err
+	}
+	return s.followRepo.FetchFollowers(ctx, userID, cursor, num)
+}
+
+func (s *Service) FetchFollowing(ctx context.Context, userID int64, cursor string, num int64) ([]domain.Follow, string, error) {
+	if _, err := s.userRepo.GetByID(ctx, userID); err != nil {
+		return nil, "", err
+	}
+	return s.followRepo.FetchFollowing(ctx, userID, cursor, num)
+}
+
+// Feed returns a page of the recent published articles of the authors
+// followed by userID, newest first. The timeline holds the articles of the
+// authors that fan out on write; the articles of popular authors are merged
+// in from the database.
+func (s *Service) Feed(ctx context.Context, userID int64, cursor string, num int64) ([]domain.Article, string, error) {
+	before := time.Now()
+	if cursor != "" {
+		decodedCursor, err := repository.DecodeCursor(cursor)
+		if err != nil {
+			return nil, "", domain.ErrBadParamInput
+		}
+		before = decodedCursor
+	}
+	repository.PageVerify(&num)
+
+	following, err := s.followRepo.FollowingIDs(ctx, userID)
+	if err != nil || len(following) == 0 {
+		return nil, "", err
+	}
+	popular, err := s.followRepo.FilterPopular(ctx, following, s.fanOutLimit)
+	if err != nil {
+		return nil, "", err
+	}
+
+	entries, err := s.timeline(ctx, userID, following, popular, before, num)
+	if err != nil {
+		return nil, "", err
+	}
+	ids := make([]int64, len(entries))
+	for i, e := range entries {
+		ids[i] = e.ArticleID
+	}
+	pushed, err := s.articleRepo.FetchByIDs(ctx, ids)
+	if err != nil {
+		return nil, "", err
+	}
+	pulled, err := s.articleRepo.FetchByAuthors(ctx, popular, before, num)
+	if err != nil {
+		return nil, "", err
+	}
+
+	res := mergeFeed(pushed, pulled, following, int(num))
+	res, err = s.fillUsers(ctx, res)
+	if err != nil {
+		return nil, "", err
+	}
+
+	var nextCursor string
+	// entries dropped by the filter can make a page short, keep paging while a source is full
+	if len(res) > 0 && (len(res) == int(num) || len(entries) == int(num) || len(pulled) == int(num)) {
+		nextCursor = repository.EncodeCursor(res[len(res)-1].CreatedAt)
+	}
+	return res, nextCursor, nil
+}
+
+// timeline reads the cached timeline of the user and rebuilds it from the
+// database when it is missing
+func (s *Service) timeline(ctx context.Context, userID int64, following, popular []int64, before time.Time, num int64) ([]domain.FeedEntry, error) {
+	entries, ok, err := s.feedCache.Fetch(ctx, userID, before, num)
+	if err != nil {
+		logrus.Warnf("feed cache fetch error: %v", err)
+	}
+	if err == nil && ok {
+		return entries, nil
+	}
+
+	authors := make([]int64, 0, len(following))
+	for _, id := range following {
+		if !slices.Contains(popular, id) {
+			authors = append(authors, id)
+		}
+	}
+	articles, err := s.articleRepo.FetchByAuthors(ctx, authors, time.Now(), timelineSize)
+	if err != nil {
+		return nil, err
+	}
+	all := feedEntries(articles)
+	if err := s.feedCache.Rebuild(ctx, userID, all); err != nil {
+		logrus.Warnf("feed cache rebuild error: %v", err)
+	}
+
+	entries = make([]domain.FeedEntry, 0, num)
+	for _, e := range all {
+		if len(entries) == int(num) {
+			break
+		}
+		if e.CreatedAt.Before(before) {
+			entries = append(entries, e)
+		}
+	}
+	return entries, nil
+}
+
+func (s *Service) fillUsers(ctx context.Context, articles []domain.Article) ([]domain.Article, error) {
+	users := make(map[int64]domain.User)
+	for i := range articles {
+		id := articles[i].User.ID
+		user, ok := users[id]
+		if !ok {
+			var err error
+			user, err = s.userRepo.GetByID(ctx, id)
+			if err != nil {
+				return nil, err
+			}
+			users[id] = user
+		}
+		articles[i].User = user
+	}
+	return articles, nil
+}
+
+// Name implements domain.EventSink
+func (s *Service) Name() string {
+	return "feed"
+}
+
+// Publish implements domain.EventSink. It pushes newly published articles of
+// authors with at most fanOutLimit followers into the follower timelines.
+func (s *Service) Publish(ctx context.Context, event domain.ArticleEvent) error {
+	if event.Type != domain.ArticlePublished || !event.Article.Listed() {
+		return nil
+	}
+	authorID := event.Article.User.ID
+	stats, err := s.followRepo.Stats(ctx, authorID)
+	if err != nil {
+		return err
+	}
+	if stats.Followers == 0 || stats.Followers > s.fanOutLimit {
+		return nil
+	}
+
+	entries := feedEntries([]domain.Article{event.Article})
+	var after int64
+	for {
+		ids, err := s.followRepo.FollowerIDs(ctx, authorID, after, fanOutBatch)
+		if err != nil {
+			return err
+		}
+		if len(ids) == 0 {
+			return nil
+		}
+		// pushing is idempotent, a redelivered event rewrites the same entries
+		if err := s.feedCache.Push(ctx, ids, entries); err != nil {
+			return err
+		}
+		after = ids[len(ids)-1]
+	}
+}
+
+func feedEntries(articles []domain.Article) []domain.FeedEntry {
+	res := make([]domain.FeedEntry, len(articles))
+	for i := range articles {
+		res[i] = domain.FeedEntry{ArticleID: articles[i].ID, CreatedAt: articles[i].CreatedAt}
+	}
+	return res
+}
+
+// mergeFeed merges the timeline articles and the pulled articles newest
+// first, dropping duplicates, articles that are no longer listed and
+// articles of authors that are no longer followed
+func mergeFeed(pushed, pulled []domain.Article, following []int64, num int) []domain.Article {
+	seen := make(map[int64]bool, len(pushed)+len(pulled))
+	res := make([]domain.Article, 0, len(pushed)+len(pulled))
+	for _, articles := range [][]domain.Article{pushed, pulled} {
+		for _, a := range articles {
+			if seen[a.ID] || !a.Listed() || !slices.Contains(following, a.User.ID) {
+				continue
+			}
+			seen[a.ID] = true
+			res = append(res, a)
+		}
+	}
+	slices.SortFunc(res, func(a, b domain.Article) int {
+		if c := b.CreatedAt.Compare(a.CreatedAt); c != 0 {
+			return c
+		}
+		return cmp.Compare(b.ID, a.ID)
+	})
+	if len(res) > num {
+		res = res[:num]
+	}
+	return res
+}
@@ -0,0 +1,36 @@
+package feed
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/bxcodec/go-clean-arch/domain"
+)
+
+func TestMergeFeed(t *testing.T) {
+	now := time.Now()
+	article := func(id, author int64, age time.Duration, status domain.ArticleStatus) domain.Article {
+		return domain.Article{ID: id, User: domain.User{ID: author}, Status: status, CreatedAt: now.Add(-age)}
+	}
+	pushed := []domain.Article{
+		article(1, 10, 3*time.Minute, domain.ArticleStatusPublished),
+		article(2, 10, time.Minute, domain.ArticleStatusDraft),
+		article(3, 30, 2*time.Minute, domain.ArticleStatusPublished),
+		article(4, 10, 5*time.Minute, domain.ArticleStatusPublished),
+	}
+	pulled := []domain.Article{
+		article(5, 20, 0, domain.ArticleStatusPublished),
+		article(6, 20, 4*time.Minute, domain.ArticleStatusPublished),
+		article(1, 10, 3*time.Minute, domain.ArticleStatusPublished),
+	}
+
+	res := mergeFeed(pushed, pulled, []int64{10, 20}, 3)
+
+	ids := make([]int64, len(res))
+	for i := range res {
+		ids[i] = res[i].ID
+	}
+	assert.Equal(t, []int64{5, 1, 6}, ids)
+}
@@ -0,0 +1,169 @@
+package notification
+
+import (
+	"context"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/bxcodec/go-clean-arch/domain"
+)
+
+// fanOutBatch is the number of followers notified per insert when an article is published
+const fanOutBatch = 500
+
+type Service struct {
+	notificationRepo domain.NotificationRepository
+	followRepo       domain.FollowRepository
+}
+
+// NewService will create a new notification service object
+func NewService(n domain.NotificationRepository, f domain.FollowRepository) *Service {
+	return &Service{
+		notificationRepo: n,
+		followRepo:       f,
+	}
+}
+
+// Notify implements domain.Notifier. Activities of the same group are
+// aggregated into one unread notification, e.g. every reaction to an article.
+func (s *Service) Notify(ctx context.Context, a domain.Activity) {
+	if a.RecipientID == 0 || a.RecipientID == a.ActorID {
+		return
+	}
+	prefs, err := s.notificationRepo.Preferences(ctx, a.RecipientID)
+	if err != nil {
+		logrus.Warnf("failed to load notification preferences of user %d: %v", a.RecipientID, err)
+		return
+	}
+	if enabled, ok := prefs[a.Type]; ok && !enabled {
+		return
+	}
+
+	at := a.OccurredAt
+	if at.IsZero() {
+		at = time.Now()
+	}
+	n := domain.Notification{
+		UserID:    a.RecipientID,
+		Type:      a.Type,
+		GroupKey:  groupKey(a),
+		ArticleID: a.ArticleID,
+		CommentID: a.CommentID,
+		CreatedAt: at,
+		UpdatedAt: at,
+	}
+	if err := s.notificationRepo.Record(ctx, &n, a.ActorID); err != nil {
+		logrus.Warnf("failed to record %s notification for user %d: %v", a.Type, a.RecipientID, err)
+	}
+}
+
+// groupKey tells which activities are aggregated together
+func groupKey(a domain.Activity) string {
+	switch a.Type {
+	case domain.NotificationFollow:
+		return string(a.Type)
+	case domain.NotificationReply:
+		return string(a.Type) + ":comment:" + strconv.FormatInt(a.CommentID, 10)
+	default:
+		return string(a.Type) + ":article:" + strconv.FormatInt(a.ArticleID, 10)
+	}
+}
+
+// Fetch returns a page of the notifications of a user, most recently updated first
+func (s *Service) Fetch(ctx context.Context, userID int64, unreadOnly bool, cursor string, num int64) ([]domain.Notification, string, error) {
+	return s.notificationRepo.Fetch(ctx, userID, unreadOnly, cursor, num)
+}
+
+func (s *Service) CountUnread(ctx context.Context, userID int64) (int64, error) {
+	return s.notificationRepo.CountUnread(ctx, userID)
+}
+
+func (s *Service) MarkRead(ctx context.Context, userID, id int64) error {
+	return s.notificationRepo.MarkRead(ctx, userID, id, time.Now())
+}
+
+// MarkAllRead marks every unread notification of the user read and returns how many there were
+func (s *Service) MarkAllRead(ctx context.Context, userID int64) (int64, error) {
+	return s.notificationRepo.MarkAllRead(ctx, userID, time.Now())
+}
+
+// Preferences returns whether each notification type is enabled for the user
+func (s *Service) Preferences(ctx context.Context, userID int64) (map[domain.NotificationType]bool, error) {
+	stored, err := s.notificationRepo.Preferences(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	res := make(map[domain.NotificationType]bool, len(domain.NotificationTypes))
+	for _, t := range domain.NotificationTypes {
+		enabled, ok := stored[t]
+		res[t] = !ok || enabled
+	}
+	return res, nil
+}
+
+// SetPreferences enables or disables the given notification types, the other types are left as they are
+func (s *Service) SetPreferences(ctx context.Context, userID int64, prefs map[domain.NotificationType]bool) (map[domain.NotificationType]bool, error) {
+	for t := range prefs {
+		if !t.Valid() {
+			return nil, domain.ErrBadParamInput
+		}
+	}
+	if err := s.notificationRepo.SetPreferences(ctx, userID, prefs); err != nil {
+		return nil, err
+	}
+	return s.Preferences(ctx, userID)
+}
+
+// Name implements domain.EventSink
+func (s *Service) Name() string {
+	return "notification"
+}
+
+// Publish implements domain.EventSink. It notifies the followers of the author
+// when an article is published.
+func (s *Service) Publish(ctx context.Context, event domain.ArticleEvent) error {
+	if event.Type != domain.ArticlePublished || !event.Article.Listed() {
+		return nil
+	}
+	authorID := event.Article.User.ID
+	var after int64
+	for {
+		ids, err := s.followRepo.FollowerIDs(ctx, authorID, after, fanOutBatch)
+		if err != nil {
+			return err
+		}
+		if len(ids) == 0 {
+			return nil
+		}
+		after = ids[len(ids)-1]
+
+		muted, err := s.notificationRepo.Muted(ctx, domain.NotificationArticle, ids)
+		if err != nil {
+			return err
+		}
+		activity := domain.Activity{Type: domain.NotificationArticle, ActorID: authorID, ArticleID: event.ArticleID}
+		notifications := make([]domain.Notification, 0, len(ids))
+		for _, id := range ids {
+			if slices.Contains(muted, id) {
+				continue
+			}
+			notifications = append(notifications, domain.Notification{
+				UserID:     id,
+				Type:       domain.NotificationArticle,
+				GroupKey:   groupKey(activity),
+				ArticleID:  event.ArticleID,
+				Actor:      domain.User{ID: authorID},
+				ActorCount: 1,
+				CreatedAt:  event.OccurredAt,
+				UpdatedAt:  event.OccurredAt,
+			})
+		}
+		// a redelivered event is skipped while the first notification is unread
+		if err := s.notificationRepo.RecordMany(ctx, notifications); err != nil {
+			return err
+		}
+	}
+}
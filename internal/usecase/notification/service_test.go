@@ -0,0 +1,56 @@
+package notification
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/bxcodec/go-clean-arch/domain"
+)
+
+type recordingRepo struct {
+	domain.NotificationRepository
+	prefs    map[domain.NotificationType]bool
+	recorded []domain.Notification
+}
+
+func (r *recordingRepo) Preferences(ctx context.Context, userID int64) (map[domain.NotificationType]bool, error) {
+	return r.prefs, nil
+}
+
+func (r *recordingRepo) Record(ctx context.Context, n *domain.Notification, actorID int64) error {
+	n.Actor.ID = actorID
+	r.recorded = append(r.recorded, *n)
+	return nil
+}
+
+func TestNotify(t *testing.T) {
+	repo := &recordingRepo{prefs: map[domain.NotificationType]bool{domain.NotificationFollow: false}}
+	svc := NewService(repo, nil)
+	ctx := context.Background()
+
+	svc.Notify(ctx, domain.Activity{Type: domain.NotificationReaction, ActorID: 2, RecipientID: 1, ArticleID: 7})
+	svc.Notify(ctx, domain.Activity{Type: domain.NotificationReply, ActorID: 3, RecipientID: 1, ArticleID: 7, CommentID: 9})
+	// own activity and muted types are not notified
+	svc.Notify(ctx, domain.Activity{Type: domain.NotificationReaction, ActorID: 1, RecipientID: 1, ArticleID: 7})
+	svc.Notify(ctx, domain.Activity{Type: domain.NotificationFollow, ActorID: 4, RecipientID: 1})
+
+	if assert.Len(t, repo.recorded, 2) {
+		assert.Equal(t, "reaction:article:7", repo.recorded[0].GroupKey)
+		assert.Equal(t, int64(2), repo.recorded[0].Actor.ID)
+		assert.Equal(t, "reply:comment:9", repo.recorded[1].GroupKey)
+	}
+}
+
+func TestPreferencesDefaultToEnabled(t *testing.T) {
+	repo := &recordingRepo{prefs: map[domain.NotificationType]bool{domain.NotificationArticle: false}}
+	svc := NewService(repo, nil)
+
+	prefs, err := svc.Preferences(context.Background(), 1)
+
+	assert.NoError(t, err)
+	assert.Len(t, prefs, len(domain.NotificationTypes))
+	assert.False(t, prefs[domain.NotificationArticle])
+	assert.True(t, prefs[domain.NotificationComment])
+}
@@ -0,0 +1,139 @@
+package reaction
+
+import (
+	"context"
+	"slices"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/bxcodec/go-clean-arch/domain"
+)
+
+// DefaultKinds is the reaction set used when none is configured
+var DefaultKinds = []string{domain.ReactionLike, "love", "laugh", "wow", "sad"}
+
+type Service struct {
+	reactionRepo  domain.ReactionRepository
+	reactionCache domain.ReactionCache
+	articleRepo   domain.ArticleRepository
+	notifier      domain.Notifier
+	kinds         []string
+}
+
+// NewService will create a new reaction service object. Like is always part of the
+// reaction kinds, an empty kinds list falls back to DefaultKinds.
+func NewService(r domain.ReactionRepository, rc domain.ReactionCache, a domain.ArticleRepository, n domain.Notifier, kinds []string) *Service {
+	if len(kinds) == 0 {
+		kinds = DefaultKinds
+	}
+	if !slices.Contains(kinds, domain.ReactionLike) {
+		kinds = append([]string{domain.ReactionLike}, kinds...)
+	}
+	return &Service{
+		reactionRepo:  r,
+		reactionCache: rc,
+		articleRepo:   a,
+		notifier:      n,
+		kinds:         kinds,
+	}
+}
+
+// React adds the reaction of r.UserID, reacting twice with the same kind is a no-op
+func (s *Service) React(ctx context.Context, r *domain.Reaction) (domain.ReactionSummary, error) {
+	article, err := s.check(ctx, r)
+	if err != nil {
+		return domain.ReactionSummary{}, err
+	}
+	r.CreatedAt = time.Now()
+	added, err := s.reactionRepo.Add(ctx, r)
+	if err != nil {
+		return domain.ReactionSummary{}, err
+	}
+	if added {
+		s.incr(ctx, r, 1)
+		s.notifier.Notify(ctx, domain.Activity{
+			Type:        domain.NotificationReaction,
+			ActorID:     r.UserID,
+			RecipientID: article.User.ID,
+			ArticleID:   r.ArticleID,
+			OccurredAt:  r.CreatedAt,
+		})
+	}
+	return s.Summary(ctx, r.ArticleID, r.UserID)
+}
+
+// Unreact removes the reaction of r.UserID, removing a missing reaction is a no-op
+func (s *Service) Unreact(ctx context.Context, r *domain.Reaction) (domain.ReactionSummary, error) {
+	if _, err := s.check(ctx, r); err != nil {
+		return domain.ReactionSummary{}, err
+	}
+	removed, err := s.reactionRepo.Remove(ctx, r)
+	if err != nil {
+		return domain.ReactionSummary{}, err
+	}
+	if removed {
+		s.incr(ctx, r, -1)
+	}
+	return s.Summary(ctx, r.ArticleID, r.UserID)
+}
+
+// Summary returns the reaction counts of a published article. Mine is only
+// filled when userID is set.
+func (s *Service) Summary(ctx context.Context, articleID, userID int64) (domain.ReactionSummary, error) {
+	if _, err := s.checkArticle(ctx, articleID); err != nil {
+		return domain.ReactionSummary{}, err
+	}
+	synced, err := s.reactionRepo.Counts(ctx, articleID)
+	if err != nil {
+		return domain.ReactionSummary{}, err
+	}
+	pending, err := s.reactionCache.Pending(ctx, articleID, s.kinds)
+	if err != nil {
+		// the synced counts are at most one sync interval behind
+		logrus.Warnf("failed to get pending reactions of article %d: %v", articleID, err)
+	}
+
+	res := domain.ReactionSummary{
+		ArticleID: articleID,
+		Counts:    make(map[string]int64, len(s.kinds)),
+		Mine:      []string{},
+	}
+	for _, kind := range s.kinds {
+		res.Counts[kind] = max(synced[kind]+pending[kind], 0)
+	}
+	if userID != 0 {
+		mine, err := s.reactionRepo.ListKinds(ctx, articleID, userID)
+		if err != nil {
+			return domain.ReactionSummary{}, err
+		}
+		res.Mine = append(res.Mine, mine...)
+	}
+	return res, nil
+}
+
+func (s *Service) check(ctx context.Context, r *domain.Reaction) (domain.Article, error) {
+	if !slices.Contains(s.kinds, r.Kind) {
+		return domain.Article{}, domain.ErrBadParamInput
+	}
+	return s.checkArticle(ctx, r.ArticleID)
+}
+
+func (s *Service) checkArticle(ctx context.Context, articleID int64) (domain.Article, error) {
+	article, err := s.articleRepo.GetByID(ctx, articleID)
+	if err != nil {
+		return domain.Article{}, err
+	}
+	if !article.Reachable() {
+		return domain.Article{}, domain.ErrNotFound
+	}
+	return article, nil
+}
+
+func (s *Service) incr(ctx context.Context, r *domain.Reaction, delta int64) {
+	key := domain.ReactionKey{ArticleID: r.ArticleID, Kind: r.Kind}
+	if err := s.reactionCache.Incr(ctx, key, delta); err != nil {
+		// the reaction itself is stored, the sync job recounts the counter from it
+		logrus.Warnf("failed to count reaction %s on article %d: %v", r.Kind, r.ArticleID, err)
+	}
+}
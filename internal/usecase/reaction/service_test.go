@@ -0,0 +1,189 @@
+package reaction
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bxcodec/go-clean-arch/domain"
+)
+
+type fakeReactionRepo struct {
+	domain.ReactionRepository
+	reactions map[domain.Reaction]bool
+	counts    map[string]int64
+}
+
+func (f *fakeReactionRepo) Add(_ context.Context, r *domain.Reaction) (bool, error) {
+	key := domain.Reaction{ArticleID: r.ArticleID, UserID: r.UserID, Kind: r.Kind}
+	if f.reactions[key] {
+		return false, nil
+	}
+	f.reactions[key] = true
+	return true, nil
+}
+
+func (f *fakeReactionRepo) Remove(_ context.Context, r *domain.Reaction) (bool, error) {
+	key := domain.Reaction{ArticleID: r.ArticleID, UserID: r.UserID, Kind: r.Kind}
+	if !f.reactions[key] {
+		return false, nil
+	}
+	delete(f.reactions, key)
+	return true, nil
+}
+
+func (f *fakeReactionRepo) ListKinds(_ context.Context, articleID, userID int64) ([]string, error) {
+	var kinds []string
+	for r := range f.reactions {
+		if r.ArticleID == articleID && r.UserID == userID {
+			kinds = append(kinds, r.Kind)
+		}
+	}
+	return kinds, nil
+}
+
+func (f *fakeReactionRepo) Counts(context.Context, int64) (map[string]int64, error) {
+	return f.counts, nil
+}
+
+type fakeReactionCache struct {
+	domain.ReactionCache
+	deltas map[domain.ReactionKey]int64
+	err    error
+}
+
+func (f *fakeReactionCache) Incr(_ context.Context, key domain.ReactionKey, delta int64) error {
+	if f.err != nil {
+		return f.err
+	}
+	f.deltas[key] += delta
+	return nil
+}
+
+func (f *fakeReactionCache) Pending(_ context.Context, articleID int64, kinds []string) (map[string]int64, error) {
+	if f.err != nil {
+		return nil, f.err
+	}
+	res := make(map[string]int64)
+	for _, kind := range kinds {
+		res[kind] = f.deltas[domain.ReactionKey{ArticleID: articleID, Kind: kind}]
+	}
+	return res, nil
+}
+
+type fakeArticleRepo struct {
+	domain.ArticleRepository
+	articles map[int64]domain.Article
+}
+
+func (f *fakeArticleRepo) GetByID(_ context.Context, id int64) (domain.Article, error) {
+	a, ok := f.articles[id]
+	if !ok {
+		return domain.Article{}, domain.ErrNotFound
+	}
+	return a, nil
+}
+
+type fakeNotifier struct {
+	activities []domain.Activity
+}
+
+func (f *fakeNotifier) Notify(_ context.Context, a domain.Activity) {
+	f.activities = append(f.activities, a)
+}
+
+func newTestService() (*Service, *fakeReactionRepo, *fakeReactionCache, *fakeNotifier) {
+	repo := &fakeReactionRepo{reactions: map[domain.Reaction]bool{}, counts: map[string]int64{}}
+	cache := &fakeReactionCache{deltas: map[domain.ReactionKey]int64{}}
+	articles := &fakeArticleRepo{articles: map[int64]domain.Article{
+		1: {ID: 1, User: domain.User{ID: 10}, Status: domain.ArticleStatusPublished},
+		2: {ID: 2, User: domain.User{ID: 10}, Status: domain.ArticleStatusDraft},
+		3: {ID: 3, User: domain.User{ID: 10}, Status: domain.ArticleStatusPublished, Visibility: domain.ArticleVisibilityPrivate},
+	}}
+	notifier := &fakeNotifier{}
+	return NewService(repo, cache, articles, notifier, []string{"love"}), repo, cache, notifier
+}
+
+func TestNewServiceAlwaysOffersLike(t *testing.T) {
+	svc, _, _, _ := newTestService()
+	assert.Equal(t, []string{domain.ReactionLike, "love"}, svc.kinds)
+
+	svc = NewService(nil, nil, nil, nil, nil)
+	assert.Equal(t, DefaultKinds, svc.kinds)
+}
+
+func TestReact(t *testing.T) {
+	svc, _, cache, notifier := newTestService()
+	ctx := context.Background()
+
+	summary, err := svc.React(ctx, &domain.Reaction{ArticleID: 1, UserID: 20, Kind: "love"})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]int64{"like": 0, "love": 1}, summary.Counts)
+	assert.Equal(t, []string{"love"}, summary.Mine)
+	require.Len(t, notifier.activities, 1)
+	assert.Equal(t, int64(10), notifier.activities[0].RecipientID)
+
+	// reacting twice is a no-op
+	summary, err = svc.React(ctx, &domain.Reaction{ArticleID: 1, UserID: 20, Kind: "love"})
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), summary.Counts["love"])
+	assert.Equal(t, int64(1), cache.deltas[domain.ReactionKey{ArticleID: 1, Kind: "love"}])
+	assert.Len(t, notifier.activities, 1)
+}
+
+func TestReactValidation(t *testing.T) {
+	svc, _, _, _ := newTestService()
+	ctx := context.Background()
+
+	_, err := svc.React(ctx, &domain.Reaction{ArticleID: 1, UserID: 20, Kind: "angry"})
+	assert.ErrorIs(t, err, domain.ErrBadParamInput)
+
+	// drafts, private and missing articles can not be reacted to
+	for _, id := range []int64{2, 3, 4} {
+		_, err = svc.React(ctx, &domain.Reaction{ArticleID: id, UserID: 20, Kind: "like"})
+		assert.ErrorIs(t, err, domain.ErrNotFound)
+	}
+}
+
+func TestUnreact(t *testing.T) {
+	svc, repo, cache, _ := newTestService()
+	ctx := context.Background()
+	repo.counts["like"] = 1
+	repo.reactions[domain.Reaction{ArticleID: 1, UserID: 20, Kind: "like"}] = true
+
+	summary, err := svc.Unreact(ctx, &domain.Reaction{ArticleID: 1, UserID: 20, Kind: "like"})
+	require.NoError(t, err)
+	assert.Equal(t, int64(0), summary.Counts["like"])
+	assert.Empty(t, summary.Mine)
+
+	// removing a missing reaction does not count twice
+	_, err = svc.Unreact(ctx, &domain.Reaction{ArticleID: 1, UserID: 20, Kind: "like"})
+	require.NoError(t, err)
+	assert.Equal(t, int64(-1), cache.deltas[domain.ReactionKey{ArticleID: 1, Kind: "like"}])
+}
+
+func TestSummary(t *testing.T) {
+	svc, repo, cache, _ := newTestService()
+	ctx := context.Background()
+	repo.counts["like"] = 4
+	cache.deltas[domain.ReactionKey{ArticleID: 1, Kind: "like"}] = 2
+	// a drifted counter is never shown below zero
+	cache.deltas[domain.ReactionKey{ArticleID: 1, Kind: "love"}] = -1
+
+	summary, err := svc.Summary(ctx, 1, 0)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]int64{"like": 6, "love": 0}, summary.Counts)
+	assert.Equal(t, []string{}, summary.Mine)
+
+	// the synced counts are served when redis is unavailable
+	cache.err = errors.New("unavailable")
+	summary, err = svc.Summary(ctx, 1, 0)
+	require.NoError(t, err)
+	assert.Equal(t, int64(4), summary.Counts["like"])
+
+	_, err = svc.Summary(ctx, 2, 0)
+	assert.ErrorIs(t, err, domain.ErrNotFound)
+}
@@ -0,0 +1,214 @@
+package related
+
+import (
+	"cmp"
+	"math"
+	"slices"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/bxcodec/go-clean-arch/domain"
+	"github.com/bxcodec/go-clean-arch/internal/content"
+)
+
+// The weights of the signals in the score of a recommendation, they add up to 1
+const (
+	tagWeight    = 0.3
+	textWeight   = 0.4
+	coViewWeight = 0.3
+
+	// minTermLength leaves out the short words that carry no topic
+	minTermLength = 3
+)
+
+type document struct {
+	id int64
+	// listed documents are the only ones recommended, the others only get recommendations
+	listed bool
+	tags   []int64
+	// terms holds the TF-IDF weights of the words, normalized to a unit vector
+	terms map[string]float64
+}
+
+type posting struct {
+	doc    int
+	weight float64
+}
+
+// index holds the term vectors and tags of the readable articles with an
+// inverted index from terms to the documents using them
+type index struct {
+	docs  []document
+	byID  map[int64]int
+	terms map[string][]posting
+}
+
+// terms splits text into lowercase words and counts them
+func terms(text string) map[string]int {
+	res := map[string]int{}
+	words := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	for _, w := range words {
+		if utf8.RuneCountInString(w) >= minTermLength {
+			res[w]++
+		}
+	}
+	return res
+}
+
+// counted holds the word counts of an article and the version they were
+// counted from, so that articles are only read again once they changed
+type counted struct {
+	updatedAt   time.Time
+	fingerprint uint64
+	counts      map[string]int
+}
+
+func countTerms(ar domain.Article) counted {
+	return counted{
+		updatedAt:   ar.UpdatedAt,
+		fingerprint: ar.Fingerprint,
+		counts:      terms(ar.Title + " " + content.PlainText(ar.ContentHTML)),
+	}
+}
+
+// current reports whether the counts were taken from the article as it is,
+// rendering an article changes its fingerprint but not its update time
+func (c counted) current(ar domain.Article) bool {
+	return c.counts != nil && c.updatedAt.Equal(ar.UpdatedAt) && c.fingerprint == ar.Fingerprint
+}
+
+// builder collects the word counts of articles so that the index is built
+// without their content
+type builder struct {
+	docs   []document
+	counts []map[string]int
+	df     map[string]int
+}
+
+func newBuilder() *builder {
+	return &builder{df: map[string]int{}}
+}
+
+func (b *builder) add(ar domain.Article, tags []int64, counts map[string]int) {
+	for t := range counts {
+		b.df[t]++
+	}
+	b.docs = append(b.docs, document{id: ar.ID, listed: ar.Listed(), tags: tags})
+	b.counts = append(b.counts, counts)
+}
+
+// build computes the TF-IDF vectors of the articles added
+func (b *builder) build() *index {
+	idx := &index{
+		docs:  b.docs,
+		byID:  make(map[int64]int, len(b.docs)),
+		terms: map[string][]posting{},
+	}
+	n := float64(len(b.docs))
+	for i, counts := range b.counts {
+		total := 0
+		for _, c := range counts {
+			total += c
+		}
+		vector := make(map[string]float64, len(counts))
+		var norm float64
+		for t, c := range counts {
+			// terms used by every article weigh nothing
+			w := float64(c) / float64(total) * math.Log(n/float64(b.df[t]))
+			if w > 0 {
+				vector[t] = w
+				norm += w * w
+			}
+		}
+		norm = math.Sqrt(norm)
+		for t := range vector {
+			vector[t] /= norm
+			idx.terms[t] = append(idx.terms[t], posting{doc: i, weight: vector[t]})
+		}
+		idx.docs[i].terms = vector
+		idx.byID[idx.docs[i].id] = i
+	}
+	return idx
+}
+
+// related ranks the other documents against the document at position i and
+// returns the best limit with a positive score. coViews maps article ids to
+// the number of readers they share with the document.
+func (idx *index) related(i int, coViews map[int64]float64, limit int) []domain.RelatedScore {
+	doc := idx.docs[i]
+	text := make(map[int]float64)
+	for t, w := range doc.terms {
+		for _, p := range idx.terms[t] {
+			text[p.doc] += w * p.weight
+		}
+	}
+	var maxCoViews float64
+	for _, n := range coViews {
+		maxCoViews = max(maxCoViews, n)
+	}
+
+	scores := make(map[int]float64, len(text))
+	for j, cos := range text {
+		scores[j] += textWeight * cos
+	}
+	for id, n := range coViews {
+		if j, ok := idx.byID[id]; ok {
+			scores[j] += coViewWeight * n / maxCoViews
+		}
+	}
+	if len(doc.tags) > 0 {
+		for j := range idx.docs {
+			if s := jaccard(doc.tags, idx.docs[j].tags); s > 0 {
+				scores[j] += tagWeight * s
+			}
+		}
+	}
+	res := make([]domain.RelatedScore, 0, len(scores))
+	for j, s := range scores {
+		if j != i && s > 0 && idx.docs[j].listed {
+			res = append(res, domain.RelatedScore{ArticleID: idx.docs[j].id, Score: s})
+		}
+	}
+	slices.SortFunc(res, func(a, b domain.RelatedScore) int {
+		if c := cmp.Compare(b.Score, a.Score); c != 0 {
+			return c
+		}
+		return cmp.Compare(b.ArticleID, a.ArticleID)
+	})
+	if len(res) > limit {
+		res = res[:limit]
+	}
+	return res
+}
+
+// jaccard is the share of the tags of both articles that they have in common
+func jaccard(a, b []int64) float64 {
+	if len(a) == 0 || len(b) == 0 {
+		return 0
+	}
+	shared := 0
+	for _, x := range a {
+		if slices.Contains(b, x) {
+			shared++
+		}
+	}
+	return float64(shared) / float64(len(a)+len(b)-shared)
+}
+
+// after returns up to limit document ids following afterID in id order,
+// wrapping around to the first ones
+func (idx *index) after(afterID int64, limit int) []int64 {
+	start, _ := slices.BinarySearchFunc(idx.docs, afterID+1, func(d document, id int64) int {
+		return cmp.Compare(d.id, id)
+	})
+	limit = min(limit, len(idx.docs))
+	res := make([]int64, limit)
+	for i := range res {
+		res[i] = idx.docs[(start+i)%len(idx.docs)].id
+	}
+	return res
+}
@@ -0,0 +1,55 @@
+package related
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bxcodec/go-clean-arch/domain"
+)
+
+func testIndex() *index {
+	b := newBuilder()
+	add := func(id int64, html string, listed bool, tags ...int64) {
+		ar := domain.Article{ID: id, ContentHTML: html, Status: domain.ArticleStatusPublished, Visibility: domain.ArticleVisibilityPublic}
+		if !listed {
+			ar.Visibility = domain.ArticleVisibilityUnlisted
+		}
+		b.add(ar, tags, countTerms(ar).counts)
+	}
+	add(1, "<p>Goroutines and channels make concurrency in Go simple</p>", true, 10, 11)
+	add(2, "<p>Goroutines and <em>channels</em> make concurrency simple</p>", true)
+	add(3, "<p>Baking sourdough bread needs a healthy starter</p>", true, 10)
+	add(4, "<p>Sourdough starter feeding schedule</p>", true)
+	add(5, "<p>Goroutines leak when channels are never closed</p>", false)
+	return b.build()
+}
+
+func TestRelated(t *testing.T) {
+	idx := testIndex()
+
+	res := idx.related(idx.byID[1], nil, Size)
+	require.Len(t, res, 2, "unlisted articles and articles sharing nothing are left out")
+	assert.Equal(t, int64(2), res[0].ArticleID, "shared words outrank a shared tag")
+	assert.Equal(t, int64(3), res[1].ArticleID)
+	assert.InDelta(t, tagWeight*0.5, res[1].Score, 1e-9)
+
+	res = idx.related(idx.byID[1], map[int64]float64{4: 8, 3: 2}, Size)
+	require.Len(t, res, 3)
+	assert.Equal(t, int64(4), res[1].ArticleID, "co-views alone recommend an article")
+	assert.InDelta(t, coViewWeight, res[1].Score, 1e-9)
+	assert.InDelta(t, tagWeight*0.5+coViewWeight*0.25, res[2].Score, 1e-9)
+
+	res = idx.related(idx.byID[5], nil, 1)
+	require.Len(t, res, 1, "unlisted articles get recommendations")
+	assert.Equal(t, int64(2), res[0].ArticleID)
+}
+
+func TestIndexAfter(t *testing.T) {
+	idx := testIndex()
+
+	assert.Equal(t, []int64{1, 2}, idx.after(0, 2))
+	assert.Equal(t, []int64{4, 5, 1}, idx.after(3, 3), "the refresh wraps around")
+	assert.Equal(t, []int64{1, 2, 3, 4, 5}, idx.after(9, 10))
+}
@@ -0,0 +1,264 @@
+package related
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"sync"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/sirupsen/logrus"
+
+	"github.com/bxcodec/go-clean-arch/domain"
+)
+
+const (
+	// Size is the number of recommendations computed per article
+	Size = 20
+	// corpusPage is the number of articles read at once to build the index
+	corpusPage = 500
+	// coViewCandidates bounds the co-viewed articles considered per article
+	coViewCandidates = 100
+)
+
+type Service struct {
+	articleRepo  domain.ArticleRepository
+	userRepo     domain.UserRepository
+	categoryRepo domain.CategoryRepository
+	cache        domain.RelatedCache
+
+	// mu guards corpus, the word counts kept from one refresh to the next so
+	// that only the articles changed in between are read with their content
+	mu     sync.Mutex
+	corpus map[int64]counted
+}
+
+// NewService will create a new related articles service object
+func NewService(a domain.ArticleRepository, u domain.UserRepository, c domain.CategoryRepository, rc domain.RelatedCache) *Service {
+	return &Service{
+		articleRepo:  a,
+		userRepo:     u,
+		categoryRepo: c,
+		cache:        rc,
+		corpus:       map[int64]counted{},
+	}
+}
+
+// Related returns up to num listed articles recommended next to a readable
+// article, the best first. It is empty until the worker computed them.
+func (s *Service) Related(ctx context.Context, articleID int64, num int64) ([]domain.RelatedArticle, error) {
+	ar, err := s.articleRepo.GetByID(ctx, articleID)
+	if err != nil {
+		return nil, err
+	}
+	if !ar.Reachable() {
+		return nil, domain.ErrNotFound
+	}
+	if num <= 0 || num > Size {
+		num = Size
+	}
+
+	scores, err := s.cache.Get(ctx, articleID)
+	if errors.Is(err, redis.Nil) {
+		if err := s.cache.MarkDirty(ctx, articleID); err != nil {
+			logrus.Warnf("failed to queue recommendations of article %d: %v", articleID, err)
+		}
+		return []domain.RelatedArticle{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	ids := make([]int64, len(scores))
+	for i, sc := range scores {
+		ids[i] = sc.ArticleID
+	}
+	articles, err := s.articleRepo.FetchByIDs(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	byID := make(map[int64]domain.Article, len(articles))
+	for _, a := range articles {
+		byID[a.ID] = a
+	}
+	// recommendations deleted or hidden since they were computed are skipped
+	res := make([]domain.RelatedArticle, 0, num)
+	for _, sc := range scores {
+		a, ok := byID[sc.ArticleID]
+		if !ok || !a.Listed() {
+			continue
+		}
+		res = append(res, domain.RelatedArticle{Article: a, Score: sc.Score})
+		if len(res) == int(num) {
+			break
+		}
+	}
+	return s.fillUsers(ctx, res)
+}
+
+func (s *Service) fillUsers(ctx context.Context, related []domain.RelatedArticle) ([]domain.RelatedArticle, error) {
+	users := make(map[int64]domain.User)
+	for i := range related {
+		id := related[i].Article.User.ID
+		user, ok := users[id]
+		if !ok {
+			var err error
+			user, err = s.userRepo.GetByID(ctx, id)
+			if err != nil {
+				return nil, err
+			}
+			users[id] = user
+		}
+		related[i].Article.User = user
+	}
+	return related, nil
+}
+
+// Refresh computes the recommendations of up to limit articles: the ones
+// queued by changes first, then the next ones of a rolling refresh so that
+// every article follows the changes of the others and of its co-views. It
+// reports how many it computed.
+func (s *Service) Refresh(ctx context.Context, limit int) (int, error) {
+	idx, err := s.buildIndex(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	ids, err := s.cache.PopDirty(ctx, limit)
+	if err != nil {
+		return 0, err
+	}
+	if rest := limit - len(ids); rest > 0 && len(idx.docs) > 0 {
+		cursor, err := s.cache.Cursor(ctx)
+		if err != nil {
+			return 0, err
+		}
+		next := idx.after(cursor, rest)
+		for _, id := range next {
+			if !slices.Contains(ids, id) {
+				ids = append(ids, id)
+			}
+		}
+		if err := s.cache.SaveCursor(ctx, next[len(next)-1]); err != nil {
+			return 0, err
+		}
+	}
+
+	computed := 0
+	for _, id := range ids {
+		i, ok := idx.byID[id]
+		if !ok {
+			// drafts and private articles get no recommendations
+			continue
+		}
+		coViews, err := s.cache.CoViews(ctx, id, coViewCandidates)
+		if err != nil {
+			return computed, err
+		}
+		if err := s.cache.Set(ctx, id, idx.related(i, coViews, Size)); err != nil {
+			return computed, err
+		}
+		computed++
+	}
+	return computed, nil
+}
+
+// buildIndex reads every readable article with its tags into an index. The
+// articles are listed without their content, only the ones that changed since
+// the last refresh are counted again.
+func (s *Service) buildIndex(ctx context.Context) (*index, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	b := newBuilder()
+	seen := make(map[int64]bool, len(s.corpus))
+	var afterID int64
+	for {
+		page, err := s.articleRepo.FetchAfter(ctx, afterID, corpusPage, false)
+		if err != nil {
+			return nil, err
+		}
+		if len(page) == 0 {
+			break
+		}
+		afterID = page[len(page)-1].ID
+
+		readable := page[:0]
+		ids := make([]int64, 0, len(page))
+		var stale []int64
+		for _, ar := range page {
+			if !ar.Reachable() {
+				continue
+			}
+			readable = append(readable, ar)
+			ids = append(ids, ar.ID)
+			seen[ar.ID] = true
+			if !s.corpus[ar.ID].current(ar) {
+				stale = append(stale, ar.ID)
+			}
+		}
+		if err := s.count(ctx, readable, stale); err != nil {
+			return nil, err
+		}
+		categories, err := s.categoryRepo.FetchByArticles(ctx, ids)
+		if err != nil {
+			return nil, err
+		}
+		for _, ar := range readable {
+			tags := make([]int64, len(categories[ar.ID]))
+			for i, c := range categories[ar.ID] {
+				tags[i] = c.ID
+			}
+			b.add(ar, tags, s.corpus[ar.ID].counts)
+		}
+	}
+
+	// articles deleted, drafted or made private since the last refresh
+	for id := range s.corpus {
+		if !seen[id] {
+			delete(s.corpus, id)
+		}
+	}
+	return b.build(), nil
+}
+
+// count reads the content of the stale articles of a page and keeps their
+// word counts with the version of the page
+func (s *Service) count(ctx context.Context, page []domain.Article, stale []int64) error {
+	if len(stale) == 0 {
+		return nil
+	}
+	rendered, err := s.articleRepo.FetchRenderedByIDs(ctx, stale)
+	if err != nil {
+		return err
+	}
+	byID := make(map[int64]domain.Article, len(rendered))
+	for _, r := range rendered {
+		byID[r.ID] = r
+	}
+	for _, ar := range page {
+		r, ok := byID[ar.ID]
+		if !ok {
+			continue
+		}
+		// a change made since the page was read is counted on the next refresh
+		r.UpdatedAt = ar.UpdatedAt
+		r.Fingerprint = ar.Fingerprint
+		s.corpus[ar.ID] = countTerms(r)
+	}
+	return nil
+}
+
+// Name implements domain.EventSink
+func (s *Service) Name() string {
+	return "related"
+}
+
+// Publish implements domain.EventSink. Changed articles get their
+// recommendations computed again, deleted ones lose them.
+func (s *Service) Publish(ctx context.Context, event domain.ArticleEvent) error {
+	if event.Type == domain.ArticleDeleted {
+		return s.cache.Del(ctx, event.ArticleID)
+	}
+	return s.cache.MarkDirty(ctx, event.ArticleID)
+}
@@ -0,0 +1,118 @@
+package related
+
+import (
+	"context"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bxcodec/go-clean-arch/domain"
+)
+
+type fakeArticleRepo struct {
+	domain.ArticleRepository
+	articles []domain.Article
+	// rendered lists the ids of every FetchRenderedByIDs call
+	rendered [][]int64
+}
+
+func (f *fakeArticleRepo) FetchAfter(_ context.Context, afterID int64, limit int, withContent bool) ([]domain.Article, error) {
+	var res []domain.Article
+	for _, ar := range f.articles {
+		if ar.ID > afterID && len(res) < limit {
+			if !withContent {
+				ar.Content, ar.ContentHTML, ar.TOC = "", "", nil
+			}
+			res = append(res, ar)
+		}
+	}
+	return res, nil
+}
+
+func (f *fakeArticleRepo) FetchRenderedByIDs(_ context.Context, ids []int64) ([]domain.Article, error) {
+	f.rendered = append(f.rendered, ids)
+	var res []domain.Article
+	for _, ar := range f.articles {
+		if slices.Contains(ids, ar.ID) {
+			res = append(res, domain.Article{ID: ar.ID, Title: ar.Title, ContentHTML: ar.ContentHTML})
+		}
+	}
+	return res, nil
+}
+
+type fakeCategoryRepo struct {
+	domain.CategoryRepository
+}
+
+func (fakeCategoryRepo) FetchByArticles(context.Context, []int64) (map[int64][]domain.Category, error) {
+	return nil, nil
+}
+
+type fakeCache struct {
+	domain.RelatedCache
+	dirty  []int64
+	scores map[int64][]domain.RelatedScore
+}
+
+func (f *fakeCache) PopDirty(_ context.Context, limit int) ([]int64, error) {
+	n := min(limit, len(f.dirty))
+	ids := f.dirty[:n]
+	f.dirty = f.dirty[n:]
+	return ids, nil
+}
+
+func (f *fakeCache) CoViews(context.Context, int64, int) (map[int64]float64, error) {
+	return nil, nil
+}
+
+func (f *fakeCache) Set(_ context.Context, id int64, related []domain.RelatedScore) error {
+	f.scores[id] = related
+	return nil
+}
+
+func TestRefreshOnlyReadsChangedArticles(t *testing.T) {
+	updated := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	article := func(id int64, html string) domain.Article {
+		return domain.Article{ID: id, ContentHTML: html, Fingerprint: uint64(id), UpdatedAt: updated,
+			Status: domain.ArticleStatusPublished, Visibility: domain.ArticleVisibilityPublic}
+	}
+	repo := &fakeArticleRepo{articles: []domain.Article{
+		article(1, "<p>Goroutines and channels make concurrency simple</p>"),
+		article(2, "<p>Baking sourdough bread needs a healthy starter</p>"),
+		article(3, "<p>Sourdough starter feeding schedule</p>"),
+		article(4, "<p>Tuning the garbage collector</p>"),
+	}}
+	cache := &fakeCache{scores: map[int64][]domain.RelatedScore{}}
+	s := NewService(repo, nil, fakeCategoryRepo{}, cache)
+	ctx := context.Background()
+
+	cache.dirty = []int64{1, 2, 3}
+	_, err := s.Refresh(ctx, 3)
+	require.NoError(t, err)
+	assert.Equal(t, [][]int64{{1, 2, 3, 4}}, repo.rendered)
+	assert.Empty(t, cache.scores[1])
+	require.NotEmpty(t, cache.scores[2])
+	assert.Equal(t, int64(3), cache.scores[2][0].ArticleID)
+
+	// nothing changed, the counts of the last refresh are used
+	cache.dirty = []int64{1, 2, 3}
+	_, err = s.Refresh(ctx, 3)
+	require.NoError(t, err)
+	assert.Len(t, repo.rendered, 1)
+
+	// an edited article and a rendered one are counted again
+	repo.articles[0].ContentHTML = "<p>Sourdough starter in a warm kitchen</p>"
+	repo.articles[0].UpdatedAt = updated.Add(time.Hour)
+	repo.articles[1].Fingerprint = 20
+	repo.articles[2].Status = domain.ArticleStatusDraft
+	cache.dirty = []int64{1, 2}
+	_, err = s.Refresh(ctx, 2)
+	require.NoError(t, err)
+	assert.Equal(t, []int64{1, 2}, repo.rendered[1])
+	require.NotEmpty(t, cache.scores[1])
+	assert.Equal(t, int64(2), cache.scores[1][0].ArticleID)
+	assert.NotContains(t, s.corpus, int64(3), "drafts leave the corpus")
+}
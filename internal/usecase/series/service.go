@@ -0,0 +1,200 @@
+package series
+
+import (
+	"context"
+	"net/url"
+	"strings"
+	"time"
+
+	"golang.org/x/sync/errgroup"
+
+	"github.com/bxcodec/go-clean-arch/domain"
+)
+
+const (
+	maxTitleLength       = 100
+	maxDescriptionLength = 2000
+	maxCoverLength       = 255
+	// maxArticles bounds the size of a series
+	maxArticles = 200
+)
+
+type Service struct {
+	seriesRepo  domain.SeriesRepository
+	articleRepo domain.ArticleRepository
+	userRepo    domain.UserRepository
+}
+
+// NewService will create a new series service object
+func NewService(s domain.SeriesRepository, a domain.ArticleRepository, u domain.UserRepository) *Service {
+	return &Service{
+		seriesRepo:  s,
+		articleRepo: a,
+		userRepo:    u,
+	}
+}
+
+// Fetch returns a page of series, newest first, of one author when authorID is set
+func (s *Service) Fetch(ctx context.Context, authorID int64, cursor string, num int64) ([]domain.Series, string, error) {
+	res, nextCursor, err := s.seriesRepo.Fetch(ctx, authorID, cursor, num)
+	if err != nil {
+		return nil, "", err
+	}
+	if err := s.fillUsers(ctx, res); err != nil {
+		return nil, "", err
+	}
+	return res, nextCursor, nil
+}
+
+// GetByID returns a series with its published articles in order
+func (s *Service) GetByID(ctx context.Context, id int64) (domain.Series, error) {
+	res, err := s.seriesRepo.GetByID(ctx, id)
+	if err != nil {
+		return domain.Series{}, err
+	}
+	g, gctx := errgroup.WithContext(ctx)
+	g.Go(func() (err error) {
+		res.Articles, err = s.seriesRepo.Articles(gctx, id, true)
+		return
+	})
+	g.Go(func() (err error) {
+		res.User, err = s.userRepo.GetByID(gctx, res.User.ID)
+		return
+	})
+	if err := g.Wait(); err != nil {
+		return domain.Series{}, err
+	}
+	for i := range res.Articles {
+		res.Articles[i].User = res.User
+	}
+	return res, nil
+}
+
+func (s *Service) Store(ctx context.Context, m *domain.Series) error {
+	if err := clean(m); err != nil {
+		return err
+	}
+	now := time.Now()
+	m.CreatedAt = now
+	m.UpdatedAt = now
+	if err := s.seriesRepo.Store(ctx, m); err != nil {
+		return err
+	}
+	user, err := s.userRepo.GetByID(ctx, m.User.ID)
+	if err != nil {
+		return err
+	}
+	m.User = user
+	return nil
+}
+
+// Update changes the metadata of a series owned by m.User
+func (s *Service) Update(ctx context.Context, m *domain.Series) error {
+	if err := clean(m); err != nil {
+		return err
+	}
+	if _, err := s.ownSeries(ctx, m.User.ID, m.ID); err != nil {
+		return err
+	}
+	m.UpdatedAt = time.Now()
+	if err := s.seriesRepo.Update(ctx, m); err != nil {
+		return err
+	}
+	updated, err := s.GetByID(ctx, m.ID)
+	if err != nil {
+		return err
+	}
+	*m = updated
+	return nil
+}
+
+// Delete removes a series owned by userID, its articles are kept
+func (s *Service) Delete(ctx context.Context, userID, id int64) error {
+	if _, err := s.ownSeries(ctx, userID, id); err != nil {
+		return err
+	}
+	return s.seriesRepo.Delete(ctx, id)
+}
+
+// SetArticles puts the given articles of the author in the series in the given order,
+// drafts may be included and show up once published
+func (s *Service) SetArticles(ctx context.Context, userID, id int64, articleIDs []int64) (domain.Series, error) {
+	if len(articleIDs) > maxArticles {
+		return domain.Series{}, domain.ErrBadParamInput
+	}
+	if _, err := s.ownSeries(ctx, userID, id); err != nil {
+		return domain.Series{}, err
+	}
+	seen := make(map[int64]bool, len(articleIDs))
+	for _, articleID := range articleIDs {
+		if seen[articleID] {
+			return domain.Series{}, domain.ErrBadParamInput
+		}
+		seen[articleID] = true
+	}
+	articles, err := s.articleRepo.FetchByIDs(ctx, articleIDs)
+	if err != nil {
+		return domain.Series{}, err
+	}
+	if len(articles) != len(articleIDs) {
+		return domain.Series{}, domain.ErrNotFound
+	}
+	for _, article := range articles {
+		if article.User.ID != userID {
+			return domain.Series{}, domain.ErrForbidden
+		}
+	}
+
+	if err := s.seriesRepo.SetArticles(ctx, id, articleIDs); err != nil {
+		return domain.Series{}, err
+	}
+	return s.GetByID(ctx, id)
+}
+
+func (s *Service) ownSeries(ctx context.Context, userID, id int64) (domain.Series, error) {
+	series, err := s.seriesRepo.GetByID(ctx, id)
+	if err != nil {
+		return domain.Series{}, err
+	}
+	if series.User.ID != userID {
+		return domain.Series{}, domain.ErrForbidden
+	}
+	return series, nil
+}
+
+func (s *Service) fillUsers(ctx context.Context, list []domain.Series) error {
+	users := make(map[int64]domain.User)
+	for i := range list {
+		id := list[i].User.ID
+		user, ok := users[id]
+		if !ok {
+			var err error
+			user, err = s.userRepo.GetByID(ctx, id)
+			if err != nil {
+				return err
+			}
+			users[id] = user
+		}
+		list[i].User = user
+	}
+	return nil
+}
+
+func clean(m *domain.Series) error {
+	m.Title = strings.TrimSpace(m.Title)
+	m.Description = strings.TrimSpace(m.Description)
+	m.CoverURL = strings.TrimSpace(m.CoverURL)
+	m.CoverAlt = strings.TrimSpace(m.CoverAlt)
+	if m.Title == "" || len([]rune(m.Title)) > maxTitleLength ||
+		len([]rune(m.Description)) > maxDescriptionLength ||
+		len(m.CoverURL) > maxCoverLength || len([]rune(m.CoverAlt)) > maxCoverLength {
+		return domain.ErrBadParamInput
+	}
+	if m.CoverURL != "" {
+		u, err := url.Parse(m.CoverURL)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return domain.ErrBadParamInput
+		}
+	}
+	return nil
+}
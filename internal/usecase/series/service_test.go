@@ -0,0 +1,61 @@
+package series
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/bxcodec/go-clean-arch/domain"
+)
+
+func TestClean(t *testing.T) {
+	s := domain.Series{Title: "  Go tutorial ", CoverURL: "https://example.com/cover.png"}
+	assert.NoError(t, clean(&s))
+	assert.Equal(t, "Go tutorial", s.Title)
+
+	assert.ErrorIs(t, clean(&domain.Series{Title: " "}), domain.ErrBadParamInput)
+	assert.ErrorIs(t, clean(&domain.Series{Title: "Go", CoverURL: "javascript:alert(1)"}), domain.ErrBadParamInput)
+}
+
+func TestNavigation(t *testing.T) {
+	s := domain.Series{ID: 1, Title: "Go", Articles: []domain.Article{
+		{ID: 10, Title: "Part 1", Status: domain.ArticleStatusPublished},
+		{ID: 11, Title: "Part 2", Status: domain.ArticleStatusDraft},
+		{ID: 12, Title: "Part 3", Status: domain.ArticleStatusPublished},
+	}}
+
+	nav, ok := s.Navigation(12)
+	assert.True(t, ok)
+	assert.Equal(t, 2, nav.Position)
+	assert.Equal(t, 2, nav.Total)
+	assert.Equal(t, &domain.SeriesLink{ID: 10, Title: "Part 1"}, nav.Prev)
+	assert.Nil(t, nav.Next)
+
+	// a draft previewed by its author keeps its place
+	nav, ok = s.Navigation(11)
+	assert.True(t, ok)
+	assert.Equal(t, 2, nav.Position)
+	assert.Equal(t, int64(12), nav.Next.ID)
+
+	_, ok = s.Navigation(99)
+	assert.False(t, ok)
+}
+
+func TestNavigationSkipsUnlistedArticles(t *testing.T) {
+	s := domain.Series{ID: 1, Title: "Go", Articles: []domain.Article{
+		{ID: 10, Title: "Part 1", Status: domain.ArticleStatusPublished},
+		{ID: 11, Title: "Part 2", Status: domain.ArticleStatusPublished, Visibility: domain.ArticleVisibilityUnlisted},
+		{ID: 12, Title: "Part 3", Status: domain.ArticleStatusPublished},
+	}}
+
+	nav, ok := s.Navigation(10)
+	assert.True(t, ok)
+	assert.Equal(t, 2, nav.Total)
+	assert.Equal(t, &domain.SeriesLink{ID: 12, Title: "Part 3"}, nav.Next)
+
+	// readers with the link to the unlisted article still see its place
+	nav, ok = s.Navigation(11)
+	assert.True(t, ok)
+	assert.Equal(t, 2, nav.Position)
+	assert.Equal(t, 3, nav.Total)
+}
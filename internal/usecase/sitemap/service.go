@@ -0,0 +1,235 @@
+package sitemap
+
+import (
+	"bytes"
+	"cmp"
+	"context"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/sirupsen/logrus"
+
+	"github.com/bxcodec/go-clean-arch/domain"
+)
+
+const sitemapNS = "http://www.sitemaps.org/schemas/sitemap/0.9"
+
+type Service struct {
+	sitemapRepo domain.SitemapRepository
+	cache       domain.SitemapCache
+	baseURL     string
+}
+
+// NewService will create a new sitemap service object, baseURL prefixes every
+// location of the sitemaps and must be absolute since crawlers reject relative ones
+func NewService(r domain.SitemapRepository, c domain.SitemapCache, baseURL string) (*Service, error) {
+	u, err := url.Parse(baseURL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return nil, fmt.Errorf("sitemap base URL %q is not an absolute http(s) URL", baseURL)
+	}
+	return &Service{
+		sitemapRepo: r,
+		cache:       c,
+		baseURL:     strings.TrimRight(baseURL, "/"),
+	}, nil
+}
+
+// Index returns the sitemap index, ErrNotFound until it was first generated
+func (s *Service) Index(ctx context.Context) ([]byte, error) {
+	body, err := s.cache.Index(ctx)
+	if errors.Is(err, redis.Nil) {
+		return nil, domain.ErrNotFound
+	}
+	return body, err
+}
+
+// Chunk returns the sitemap file with the given name
+func (s *Service) Chunk(ctx context.Context, name string) ([]byte, error) {
+	chunk, ok := domain.ParseSitemapChunk(name)
+	if !ok {
+		return nil, domain.ErrNotFound
+	}
+	body, err := s.cache.Chunk(ctx, chunk)
+	if errors.Is(err, redis.Nil) {
+		return nil, domain.ErrNotFound
+	}
+	return body, err
+}
+
+// Regenerate rebuilds up to limit chunks whose pages changed, then the index
+// when any chunk was rebuilt. The first run, when there is no index yet, marks
+// every chunk as changed. It reports how many chunks it rebuilt.
+func (s *Service) Regenerate(ctx context.Context, limit int) (int, error) {
+	_, err := s.cache.Index(ctx)
+	full := errors.Is(err, redis.Nil)
+	if err != nil && !full {
+		return 0, err
+	}
+	if full {
+		if err := s.markAll(ctx); err != nil {
+			return 0, err
+		}
+	}
+
+	chunks, err := s.cache.PopDirty(ctx, limit)
+	if err != nil {
+		return 0, err
+	}
+	for i, chunk := range chunks {
+		if err := s.rebuild(ctx, chunk); err != nil {
+			// the chunks left are taken again on the next run
+			if markErr := s.cache.MarkDirty(ctx, chunks[i:]...); markErr != nil {
+				logrus.Warnf("failed to requeue sitemap chunks: %v", markErr)
+			}
+			return i, err
+		}
+	}
+	if len(chunks) > 0 || full {
+		if err := s.rebuildIndex(ctx); err != nil {
+			return len(chunks), err
+		}
+	}
+	return len(chunks), nil
+}
+
+func (s *Service) markAll(ctx context.Context) error {
+	articleID, userID, err := s.sitemapRepo.MaxIDs(ctx)
+	if err != nil {
+		return err
+	}
+	var chunks []domain.SitemapChunk
+	for _, kind := range []struct {
+		kind  domain.SitemapKind
+		maxID int64
+	}{{domain.SitemapArticles, articleID}, {domain.SitemapAuthors, userID}} {
+		if kind.maxID == 0 {
+			continue
+		}
+		last := domain.SitemapChunkOf(kind.kind, kind.maxID)
+		for n := int64(0); n <= last.Number; n++ {
+			chunks = append(chunks, domain.SitemapChunk{Kind: kind.kind, Number: n})
+		}
+	}
+	return s.cache.MarkDirty(ctx, chunks...)
+}
+
+func (s *Service) rebuild(ctx context.Context, chunk domain.SitemapChunk) error {
+	from, to := chunk.IDRange()
+	var (
+		entries []domain.SitemapEntry
+		err     error
+		path    string
+	)
+	switch chunk.Kind {
+	case domain.SitemapArticles:
+		entries, err = s.sitemapRepo.ArticleEntries(ctx, from, to)
+		path = "/articles/%d"
+	case domain.SitemapAuthors:
+		entries, err = s.sitemapRepo.AuthorEntries(ctx, from, to)
+		path = "/users/%d"
+	}
+	if err != nil {
+		return err
+	}
+	if len(entries) == 0 {
+		return s.cache.SaveChunk(ctx, chunk, nil, time.Time{})
+	}
+
+	set := urlSet{XMLNS: sitemapNS, URLs: make([]location, len(entries))}
+	var lastMod time.Time
+	for i, e := range entries {
+		set.URLs[i] = location{Loc: s.baseURL + fmt.Sprintf(path, e.ID), LastMod: formatLastMod(e.LastMod)}
+		if e.LastMod.After(lastMod) {
+			lastMod = e.LastMod
+		}
+	}
+	body, err := marshalXML(set)
+	if err != nil {
+		return err
+	}
+	return s.cache.SaveChunk(ctx, chunk, body, lastMod)
+}
+
+func (s *Service) rebuildIndex(ctx context.Context) error {
+	chunks, err := s.cache.Chunks(ctx)
+	if err != nil {
+		return err
+	}
+	index := sitemapIndex{XMLNS: sitemapNS, Sitemaps: make([]location, 0, len(chunks))}
+	for _, chunk := range sortedChunks(chunks) {
+		index.Sitemaps = append(index.Sitemaps, location{
+			Loc:     fmt.Sprintf("%s/sitemaps/%s.xml", s.baseURL, chunk.Name()),
+			LastMod: formatLastMod(chunks[chunk]),
+		})
+	}
+	body, err := marshalXML(index)
+	if err != nil {
+		return err
+	}
+	return s.cache.SaveIndex(ctx, body)
+}
+
+// sortedChunks orders chunks by kind then number so the index is stable
+func sortedChunks(chunks map[domain.SitemapChunk]time.Time) []domain.SitemapChunk {
+	res := make([]domain.SitemapChunk, 0, len(chunks))
+	for chunk := range chunks {
+		res = append(res, chunk)
+	}
+	slices.SortFunc(res, func(a, b domain.SitemapChunk) int {
+		return cmp.Or(cmp.Compare(a.Kind, b.Kind), cmp.Compare(a.Number, b.Number))
+	})
+	return res
+}
+
+type urlSet struct {
+	XMLName xml.Name   `xml:"urlset"`
+	XMLNS   string     `xml:"xmlns,attr"`
+	URLs    []location `xml:"url"`
+}
+
+type sitemapIndex struct {
+	XMLName  xml.Name   `xml:"sitemapindex"`
+	XMLNS    string     `xml:"xmlns,attr"`
+	Sitemaps []location `xml:"sitemap"`
+}
+
+type location struct {
+	Loc     string `xml:"loc"`
+	LastMod string `xml:"lastmod,omitempty"`
+}
+
+func formatLastMod(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.UTC().Format(time.RFC3339)
+}
+
+func marshalXML(v any) ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteString(xml.Header)
+	if err := xml.NewEncoder(&buf).Encode(v); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func (s *Service) Name() string {
+	return "sitemap"
+}
+
+// Publish implements domain.EventSink, it marks the chunks of the article and
+// of its author for regeneration
+func (s *Service) Publish(ctx context.Context, event domain.ArticleEvent) error {
+	chunks := []domain.SitemapChunk{domain.SitemapChunkOf(domain.SitemapArticles, event.ArticleID)}
+	if event.Article.User.ID != 0 {
+		chunks = append(chunks, domain.SitemapChunkOf(domain.SitemapAuthors, event.Article.User.ID))
+	}
+	return s.cache.MarkDirty(ctx, chunks...)
+}
@@ -0,0 +1,162 @@
+package sitemap
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bxcodec/go-clean-arch/domain"
+)
+
+type fakeRepo struct {
+	articles, authors []domain.SitemapEntry
+}
+
+func inRange(entries []domain.SitemapEntry, from, to int64) []domain.SitemapEntry {
+	var res []domain.SitemapEntry
+	for _, e := range entries {
+		if e.ID >= from && e.ID <= to {
+			res = append(res, e)
+		}
+	}
+	return res
+}
+
+func (f *fakeRepo) ArticleEntries(_ context.Context, from, to int64) ([]domain.SitemapEntry, error) {
+	return inRange(f.articles, from, to), nil
+}
+
+func (f *fakeRepo) AuthorEntries(_ context.Context, from, to int64) ([]domain.SitemapEntry, error) {
+	return inRange(f.authors, from, to), nil
+}
+
+func (f *fakeRepo) MaxIDs(context.Context) (int64, int64, error) {
+	return f.articles[len(f.articles)-1].ID, f.authors[len(f.authors)-1].ID, nil
+}
+
+type fakeCache struct {
+	index   []byte
+	chunks  map[domain.SitemapChunk][]byte
+	lastMod map[domain.SitemapChunk]time.Time
+	dirty   map[domain.SitemapChunk]bool
+}
+
+func newFakeCache() *fakeCache {
+	return &fakeCache{
+		chunks:  map[domain.SitemapChunk][]byte{},
+		lastMod: map[domain.SitemapChunk]time.Time{},
+		dirty:   map[domain.SitemapChunk]bool{},
+	}
+}
+
+func (f *fakeCache) Index(context.Context) ([]byte, error) {
+	if f.index == nil {
+		return nil, redis.Nil
+	}
+	return f.index, nil
+}
+
+func (f *fakeCache) SaveIndex(_ context.Context, body []byte) error {
+	f.index = body
+	return nil
+}
+
+func (f *fakeCache) Chunk(_ context.Context, chunk domain.SitemapChunk) ([]byte, error) {
+	if body, ok := f.chunks[chunk]; ok {
+		return body, nil
+	}
+	return nil, redis.Nil
+}
+
+func (f *fakeCache) SaveChunk(_ context.Context, chunk domain.SitemapChunk, body []byte, lastMod time.Time) error {
+	if body == nil {
+		delete(f.chunks, chunk)
+		delete(f.lastMod, chunk)
+		return nil
+	}
+	f.chunks[chunk], f.lastMod[chunk] = body, lastMod
+	return nil
+}
+
+func (f *fakeCache) Chunks(context.Context) (map[domain.SitemapChunk]time.Time, error) {
+	return f.lastMod, nil
+}
+
+func (f *fakeCache) MarkDirty(_ context.Context, chunks ...domain.SitemapChunk) error {
+	for _, chunk := range chunks {
+		f.dirty[chunk] = true
+	}
+	return nil
+}
+
+func (f *fakeCache) PopDirty(_ context.Context, limit int) ([]domain.SitemapChunk, error) {
+	var res []domain.SitemapChunk
+	for chunk := range f.dirty {
+		if len(res) == limit {
+			break
+		}
+		res = append(res, chunk)
+		delete(f.dirty, chunk)
+	}
+	return res, nil
+}
+
+func TestNewServiceRequiresAbsoluteBaseURL(t *testing.T) {
+	for _, baseURL := range []string{"", "/", "blog.example", "//blog.example", "ftp://blog.example", "https://"} {
+		_, err := NewService(&fakeRepo{}, newFakeCache(), baseURL)
+		assert.Error(t, err, baseURL)
+	}
+	_, err := NewService(&fakeRepo{}, newFakeCache(), "http://localhost:9090")
+	assert.NoError(t, err)
+}
+
+func TestRegenerate(t *testing.T) {
+	day := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+	repo := &fakeRepo{
+		articles: []domain.SitemapEntry{{ID: 1, LastMod: day}, {ID: domain.SitemapChunkSize + 2, LastMod: day.Add(time.Hour)}},
+		authors:  []domain.SitemapEntry{{ID: 4, LastMod: day}},
+	}
+	cache := newFakeCache()
+	svc, err := NewService(repo, cache, "https://blog.example/")
+	require.NoError(t, err)
+
+	n, err := svc.Regenerate(context.Background(), 10)
+	require.NoError(t, err)
+	assert.Equal(t, 3, n)
+
+	index, err := svc.Index(context.Background())
+	require.NoError(t, err)
+	assert.Contains(t, string(index), "<sitemap><loc>https://blog.example/sitemaps/articles-0.xml</loc><lastmod>2024-05-01T00:00:00Z</lastmod></sitemap>"+
+		"<sitemap><loc>https://blog.example/sitemaps/articles-1.xml</loc><lastmod>2024-05-01T01:00:00Z</lastmod></sitemap>"+
+		"<sitemap><loc>https://blog.example/sitemaps/authors-0.xml</loc>")
+
+	chunk, err := svc.Chunk(context.Background(), "articles-1")
+	require.NoError(t, err)
+	assert.Contains(t, string(chunk), "<url><loc>https://blog.example/articles/50002</loc><lastmod>2024-05-01T01:00:00Z</lastmod></url>")
+
+	// the article of the second chunk is gone, only that chunk is rebuilt
+	repo.articles = repo.articles[:1]
+	require.NoError(t, svc.Publish(context.Background(), domain.ArticleEvent{ArticleID: domain.SitemapChunkSize + 2}))
+	n, err = svc.Regenerate(context.Background(), 10)
+	require.NoError(t, err)
+	assert.Equal(t, 1, n)
+	_, err = svc.Chunk(context.Background(), "articles-1")
+	assert.ErrorIs(t, err, domain.ErrNotFound)
+	index, _ = svc.Index(context.Background())
+	assert.NotContains(t, string(index), "articles-1.xml")
+}
+
+func TestParseSitemapChunk(t *testing.T) {
+	chunk, ok := domain.ParseSitemapChunk("authors-3")
+	assert.True(t, ok)
+	assert.Equal(t, domain.SitemapChunk{Kind: domain.SitemapAuthors, Number: 3}, chunk)
+
+	for _, name := range []string{"articles", "articles--1", "users-0", "articles-x"} {
+		_, ok := domain.ParseSitemapChunk(name)
+		assert.False(t, ok, name)
+	}
+}
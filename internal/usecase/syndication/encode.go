@@ -0,0 +1,250 @@
+package syndication
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"time"
+
+	"github.com/bxcodec/go-clean-arch/domain"
+)
+
+type encoder func(ch channel, articleURL, authorURL func(id int64) string) ([]byte, error)
+
+var encoders = map[domain.SyndicationFormat]encoder{
+	domain.SyndicationRSS:  encodeRSS,
+	domain.SyndicationAtom: encodeAtom,
+	domain.SyndicationJSON: encodeJSON,
+}
+
+// updatedAt is when an article last changed, rows written before updates were
+// tracked only have a creation time
+func updatedAt(a *domain.Article) time.Time {
+	return latest(a.CreatedAt, a.UpdatedAt)
+}
+
+// RSS 2.0 with the full content in content:encoded
+
+type rssFeed struct {
+	XMLName      xml.Name   `xml:"rss"`
+	Version      string     `xml:"version,attr"`
+	XMLNSAtom    string     `xml:"xmlns:atom,attr"`
+	XMLNSContent string     `xml:"xmlns:content,attr"`
+	XMLNSDC      string     `xml:"xmlns:dc,attr"`
+	Channel      rssChannel `xml:"channel"`
+}
+
+type rssChannel struct {
+	Title         string    `xml:"title"`
+	Link          string    `xml:"link"`
+	Description   string    `xml:"description"`
+	AtomLink      atomLink  `xml:"atom:link"`
+	LastBuildDate string    `xml:"lastBuildDate,omitempty"`
+	Items         []rssItem `xml:"item"`
+}
+
+type rssItem struct {
+	Title       string   `xml:"title"`
+	Link        string   `xml:"link"`
+	GUID        rssGUID  `xml:"guid"`
+	Description string   `xml:"description"`
+	Content     cdata    `xml:"content:encoded"`
+	Creator     string   `xml:"dc:creator,omitempty"`
+	Categories  []string `xml:"category"`
+	PubDate     string   `xml:"pubDate"`
+}
+
+type rssGUID struct {
+	IsPermaLink bool   `xml:"isPermaLink,attr"`
+	Value       string `xml:",chardata"`
+}
+
+type cdata struct {
+	Value string `xml:",cdata"`
+}
+
+func encodeRSS(ch channel, articleURL, _ func(id int64) string) ([]byte, error) {
+	feed := rssFeed{
+		Version:      "2.0",
+		XMLNSAtom:    "http://www.w3.org/2005/Atom",
+		XMLNSContent: "http://purl.org/rss/1.0/modules/content/",
+		XMLNSDC:      "http://purl.org/dc/elements/1.1/",
+		Channel: rssChannel{
+			Title:       ch.Title,
+			Link:        ch.Link,
+			Description: ch.Title,
+			AtomLink:    atomLink{Href: ch.Self, Rel: "self", Type: "application/rss+xml"},
+			Items:       make([]rssItem, len(ch.Articles)),
+		},
+	}
+	if !ch.Updated.IsZero() {
+		feed.Channel.LastBuildDate = ch.Updated.UTC().Format(time.RFC1123Z)
+	}
+	for i := range ch.Articles {
+		a := &ch.Articles[i]
+		link := articleURL(a.ID)
+		item := rssItem{
+			Title:       a.Title,
+			Link:        link,
+			GUID:        rssGUID{IsPermaLink: true, Value: link},
+			Description: a.Excerpt,
+			Content:     cdata{a.ContentHTML},
+			Creator:     a.User.Name,
+			PubDate:     a.CreatedAt.UTC().Format(time.RFC1123Z),
+		}
+		for _, tag := range a.Tags {
+			item.Categories = append(item.Categories, tag.Name)
+		}
+		feed.Channel.Items[i] = item
+	}
+	return marshalXML(feed)
+}
+
+// Atom 1.0
+
+type atomFeed struct {
+	XMLName xml.Name    `xml:"http://www.w3.org/2005/Atom feed"`
+	ID      string      `xml:"id"`
+	Title   string      `xml:"title"`
+	Updated string      `xml:"updated"`
+	Links   []atomLink  `xml:"link"`
+	Entries []atomEntry `xml:"entry"`
+}
+
+type atomLink struct {
+	Href string `xml:"href,attr"`
+	Rel  string `xml:"rel,attr,omitempty"`
+	Type string `xml:"type,attr,omitempty"`
+}
+
+type atomEntry struct {
+	ID         string         `xml:"id"`
+	Title      string         `xml:"title"`
+	Link       atomLink       `xml:"link"`
+	Published  string         `xml:"published"`
+	Updated    string         `xml:"updated"`
+	Author     atomPerson     `xml:"author"`
+	Summary    string         `xml:"summary,omitempty"`
+	Content    atomText       `xml:"content"`
+	Categories []atomCategory `xml:"category"`
+}
+
+type atomPerson struct {
+	Name string `xml:"name"`
+	URI  string `xml:"uri,omitempty"`
+}
+
+type atomText struct {
+	Type  string `xml:"type,attr"`
+	Value string `xml:",chardata"`
+}
+
+type atomCategory struct {
+	Term  string `xml:"term,attr"`
+	Label string `xml:"label,attr,omitempty"`
+}
+
+func encodeAtom(ch channel, articleURL, authorURL func(id int64) string) ([]byte, error) {
+	updated := ch.Updated
+	if updated.IsZero() {
+		// an empty feed still needs a stable date
+		updated = time.Unix(0, 0)
+	}
+	feed := atomFeed{
+		ID:      ch.Self,
+		Title:   ch.Title,
+		Updated: updated.UTC().Format(time.RFC3339),
+		Links: []atomLink{
+			{Href: ch.Self, Rel: "self", Type: "application/atom+xml"},
+			{Href: ch.Link, Rel: "alternate", Type: "text/html"},
+		},
+		Entries: make([]atomEntry, len(ch.Articles)),
+	}
+	for i := range ch.Articles {
+		a := &ch.Articles[i]
+		link := articleURL(a.ID)
+		entry := atomEntry{
+			ID:        link,
+			Title:     a.Title,
+			Link:      atomLink{Href: link, Rel: "alternate", Type: "text/html"},
+			Published: a.CreatedAt.UTC().Format(time.RFC3339),
+			Updated:   updatedAt(a).UTC().Format(time.RFC3339),
+			Author:    atomPerson{Name: a.User.Name, URI: authorURL(a.User.ID)},
+			Summary:   a.Excerpt,
+			Content:   atomText{Type: "html", Value: a.ContentHTML},
+		}
+		for _, tag := range a.Tags {
+			entry.Categories = append(entry.Categories, atomCategory{Term: tag.Tag, Label: tag.Name})
+		}
+		feed.Entries[i] = entry
+	}
+	return marshalXML(feed)
+}
+
+func marshalXML(v any) ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteString(xml.Header)
+	enc := xml.NewEncoder(&buf)
+	if err := enc.Encode(v); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// JSON Feed 1.1
+
+type jsonFeed struct {
+	Version     string     `json:"version"`
+	Title       string     `json:"title"`
+	HomePageURL string     `json:"home_page_url"`
+	FeedURL     string     `json:"feed_url"`
+	Items       []jsonItem `json:"items"`
+}
+
+type jsonItem struct {
+	ID            string       `json:"id"`
+	URL           string       `json:"url"`
+	Title         string       `json:"title"`
+	ContentHTML   string       `json:"content_html"`
+	Summary       string       `json:"summary,omitempty"`
+	DatePublished string       `json:"date_published"`
+	DateModified  string       `json:"date_modified"`
+	Authors       []jsonAuthor `json:"authors,omitempty"`
+	Tags          []string     `json:"tags,omitempty"`
+}
+
+type jsonAuthor struct {
+	Name string `json:"name"`
+	URL  string `json:"url"`
+}
+
+func encodeJSON(ch channel, articleURL, authorURL func(id int64) string) ([]byte, error) {
+	feed := jsonFeed{
+		Version:     "https://jsonfeed.org/version/1.1",
+		Title:       ch.Title,
+		HomePageURL: ch.Link,
+		FeedURL:     ch.Self,
+		Items:       make([]jsonItem, len(ch.Articles)),
+	}
+	for i := range ch.Articles {
+		a := &ch.Articles[i]
+		link := articleURL(a.ID)
+		item := jsonItem{
+			ID:            link,
+			URL:           link,
+			Title:         a.Title,
+			ContentHTML:   a.ContentHTML,
+			Summary:       a.Excerpt,
+			DatePublished: a.CreatedAt.UTC().Format(time.RFC3339),
+			DateModified:  updatedAt(a).UTC().Format(time.RFC3339),
+		}
+		if a.User.Name != "" {
+			item.Authors = []jsonAuthor{{Name: a.User.Name, URL: authorURL(a.User.ID)}}
+		}
+		for _, tag := range a.Tags {
+			item.Tags = append(item.Tags, tag.Name)
+		}
+		feed.Items[i] = item
+	}
+	return json.Marshal(feed)
+}
@@ -0,0 +1,220 @@
+package syndication
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/sirupsen/logrus"
+
+	"github.com/bxcodec/go-clean-arch/domain"
+)
+
+// DefaultSize is the number of articles of a feed
+const DefaultSize = 20
+
+var contentTypes = map[domain.SyndicationFormat]string{
+	domain.SyndicationRSS:  "application/rss+xml; charset=utf-8",
+	domain.SyndicationAtom: "application/atom+xml; charset=utf-8",
+	domain.SyndicationJSON: "application/feed+json; charset=utf-8",
+}
+
+var fileNames = map[domain.SyndicationFormat]string{
+	domain.SyndicationRSS:  "rss.xml",
+	domain.SyndicationAtom: "atom.xml",
+	domain.SyndicationJSON: "feed.json",
+}
+
+type Service struct {
+	articleRepo  domain.ArticleRepository
+	userRepo     domain.UserRepository
+	categoryRepo domain.CategoryRepository
+	cache        domain.SyndicationCache
+	baseURL      string
+	size         int64
+}
+
+// NewService will create a new syndication service object, baseURL prefixes
+// every link of the feeds and a zero size falls back to DefaultSize
+func NewService(a domain.ArticleRepository, u domain.UserRepository, c domain.CategoryRepository, sc domain.SyndicationCache, baseURL string, size int64) *Service {
+	if size <= 0 {
+		size = DefaultSize
+	}
+	return &Service{
+		articleRepo:  a,
+		userRepo:     u,
+		categoryRepo: c,
+		cache:        sc,
+		baseURL:      strings.TrimRight(baseURL, "/"),
+		size:         size,
+	}
+}
+
+// channel is what a feed says about itself
+type channel struct {
+	Title    string
+	Link     string
+	Self     string
+	Updated  time.Time
+	Articles []domain.Article
+}
+
+// Feed returns the feed of scope in format, from the cache unless the
+// articles it lists changed since it was rendered
+func (s *Service) Feed(ctx context.Context, scope domain.SyndicationScope, format domain.SyndicationFormat) (domain.SyndicationDocument, error) {
+	encode, ok := encoders[format]
+	if !ok {
+		return domain.SyndicationDocument{}, domain.ErrBadParamInput
+	}
+	doc, err := s.cache.Get(ctx, scope, format)
+	if err == nil {
+		return doc, nil
+	}
+	if !errors.Is(err, redis.Nil) {
+		logrus.Warnf("failed to read cached %s feed: %v", format, err)
+	}
+
+	ch, err := s.channel(ctx, scope, format)
+	if err != nil {
+		return domain.SyndicationDocument{}, err
+	}
+	body, err := encode(ch, s.articleURL, s.authorURL)
+	if err != nil {
+		return domain.SyndicationDocument{}, err
+	}
+	sum := sha256.Sum256(body)
+	doc = domain.SyndicationDocument{
+		Body:        body,
+		ContentType: contentTypes[format],
+		ETag:        `"` + hex.EncodeToString(sum[:16]) + `"`,
+		// the feed changes when one of the articles it lists does
+		LastModified: ch.Updated.UTC().Truncate(time.Second),
+	}
+	if err := s.cache.Set(ctx, scope, format, doc); err != nil {
+		logrus.Warnf("failed to cache %s feed: %v", format, err)
+	}
+	return doc, nil
+}
+
+// channel loads the articles of scope with their authors and tags
+func (s *Service) channel(ctx context.Context, scope domain.SyndicationScope, format domain.SyndicationFormat) (channel, error) {
+	ch := channel{Title: "Articles", Link: s.baseURL + "/articles"}
+	var categoryID int64
+	switch {
+	case scope.AuthorID != 0:
+		author, err := s.userRepo.GetByID(ctx, scope.AuthorID)
+		if err != nil {
+			return channel{}, err
+		}
+		ch.Title = "Articles by " + author.Name
+		ch.Link = s.authorURL(author.ID)
+		ch.Self = fmt.Sprintf("%s/feeds/authors/%d/%s", s.baseURL, author.ID, fileNames[format])
+	case scope.Tag != "":
+		category, err := s.categoryRepo.GetByTag(ctx, scope.Tag)
+		if err != nil {
+			return channel{}, err
+		}
+		categoryID = category.ID
+		ch.Title = "Articles tagged " + category.Name
+		ch.Self = fmt.Sprintf("%s/feeds/tags/%s/%s", s.baseURL, url.PathEscape(category.Tag), fileNames[format])
+	default:
+		ch.Self = fmt.Sprintf("%s/feeds/%s", s.baseURL, fileNames[format])
+	}
+
+	articles, err := s.articleRepo.FetchRecent(ctx, scope.AuthorID, categoryID, s.size)
+	if err != nil {
+		return channel{}, err
+	}
+	if err := s.fillDetails(ctx, articles); err != nil {
+		return channel{}, err
+	}
+	ch.Articles = articles
+	for i := range articles {
+		ch.Updated = latest(ch.Updated, articles[i].CreatedAt, articles[i].UpdatedAt)
+	}
+	return ch, nil
+}
+
+func (s *Service) fillDetails(ctx context.Context, articles []domain.Article) error {
+	ids := make([]int64, len(articles))
+	users := map[int64]domain.User{}
+	for i := range articles {
+		ids[i] = articles[i].ID
+		users[articles[i].User.ID] = domain.User{}
+	}
+	for id := range users {
+		user, err := s.userRepo.GetByID(ctx, id)
+		if errors.Is(err, domain.ErrNotFound) {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		users[id] = user
+	}
+	tags, err := s.categoryRepo.FetchByArticles(ctx, ids)
+	if err != nil {
+		return err
+	}
+	for i := range articles {
+		if user := users[articles[i].User.ID]; user.ID != 0 {
+			articles[i].User = user
+		}
+		articles[i].Tags = tags[articles[i].ID]
+	}
+	return nil
+}
+
+func latest(times ...time.Time) time.Time {
+	var res time.Time
+	for _, t := range times {
+		if t.After(res) {
+			res = t
+		}
+	}
+	return res
+}
+
+func (s *Service) articleURL(id int64) string {
+	return fmt.Sprintf("%s/articles/%d", s.baseURL, id)
+}
+
+func (s *Service) authorURL(id int64) string {
+	return fmt.Sprintf("%s/users/%d", s.baseURL, id)
+}
+
+func (s *Service) Name() string {
+	return "syndication"
+}
+
+// Publish implements domain.EventSink. Any change of an article may add it to,
+// change it in or remove it from the site feed, the feed of its author and the
+// feeds of its tags, so all of them are dropped from the cache. The tags it had
+// before the change are included so that feeds it left are dropped too.
+func (s *Service) Publish(ctx context.Context, event domain.ArticleEvent) error {
+	scopes := []domain.SyndicationScope{{}}
+	if event.Article.User.ID != 0 {
+		scopes = append(scopes, domain.SyndicationScope{AuthorID: event.Article.User.ID})
+	}
+	tags, err := s.categoryRepo.FetchByArticles(ctx, []int64{event.ArticleID})
+	if err != nil {
+		return err
+	}
+	seen := map[string]bool{}
+	for _, tag := range event.PreviousTags {
+		seen[tag] = true
+		scopes = append(scopes, domain.SyndicationScope{Tag: tag})
+	}
+	for _, category := range tags[event.ArticleID] {
+		if !seen[category.Tag] {
+			scopes = append(scopes, domain.SyndicationScope{Tag: category.Tag})
+		}
+	}
+	return s.cache.Invalidate(ctx, scopes...)
+}
@@ -0,0 +1,180 @@
+package syndication
+
+import (
+	"context"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bxcodec/go-clean-arch/domain"
+)
+
+func testChannel() channel {
+	created := time.Date(2024, 3, 1, 10, 0, 0, 0, time.UTC)
+	return channel{
+		Title:   "Articles",
+		Link:    "https://blog.example/articles",
+		Self:    "https://blog.example/feeds/rss.xml",
+		Updated: created.Add(time.Hour),
+		Articles: []domain.Article{{
+			ID:          7,
+			Title:       "Fish & chips",
+			ContentHTML: "<p>Crispy <b>and</b> hot</p>",
+			Excerpt:     "Crispy and hot",
+			User:        domain.User{ID: 3, Name: "Ann"},
+			Tags:        []domain.Category{{Name: "Makanan", Tag: "food"}},
+			CreatedAt:   created,
+			UpdatedAt:   created.Add(time.Hour),
+		}},
+	}
+}
+
+func articleURL(id int64) string { return fmt.Sprintf("https://blog.example/articles/%d", id) }
+func authorURL(id int64) string  { return fmt.Sprintf("https://blog.example/users/%d", id) }
+
+func TestEncodeRSS(t *testing.T) {
+	body, err := encodeRSS(testChannel(), articleURL, authorURL)
+	require.NoError(t, err)
+
+	var feed struct {
+		Items []struct {
+			Title    string `xml:"title"`
+			Link     string `xml:"link"`
+			Content  string `xml:"http://purl.org/rss/1.0/modules/content/ encoded"`
+			Category string `xml:"category"`
+			PubDate  string `xml:"pubDate"`
+		} `xml:"channel>item"`
+	}
+	require.NoError(t, xml.Unmarshal(body, &feed))
+	require.Len(t, feed.Items, 1)
+	assert.Equal(t, "Fish & chips", feed.Items[0].Title)
+	assert.Equal(t, "https://blog.example/articles/7", feed.Items[0].Link)
+	assert.Equal(t, "<p>Crispy <b>and</b> hot</p>", feed.Items[0].Content)
+	assert.Equal(t, "Makanan", feed.Items[0].Category)
+	assert.Equal(t, "Fri, 01 Mar 2024 10:00:00 +0000", feed.Items[0].PubDate)
+}
+
+func TestEncodeAtom(t *testing.T) {
+	body, err := encodeAtom(testChannel(), articleURL, authorURL)
+	require.NoError(t, err)
+
+	var feed struct {
+		Updated string `xml:"updated"`
+		Entries []struct {
+			ID      string `xml:"id"`
+			Updated string `xml:"updated"`
+			Author  string `xml:"author>uri"`
+			Content string `xml:"content"`
+		} `xml:"entry"`
+	}
+	require.NoError(t, xml.Unmarshal(body, &feed))
+	assert.Equal(t, "2024-03-01T11:00:00Z", feed.Updated)
+	require.Len(t, feed.Entries, 1)
+	assert.Equal(t, "https://blog.example/articles/7", feed.Entries[0].ID)
+	assert.Equal(t, "2024-03-01T11:00:00Z", feed.Entries[0].Updated)
+	assert.Equal(t, "https://blog.example/users/3", feed.Entries[0].Author)
+	assert.Equal(t, "<p>Crispy <b>and</b> hot</p>", feed.Entries[0].Content)
+}
+
+func TestEncodeJSON(t *testing.T) {
+	body, err := encodeJSON(testChannel(), articleURL, authorURL)
+	require.NoError(t, err)
+
+	var feed jsonFeed
+	require.NoError(t, json.Unmarshal(body, &feed))
+	assert.Equal(t, "https://jsonfeed.org/version/1.1", feed.Version)
+	assert.Equal(t, "https://blog.example/feeds/rss.xml", feed.FeedURL)
+	require.Len(t, feed.Items, 1)
+	assert.Equal(t, []string{"Makanan"}, feed.Items[0].Tags)
+	assert.Equal(t, []jsonAuthor{{Name: "Ann", URL: "https://blog.example/users/3"}}, feed.Items[0].Authors)
+	assert.Equal(t, "2024-03-01T10:00:00Z", feed.Items[0].DatePublished)
+}
+
+type fakeArticleRepo struct {
+	domain.ArticleRepository
+	articles []domain.Article
+}
+
+func (f *fakeArticleRepo) FetchRecent(context.Context, int64, int64, int64) ([]domain.Article, error) {
+	return f.articles, nil
+}
+
+type fakeUserRepo struct {
+	domain.UserRepository
+}
+
+func (fakeUserRepo) GetByID(_ context.Context, id int64) (domain.User, error) {
+	return domain.User{ID: id, Name: "Ann"}, nil
+}
+
+type fakeCategoryRepo struct {
+	domain.CategoryRepository
+	tags map[int64][]domain.Category
+}
+
+func (f *fakeCategoryRepo) FetchByArticles(context.Context, []int64) (map[int64][]domain.Category, error) {
+	return f.tags, nil
+}
+
+type fakeCache struct {
+	domain.SyndicationCache
+	invalidated []domain.SyndicationScope
+}
+
+func (f *fakeCache) Get(context.Context, domain.SyndicationScope, domain.SyndicationFormat) (domain.SyndicationDocument, error) {
+	return domain.SyndicationDocument{}, redis.Nil
+}
+
+func (f *fakeCache) Set(context.Context, domain.SyndicationScope, domain.SyndicationFormat, domain.SyndicationDocument) error {
+	return nil
+}
+
+func (f *fakeCache) Invalidate(_ context.Context, scopes ...domain.SyndicationScope) error {
+	f.invalidated = append(f.invalidated, scopes...)
+	return nil
+}
+
+func TestFeedLastModifiedIsLatestChange(t *testing.T) {
+	ch := testChannel()
+	s := NewService(&fakeArticleRepo{articles: ch.Articles}, fakeUserRepo{}, &fakeCategoryRepo{}, &fakeCache{}, "https://blog.example/", 0)
+
+	doc, err := s.Feed(context.Background(), domain.SyndicationScope{}, domain.SyndicationRSS)
+	require.NoError(t, err)
+	assert.Equal(t, ch.Updated, doc.LastModified)
+
+	// rendering the same articles again gives the same validators
+	again, err := s.Feed(context.Background(), domain.SyndicationScope{}, domain.SyndicationRSS)
+	require.NoError(t, err)
+	assert.Equal(t, doc.LastModified, again.LastModified)
+	assert.Equal(t, doc.ETag, again.ETag)
+}
+
+func TestPublishInvalidatesPreviousTags(t *testing.T) {
+	cache := &fakeCache{}
+	categories := &fakeCategoryRepo{tags: map[int64][]domain.Category{
+		7: {{Tag: "food"}, {Tag: "travel"}},
+	}}
+	s := NewService(&fakeArticleRepo{}, fakeUserRepo{}, categories, cache, "https://blog.example", 0)
+
+	// the article moved from "news" and "food" to "food" and "travel"
+	err := s.Publish(context.Background(), domain.ArticleEvent{
+		Type:         domain.ArticleUpdated,
+		ArticleID:    7,
+		Article:      domain.Article{ID: 7, User: domain.User{ID: 3}},
+		PreviousTags: []string{"food", "news"},
+	})
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []domain.SyndicationScope{
+		{},
+		{AuthorID: 3},
+		{Tag: "food"},
+		{Tag: "news"},
+		{Tag: "travel"},
+	}, cache.invalidated)
+}
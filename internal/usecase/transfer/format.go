@@ -0,0 +1,353 @@
+package transfer
+
+import (
+	"archive/zip"
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/bxcodec/go-clean-arch/domain"
+)
+
+// csvHeader lists the columns of CSV exports, imports accept them in any order
+var csvHeader = []string{
+	"id", "title", "slug", "author", "author_name", "tags",
+	"status", "visibility", "content_format", "created_at", "updated_at", "content",
+}
+
+const frontMatterDelimiter = "---\n"
+
+// maxMarkdownFileSize bounds a Markdown file of an archive once decompressed,
+// the archive itself may be much smaller
+const maxMarkdownFileSize = 16 << 20
+
+// rowError is a record that cannot be read, the rows after it still can
+type rowError struct {
+	err error
+}
+
+func (e *rowError) Error() string {
+	return e.err.Error()
+}
+
+type encoder interface {
+	Encode(rec *Record) error
+	Close() error
+}
+
+func newEncoder(format domain.TransferFormat, w io.Writer) (encoder, error) {
+	switch format {
+	case domain.TransferJSONL:
+		enc := json.NewEncoder(w)
+		enc.SetEscapeHTML(false)
+		return &jsonlEncoder{enc}, nil
+	case domain.TransferCSV:
+		return &csvEncoder{w: csv.NewWriter(w)}, nil
+	case domain.TransferMarkdown:
+		return &markdownEncoder{zip.NewWriter(w)}, nil
+	}
+	return nil, domain.ErrBadParamInput
+}
+
+type jsonlEncoder struct {
+	enc *json.Encoder
+}
+
+func (e *jsonlEncoder) Encode(rec *Record) error {
+	return e.enc.Encode(rec)
+}
+
+func (e *jsonlEncoder) Close() error {
+	return nil
+}
+
+type csvEncoder struct {
+	w             *csv.Writer
+	headerWritten bool
+}
+
+func (e *csvEncoder) Encode(rec *Record) error {
+	if !e.headerWritten {
+		if err := e.w.Write(csvHeader); err != nil {
+			return err
+		}
+		e.headerWritten = true
+	}
+	return e.w.Write([]string{
+		strconv.FormatInt(rec.ID, 10), rec.Title, rec.Slug, rec.Author, rec.AuthorName,
+		strings.Join(rec.Tags, ","), rec.Status, rec.Visibility, rec.ContentFormat,
+		formatTime(rec.CreatedAt), formatTime(rec.UpdatedAt), rec.Content,
+	})
+}
+
+func (e *csvEncoder) Close() error {
+	if !e.headerWritten {
+		if err := e.w.Write(csvHeader); err != nil {
+			return err
+		}
+	}
+	e.w.Flush()
+	return e.w.Error()
+}
+
+type markdownEncoder struct {
+	zw *zip.Writer
+}
+
+func (e *markdownEncoder) Encode(rec *Record) error {
+	front, err := yaml.Marshal(rec)
+	if err != nil {
+		return err
+	}
+	f, err := e.zw.CreateHeader(&zip.FileHeader{
+		Name:     fmt.Sprintf("%d-%s.md", rec.ID, rec.Slug),
+		Method:   zip.Deflate,
+		Modified: rec.UpdatedAt,
+	})
+	if err != nil {
+		return err
+	}
+	_, err = io.WriteString(f, frontMatterDelimiter+string(front)+frontMatterDelimiter+rec.Content)
+	return err
+}
+
+func (e *markdownEncoder) Close() error {
+	return e.zw.Close()
+}
+
+func formatTime(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.Format(time.RFC3339)
+}
+
+// decoder reads records one by one, Next returns io.EOF after the last one
+// and a *rowError for a record that cannot be read
+type decoder interface {
+	Next() (Record, error)
+	Close() error
+}
+
+func newDecoder(format domain.TransferFormat, r io.Reader) (decoder, error) {
+	switch format {
+	case domain.TransferJSONL:
+		return &jsonlDecoder{r: bufio.NewReader(r)}, nil
+	case domain.TransferCSV:
+		return newCSVDecoder(r)
+	case domain.TransferMarkdown:
+		return newMarkdownDecoder(r)
+	}
+	return nil, domain.ErrBadParamInput
+}
+
+type jsonlDecoder struct {
+	r *bufio.Reader
+}
+
+func (d *jsonlDecoder) Next() (Record, error) {
+	for {
+		line, err := d.r.ReadBytes('\n')
+		if len(bytes.TrimSpace(line)) == 0 {
+			if err != nil {
+				return Record{}, err
+			}
+			continue
+		}
+		if err != nil && !errors.Is(err, io.EOF) {
+			return Record{}, err
+		}
+		var rec Record
+		if err := json.Unmarshal(line, &rec); err != nil {
+			return Record{}, &rowError{fmt.Errorf("invalid JSON: %w", err)}
+		}
+		return rec, nil
+	}
+}
+
+func (d *jsonlDecoder) Close() error {
+	return nil
+}
+
+type csvDecoder struct {
+	r       *csv.Reader
+	columns map[string]int
+}
+
+func newCSVDecoder(r io.Reader) (*csvDecoder, error) {
+	cr := csv.NewReader(r)
+	cr.FieldsPerRecord = -1
+	header, err := cr.Read()
+	if errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("%w: the CSV has no header", domain.ErrBadParamInput)
+	}
+	if err != nil {
+		return nil, err
+	}
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		columns[strings.TrimSpace(strings.ToLower(name))] = i
+	}
+	for _, required := range []string{"title", "content"} {
+		if _, ok := columns[required]; !ok {
+			return nil, fmt.Errorf("%w: the CSV has no %s column", domain.ErrBadParamInput, required)
+		}
+	}
+	return &csvDecoder{r: cr, columns: columns}, nil
+}
+
+func (d *csvDecoder) Next() (Record, error) {
+	row, err := d.r.Read()
+	var parseErr *csv.ParseError
+	if errors.As(err, &parseErr) {
+		return Record{}, &rowError{err}
+	}
+	if err != nil {
+		return Record{}, err
+	}
+	field := func(name string) string {
+		if i, ok := d.columns[name]; ok && i < len(row) {
+			return row[i]
+		}
+		return ""
+	}
+
+	rec := Record{
+		Title:         field("title"),
+		Slug:          field("slug"),
+		Author:        field("author"),
+		AuthorName:    field("author_name"),
+		Status:        field("status"),
+		Visibility:    field("visibility"),
+		ContentFormat: field("content_format"),
+		Content:       field("content"),
+	}
+	if id := field("id"); id != "" {
+		if rec.ID, err = strconv.ParseInt(id, 10, 64); err != nil {
+			return Record{}, &rowError{fmt.Errorf("invalid id %q", id)}
+		}
+	}
+	for _, tag := range strings.Split(field("tags"), ",") {
+		if tag = strings.TrimSpace(tag); tag != "" {
+			rec.Tags = append(rec.Tags, tag)
+		}
+	}
+	if rec.CreatedAt, err = parseTime(field("created_at")); err != nil {
+		return Record{}, &rowError{err}
+	}
+	if rec.UpdatedAt, err = parseTime(field("updated_at")); err != nil {
+		return Record{}, &rowError{err}
+	}
+	return rec, nil
+}
+
+func (d *csvDecoder) Close() error {
+	return nil
+}
+
+func parseTime(value string) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid time %q", value)
+	}
+	return t, nil
+}
+
+// markdownDecoder reads the .md files of a zip in name order. The zip is
+// spooled to a temporary file because its directory is at the end.
+type markdownDecoder struct {
+	file  *os.File
+	files []*zip.File
+}
+
+func newMarkdownDecoder(r io.Reader) (*markdownDecoder, error) {
+	f, err := os.CreateTemp("", "article-import-*.zip")
+	if err != nil {
+		return nil, err
+	}
+	d := &markdownDecoder{file: f}
+	size, err := io.Copy(f, r)
+	if err != nil {
+		d.Close()
+		return nil, err
+	}
+	zr, err := zip.NewReader(f, size)
+	if err != nil {
+		d.Close()
+		return nil, fmt.Errorf("%w: %v", domain.ErrBadParamInput, err)
+	}
+	for _, zf := range zr.File {
+		if !zf.FileInfo().IsDir() && strings.EqualFold(path.Ext(zf.Name), ".md") {
+			d.files = append(d.files, zf)
+		}
+	}
+	slices.SortFunc(d.files, func(a, b *zip.File) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+	return d, nil
+}
+
+func (d *markdownDecoder) Next() (Record, error) {
+	if len(d.files) == 0 {
+		return Record{}, io.EOF
+	}
+	zf := d.files[0]
+	d.files = d.files[1:]
+
+	rc, err := zf.Open()
+	if err != nil {
+		return Record{}, &rowError{fmt.Errorf("%s: %w", zf.Name, err)}
+	}
+	defer rc.Close()
+	data, err := io.ReadAll(io.LimitReader(rc, maxMarkdownFileSize+1))
+	if err != nil {
+		return Record{}, &rowError{fmt.Errorf("%s: %w", zf.Name, err)}
+	}
+	if len(data) > maxMarkdownFileSize {
+		return Record{}, &rowError{fmt.Errorf("%s: %w", zf.Name, domain.ErrTooLarge)}
+	}
+	rec, err := parseMarkdown(string(data))
+	if err != nil {
+		return Record{}, &rowError{fmt.Errorf("%s: %w", zf.Name, err)}
+	}
+	return rec, nil
+}
+
+// parseMarkdown splits a file into its YAML front matter and its content
+func parseMarkdown(data string) (Record, error) {
+	data = strings.ReplaceAll(data, "\r\n", "\n")
+	rest, ok := strings.CutPrefix(data, frontMatterDelimiter)
+	if !ok {
+		return Record{}, errors.New("missing front matter")
+	}
+	front, body, ok := strings.Cut(rest, "\n"+frontMatterDelimiter)
+	if !ok {
+		return Record{}, errors.New("unterminated front matter")
+	}
+	var rec Record
+	if err := yaml.Unmarshal([]byte(front), &rec); err != nil {
+		return Record{}, fmt.Errorf("invalid front matter: %w", err)
+	}
+	rec.Content = body
+	return rec, nil
+}
+
+func (d *markdownDecoder) Close() error {
+	d.file.Close()
+	return os.Remove(d.file.Name())
+}
@@ -0,0 +1,99 @@
+package transfer
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bxcodec/go-clean-arch/domain"
+)
+
+func TestFormatsRoundTrip(t *testing.T) {
+	created := time.Date(2024, 2, 3, 4, 5, 6, 0, time.UTC)
+	records := []Record{
+		{
+			ID: 1, Title: "Hello, world", Slug: "hello-world", Author: "ann", AuthorName: "Ann",
+			Tags: []string{"food", "life"}, Status: "published", Visibility: "public", ContentFormat: "markdown",
+			CreatedAt: created, UpdatedAt: created.Add(time.Hour),
+			Content: "# Hi\n\n---\n\nA \"quoted\", <b>multi</b>\nline body\n",
+		},
+		{
+			ID: 2, Title: "Draft", Slug: "draft", Author: "bob", Tags: []string{},
+			Status: "draft", Visibility: "private", ContentFormat: "html",
+			CreatedAt: created, UpdatedAt: created, Content: "<p>x</p>",
+		},
+	}
+
+	for _, format := range []domain.TransferFormat{domain.TransferJSONL, domain.TransferCSV, domain.TransferMarkdown} {
+		t.Run(string(format), func(t *testing.T) {
+			var buf bytes.Buffer
+			enc, err := newEncoder(format, &buf)
+			require.NoError(t, err)
+			for i := range records {
+				require.NoError(t, enc.Encode(&records[i]))
+			}
+			require.NoError(t, enc.Close())
+
+			dec, err := newDecoder(format, &buf)
+			require.NoError(t, err)
+			defer dec.Close()
+			for i := range records {
+				rec, err := dec.Next()
+				require.NoError(t, err)
+				if len(rec.Tags) == 0 {
+					rec.Tags = []string{}
+				}
+				assert.Equal(t, records[i], rec)
+			}
+			_, err = dec.Next()
+			assert.ErrorIs(t, err, io.EOF)
+		})
+	}
+}
+
+func TestDecodeInvalidRows(t *testing.T) {
+	dec, err := newDecoder(domain.TransferJSONL, strings.NewReader("{\"title\":\"a\"}\nnot json\n\n{\"title\":\"b\"}"))
+	require.NoError(t, err)
+
+	rec, err := dec.Next()
+	require.NoError(t, err)
+	assert.Equal(t, "a", rec.Title)
+	_, err = dec.Next()
+	var rowErr *rowError
+	assert.True(t, errors.As(err, &rowErr))
+	rec, err = dec.Next()
+	require.NoError(t, err)
+	assert.Equal(t, "b", rec.Title)
+	_, err = dec.Next()
+	assert.ErrorIs(t, err, io.EOF)
+
+	_, err = newDecoder(domain.TransferCSV, strings.NewReader("id,name\n1,x\n"))
+	assert.ErrorIs(t, err, domain.ErrBadParamInput)
+}
+
+func TestMarkdownDecoderLimitsFileSize(t *testing.T) {
+	var buf bytes.Buffer
+	enc, err := newEncoder(domain.TransferMarkdown, &buf)
+	require.NoError(t, err)
+	// compresses to a few kilobytes but does not fit in memory once inflated
+	require.NoError(t, enc.Encode(&Record{ID: 1, Slug: "big", Title: "Big", Content: strings.Repeat("a", maxMarkdownFileSize)}))
+	require.NoError(t, enc.Encode(&Record{ID: 2, Slug: "small", Title: "Small", Content: "small"}))
+	require.NoError(t, enc.Close())
+
+	dec, err := newDecoder(domain.TransferMarkdown, &buf)
+	require.NoError(t, err)
+	defer dec.Close()
+	_, err = dec.Next()
+	var rowErr *rowError
+	require.True(t, errors.As(err, &rowErr))
+	assert.ErrorIs(t, rowErr.err, domain.ErrTooLarge)
+	rec, err := dec.Next()
+	require.NoError(t, err)
+	assert.Equal(t, "Small", rec.Title)
+}
@@ -0,0 +1,461 @@
+package transfer
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"slices"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/bxcodec/go-clean-arch/domain"
+	"github.com/bxcodec/go-clean-arch/internal/content"
+)
+
+const (
+	// exportBatch is the number of articles loaded per query while exporting
+	exportBatch = 200
+	// maxTitleLength is the size of the title column
+	maxTitleLength = 45
+	// maxCategoryLength is the size of the name and tag columns of categories
+	maxCategoryLength = 45
+)
+
+// Record is an article as it is exported and imported, with its author and
+// tags referenced by username and tag so that it survives a move between sites
+type Record struct {
+	ID            int64     `json:"id" yaml:"id"`
+	Title         string    `json:"title" yaml:"title"`
+	Slug          string    `json:"slug" yaml:"slug"`
+	Author        string    `json:"author" yaml:"author"`
+	AuthorName    string    `json:"author_name" yaml:"author_name"`
+	Tags          []string  `json:"tags" yaml:"tags"`
+	Status        string    `json:"status" yaml:"status"`
+	Visibility    string    `json:"visibility" yaml:"visibility"`
+	ContentFormat string    `json:"content_format" yaml:"content_format"`
+	CreatedAt     time.Time `json:"created_at" yaml:"created_at"`
+	UpdatedAt     time.Time `json:"updated_at" yaml:"updated_at"`
+	// Content is the body of the Markdown files rather than a front matter field
+	Content string `json:"content" yaml:"-"`
+	// SourceURL is the address of the article on the site it is imported from
+	SourceURL string `json:"-" yaml:"-"`
+}
+
+type Service struct {
+	articleRepo  domain.ArticleRepository
+	userRepo     domain.UserRepository
+	categoryRepo domain.CategoryRepository
+	contribRepo  domain.ContributorRepository
+	renderer     domain.ContentRenderer
+}
+
+// NewService will create a new import and export service object
+func NewService(a domain.ArticleRepository, u domain.UserRepository, c domain.CategoryRepository, cr domain.ContributorRepository, r domain.ContentRenderer) *Service {
+	return &Service{
+		articleRepo:  a,
+		userRepo:     u,
+		categoryRepo: c,
+		contribRepo:  cr,
+		renderer:     r,
+	}
+}
+
+// Export writes every article, drafts and private ones included, to w in format
+func (s *Service) Export(ctx context.Context, format domain.TransferFormat, w io.Writer) error {
+	enc, err := newEncoder(format, w)
+	if err != nil {
+		return err
+	}
+	var afterID int64
+	for {
+		list, err := s.articleRepo.FetchAfter(ctx, afterID, exportBatch, true)
+		if err != nil {
+			return err
+		}
+		if len(list) == 0 {
+			break
+		}
+		records, err := s.records(ctx, list)
+		if err != nil {
+			return err
+		}
+		for i := range records {
+			if err := enc.Encode(&records[i]); err != nil {
+				return err
+			}
+		}
+		afterID = list[len(list)-1].ID
+	}
+	return enc.Close()
+}
+
+func (s *Service) records(ctx context.Context, list []domain.Article) ([]Record, error) {
+	ids := make([]int64, len(list))
+	for i := range list {
+		ids[i] = list[i].ID
+	}
+	contributors, err := s.contribRepo.FetchByArticles(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	tags, err := s.categoryRepo.FetchByArticles(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([]Record, len(list))
+	for i := range list {
+		a := &list[i]
+		rec := Record{
+			ID:            a.ID,
+			Title:         a.Title,
+			Slug:          content.Slug(a.Title),
+			Tags:          []string{},
+			Status:        string(a.Status),
+			Visibility:    string(a.Visibility),
+			ContentFormat: string(a.ContentFormat),
+			CreatedAt:     a.CreatedAt,
+			UpdatedAt:     a.UpdatedAt,
+			Content:       a.Content,
+		}
+		for _, c := range contributors[a.ID] {
+			if c.Role == domain.ContributorAuthor {
+				rec.Author, rec.AuthorName = c.User.Username, c.User.Name
+			}
+		}
+		for _, tag := range tags[a.ID] {
+			rec.Tags = append(rec.Tags, tag.Tag)
+		}
+		res[i] = rec
+	}
+	return res, nil
+}
+
+// Import reads records in format from r and stores the valid ones that are
+// not duplicates, reporting the outcome of every row. Only unreadable input
+// fails the whole import.
+func (s *Service) Import(ctx context.Context, format domain.TransferFormat, r io.Reader, opts domain.ImportOptions) (domain.ImportReport, error) {
+	if format == domain.TransferWXR {
+		return s.importWXR(ctx, r, opts)
+	}
+	dec, err := newDecoder(format, r)
+	if err != nil {
+		return domain.ImportReport{}, err
+	}
+	defer dec.Close()
+
+	imp := s.newImporter(opts)
+	for {
+		rec, err := dec.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		var rowErr *rowError
+		if errors.As(err, &rowErr) {
+			imp.fail(domain.ImportRow{}, rowErr.err)
+			continue
+		}
+		if err != nil {
+			return domain.ImportReport{}, err
+		}
+		if err := imp.add(ctx, &rec); err != nil {
+			return imp.report, err
+		}
+	}
+	return imp.report, nil
+}
+
+// ImportRecords imports records that were read by another importer, see Import
+func (s *Service) ImportRecords(ctx context.Context, records []Record, opts domain.ImportOptions) (domain.ImportReport, error) {
+	imp := s.newImporter(opts)
+	for i := range records {
+		if err := imp.add(ctx, &records[i]); err != nil {
+			return imp.report, err
+		}
+	}
+	return imp.report, nil
+}
+
+// importer keeps what an import already looked up or saw
+type importer struct {
+	*Service
+	opts       domain.ImportOptions
+	report     domain.ImportReport
+	users      map[string]domain.User
+	categories map[string]domain.Category
+	// seen maps the slugs and lowercased titles of the rows so far to their row
+	seen map[string]int
+}
+
+func (s *Service) newImporter(opts domain.ImportOptions) *importer {
+	return &importer{
+		Service:    s,
+		opts:       opts,
+		report:     domain.ImportReport{DryRun: opts.DryRun, Rows: []domain.ImportRow{}},
+		users:      map[string]domain.User{},
+		categories: map[string]domain.Category{},
+		seen:       map[string]int{},
+	}
+}
+
+func (imp *importer) fail(row domain.ImportRow, err error) {
+	row.Row = len(imp.report.Rows) + 1
+	row.Result = domain.ImportFailed
+	row.Error = err.Error()
+	imp.report.Failed++
+	imp.report.Rows = append(imp.report.Rows, row)
+}
+
+func (imp *importer) skip(row domain.ImportRow, reason string) {
+	row.Row = len(imp.report.Rows) + 1
+	row.Result = domain.ImportSkipped
+	row.Error = reason
+	imp.report.Skipped++
+	imp.report.Rows = append(imp.report.Rows, row)
+}
+
+// add imports one record, the error is only set when the import has to stop
+func (imp *importer) add(ctx context.Context, rec *Record) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	row, err := imp.addRow(ctx, rec)
+	if err != nil {
+		if stopsImport(ctx, err) {
+			return err
+		}
+		imp.fail(row, err)
+	}
+	return nil
+}
+
+// stopsImport reports whether an error ends the import rather than failing
+// its row: the import was cancelled or the database cannot be reached
+func stopsImport(ctx context.Context, err error) bool {
+	var netErr net.Error
+	return ctx.Err() != nil || errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) ||
+		errors.Is(err, driver.ErrBadConn) || errors.As(err, &netErr)
+}
+
+// addRow imports one record and reports it unless it returns an error for
+// the row
+func (imp *importer) addRow(ctx context.Context, rec *Record) (domain.ImportRow, error) {
+	row := domain.ImportRow{SourceID: rec.ID, SourceURL: rec.SourceURL, Title: rec.Title, Slug: rec.Slug}
+	if row.Slug == "" {
+		row.Slug = content.Slug(rec.Title)
+	}
+
+	ar, err := imp.article(rec)
+	if err != nil {
+		return row, err
+	}
+	titleKey := "title:" + strings.ToLower(ar.Title)
+	for _, key := range []string{"slug:" + row.Slug, titleKey} {
+		if n, ok := imp.seen[key]; ok {
+			imp.skip(row, fmt.Sprintf("duplicate of row %d", n))
+			return row, nil
+		}
+	}
+	existing, err := imp.articleRepo.GetByTitle(ctx, ar.Title)
+	if err == nil && existing.ID != 0 {
+		row.ArticleID = existing.ID
+		imp.skip(row, fmt.Sprintf("duplicate of article %d", existing.ID))
+		return row, nil
+	}
+	for _, slug := range []string{row.Slug, content.Slug(ar.Title)} {
+		id, err := imp.slugTaken(ctx, slug)
+		if err != nil {
+			return row, err
+		}
+		if id != 0 {
+			row.ArticleID = id
+			imp.skip(row, fmt.Sprintf("duplicate of article %d", id))
+			return row, nil
+		}
+	}
+
+	author, err := imp.author(ctx, rec.Author)
+	if err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			return row, fmt.Errorf("unknown author %q", rec.Author)
+		}
+		return row, err
+	}
+	ar.User = author
+	categoryIDs, newCategories, err := imp.tags(ctx, rec.Tags)
+	if err != nil {
+		return row, err
+	}
+
+	// new categories are only created with the row, a failed row leaves none behind
+	if !imp.opts.DryRun {
+		opts := domain.StoreOptions{CategoryIDs: categoryIDs, NewCategories: newCategories, Imported: true}
+		if err := imp.articleRepo.StoreWith(ctx, &ar, opts); err != nil {
+			return row, err
+		}
+	}
+	for _, c := range newCategories {
+		imp.categories[c.Tag] = c
+	}
+	n := len(imp.report.Rows) + 1
+	imp.seen["slug:"+row.Slug], imp.seen[titleKey] = n, n
+	row.Row = n
+	row.Result = domain.ImportCreated
+	row.ArticleID = ar.ID
+	imp.report.Created++
+	imp.report.Rows = append(imp.report.Rows, row)
+	return row, nil
+}
+
+// slugTaken returns the id of an article of the site whose title has the given
+// slug, or 0 when there is none
+func (imp *importer) slugTaken(ctx context.Context, slug string) (int64, error) {
+	// no title has a slug that is not normalized
+	if slug == "" || content.Slug(slug) != slug {
+		return 0, nil
+	}
+	candidates, err := imp.articleRepo.FetchByTitleSlug(ctx, slug)
+	if err != nil {
+		return 0, err
+	}
+	for _, c := range candidates {
+		if content.Slug(c.Title) == slug {
+			return c.ID, nil
+		}
+	}
+	return 0, nil
+}
+
+// article validates a record the way articles created through the API are and
+// renders its content
+func (imp *importer) article(rec *Record) (domain.Article, error) {
+	ar := domain.Article{
+		Title:         strings.TrimSpace(rec.Title),
+		Content:       rec.Content,
+		ContentFormat: domain.ContentFormat(rec.ContentFormat),
+		Status:        domain.ArticleStatus(rec.Status),
+		Visibility:    domain.ArticleVisibility(rec.Visibility),
+		CreatedAt:     rec.CreatedAt,
+		UpdatedAt:     rec.UpdatedAt,
+	}
+	if ar.Status == "" {
+		ar.Status = domain.ArticleStatusPublished
+	}
+	if ar.Visibility == "" {
+		ar.Visibility = domain.ArticleVisibilityPublic
+	}
+	if ar.ContentFormat == "" {
+		ar.ContentFormat = domain.ContentFormatMarkdown
+	}
+	switch {
+	case ar.Title == "":
+		return ar, errors.New("title is required")
+	case utf8.RuneCountInString(ar.Title) > maxTitleLength:
+		return ar, fmt.Errorf("title is longer than %d characters", maxTitleLength)
+	case strings.TrimSpace(ar.Content) == "":
+		return ar, errors.New("content is required")
+	case !ar.Status.Valid():
+		return ar, fmt.Errorf("invalid status %q", ar.Status)
+	case !ar.Visibility.Valid():
+		return ar, fmt.Errorf("invalid visibility %q", ar.Visibility)
+	case !ar.ContentFormat.Valid():
+		return ar, fmt.Errorf("invalid content format %q", ar.ContentFormat)
+	}
+	if ar.UpdatedAt.Before(ar.CreatedAt) {
+		ar.UpdatedAt = ar.CreatedAt
+	}
+
+	rendered, err := imp.renderer.Render(ar.ContentFormat, ar.Content)
+	if err != nil {
+		return ar, fmt.Errorf("failed to render content: %w", err)
+	}
+	ar.ContentHTML = rendered.HTML
+	ar.Excerpt = rendered.Excerpt
+	ar.WordCount = rendered.WordCount
+	ar.ReadingMinutes = domain.ReadingMinutes(rendered.WordCount)
+	ar.Fingerprint = rendered.Fingerprint
+	ar.TOC = rendered.TOC
+	return ar, nil
+}
+
+// author maps the username of the source to a user of the site
+func (imp *importer) author(ctx context.Context, username string) (domain.User, error) {
+	if mapped, ok := imp.opts.AuthorMap[username]; ok {
+		username = mapped
+	}
+	user, err := imp.user(ctx, username)
+	if errors.Is(err, domain.ErrNotFound) && imp.opts.DefaultAuthor != "" {
+		return imp.user(ctx, imp.opts.DefaultAuthor)
+	}
+	return user, err
+}
+
+func (imp *importer) user(ctx context.Context, username string) (domain.User, error) {
+	if username == "" {
+		return domain.User{}, domain.ErrNotFound
+	}
+	if user, ok := imp.users[username]; ok {
+		return user, nil
+	}
+	user, err := imp.userRepo.GetByUsername(ctx, username)
+	if err != nil {
+		return domain.User{}, err
+	}
+	imp.users[username] = user
+	return user, nil
+}
+
+// tags returns the ids of the known categories with the given names or tags
+// and the categories to create along with the row. Tags the importer already
+// knows are taken as they are, the others are slugged.
+func (imp *importer) tags(ctx context.Context, names []string) ([]int64, []domain.Category, error) {
+	var tags, missing []string
+	named := map[string]string{}
+	for _, name := range names {
+		tag := name
+		if _, ok := imp.categories[tag]; !ok {
+			tag = content.Slug(name)
+		}
+		if tag == "" {
+			continue
+		}
+		name = strings.TrimSpace(name)
+		if utf8.RuneCountInString(name) > maxCategoryLength || utf8.RuneCountInString(tag) > maxCategoryLength {
+			return nil, nil, fmt.Errorf("tag %q is longer than %d characters", name, maxCategoryLength)
+		}
+		tags = append(tags, tag)
+		named[tag] = name
+		if _, ok := imp.categories[tag]; !ok {
+			missing = append(missing, tag)
+		}
+	}
+	if len(missing) > 0 {
+		found, err := imp.categoryRepo.FetchByTags(ctx, missing)
+		if err != nil {
+			return nil, nil, err
+		}
+		for _, c := range found {
+			imp.categories[c.Tag] = c
+		}
+	}
+
+	ids := make([]int64, 0, len(tags))
+	var created []domain.Category
+	for _, tag := range tags {
+		c, ok := imp.categories[tag]
+		if !ok {
+			if !slices.ContainsFunc(created, func(c domain.Category) bool { return c.Tag == tag }) {
+				created = append(created, domain.Category{Name: named[tag], Tag: tag})
+			}
+			continue
+		}
+		if c.ID != 0 && !slices.Contains(ids, c.ID) {
+			ids = append(ids, c.ID)
+		}
+	}
+	return ids, created, nil
+}
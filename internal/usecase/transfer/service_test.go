@@ -0,0 +1,220 @@
+package transfer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bxcodec/go-clean-arch/domain"
+	"github.com/bxcodec/go-clean-arch/internal/content"
+)
+
+type fakeArticleRepo struct {
+	domain.ArticleRepository
+	articles   []domain.Article
+	stored     []domain.StoreOptions
+	failTitles map[string]bool
+}
+
+func (f *fakeArticleRepo) GetByTitle(_ context.Context, title string) (domain.Article, error) {
+	for _, a := range f.articles {
+		if a.Title == title {
+			return a, nil
+		}
+	}
+	return domain.Article{}, domain.ErrNotFound
+}
+
+func (f *fakeArticleRepo) FetchByTitleSlug(_ context.Context, slug string) ([]domain.Article, error) {
+	var res []domain.Article
+	for _, a := range f.articles {
+		if content.Slug(a.Title) == slug {
+			res = append(res, a)
+		}
+	}
+	return res, nil
+}
+
+func (f *fakeArticleRepo) StoreWith(_ context.Context, a *domain.Article, opts domain.StoreOptions) error {
+	if f.failTitles[a.Title] {
+		return errors.New("Error 1406: Data too long for column 'content'")
+	}
+	for i := range opts.NewCategories {
+		opts.NewCategories[i].ID = int64(100 + i)
+	}
+	a.ID = int64(len(f.articles) + 1)
+	f.articles = append(f.articles, *a)
+	f.stored = append(f.stored, opts)
+	return nil
+}
+
+type fakeUserRepo struct {
+	domain.UserRepository
+	inserted []domain.User
+}
+
+func (f *fakeUserRepo) GetByUsername(_ context.Context, username string) (domain.User, error) {
+	if username == "ann" {
+		return domain.User{ID: 3, Username: "ann"}, nil
+	}
+	for _, u := range f.inserted {
+		if u.Username == username {
+			return u, nil
+		}
+	}
+	return domain.User{}, domain.ErrNotFound
+}
+
+func (f *fakeUserRepo) Insert(_ context.Context, u *domain.User) error {
+	u.ID = int64(len(f.inserted) + 10)
+	f.inserted = append(f.inserted, *u)
+	return nil
+}
+
+type fakeCategoryRepo struct {
+	domain.CategoryRepository
+	categories []domain.Category
+}
+
+func (f *fakeCategoryRepo) FetchByTags(_ context.Context, tags []string) ([]domain.Category, error) {
+	var res []domain.Category
+	for _, c := range f.categories {
+		for _, tag := range tags {
+			if c.Tag == tag {
+				res = append(res, c)
+			}
+		}
+	}
+	return res, nil
+}
+
+func (f *fakeCategoryRepo) Store(_ context.Context, c *domain.Category) error {
+	c.ID = int64(len(f.categories) + 1)
+	f.categories = append(f.categories, *c)
+	return nil
+}
+
+func newTestService(existing ...domain.Article) (*Service, *fakeArticleRepo) {
+	s, repo, _ := newTestServiceWithUsers(existing...)
+	return s, repo
+}
+
+func newTestServiceWithUsers(existing ...domain.Article) (*Service, *fakeArticleRepo, *fakeUserRepo) {
+	repo := &fakeArticleRepo{articles: existing}
+	users := &fakeUserRepo{}
+	categories := &fakeCategoryRepo{categories: []domain.Category{{ID: 1, Name: "Food", Tag: "food"}}}
+	return NewService(repo, users, categories, nil, content.NewRenderer()), repo, users
+}
+
+func testRecord(title string) Record {
+	created := time.Date(2019, 5, 2, 10, 0, 0, 0, time.UTC)
+	return Record{Title: title, Author: "ann", Content: "Hot *soup*", CreatedAt: created, UpdatedAt: created}
+}
+
+func TestImportStoresRowsQuietly(t *testing.T) {
+	s, repo := newTestService()
+	rec := testRecord("Soup")
+	rec.Tags = []string{"Food", "food", "Recipes"}
+
+	report, err := s.ImportRecords(context.Background(), []Record{rec}, domain.ImportOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, 1, report.Created)
+
+	// the row and its tags are written together, and old posts are not announced
+	require.Len(t, repo.stored, 1)
+	assert.True(t, repo.stored[0].Imported)
+	assert.Equal(t, []int64{1}, repo.stored[0].CategoryIDs)
+	assert.Equal(t, []domain.Category{{ID: 100, Name: "Recipes", Tag: "recipes"}}, repo.stored[0].NewCategories)
+	assert.Equal(t, int64(3), repo.articles[0].User.ID)
+	assert.Equal(t, domain.ArticleStatusPublished, repo.articles[0].Status)
+}
+
+func TestImportSkipsSlugsTakenOnTheSite(t *testing.T) {
+	s, repo := newTestService(domain.Article{ID: 9, Title: "Hot Soup!"})
+
+	hot := testRecord("Hot soup")
+	renamed := testRecord("Winter soup")
+	renamed.Slug = "hot-soup"
+	other := testRecord("Hot summer soup")
+	report, err := s.ImportRecords(context.Background(), []Record{hot, renamed, other}, domain.ImportOptions{})
+	require.NoError(t, err)
+
+	assert.Equal(t, 2, report.Skipped)
+	assert.Equal(t, 1, report.Created)
+	for _, row := range report.Rows[:2] {
+		assert.Equal(t, domain.ImportSkipped, row.Result)
+		assert.Equal(t, int64(9), row.ArticleID)
+		assert.Equal(t, "duplicate of article 9", row.Error)
+	}
+	assert.Equal(t, domain.ImportCreated, report.Rows[2].Result)
+	assert.Len(t, repo.stored, 1)
+}
+
+func TestImportSkipsDuplicateRows(t *testing.T) {
+	s, repo := newTestService()
+
+	report, err := s.ImportRecords(context.Background(), []Record{testRecord("Soup"), testRecord("soup")}, domain.ImportOptions{DryRun: true})
+	require.NoError(t, err)
+	assert.Equal(t, 1, report.Created)
+	assert.Equal(t, 1, report.Skipped)
+	assert.Equal(t, "duplicate of row 1", report.Rows[1].Error)
+	assert.Empty(t, repo.stored)
+}
+
+func TestImportSkipsSlugAmongManySimilarTitles(t *testing.T) {
+	var existing []domain.Article
+	for i := 1; i <= 150; i++ {
+		existing = append(existing, domain.Article{ID: int64(i), Title: fmt.Sprintf("Hot soup %d", i)})
+	}
+	existing = append(existing, domain.Article{ID: 151, Title: "Hot: soup"})
+	s, repo := newTestService(existing...)
+
+	rec := testRecord("Winter soup")
+	rec.Slug = "hot-soup"
+	report, err := s.ImportRecords(context.Background(), []Record{rec}, domain.ImportOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, 1, report.Skipped)
+	assert.Equal(t, int64(151), report.Rows[0].ArticleID)
+	assert.Empty(t, repo.stored)
+}
+
+func TestImportFailsOnlyTheFailingRows(t *testing.T) {
+	s, repo := newTestService()
+	repo.failTitles = map[string]bool{"Stew": true}
+
+	long := testRecord("Salad")
+	long.Tags = []string{strings.Repeat("leaf", 12)}
+	stew := testRecord("Stew")
+	stew.Tags = []string{"Winter"}
+	soup := testRecord("Soup")
+	soup.Tags = []string{"Winter"}
+	report, err := s.ImportRecords(context.Background(), []Record{long, stew, soup}, domain.ImportOptions{})
+	require.NoError(t, err)
+
+	assert.Equal(t, 2, report.Failed)
+	assert.Equal(t, 1, report.Created)
+	assert.Equal(t, domain.ImportFailed, report.Rows[0].Result)
+	assert.Contains(t, report.Rows[0].Error, "longer than 45 characters")
+	assert.Equal(t, domain.ImportFailed, report.Rows[1].Result)
+	assert.Contains(t, report.Rows[1].Error, "Data too long")
+	assert.Equal(t, domain.ImportCreated, report.Rows[2].Result)
+
+	// the tag of the failed row is only created with the row that succeeded
+	require.Len(t, repo.stored, 1)
+	assert.Equal(t, []domain.Category{{ID: 100, Name: "Winter", Tag: "winter"}}, repo.stored[0].NewCategories)
+}
+
+func TestImportStopsWhenCancelled(t *testing.T) {
+	s, _ := newTestService()
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := s.ImportRecords(ctx, []Record{testRecord("Soup")}, domain.ImportOptions{})
+	assert.ErrorIs(t, err, context.Canceled)
+}
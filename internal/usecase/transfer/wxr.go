@@ -0,0 +1,316 @@
+package transfer
+
+import (
+	"context"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/bxcodec/go-clean-arch/domain"
+)
+
+const (
+	// the sizes of the name and username columns of users
+	maxUserNameLength = 32
+	wxrDateLayout     = "2006-01-02 15:04:05"
+	wxrZeroDate       = "0000-00-00 00:00:00"
+)
+
+// wxrExport is what is kept of a WordPress export. The wp namespace changes
+// with the WXR version, so its elements are matched by local name only.
+type wxrExport struct {
+	Authors []wxrAuthor
+	Terms   []wxrTerm
+	Items   []wxrItem
+}
+
+type wxrAuthor struct {
+	ID          int64  `xml:"author_id"`
+	Login       string `xml:"author_login"`
+	DisplayName string `xml:"author_display_name"`
+}
+
+// wxrTerm is a category (nicename and cat_name) or a tag (tag_slug and tag_name)
+type wxrTerm struct {
+	ID       int64  `xml:"term_id"`
+	Nicename string `xml:"category_nicename"`
+	CatName  string `xml:"cat_name"`
+	TagSlug  string `xml:"tag_slug"`
+	TagName  string `xml:"tag_name"`
+}
+
+func (t *wxrTerm) tag() (slug, name string) {
+	if t.TagSlug != "" {
+		return t.TagSlug, t.TagName
+	}
+	return t.Nicename, t.CatName
+}
+
+type wxrItem struct {
+	Title       string            `xml:"title"`
+	Link        string            `xml:"link"`
+	Creator     string            `xml:"http://purl.org/dc/elements/1.1/ creator"`
+	Content     string            `xml:"http://purl.org/rss/1.0/modules/content/ encoded"`
+	PostID      int64             `xml:"post_id"`
+	PostDate    string            `xml:"post_date"`
+	PostDateGMT string            `xml:"post_date_gmt"`
+	ModifiedGMT string            `xml:"post_modified_gmt"`
+	PostName    string            `xml:"post_name"`
+	Status      string            `xml:"status"`
+	PostType    string            `xml:"post_type"`
+	Categories  []wxrItemCategory `xml:"category"`
+}
+
+type wxrItemCategory struct {
+	Domain   string `xml:"domain,attr"`
+	Nicename string `xml:"nicename,attr"`
+}
+
+// parseWXR reads the authors and terms of the channel and its items
+func parseWXR(r io.Reader) (wxrExport, error) {
+	var res wxrExport
+	dec := xml.NewDecoder(r)
+	// exports declare UTF-8 and are sometimes not, the content is sanitized later anyway
+	dec.CharsetReader = func(_ string, input io.Reader) (io.Reader, error) {
+		return input, nil
+	}
+	for {
+		tok, err := dec.Token()
+		if errors.Is(err, io.EOF) {
+			return res, nil
+		}
+		if err != nil {
+			return wxrExport{}, fmt.Errorf("%w: invalid WXR: %v", domain.ErrBadParamInput, err)
+		}
+		start, ok := tok.(xml.StartElement)
+		if !ok {
+			continue
+		}
+		// the channel also has RSS elements of the same names, the WXR ones are namespaced
+		wp := strings.Contains(start.Name.Space, "wordpress.org/export")
+		switch {
+		case wp && start.Name.Local == "author":
+			var author wxrAuthor
+			err = dec.DecodeElement(&author, &start)
+			res.Authors = append(res.Authors, author)
+		case wp && (start.Name.Local == "category" || start.Name.Local == "tag"):
+			var term wxrTerm
+			err = dec.DecodeElement(&term, &start)
+			res.Terms = append(res.Terms, term)
+		case start.Name.Local == "item":
+			var item wxrItem
+			err = dec.DecodeElement(&item, &start)
+			res.Items = append(res.Items, item)
+		}
+		if err != nil {
+			return wxrExport{}, fmt.Errorf("%w: invalid WXR: %v", domain.ErrBadParamInput, err)
+		}
+	}
+}
+
+// importWXR imports the posts of a WordPress export with their authors,
+// categories and tags. Authors without a user of the same username get one
+// without a usable password, pages, attachments and trashed posts are left out.
+func (s *Service) importWXR(ctx context.Context, r io.Reader, opts domain.ImportOptions) (domain.ImportReport, error) {
+	export, err := parseWXR(r)
+	if err != nil {
+		return domain.ImportReport{}, err
+	}
+	imp := s.newImporter(opts)
+	imp.report.Authors = []domain.ImportAuthor{}
+	imp.report.Categories = []domain.ImportCategory{}
+
+	for _, author := range export.Authors {
+		res, err := imp.ensureUser(ctx, author)
+		if err != nil {
+			return imp.report, err
+		}
+		imp.report.Authors = append(imp.report.Authors, res)
+	}
+	for _, term := range export.Terms {
+		res, err := imp.ensureCategory(ctx, term)
+		if err != nil {
+			return imp.report, err
+		}
+		imp.report.Categories = append(imp.report.Categories, res)
+	}
+
+	for _, item := range export.Items {
+		if item.PostType != "post" {
+			continue
+		}
+		rec, err := wxrRecord(&item)
+		if err != nil {
+			imp.fail(domain.ImportRow{SourceID: item.PostID, SourceURL: item.Link, Title: item.Title}, err)
+			continue
+		}
+		if rec.Status == "" {
+			imp.skip(domain.ImportRow{SourceID: item.PostID, SourceURL: item.Link, Title: item.Title, Slug: rec.Slug},
+				fmt.Sprintf("status %s is not imported", item.Status))
+			continue
+		}
+		if err := imp.add(ctx, &rec); err != nil {
+			return imp.report, err
+		}
+	}
+	return imp.report, nil
+}
+
+// ensureUser finds the user of an author or creates it, the error is only set
+// when the import has to stop
+func (imp *importer) ensureUser(ctx context.Context, author wxrAuthor) (domain.ImportAuthor, error) {
+	username := author.Login
+	if mapped, ok := imp.opts.AuthorMap[username]; ok {
+		username = mapped
+	}
+	res := domain.ImportAuthor{SourceID: author.ID, Username: username}
+	user, err := imp.user(ctx, username)
+	if err == nil {
+		res.UserID = user.ID
+		return res, nil
+	}
+	if !errors.Is(err, domain.ErrNotFound) {
+		return res, err
+	}
+	if username == "" || utf8.RuneCountInString(username) > maxUserNameLength {
+		res.Error = fmt.Sprintf("username %q is empty or longer than %d characters", username, maxUserNameLength)
+		return res, nil
+	}
+
+	name := strings.TrimSpace(author.DisplayName)
+	if name == "" {
+		name = username
+	}
+	if utf8.RuneCountInString(name) > maxUserNameLength {
+		name = string([]rune(name)[:maxUserNameLength])
+	}
+	// the user signs in after an administrator issued a password reset
+	user = domain.User{Name: name, Username: username, Password: domain.UnusablePassword}
+	res.Created = true
+	if !imp.opts.DryRun {
+		if err := imp.userRepo.Insert(ctx, &user); err != nil {
+			return res, err
+		}
+	}
+	res.UserID = user.ID
+	imp.users[username] = user
+	return res, nil
+}
+
+// ensureCategory finds the category of a term by tag or creates it
+func (imp *importer) ensureCategory(ctx context.Context, term wxrTerm) (domain.ImportCategory, error) {
+	tag, name := term.tag()
+	res := domain.ImportCategory{SourceID: term.ID, Tag: tag}
+	if c, ok := imp.categories[tag]; ok || tag == "" {
+		res.CategoryID = c.ID
+		return res, nil
+	}
+	found, err := imp.categoryRepo.FetchByTags(ctx, []string{tag})
+	if err != nil {
+		return res, err
+	}
+	c := domain.Category{Name: strings.TrimSpace(name), Tag: tag}
+	if len(found) > 0 {
+		c = found[0]
+	} else {
+		if c.Name == "" {
+			c.Name = tag
+		}
+		// the posts using it fail on their own
+		if utf8.RuneCountInString(c.Name) > maxCategoryLength || utf8.RuneCountInString(tag) > maxCategoryLength {
+			res.Error = fmt.Sprintf("name or tag is longer than %d characters", maxCategoryLength)
+			return res, nil
+		}
+		res.Created = true
+		if !imp.opts.DryRun {
+			if err := imp.categoryRepo.Store(ctx, &c); err != nil {
+				return res, err
+			}
+		}
+	}
+	res.CategoryID = c.ID
+	imp.categories[tag] = c
+	return res, nil
+}
+
+// wxrRecord converts a post, the status is empty for posts that are not imported
+func wxrRecord(item *wxrItem) (Record, error) {
+	rec := Record{
+		ID:            item.PostID,
+		Title:         strings.TrimSpace(item.Title),
+		Slug:          item.PostName,
+		Author:        item.Creator,
+		Tags:          []string{},
+		ContentFormat: string(domain.ContentFormatHTML),
+		Content:       autop(item.Content),
+		SourceURL:     item.Link,
+	}
+	switch item.Status {
+	case "publish":
+		rec.Status, rec.Visibility = string(domain.ArticleStatusPublished), string(domain.ArticleVisibilityPublic)
+	case "private":
+		rec.Status, rec.Visibility = string(domain.ArticleStatusPublished), string(domain.ArticleVisibilityPrivate)
+	case "draft", "pending", "future":
+		rec.Status, rec.Visibility = string(domain.ArticleStatusDraft), string(domain.ArticleVisibilityPublic)
+	}
+	for _, c := range item.Categories {
+		if (c.Domain == "category" || c.Domain == "post_tag") && c.Nicename != "" {
+			rec.Tags = append(rec.Tags, c.Nicename)
+		}
+	}
+
+	var err error
+	// drafts never published have no GMT date, their local date is the best there is
+	if rec.CreatedAt, err = wxrDate(item.PostDateGMT, item.PostDate); err != nil {
+		return rec, err
+	}
+	if rec.UpdatedAt, err = wxrDate(item.ModifiedGMT, ""); err != nil {
+		return rec, err
+	}
+	return rec, nil
+}
+
+func wxrDate(gmt, local string) (time.Time, error) {
+	for _, value := range []string{gmt, local} {
+		if value = strings.TrimSpace(value); value == "" || value == wxrZeroDate {
+			continue
+		}
+		t, err := time.Parse(wxrDateLayout, value)
+		if err != nil {
+			return time.Time{}, fmt.Errorf("invalid date %q", value)
+		}
+		return t, nil
+	}
+	return time.Time{}, nil
+}
+
+var (
+	blankLines = regexp.MustCompile(`\n\s*\n`)
+	// blockStart matches paragraphs that already are block elements or comments
+	blockStart = regexp.MustCompile(`(?i)^<(!--|/?(p|div|h[1-6]|ul|ol|li|blockquote|pre|table|thead|tbody|tr|td|th|figure|figcaption|hr|dl|dt|dd|section|article|aside|header|footer|address|iframe|img)\b)`)
+)
+
+// autop wraps the text of WordPress posts, which is stored without the
+// paragraphs WordPress only adds on display, into paragraphs and line breaks
+func autop(text string) string {
+	text = strings.ReplaceAll(strings.ReplaceAll(text, "\r\n", "\n"), "\r", "\n")
+	var b strings.Builder
+	for _, part := range blankLines.Split(strings.TrimSpace(text), -1) {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		if blockStart.MatchString(part) {
+			b.WriteString(part)
+		} else {
+			b.WriteString("<p>" + strings.ReplaceAll(part, "\n", "<br>\n") + "</p>")
+		}
+		b.WriteByte('\n')
+	}
+	return b.String()
+}
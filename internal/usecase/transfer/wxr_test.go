@@ -0,0 +1,113 @@
+package transfer
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bxcodec/go-clean-arch/domain"
+)
+
+const testWXR = `<?xml version="1.0" encoding="UTF-8" ?>
+<rss version="2.0"
+	xmlns:content="http://purl.org/rss/1.0/modules/content/"
+	xmlns:excerpt="http://wordpress.org/export/1.2/excerpt/"
+	xmlns:dc="http://purl.org/dc/elements/1.1/"
+	xmlns:wp="http://wordpress.org/export/1.2/">
+<channel>
+	<title>Old blog</title>
+	<wp:author><wp:author_id>2</wp:author_id><wp:author_login><![CDATA[ann]]></wp:author_login><wp:author_display_name><![CDATA[Ann Lee]]></wp:author_display_name></wp:author>
+	<wp:category><wp:term_id>5</wp:term_id><wp:category_nicename><![CDATA[food]]></wp:category_nicename><wp:cat_name><![CDATA[Food]]></wp:cat_name></wp:category>
+	<wp:tag><wp:term_id>9</wp:term_id><wp:tag_slug><![CDATA[recipes]]></wp:tag_slug><wp:tag_name><![CDATA[Recipes]]></wp:tag_name></wp:tag>
+	<item>
+		<title>Soup</title>
+		<link>https://old.example/2019/05/soup/</link>
+		<dc:creator><![CDATA[ann]]></dc:creator>
+		<content:encoded><![CDATA[Hot soup
+with bread
+
+<h2>Steps</h2>]]></content:encoded>
+		<excerpt:encoded><![CDATA[Not the content]]></excerpt:encoded>
+		<wp:post_id>41</wp:post_id>
+		<wp:post_date><![CDATA[2019-05-02 17:00:00]]></wp:post_date>
+		<wp:post_date_gmt><![CDATA[2019-05-02 10:00:00]]></wp:post_date_gmt>
+		<wp:post_name><![CDATA[soup]]></wp:post_name>
+		<wp:status><![CDATA[publish]]></wp:status>
+		<wp:post_type><![CDATA[post]]></wp:post_type>
+		<category domain="category" nicename="food"><![CDATA[Food]]></category>
+		<category domain="post_tag" nicename="recipes"><![CDATA[Recipes]]></category>
+	</item>
+	<item>
+		<title>Unfinished</title>
+		<dc:creator><![CDATA[ann]]></dc:creator>
+		<content:encoded><![CDATA[Later]]></content:encoded>
+		<wp:post_id>42</wp:post_id>
+		<wp:post_date><![CDATA[2019-06-01 08:00:00]]></wp:post_date>
+		<wp:post_date_gmt><![CDATA[0000-00-00 00:00:00]]></wp:post_date_gmt>
+		<wp:status><![CDATA[draft]]></wp:status>
+		<wp:post_type><![CDATA[post]]></wp:post_type>
+	</item>
+</channel>
+</rss>`
+
+func TestParseWXR(t *testing.T) {
+	export, err := parseWXR(strings.NewReader(testWXR))
+	require.NoError(t, err)
+
+	assert.Equal(t, []wxrAuthor{{ID: 2, Login: "ann", DisplayName: "Ann Lee"}}, export.Authors)
+	require.Len(t, export.Terms, 2)
+	slug, name := export.Terms[1].tag()
+	assert.Equal(t, "recipes", slug)
+	assert.Equal(t, "Recipes", name)
+	require.Len(t, export.Items, 2)
+
+	rec, err := wxrRecord(&export.Items[0])
+	require.NoError(t, err)
+	assert.Equal(t, Record{
+		ID:            41,
+		Title:         "Soup",
+		Slug:          "soup",
+		Author:        "ann",
+		Tags:          []string{"food", "recipes"},
+		Status:        string(domain.ArticleStatusPublished),
+		Visibility:    string(domain.ArticleVisibilityPublic),
+		ContentFormat: string(domain.ContentFormatHTML),
+		CreatedAt:     time.Date(2019, 5, 2, 10, 0, 0, 0, time.UTC),
+		Content:       "<p>Hot soup<br>\nwith bread</p>\n<h2>Steps</h2>\n",
+		SourceURL:     "https://old.example/2019/05/soup/",
+	}, rec)
+
+	draft, err := wxrRecord(&export.Items[1])
+	require.NoError(t, err)
+	assert.Equal(t, string(domain.ArticleStatusDraft), draft.Status)
+	assert.Equal(t, time.Date(2019, 6, 1, 8, 0, 0, 0, time.UTC), draft.CreatedAt)
+}
+
+func TestWXRRecordSkipsTrash(t *testing.T) {
+	rec, err := wxrRecord(&wxrItem{Title: "Old", Status: "trash", PostType: "post"})
+	require.NoError(t, err)
+	assert.Empty(t, rec.Status)
+}
+
+func TestImportWXRCreatesAuthorsWithoutPassword(t *testing.T) {
+	s, repo, users := newTestServiceWithUsers()
+	opts := domain.ImportOptions{AuthorMap: map[string]string{"ann": "annlee"}}
+
+	report, err := s.Import(context.Background(), domain.TransferWXR, strings.NewReader(testWXR), opts)
+	require.NoError(t, err)
+
+	require.Len(t, users.inserted, 1)
+	assert.Equal(t, "annlee", users.inserted[0].Username)
+	assert.Equal(t, domain.UnusablePassword, users.inserted[0].Password)
+	assert.Equal(t, []domain.ImportAuthor{{SourceID: 2, Username: "annlee", UserID: users.inserted[0].ID, Created: true}}, report.Authors)
+
+	// old posts are imported quietly like every other format
+	require.NotEmpty(t, repo.stored)
+	for _, opts := range repo.stored {
+		assert.True(t, opts.Imported)
+	}
+}
@@ -2,6 +2,8 @@ package user
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"time"
 
 	"github.com/bxcodec/go-clean-arch/domain"
@@ -9,6 +11,12 @@ import (
 	"golang.org/x/crypto/bcrypt"
 )
 
+// PasswordResetTTL is how long a password reset token is valid
+const PasswordResetTTL = 72 * time.Hour
+
+// passwordResetPurpose keeps reset tokens and login tokens apart
+const passwordResetPurpose = "password_reset"
+
 type Service struct {
 	userRepo  domain.UserRepository
 	jwtSecret []byte
@@ -104,3 +112,63 @@ func (s *Service) EditPassword(ctx context.Context, id int64, oldPassword, newPa
 	user.Password = hashedPassword
 	return s.userRepo.Update(ctx, &user)
 }
+
+// PasswordResetToken issues a token that lets the user set a new password
+// without the current one. An administrator hands it to the user, it stops
+// working once the password changed or after PasswordResetTTL.
+func (s *Service) PasswordResetToken(ctx context.Context, userID int64) (string, time.Time, error) {
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return "", time.Time{}, domain.ErrUserNotFound
+	}
+	expiresAt := time.Now().Add(PasswordResetTTL)
+	claims := jwt.MapClaims{
+		"purpose":  passwordResetPurpose,
+		"user_id":  user.ID,
+		"password": passwordVersion(user.Password),
+		"exp":      expiresAt.Unix(),
+		"iat":      time.Now().Unix(),
+	}
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString(s.jwtSecret)
+	return token, expiresAt, err
+}
+
+// ResetPassword sets the password of the user a reset token was issued for,
+// the token can only be used once
+func (s *Service) ResetPassword(ctx context.Context, token, newPassword string) error {
+	if newPassword == "" {
+		return domain.ErrBadParamInput
+	}
+	claims := jwt.MapClaims{}
+	_, err := jwt.ParseWithClaims(token, claims, func(*jwt.Token) (any, error) {
+		return s.jwtSecret, nil
+	}, jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}), jwt.WithExpirationRequired())
+	if err != nil || claims["purpose"] != passwordResetPurpose {
+		return domain.ErrInvalidCredentials
+	}
+	userID, ok := claims["user_id"].(float64)
+	if !ok {
+		return domain.ErrInvalidCredentials
+	}
+	user, err := s.userRepo.GetByID(ctx, int64(userID))
+	if err != nil {
+		return domain.ErrInvalidCredentials
+	}
+	// a token issued before the last password change is spent
+	if claims["password"] != passwordVersion(user.Password) {
+		return domain.ErrInvalidCredentials
+	}
+
+	hashedPassword, err := hashPassword(newPassword)
+	if err != nil {
+		return err
+	}
+	user.Password = hashedPassword
+	return s.userRepo.Update(ctx, &user)
+}
+
+// passwordVersion identifies the stored password without revealing its hash
+func passwordVersion(hash string) string {
+	sum := sha256.Sum256([]byte(hash))
+	return hex.EncodeToString(sum[:8])
+}
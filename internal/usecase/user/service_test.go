@@ -0,0 +1,81 @@
+package user
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bxcodec/go-clean-arch/domain"
+)
+
+type fakeUserRepo struct {
+	domain.UserRepository
+	users map[int64]domain.User
+}
+
+func (f *fakeUserRepo) GetByID(_ context.Context, id int64) (domain.User, error) {
+	u, ok := f.users[id]
+	if !ok {
+		return domain.User{}, domain.ErrNotFound
+	}
+	return u, nil
+}
+
+func (f *fakeUserRepo) GetByUsername(_ context.Context, username string) (domain.User, error) {
+	for _, u := range f.users {
+		if u.Username == username {
+			return u, nil
+		}
+	}
+	return domain.User{}, domain.ErrNotFound
+}
+
+func (f *fakeUserRepo) Update(_ context.Context, u *domain.User) error {
+	f.users[u.ID] = *u
+	return nil
+}
+
+func TestResetPasswordOfImportedUser(t *testing.T) {
+	repo := &fakeUserRepo{users: map[int64]domain.User{4: {ID: 4, Username: "ann", Password: domain.UnusablePassword}}}
+	s := NewService(repo, []byte("secret"), time.Hour)
+	ctx := context.Background()
+
+	// nothing signs in with the placeholder
+	_, err := s.Login(ctx, "ann", domain.UnusablePassword)
+	assert.Error(t, err)
+
+	token, expiresAt, err := s.PasswordResetToken(ctx, 4)
+	require.NoError(t, err)
+	assert.WithinDuration(t, time.Now().Add(PasswordResetTTL), expiresAt, time.Minute)
+
+	require.NoError(t, s.ResetPassword(ctx, token, "new secret"))
+	assert.True(t, checkPasswordHash("new secret", repo.users[4].Password))
+
+	// the token is spent once the password changed
+	assert.ErrorIs(t, s.ResetPassword(ctx, token, "again"), domain.ErrInvalidCredentials)
+}
+
+func TestResetPasswordRejectsOtherTokens(t *testing.T) {
+	repo := &fakeUserRepo{users: map[int64]domain.User{4: {ID: 4, Username: "ann", Password: domain.UnusablePassword}}}
+	s := NewService(repo, []byte("secret"), time.Hour)
+	ctx := context.Background()
+
+	login, err := s.generateJWT(4, "ann")
+	require.NoError(t, err)
+	assert.ErrorIs(t, s.ResetPassword(ctx, login, "new secret"), domain.ErrInvalidCredentials)
+
+	foreign, _, err := NewService(repo, []byte("other"), time.Hour).PasswordResetToken(ctx, 4)
+	require.NoError(t, err)
+	assert.ErrorIs(t, s.ResetPassword(ctx, foreign, "new secret"), domain.ErrInvalidCredentials)
+
+	token, _, err := s.PasswordResetToken(ctx, 4)
+	require.NoError(t, err)
+	assert.ErrorIs(t, s.ResetPassword(ctx, token, ""), domain.ErrBadParamInput)
+	assert.Equal(t, domain.UnusablePassword, repo.users[4].Password)
+
+	_, _, err = s.PasswordResetToken(ctx, 5)
+	assert.ErrorIs(t, err, domain.ErrUserNotFound)
+}
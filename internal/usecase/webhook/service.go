@@ -0,0 +1,433 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"syscall"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/bxcodec/go-clean-arch/domain"
+	"github.com/bxcodec/go-clean-arch/internal/events"
+)
+
+const (
+	HeaderSignature = "X-Webhook-Signature"
+	HeaderTimestamp = "X-Webhook-Timestamp"
+	HeaderDelivery  = "X-Webhook-Delivery"
+	HeaderEvent     = "X-Webhook-Event"
+
+	maxAttempts     = 8
+	baseBackoff     = 30 * time.Second
+	maxBackoff      = 6 * time.Hour
+	deliveryTimeout = 10 * time.Second
+	dueBatchSize    = 50
+)
+
+// errPrivateAddress is returned when a webhook URL points into a private network
+var errPrivateAddress = errors.New("webhook address is not public")
+
+type Service struct {
+	repo         domain.WebhookRepository
+	contribRepo  domain.ContributorRepository
+	client       *http.Client
+	allowPrivate bool
+}
+
+// NewService will create a new webhook service object. Unless allowPrivate is set,
+// which is meant for local development, webhooks may only target public addresses:
+// URLs are checked when subscribing and every connection is checked again when it
+// is dialed, so a host that later resolves to a private address is refused too.
+func NewService(r domain.WebhookRepository, c domain.ContributorRepository, allowPrivate bool) *Service {
+	dialer := &net.Dialer{Timeout: deliveryTimeout}
+	if !allowPrivate {
+		dialer.Control = func(_, address string, _ syscall.RawConn) error {
+			host, _, err := net.SplitHostPort(address)
+			if err != nil {
+				return err
+			}
+			if ip := net.ParseIP(host); ip == nil || !publicIP(ip) {
+				return errPrivateAddress
+			}
+			return nil
+		}
+	}
+	return &Service{
+		repo:        r,
+		contribRepo: c,
+		client: &http.Client{
+			Timeout: deliveryTimeout,
+			// no proxy from the environment, the dialer must see the receiver address
+			Transport: &http.Transport{
+				DialContext:         dialer.DialContext,
+				TLSHandshakeTimeout: deliveryTimeout,
+				MaxIdleConnsPerHost: 2,
+			},
+		},
+		allowPrivate: allowPrivate,
+	}
+}
+
+// Sign returns the signature sent in the X-Webhook-Signature header.
+// Receivers recompute it over "<timestamp>.<body>" with their secret and compare.
+func Sign(secret, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Subscribe stores a new subscription for s.UserID. A secret is generated when none is given.
+func (w *Service) Subscribe(ctx context.Context, s *domain.WebhookSubscription) error {
+	if err := w.validate(ctx, s); err != nil {
+		return err
+	}
+	if s.Secret == "" {
+		secret, err := randomHex(24)
+		if err != nil {
+			return err
+		}
+		s.Secret = secret
+	}
+	s.Active = true
+	return w.repo.StoreSubscription(ctx, s)
+}
+
+func (w *Service) ListSubscriptions(ctx context.Context, userID int64) ([]domain.WebhookSubscription, error) {
+	return w.repo.ListSubscriptions(ctx, userID)
+}
+
+// GetSubscription returns the subscription if it belongs to userID
+func (w *Service) GetSubscription(ctx context.Context, userID, id int64) (domain.WebhookSubscription, error) {
+	sub, err := w.repo.GetSubscription(ctx, id)
+	if err != nil {
+		return domain.WebhookSubscription{}, err
+	}
+	if sub.UserID != userID {
+		// do not reveal subscriptions of other users
+		return domain.WebhookSubscription{}, domain.ErrNotFound
+	}
+	return sub, nil
+}
+
+// UpdateSubscription replaces the URL, event filter and active flag of a subscription owned by s.UserID
+func (w *Service) UpdateSubscription(ctx context.Context, s *domain.WebhookSubscription) error {
+	if err := w.validate(ctx, s); err != nil {
+		return err
+	}
+	existing, err := w.GetSubscription(ctx, s.UserID, s.ID)
+	if err != nil {
+		return err
+	}
+	existing.URL = s.URL
+	existing.Events = s.Events
+	existing.Active = s.Active
+	existing.UpdatedAt = time.Now()
+	if err := w.repo.UpdateSubscription(ctx, &existing); err != nil {
+		return err
+	}
+	*s = existing
+	return nil
+}
+
+func (w *Service) Unsubscribe(ctx context.Context, userID, id int64) error {
+	if _, err := w.GetSubscription(ctx, userID, id); err != nil {
+		return err
+	}
+	return w.repo.DeleteSubscription(ctx, id)
+}
+
+func (w *Service) ListDeliveries(ctx context.Context, userID, subscriptionID int64, num int64) ([]domain.WebhookDelivery, error) {
+	if _, err := w.GetSubscription(ctx, userID, subscriptionID); err != nil {
+		return nil, err
+	}
+	return w.repo.ListDeliveries(ctx, subscriptionID, num)
+}
+
+// GetDelivery returns a delivery of a subscription owned by userID together with its attempts
+func (w *Service) GetDelivery(ctx context.Context, userID, subscriptionID int64, id string) (domain.WebhookDelivery, []domain.WebhookAttempt, error) {
+	d, err := w.ownedDelivery(ctx, userID, subscriptionID, id)
+	if err != nil {
+		return domain.WebhookDelivery{}, nil, err
+	}
+	attempts, err := w.repo.ListAttempts(ctx, id)
+	if err != nil {
+		return domain.WebhookDelivery{}, nil, err
+	}
+	return d, attempts, nil
+}
+
+// Replay schedules a failed delivery to be sent again right away with a fresh retry budget
+func (w *Service) Replay(ctx context.Context, userID, subscriptionID int64, id string) (domain.WebhookDelivery, error) {
+	d, err := w.ownedDelivery(ctx, userID, subscriptionID, id)
+	if err != nil {
+		return domain.WebhookDelivery{}, err
+	}
+	if d.Status != domain.WebhookDeliveryFailed {
+		return domain.WebhookDelivery{}, domain.ErrConflict
+	}
+	now := time.Now()
+	d.Status = domain.WebhookDeliveryPending
+	d.Attempts = 0
+	d.NextAttemptAt = now
+	d.UpdatedAt = now
+	if err := w.repo.UpdateDelivery(ctx, &d); err != nil {
+		return domain.WebhookDelivery{}, err
+	}
+	return d, nil
+}
+
+func (w *Service) ownedDelivery(ctx context.Context, userID, subscriptionID int64, id string) (domain.WebhookDelivery, error) {
+	if _, err := w.GetSubscription(ctx, userID, subscriptionID); err != nil {
+		return domain.WebhookDelivery{}, err
+	}
+	d, err := w.repo.GetDelivery(ctx, id)
+	if err != nil {
+		return domain.WebhookDelivery{}, err
+	}
+	if d.SubscriptionID != subscriptionID {
+		return domain.WebhookDelivery{}, domain.ErrNotFound
+	}
+	return d, nil
+}
+
+func (w *Service) Name() string {
+	return "webhooks"
+}
+
+// Publish implements domain.EventSink. It fans the event out into one pending
+// delivery per matching subscription; DeliverDue sends them. The payload carries
+// the article, so events of articles that are not listed only go to the
+// subscriptions of the article contributors.
+func (w *Service) Publish(ctx context.Context, event domain.ArticleEvent) error {
+	subs, err := w.repo.ListActiveSubscriptions(ctx)
+	if err != nil {
+		return err
+	}
+
+	payload, err := json.Marshal(events.NewMessage(&event))
+	if err != nil {
+		return err
+	}
+	now := time.Now()
+	var deliveries []domain.WebhookDelivery
+	for i := range subs {
+		if !subs[i].Matches(event.Type) {
+			continue
+		}
+		visible, err := w.canSee(ctx, &event.Article, subs[i].UserID)
+		if err != nil {
+			return err
+		}
+		if !visible {
+			continue
+		}
+		id, err := randomHex(16)
+		if err != nil {
+			return err
+		}
+		deliveries = append(deliveries, domain.WebhookDelivery{
+			ID:             id,
+			SubscriptionID: subs[i].ID,
+			EventID:        event.ID,
+			EventType:      event.Type,
+			Payload:        string(payload),
+			Status:         domain.WebhookDeliveryPending,
+			NextAttemptAt:  now,
+			CreatedAt:      now,
+			UpdatedAt:      now,
+		})
+	}
+	return w.repo.StoreDeliveries(ctx, deliveries)
+}
+
+// canSee reports whether the subscriber may receive events of the article
+func (w *Service) canSee(ctx context.Context, ar *domain.Article, userID int64) (bool, error) {
+	if ar.Listed() || ar.User.ID == userID {
+		return true, nil
+	}
+	role, err := w.contribRepo.Role(ctx, ar.ID, userID)
+	if err != nil {
+		return false, err
+	}
+	return role != "", nil
+}
+
+// DeliverDue sends every delivery whose next attempt is due
+func (w *Service) DeliverDue(ctx context.Context) error {
+	due, err := w.repo.FetchDueDeliveries(ctx, time.Now(), dueBatchSize)
+	if err != nil {
+		return fmt.Errorf("failed to fetch due deliveries: %w", err)
+	}
+
+	subs := make(map[int64]domain.WebhookSubscription)
+	for i := range due {
+		sub, ok := subs[due[i].SubscriptionID]
+		if !ok {
+			sub, err = w.repo.GetSubscription(ctx, due[i].SubscriptionID)
+			if errors.Is(err, domain.ErrNotFound) {
+				// the subscription was removed, give up on its deliveries
+				due[i].Status = domain.WebhookDeliveryFailed
+				due[i].UpdatedAt = time.Now()
+				if err := w.repo.UpdateDelivery(ctx, &due[i]); err != nil {
+					logrus.Warnf("failed to fail webhook delivery %s: %v", due[i].ID, err)
+				}
+				continue
+			}
+			if err != nil {
+				logrus.Warnf("failed to load webhook subscription %d: %v", due[i].SubscriptionID, err)
+				continue
+			}
+			subs[sub.ID] = sub
+		}
+		if err := w.deliver(ctx, &sub, &due[i]); err != nil {
+			logrus.Warnf("failed to record webhook delivery %s: %v", due[i].ID, err)
+		}
+	}
+	return nil
+}
+
+// deliver makes one attempt and schedules the next one with exponential backoff on failure
+func (w *Service) deliver(ctx context.Context, sub *domain.WebhookSubscription, d *domain.WebhookDelivery) error {
+	started := time.Now()
+	code, sendErr := w.send(ctx, sub, d)
+
+	attempt := domain.WebhookAttempt{
+		DeliveryID:  d.ID,
+		StatusCode:  code,
+		Duration:    time.Since(started),
+		AttemptedAt: started,
+	}
+	if sendErr != nil {
+		attempt.Error = sendErr.Error()
+	}
+	if err := w.repo.StoreAttempt(ctx, &attempt); err != nil {
+		return err
+	}
+
+	d.Attempts++
+	d.UpdatedAt = time.Now()
+	switch {
+	case sendErr == nil:
+		d.Status = domain.WebhookDeliverySucceeded
+	case d.Attempts >= maxAttempts || !sub.Active:
+		d.Status = domain.WebhookDeliveryFailed
+	default:
+		d.NextAttemptAt = d.UpdatedAt.Add(backoff(d.Attempts))
+	}
+	return w.repo.UpdateDelivery(ctx, d)
+}
+
+func (w *Service) send(ctx context.Context, sub *domain.WebhookSubscription, d *domain.WebhookDelivery) (int, error) {
+	if !sub.Active {
+		return 0, fmt.Errorf("subscription %d is inactive", sub.ID)
+	}
+	body := []byte(d.Payload)
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, sub.URL, bytes.NewReader(body))
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(HeaderTimestamp, timestamp)
+	req.Header.Set(HeaderDelivery, d.ID)
+	req.Header.Set(HeaderEvent, string(d.EventType))
+	req.Header.Set(HeaderSignature, Sign(sub.Secret, timestamp, body))
+
+	resp, err := w.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 1<<16))
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return resp.StatusCode, fmt.Errorf("receiver responded with status %d", resp.StatusCode)
+	}
+	return resp.StatusCode, nil
+}
+
+func backoff(attempt int) time.Duration {
+	d := baseBackoff
+	for i := 1; i < attempt; i++ {
+		d *= 2
+		if d >= maxBackoff {
+			return maxBackoff
+		}
+	}
+	return d
+}
+
+func (w *Service) validate(ctx context.Context, s *domain.WebhookSubscription) error {
+	u, err := url.Parse(s.URL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Hostname() == "" {
+		return domain.ErrBadParamInput
+	}
+	if !w.allowPrivate {
+		if err := checkPublicHost(ctx, u.Hostname()); err != nil {
+			return err
+		}
+	}
+	for _, e := range s.Events {
+		switch e {
+		case domain.ArticleCreated, domain.ArticleUpdated, domain.ArticleDeleted, domain.ArticlePublished:
+		default:
+			return domain.ErrBadParamInput
+		}
+	}
+	return nil
+}
+
+// checkPublicHost resolves the host and rejects it unless all its addresses are public
+func checkPublicHost(ctx context.Context, host string) error {
+	if ip := net.ParseIP(host); ip != nil {
+		if !publicIP(ip) {
+			return domain.ErrBadParamInput
+		}
+		return nil
+	}
+	addrs, err := net.DefaultResolver.LookupIPAddr(ctx, host)
+	if err != nil || len(addrs) == 0 {
+		return domain.ErrBadParamInput
+	}
+	for _, addr := range addrs {
+		if !publicIP(addr.IP) {
+			return domain.ErrBadParamInput
+		}
+	}
+	return nil
+}
+
+// publicIP reports whether ip is routable on the internet, as opposed to
+// loopback, private, link-local (cloud metadata endpoints) and similar ranges
+func publicIP(ip net.IP) bool {
+	return !ip.IsLoopback() && !ip.IsPrivate() && !ip.IsLinkLocalUnicast() &&
+		!ip.IsLinkLocalMulticast() && !ip.IsInterfaceLocalMulticast() &&
+		!ip.IsMulticast() && !ip.IsUnspecified() && !sharedAddressSpace.Contains(ip)
+}
+
+// sharedAddressSpace is the carrier-grade NAT range, not routable on the internet either
+var sharedAddressSpace = &net.IPNet{IP: net.IPv4(100, 64, 0, 0), Mask: net.CIDRMask(10, 32)}
+
+func randomHex(n int) (string, error) {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
@@ -0,0 +1,361 @@
+package webhook_test
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bxcodec/go-clean-arch/domain"
+	"github.com/bxcodec/go-clean-arch/internal/usecase/webhook"
+)
+
+// memoryRepo is an in-memory domain.WebhookRepository
+type memoryRepo struct {
+	mu         sync.Mutex
+	subs       map[int64]domain.WebhookSubscription
+	deliveries map[string]domain.WebhookDelivery
+	attempts   []domain.WebhookAttempt
+}
+
+func newMemoryRepo() *memoryRepo {
+	return &memoryRepo{
+		subs:       map[int64]domain.WebhookSubscription{},
+		deliveries: map[string]domain.WebhookDelivery{},
+	}
+}
+
+func (r *memoryRepo) StoreSubscription(_ context.Context, s *domain.WebhookSubscription) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	s.ID = int64(len(r.subs) + 1)
+	r.subs[s.ID] = *s
+	return nil
+}
+
+func (r *memoryRepo) GetSubscription(_ context.Context, id int64) (domain.WebhookSubscription, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	s, ok := r.subs[id]
+	if !ok {
+		return s, domain.ErrNotFound
+	}
+	return s, nil
+}
+
+func (r *memoryRepo) ListSubscriptions(_ context.Context, userID int64) ([]domain.WebhookSubscription, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var res []domain.WebhookSubscription
+	for _, s := range r.subs {
+		if s.UserID == userID {
+			res = append(res, s)
+		}
+	}
+	return res, nil
+}
+
+func (r *memoryRepo) ListActiveSubscriptions(_ context.Context) ([]domain.WebhookSubscription, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var res []domain.WebhookSubscription
+	for _, s := range r.subs {
+		if s.Active {
+			res = append(res, s)
+		}
+	}
+	return res, nil
+}
+
+func (r *memoryRepo) UpdateSubscription(_ context.Context, s *domain.WebhookSubscription) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.subs[s.ID] = *s
+	return nil
+}
+
+func (r *memoryRepo) DeleteSubscription(_ context.Context, id int64) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.subs, id)
+	return nil
+}
+
+func (r *memoryRepo) StoreDeliveries(_ context.Context, ds []domain.WebhookDelivery) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, d := range ds {
+		duplicate := false
+		for _, existing := range r.deliveries {
+			if existing.SubscriptionID == d.SubscriptionID && existing.EventID == d.EventID {
+				duplicate = true
+			}
+		}
+		if !duplicate {
+			r.deliveries[d.ID] = d
+		}
+	}
+	return nil
+}
+
+func (r *memoryRepo) GetDelivery(_ context.Context, id string) (domain.WebhookDelivery, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	d, ok := r.deliveries[id]
+	if !ok {
+		return d, domain.ErrNotFound
+	}
+	return d, nil
+}
+
+func (r *memoryRepo) ListDeliveries(_ context.Context, subscriptionID int64, _ int64) ([]domain.WebhookDelivery, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var res []domain.WebhookDelivery
+	for _, d := range r.deliveries {
+		if d.SubscriptionID == subscriptionID {
+			res = append(res, d)
+		}
+	}
+	return res, nil
+}
+
+func (r *memoryRepo) FetchDueDeliveries(_ context.Context, now time.Time, _ int) ([]domain.WebhookDelivery, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var res []domain.WebhookDelivery
+	for _, d := range r.deliveries {
+		if d.Status == domain.WebhookDeliveryPending && !d.NextAttemptAt.After(now) {
+			res = append(res, d)
+		}
+	}
+	return res, nil
+}
+
+func (r *memoryRepo) UpdateDelivery(_ context.Context, d *domain.WebhookDelivery) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.deliveries[d.ID] = *d
+	return nil
+}
+
+func (r *memoryRepo) StoreAttempt(_ context.Context, a *domain.WebhookAttempt) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.attempts = append(r.attempts, *a)
+	return nil
+}
+
+func (r *memoryRepo) ListAttempts(_ context.Context, deliveryID string) ([]domain.WebhookAttempt, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var res []domain.WebhookAttempt
+	for _, a := range r.attempts {
+		if a.DeliveryID == deliveryID {
+			res = append(res, a)
+		}
+	}
+	return res, nil
+}
+
+// contributors maps article ids to the users contributing to them
+type contributors map[int64][]int64
+
+func (c contributors) FetchByArticles(context.Context, []int64) (map[int64][]domain.Contributor, error) {
+	return nil, nil
+}
+
+func (c contributors) Role(_ context.Context, articleID, userID int64) (domain.ContributorRole, error) {
+	for _, id := range c[articleID] {
+		if id == userID {
+			return domain.ContributorEditor, nil
+		}
+	}
+	return "", nil
+}
+
+func (c contributors) Save(context.Context, int64, int64, domain.ContributorRole) error {
+	return nil
+}
+
+func (c contributors) Remove(context.Context, int64, int64) error {
+	return nil
+}
+
+type received struct {
+	header http.Header
+	body   []byte
+}
+
+func newReceiver(t *testing.T, status *int) (*httptest.Server, chan received) {
+	ch := make(chan received, 10)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		ch <- received{header: r.Header.Clone(), body: body}
+		w.WriteHeader(*status)
+	}))
+	t.Cleanup(srv.Close)
+	return srv, ch
+}
+
+func TestDeliverSignedPayload(t *testing.T) {
+	status := http.StatusOK
+	srv, ch := newReceiver(t, &status)
+	repo := newMemoryRepo()
+	svc := webhook.NewService(repo, contributors{}, true)
+	ctx := context.Background()
+
+	sub := domain.WebhookSubscription{UserID: 1, URL: srv.URL, Events: []domain.ArticleEventType{domain.ArticlePublished}}
+	require.NoError(t, svc.Subscribe(ctx, &sub))
+	require.NotEmpty(t, sub.Secret)
+
+	event := domain.ArticleEvent{
+		ID:        42,
+		Type:      domain.ArticlePublished,
+		ArticleID: 7,
+		Article:   domain.Article{ID: 7, Title: "Hello", Status: domain.ArticleStatusPublished},
+	}
+	require.NoError(t, svc.Publish(ctx, event))
+	// the outbox may publish the same event twice
+	require.NoError(t, svc.Publish(ctx, event))
+	// filtered out by the subscription
+	require.NoError(t, svc.Publish(ctx, domain.ArticleEvent{ID: 43, Type: domain.ArticleUpdated, ArticleID: 7}))
+
+	require.NoError(t, svc.DeliverDue(ctx))
+
+	got := <-ch
+	assert.Empty(t, ch)
+	timestamp := got.header.Get(webhook.HeaderTimestamp)
+	assert.Equal(t, webhook.Sign(sub.Secret, timestamp, got.body), got.header.Get(webhook.HeaderSignature))
+	assert.Equal(t, string(domain.ArticlePublished), got.header.Get(webhook.HeaderEvent))
+	assert.NotEmpty(t, got.header.Get(webhook.HeaderDelivery))
+
+	var payload map[string]any
+	require.NoError(t, json.Unmarshal(got.body, &payload))
+	assert.Equal(t, float64(7), payload["article_id"])
+
+	d, attempts, err := svc.GetDelivery(ctx, 1, sub.ID, got.header.Get(webhook.HeaderDelivery))
+	require.NoError(t, err)
+	assert.Equal(t, domain.WebhookDeliverySucceeded, d.Status)
+	assert.Len(t, attempts, 1)
+	assert.Equal(t, http.StatusOK, attempts[0].StatusCode)
+}
+
+func TestDeliverRetriesAndReplay(t *testing.T) {
+	status := http.StatusServiceUnavailable
+	srv, ch := newReceiver(t, &status)
+	repo := newMemoryRepo()
+	svc := webhook.NewService(repo, contributors{}, true)
+	ctx := context.Background()
+
+	sub := domain.WebhookSubscription{UserID: 1, URL: srv.URL}
+	require.NoError(t, svc.Subscribe(ctx, &sub))
+	require.NoError(t, svc.Publish(ctx, domain.ArticleEvent{ID: 1, Type: domain.ArticleDeleted, ArticleID: 3,
+		Article: domain.Article{ID: 3, Status: domain.ArticleStatusPublished}}))
+
+	require.NoError(t, svc.DeliverDue(ctx))
+	got := <-ch
+	id := got.header.Get(webhook.HeaderDelivery)
+
+	d, _, err := svc.GetDelivery(ctx, 1, sub.ID, id)
+	require.NoError(t, err)
+	assert.Equal(t, domain.WebhookDeliveryPending, d.Status)
+	assert.Equal(t, 1, d.Attempts)
+	assert.True(t, d.NextAttemptAt.After(time.Now()), "retry must be backed off")
+
+	// a pending delivery can not be replayed
+	_, err = svc.Replay(ctx, 1, sub.ID, id)
+	assert.ErrorIs(t, err, domain.ErrConflict)
+
+	d.Status = domain.WebhookDeliveryFailed
+	require.NoError(t, repo.UpdateDelivery(ctx, &d))
+
+	// other users can not see or replay the delivery
+	_, err = svc.Replay(ctx, 2, sub.ID, id)
+	assert.ErrorIs(t, err, domain.ErrNotFound)
+
+	status = http.StatusNoContent
+	_, err = svc.Replay(ctx, 1, sub.ID, id)
+	require.NoError(t, err)
+	require.NoError(t, svc.DeliverDue(ctx))
+	<-ch
+
+	d, attempts, err := svc.GetDelivery(ctx, 1, sub.ID, id)
+	require.NoError(t, err)
+	assert.Equal(t, domain.WebhookDeliverySucceeded, d.Status)
+	assert.Len(t, attempts, 2)
+}
+
+func TestPublishHidesUnlistedArticles(t *testing.T) {
+	repo := newMemoryRepo()
+	svc := webhook.NewService(repo, contributors{7: {3}}, true)
+	ctx := context.Background()
+
+	subs := make(map[int64]int64)
+	for _, userID := range []int64{1, 2, 3} {
+		sub := domain.WebhookSubscription{UserID: userID, URL: "http://127.0.0.1:1"}
+		require.NoError(t, svc.Subscribe(ctx, &sub))
+		subs[sub.ID] = userID
+	}
+
+	draft := domain.Article{ID: 7, User: domain.User{ID: 1}, Status: domain.ArticleStatusDraft}
+	require.NoError(t, svc.Publish(ctx, domain.ArticleEvent{ID: 1, Type: domain.ArticleUpdated, ArticleID: 7, Article: draft}))
+
+	var got []int64
+	for _, d := range repo.deliveries {
+		got = append(got, subs[d.SubscriptionID])
+	}
+	// the author and the contributor, not the stranger
+	assert.ElementsMatch(t, []int64{1, 3}, got)
+}
+
+func TestSubscribeRejectsPrivateAddresses(t *testing.T) {
+	svc := webhook.NewService(newMemoryRepo(), contributors{}, false)
+	ctx := context.Background()
+
+	for _, url := range []string{
+		"http://127.0.0.1/hook",
+		"http://localhost:8080/hook",
+		"http://10.1.2.3/hook",
+		"http://192.168.0.10/hook",
+		"http://169.254.169.254/latest/meta-data",
+		"http://[::1]/hook",
+		"http://[fe80::1]/hook",
+		"http://0.0.0.0/hook",
+		"ftp://93.184.215.14/hook",
+	} {
+		sub := domain.WebhookSubscription{UserID: 1, URL: url}
+		assert.ErrorIs(t, svc.Subscribe(ctx, &sub), domain.ErrBadParamInput, url)
+	}
+
+	sub := domain.WebhookSubscription{UserID: 1, URL: "https://93.184.215.14/hook"}
+	assert.NoError(t, svc.Subscribe(ctx, &sub))
+}
+
+func TestDeliverRefusesPrivateAddressesAtDialTime(t *testing.T) {
+	status := http.StatusOK
+	srv, ch := newReceiver(t, &status)
+	repo := newMemoryRepo()
+	svc := webhook.NewService(repo, contributors{}, false)
+	ctx := context.Background()
+
+	// as if the host resolved to a public address when subscribing and was rebound since
+	sub := domain.WebhookSubscription{UserID: 1, URL: srv.URL, Active: true}
+	require.NoError(t, repo.StoreSubscription(ctx, &sub))
+	event := domain.ArticleEvent{ID: 1, Type: domain.ArticlePublished, ArticleID: 7,
+		Article: domain.Article{ID: 7, Status: domain.ArticleStatusPublished}}
+	require.NoError(t, svc.Publish(ctx, event))
+
+	require.NoError(t, svc.DeliverDue(ctx))
+
+	assert.Empty(t, ch)
+	require.Len(t, repo.attempts, 1)
+	assert.Contains(t, repo.attempts[0].Error, "not public")
+}
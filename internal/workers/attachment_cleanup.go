@@ -0,0 +1,58 @@
+package workers
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+const attachmentCleanupBatch = 100
+
+type AttachmentCleaner interface {
+	CleanupOrphans(ctx context.Context, limit int) (int, error)
+}
+
+// AttachmentCleanupWorker deletes uploads that were never linked to an article
+// or whose article was deleted
+type AttachmentCleanupWorker struct {
+	Cleaner AttachmentCleaner
+}
+
+func NewAttachmentCleanupWorker(c AttachmentCleaner) *AttachmentCleanupWorker {
+	return &AttachmentCleanupWorker{
+		Cleaner: c,
+	}
+}
+
+// Job deletes orphaned attachments hourly in batches, each batch deletes the
+// files before the rows so that a failed run is retried by the next one
+func (w *AttachmentCleanupWorker) Job() Job {
+	return Job{
+		Name:     "attachment-cleanup",
+		Interval: 1 * time.Hour,
+		Jitter:   5 * time.Minute,
+		Timeout:  10 * time.Minute,
+		Mode:     LeaderOnly,
+		Run:      w.cleanup,
+	}
+}
+
+func (w *AttachmentCleanupWorker) cleanup(ctx context.Context) error {
+	var total int
+	for {
+		n, err := w.Cleaner.CleanupOrphans(ctx, attachmentCleanupBatch)
+		if err != nil {
+			return fmt.Errorf("failed to clean up attachments: %w", err)
+		}
+		total += n
+		if n < attachmentCleanupBatch {
+			break
+		}
+	}
+	if total > 0 {
+		logrus.Infof("deleted %d orphaned attachments", total)
+	}
+	return nil
+}
@@ -0,0 +1,51 @@
+package workers
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+const contentRenderBatch = 100
+
+type ContentRenderer interface {
+	RenderPending(ctx context.Context, limit int) (int, error)
+}
+
+// ContentRenderWorker renders the articles stored before their content was
+// rendered or their metadata computed, until none are left
+type ContentRenderWorker struct {
+	Renderer ContentRenderer
+}
+
+func NewContentRenderWorker(r ContentRenderer) *ContentRenderWorker {
+	return &ContentRenderWorker{
+		Renderer: r,
+	}
+}
+
+// Job renders a batch of pending articles every minute, once everything is
+// rendered a run is a single query
+func (w *ContentRenderWorker) Job() Job {
+	return Job{
+		Name:     "article-content-render",
+		Interval: 1 * time.Minute,
+		Jitter:   10 * time.Second,
+		Timeout:  2 * time.Minute,
+		Mode:     LeaderOnly,
+		Run:      w.render,
+	}
+}
+
+func (w *ContentRenderWorker) render(ctx context.Context) error {
+	n, err := w.Renderer.RenderPending(ctx, contentRenderBatch)
+	if err != nil {
+		return fmt.Errorf("failed to render articles: %w", err)
+	}
+	if n > 0 {
+		logrus.Infof("rendered %d articles", n)
+	}
+	return nil
+}
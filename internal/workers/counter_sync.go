@@ -0,0 +1,73 @@
+package workers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/bxcodec/go-clean-arch/domain"
+)
+
+const (
+	// maxSyncAttempts is how many times a counter batch is synced before it is
+	// dead-lettered, so that a batch that can never be applied stops blocking the newer ones
+	maxSyncAttempts = 30
+	// syncedBatchRetention is how long applied batches are remembered to skip
+	// redeliveries, far longer than a batch stays pending
+	syncedBatchRetention = 7 * 24 * time.Hour
+)
+
+// counterStore wires the Redis buffer and the database table of a counter
+// (views, reactions) into syncCounters
+type counterStore[K comparable] struct {
+	// Fetch returns the pending batch, a batch left behind by a failed sync first
+	Fetch func(ctx context.Context) (batchID string, deltas map[K]int64, err error)
+	// Apply writes the batch to the database at most once per batch id
+	Apply func(ctx context.Context, batchID string, deltas map[K]int64, fencingToken int64) error
+	// Fail counts a failed sync and reports whether the batch was dead-lettered
+	Fail func(ctx context.Context, batchID string, maxAttempts int) (bool, error)
+	// Ack drops the synced deltas from the buffer
+	Ack func(ctx context.Context, batchID string, keys []K) error
+}
+
+// syncCounters moves one batch of buffered counter deltas to the database and
+// reports whether the buffer was already drained. The batch stays in Redis until
+// it is acknowledged, so a failed or interrupted sync is retried with the same
+// batch id on the next run, until it is dead-lettered after maxSyncAttempts.
+func syncCounters[K comparable](ctx context.Context, name string, fencingToken int64, store counterStore[K]) (bool, error) {
+	batchID, deltas, err := store.Fetch(ctx)
+	if err != nil {
+		return false, fmt.Errorf("failed to get %s from redis: %w", name, err)
+	}
+	if len(deltas) == 0 {
+		return true, nil
+	}
+
+	err = store.Apply(ctx, batchID, deltas, fencingToken)
+	if errors.Is(err, domain.ErrStaleLeader) {
+		// the batch belongs to the new leader now
+		return false, fmt.Errorf("failed to update %s: %w", name, err)
+	}
+	if err != nil {
+		dead, failErr := store.Fail(ctx, batchID, maxSyncAttempts)
+		if failErr != nil {
+			logrus.Warnf("failed to count failed %s batch %s: %v", name, batchID, failErr)
+		}
+		if dead {
+			logrus.Errorf("dead-lettered %s batch %s after %d attempts", name, batchID, maxSyncAttempts)
+		}
+		return false, fmt.Errorf("failed to update %s: %w", name, err)
+	}
+
+	keys := make([]K, 0, len(deltas))
+	for key := range deltas {
+		keys = append(keys, key)
+	}
+	if err := store.Ack(ctx, batchID, keys); err != nil {
+		logrus.Warnf("failed to ack %s batch %s: %v", name, batchID, err)
+	}
+	return false, nil
+}
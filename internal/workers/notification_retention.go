@@ -0,0 +1,66 @@
+package workers
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/bxcodec/go-clean-arch/domain"
+)
+
+const (
+	// DefaultReadNotificationRetention is how long read notifications are kept
+	DefaultReadNotificationRetention = 30 * 24 * time.Hour
+	// DefaultNotificationRetention is how long unread notifications are kept
+	DefaultNotificationRetention = 90 * 24 * time.Hour
+
+	notificationPurgeBatch = 1000
+)
+
+type NotificationRetentionWorker struct {
+	NotificationRepo domain.NotificationRepository
+	ReadRetention    time.Duration
+	Retention        time.Duration
+}
+
+func NewNotificationRetentionWorker(nr domain.NotificationRepository, readRetention, retention time.Duration) *NotificationRetentionWorker {
+	return &NotificationRetentionWorker{
+		NotificationRepo: nr,
+		ReadRetention:    readRetention,
+		Retention:        retention,
+	}
+}
+
+// Job deletes expired notifications in batches so that a large backlog does not
+// hold long locks
+func (w *NotificationRetentionWorker) Job() Job {
+	return Job{
+		Name:     "notification-retention",
+		Interval: 1 * time.Hour,
+		Jitter:   5 * time.Minute,
+		Timeout:  5 * time.Minute,
+		Mode:     LeaderOnly,
+		Run:      w.purge,
+	}
+}
+
+func (w *NotificationRetentionWorker) purge(ctx context.Context) error {
+	now := time.Now()
+	var total int64
+	for {
+		n, err := w.NotificationRepo.Purge(ctx, now.Add(-w.ReadRetention), now.Add(-w.Retention), notificationPurgeBatch)
+		if err != nil {
+			return fmt.Errorf("failed to purge notifications: %w", err)
+		}
+		total += n
+		if n < notificationPurgeBatch {
+			break
+		}
+	}
+	if total > 0 {
+		logrus.Infof("purged %d notifications", total)
+	}
+	return nil
+}
@@ -0,0 +1,153 @@
+package workers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/bxcodec/go-clean-arch/domain"
+)
+
+const (
+	outboxBatchSize = 100
+	outboxRetention = 7 * 24 * time.Hour
+	// maxOutboxAttempts is how many times an event is relayed before it is
+	// dead-lettered, so that an event no sink can ever accept stops blocking its article
+	maxOutboxAttempts = 12
+	outboxBaseBackoff = 2 * time.Second
+	outboxMaxBackoff  = 10 * time.Minute
+)
+
+// OutboxRelayWorker publishes article events recorded in the outbox to every sink.
+// Delivery is at-least-once: an event is marked published only after all sinks accepted it.
+// The sinks that accepted a failed event are recorded so its retries skip them.
+type OutboxRelayWorker struct {
+	Outbox domain.OutboxRepository
+	Fence  Fence
+	Sinks  []domain.EventSink
+}
+
+func NewOutboxRelayWorker(o domain.OutboxRepository, f Fence, sinks ...domain.EventSink) *OutboxRelayWorker {
+	return &OutboxRelayWorker{
+		Outbox: o,
+		Fence:  f,
+		Sinks:  sinks,
+	}
+}
+
+// Job relays pending events every two seconds. It is leader-only so that a
+// single relay keeps the events of each article in order.
+func (w *OutboxRelayWorker) Job() Job {
+	return Job{
+		Name:      "outbox-relay",
+		Interval:  2 * time.Second,
+		Timeout:   30 * time.Second,
+		Mode:      LeaderOnly,
+		RunOnStop: true,
+		Run:       w.relay,
+	}
+}
+
+func (w *OutboxRelayWorker) relay(ctx context.Context) error {
+	token, err := fencingToken(w.Fence)
+	if err != nil {
+		return err
+	}
+
+	events, err := w.Outbox.FetchPending(ctx, outboxBatchSize)
+	if err != nil {
+		return fmt.Errorf("failed to fetch outbox events: %w", err)
+	}
+
+	// once an event of an article fails, its later events wait for its retry
+	now := time.Now()
+	blocked := make(map[int64]bool)
+	published := make([]int64, 0, len(events))
+	for _, event := range events {
+		if blocked[event.ArticleID] {
+			continue
+		}
+		if event.RetryAt.After(now) {
+			blocked[event.ArticleID] = true
+			continue
+		}
+
+		err := w.publish(ctx, &event)
+		if err == nil {
+			published = append(published, event.ID)
+			continue
+		}
+		if err := w.fail(ctx, event, err, token); err != nil {
+			return err
+		}
+		if event.Attempts+1 < maxOutboxAttempts {
+			blocked[event.ArticleID] = true
+		}
+	}
+
+	if err := w.Outbox.MarkPublished(ctx, published, token); err != nil {
+		return fmt.Errorf("failed to mark outbox events as published: %w", err)
+	}
+	if len(events) < outboxBatchSize {
+		// only purge when the backlog is drained
+		if err := w.Outbox.Purge(ctx, time.Now().Add(-outboxRetention)); err != nil {
+			logrus.Warnf("failed to purge outbox: %v", err)
+		}
+	}
+	return nil
+}
+
+// publish hands the event to the sinks that did not accept it yet and adds
+// the ones that do to event.Delivered
+func (w *OutboxRelayWorker) publish(ctx context.Context, event *domain.ArticleEvent) error {
+	for _, sink := range w.Sinks {
+		if slices.Contains(event.Delivered, sink.Name()) {
+			continue
+		}
+		if err := sink.Publish(ctx, *event); err != nil {
+			return fmt.Errorf("sink %s: %w", sink.Name(), err)
+		}
+		event.Delivered = append(event.Delivered, sink.Name())
+	}
+	return nil
+}
+
+// fail schedules the retry of an event or dead-letters it after its last attempt.
+// Only an error from the outbox itself is returned.
+func (w *OutboxRelayWorker) fail(ctx context.Context, event domain.ArticleEvent, cause error, token int64) error {
+	attempts := event.Attempts + 1
+	if attempts >= maxOutboxAttempts {
+		logrus.Errorf("dead-lettered outbox event %d (%s) after %d attempts: %v", event.ID, event.Type, attempts, cause)
+		err := w.Outbox.DeadLetter(ctx, event, cause.Error(), token)
+		if err != nil {
+			return fmt.Errorf("failed to dead-letter outbox event %d: %w", event.ID, err)
+		}
+		return nil
+	}
+
+	logrus.Warnf("failed to publish outbox event %d (%s): %v", event.ID, event.Type, cause)
+	event.RetryAt = time.Now().Add(outboxBackoff(attempts))
+	if err := w.Outbox.MarkFailed(ctx, event, cause.Error(), token); err != nil {
+		if errors.Is(err, domain.ErrStaleLeader) {
+			return err
+		}
+		logrus.Warnf("failed to mark outbox event %d as failed: %v", event.ID, err)
+	}
+	return nil
+}
+
+// outboxBackoff doubles the delay before every retry of an event
+func outboxBackoff(attempts int) time.Duration {
+	d := outboxBaseBackoff
+	for i := 1; i < attempts; i++ {
+		d *= 2
+		if d >= outboxMaxBackoff {
+			return outboxMaxBackoff
+		}
+	}
+	return d
+}
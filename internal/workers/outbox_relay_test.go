@@ -0,0 +1,179 @@
+package workers
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bxcodec/go-clean-arch/domain"
+)
+
+type fakeOutbox struct {
+	pending   []domain.ArticleEvent
+	published []int64
+	failed    []domain.ArticleEvent
+	dead      []int64
+	token     int64
+}
+
+// FetchPending leaves out the events waiting for a retry like the repository
+func (f *fakeOutbox) FetchPending(_ context.Context, limit int) ([]domain.ArticleEvent, error) {
+	now := time.Now()
+	waiting := make(map[int64]bool)
+	var res []domain.ArticleEvent
+	for _, event := range f.pending {
+		if waiting[event.ArticleID] {
+			continue
+		}
+		if event.RetryAt.After(now) {
+			waiting[event.ArticleID] = true
+			continue
+		}
+		if len(res) < limit {
+			res = append(res, event)
+		}
+	}
+	return res, nil
+}
+
+func (f *fakeOutbox) MarkPublished(_ context.Context, ids []int64, token int64) error {
+	f.token = token
+	f.published = append(f.published, ids...)
+	return nil
+}
+
+func (f *fakeOutbox) MarkFailed(_ context.Context, event domain.ArticleEvent, _ string, _ int64) error {
+	f.failed = append(f.failed, event)
+	return nil
+}
+
+func (f *fakeOutbox) DeadLetter(_ context.Context, event domain.ArticleEvent, _ string, _ int64) error {
+	f.dead = append(f.dead, event.ID)
+	return nil
+}
+
+func (f *fakeOutbox) failedIDs() []int64 {
+	ids := make([]int64, 0, len(f.failed))
+	for _, event := range f.failed {
+		ids = append(ids, event.ID)
+	}
+	return ids
+}
+
+func (f *fakeOutbox) Purge(context.Context, time.Time) error {
+	return nil
+}
+
+type fakeSink struct {
+	name     string
+	failOn   map[int64]bool
+	received []int64
+}
+
+func (f *fakeSink) Name() string {
+	if f.name == "" {
+		return "fake"
+	}
+	return f.name
+}
+
+func (f *fakeSink) Publish(_ context.Context, event domain.ArticleEvent) error {
+	if f.failOn[event.ID] {
+		return errors.New("unavailable")
+	}
+	f.received = append(f.received, event.ID)
+	return nil
+}
+
+func TestOutboxRelayKeepsPerArticleOrder(t *testing.T) {
+	outbox := &fakeOutbox{pending: []domain.ArticleEvent{
+		{ID: 1, ArticleID: 10, Type: domain.ArticleCreated},
+		{ID: 2, ArticleID: 20, Type: domain.ArticleCreated},
+		{ID: 3, ArticleID: 10, Type: domain.ArticleUpdated},
+		{ID: 4, ArticleID: 20, Type: domain.ArticleUpdated},
+	}}
+	sink := &fakeSink{failOn: map[int64]bool{2: true}}
+	w := NewOutboxRelayWorker(outbox, fixedFence(7), sink)
+
+	err := w.relay(context.Background())
+
+	assert.NoError(t, err)
+	// event 4 must wait behind the failed event 2 of the same article
+	assert.Equal(t, []int64{1, 3}, sink.received)
+	assert.Equal(t, []int64{1, 3}, outbox.published)
+	assert.Equal(t, []int64{2}, outbox.failedIDs())
+	assert.Equal(t, int64(7), outbox.token)
+}
+
+func TestOutboxRelayRecordsSinkProgress(t *testing.T) {
+	outbox := &fakeOutbox{pending: []domain.ArticleEvent{{ID: 1, ArticleID: 10}}}
+	first := &fakeSink{name: "first"}
+	second := &fakeSink{name: "second", failOn: map[int64]bool{1: true}}
+	w := NewOutboxRelayWorker(outbox, fixedFence(7), first, second)
+
+	assert.NoError(t, w.relay(context.Background()))
+	require.Len(t, outbox.failed, 1)
+	assert.Equal(t, []string{"first"}, outbox.failed[0].Delivered)
+	assert.True(t, outbox.failed[0].RetryAt.After(time.Now()))
+
+	// the retry is not due yet
+	outbox.pending = outbox.failed
+	assert.NoError(t, w.relay(context.Background()))
+	assert.Len(t, outbox.failed, 1)
+
+	// once due, only the sink that failed gets the event again
+	second.failOn = nil
+	outbox.pending[0].RetryAt = time.Time{}
+	assert.NoError(t, w.relay(context.Background()))
+	assert.Equal(t, []int64{1}, first.received)
+	assert.Equal(t, []int64{1}, second.received)
+	assert.Equal(t, []int64{1}, outbox.published)
+}
+
+func TestOutboxRelayDeadLetters(t *testing.T) {
+	outbox := &fakeOutbox{pending: []domain.ArticleEvent{
+		{ID: 1, ArticleID: 10, Attempts: maxOutboxAttempts - 1},
+		{ID: 2, ArticleID: 10},
+	}}
+	sink := &fakeSink{failOn: map[int64]bool{1: true}}
+	w := NewOutboxRelayWorker(outbox, fixedFence(7), sink)
+
+	assert.NoError(t, w.relay(context.Background()))
+
+	// the dead event no longer holds back the later events of its article
+	assert.Equal(t, []int64{1}, outbox.dead)
+	assert.Empty(t, outbox.failed)
+	assert.Equal(t, []int64{2}, outbox.published)
+}
+
+func TestOutboxRelayRequiresLeadership(t *testing.T) {
+	outbox := &fakeOutbox{pending: []domain.ArticleEvent{{ID: 1, ArticleID: 10}}}
+	sink := &fakeSink{}
+	w := NewOutboxRelayWorker(outbox, fixedFence(0), sink)
+
+	err := w.relay(context.Background())
+
+	assert.ErrorIs(t, err, domain.ErrStaleLeader)
+	assert.Empty(t, sink.received)
+}
+
+func TestOutboxRelayPassesBlockedArticles(t *testing.T) {
+	// a full batch of events waits behind the failed event 1
+	pending := []domain.ArticleEvent{{ID: 1, ArticleID: 10, Attempts: 1, RetryAt: time.Now().Add(time.Minute)}}
+	for id := int64(2); id <= outboxBatchSize+10; id++ {
+		pending = append(pending, domain.ArticleEvent{ID: id, ArticleID: 10})
+	}
+	other := int64(outboxBatchSize + 11)
+	pending = append(pending, domain.ArticleEvent{ID: other, ArticleID: 20})
+	outbox := &fakeOutbox{pending: pending}
+	sink := &fakeSink{}
+	w := NewOutboxRelayWorker(outbox, fixedFence(7), sink)
+
+	assert.NoError(t, w.relay(context.Background()))
+	assert.Equal(t, []int64{other}, sink.received)
+	assert.Equal(t, []int64{other}, outbox.published)
+}
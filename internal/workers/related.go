@@ -0,0 +1,51 @@
+package workers
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+const relatedBatch = 200
+
+type RelatedRefresher interface {
+	Refresh(ctx context.Context, limit int) (int, error)
+}
+
+// RelatedWorker precomputes the related articles of every article, so that
+// serving them never compares articles
+type RelatedWorker struct {
+	Refresher RelatedRefresher
+}
+
+func NewRelatedWorker(r RelatedRefresher) *RelatedWorker {
+	return &RelatedWorker{
+		Refresher: r,
+	}
+}
+
+// Job computes a batch of recommendations every ten minutes, each run lists
+// the whole corpus and reads the content of the articles that changed
+func (w *RelatedWorker) Job() Job {
+	return Job{
+		Name:     "related-refresh",
+		Interval: 10 * time.Minute,
+		Jitter:   time.Minute,
+		Timeout:  5 * time.Minute,
+		Mode:     LeaderOnly,
+		Run:      w.refresh,
+	}
+}
+
+func (w *RelatedWorker) refresh(ctx context.Context) error {
+	n, err := w.Refresher.Refresh(ctx, relatedBatch)
+	if err != nil {
+		return fmt.Errorf("failed to refresh related articles: %w", err)
+	}
+	if n > 0 {
+		logrus.Infof("refreshed related articles of %d articles", n)
+	}
+	return nil
+}
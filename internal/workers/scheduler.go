@@ -0,0 +1,393 @@
+package workers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"math/rand/v2"
+	"runtime/debug"
+	"sync"
+	"time"
+
+	"github.com/robfig/cron/v3"
+
+	"github.com/bxcodec/go-clean-arch/domain"
+)
+
+const (
+	defaultJobTimeout  = time.Minute
+	daemonRestartDelay = time.Second
+	historySize        = 20
+)
+
+// Mode declares on which replicas a job is allowed to run
+type Mode int
+
+const (
+	// EveryReplica jobs run on each replica independently
+	EveryReplica Mode = iota
+	// LeaderOnly jobs run only on the elected leader
+	LeaderOnly
+)
+
+func (m Mode) String() string {
+	if m == LeaderOnly {
+		return "leader-only"
+	}
+	return "every-replica"
+}
+
+// LeaderElector notifies the Scheduler whenever this replica becomes leader
+type LeaderElector interface {
+	OnElected(fn func(ctx context.Context))
+}
+
+// Fence hands out the fencing token of the current leadership term, it is 0 when
+// this replica is not the leader. Leader-only jobs pass it along with their writes
+// so that the store can reject the writes of a replica that lost the lease.
+type Fence interface {
+	Token() int64
+}
+
+// fencingToken returns the token of the current term or an error when not leader
+func fencingToken(f Fence) (int64, error) {
+	token := f.Token()
+	if token <= 0 {
+		return 0, fmt.Errorf("not the leader: %w", domain.ErrStaleLeader)
+	}
+	return token, nil
+}
+
+// Job is a unit of background work run periodically by the Scheduler.
+// Exactly one of Interval or Cron (standard five field syntax) must be set.
+type Job struct {
+	Name     string
+	Interval time.Duration
+	Cron     string
+	// Jitter delays every run by a random duration in [0, Jitter)
+	Jitter time.Duration
+	// Timeout bounds a single run, it defaults to one minute
+	Timeout time.Duration
+	Mode    Mode
+	// RunOnStart runs the job as soon as it is scheduled
+	RunOnStart bool
+	// RunOnStop runs the job one last time when the scheduler shuts down
+	RunOnStop bool
+	Run       func(ctx context.Context) error
+}
+
+// Daemon is a long running background process such as a queue consumer.
+// It runs until its context is cancelled and is restarted when it returns early or panics.
+type Daemon struct {
+	Name string
+	Mode Mode
+	Run  func(ctx context.Context) error
+}
+
+type schedule interface {
+	Next(t time.Time) time.Time
+}
+
+type every time.Duration
+
+func (e every) Next(t time.Time) time.Time {
+	return t.Add(time.Duration(e))
+}
+
+type scheduledJob struct {
+	Job
+	schedule schedule
+	daemon   bool
+
+	mu      sync.Mutex
+	running bool
+	next    time.Time
+	stats   domain.ScheduledJob
+}
+
+// Scheduler runs registered jobs on their schedule until it is stopped
+type Scheduler struct {
+	elector LeaderElector
+
+	mu      sync.Mutex
+	ctx     context.Context
+	cancel  context.CancelFunc
+	stopped bool
+	jobs    []*scheduledJob
+	wg      sync.WaitGroup
+}
+
+// NewScheduler will create a scheduler. The elector may be nil when no leader-only job is registered.
+func NewScheduler(elector LeaderElector) *Scheduler {
+	return &Scheduler{
+		elector: elector,
+	}
+}
+
+// Register adds a job to the scheduler. It must be called before Start.
+func (s *Scheduler) Register(job Job) error {
+	if job.Name == "" || job.Run == nil {
+		return errors.New("job requires a name and a run function")
+	}
+
+	var (
+		sched schedule
+		desc  string
+	)
+	switch {
+	case job.Interval > 0 && job.Cron == "":
+		sched, desc = every(job.Interval), "@every "+job.Interval.String()
+	case job.Interval <= 0 && job.Cron != "":
+		parsed, err := cron.ParseStandard(job.Cron)
+		if err != nil {
+			return fmt.Errorf("job %s: invalid cron expression: %w", job.Name, err)
+		}
+		sched, desc = parsed, job.Cron
+	default:
+		return fmt.Errorf("job %s: exactly one of interval or cron is required", job.Name)
+	}
+
+	if job.Mode == LeaderOnly && s.elector == nil {
+		return fmt.Errorf("job %s: leader-only jobs require a leader elector", job.Name)
+	}
+	if job.Timeout <= 0 {
+		job.Timeout = defaultJobTimeout
+	}
+
+	return s.add(&scheduledJob{
+		Job:      job,
+		schedule: sched,
+		stats: domain.ScheduledJob{
+			Name:     job.Name,
+			Schedule: desc,
+			Mode:     job.Mode.String(),
+		},
+	})
+}
+
+// RegisterDaemon adds a long running process to the scheduler. It must be called before Start.
+func (s *Scheduler) RegisterDaemon(d Daemon) error {
+	if d.Name == "" || d.Run == nil {
+		return errors.New("daemon requires a name and a run function")
+	}
+	if d.Mode == LeaderOnly && s.elector == nil {
+		return fmt.Errorf("daemon %s: leader-only daemons require a leader elector", d.Name)
+	}
+	return s.add(&scheduledJob{
+		Job:    Job{Name: d.Name, Mode: d.Mode, Run: d.Run},
+		daemon: true,
+		stats: domain.ScheduledJob{
+			Name:     d.Name,
+			Schedule: "daemon",
+			Mode:     d.Mode.String(),
+		},
+	})
+}
+
+func (s *Scheduler) add(job *scheduledJob) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, j := range s.jobs {
+		if j.Name == job.Name {
+			return fmt.Errorf("job %s is already registered", job.Name)
+		}
+	}
+	s.jobs = append(s.jobs, job)
+	return nil
+}
+
+// Start schedules every-replica jobs right away and hands leader-only jobs to the elector,
+// which starts them on election. Leader-only jobs stop on shutdown or as soon as the
+// leadership is lost, which also cancels their in-flight run.
+func (s *Scheduler) Start(ctx context.Context) {
+	s.mu.Lock()
+	s.ctx, s.cancel = context.WithCancel(ctx)
+	jobs := s.jobs
+	s.mu.Unlock()
+
+	for _, j := range jobs {
+		log.Printf("scheduling job %s (%s, %s)", j.Name, j.stats.Schedule, j.Mode)
+		if j.Mode == LeaderOnly {
+			s.elector.OnElected(func(term context.Context) {
+				s.lead(term, j)
+			})
+			continue
+		}
+		// runs are detached from shutdown, they are bounded by the job timeout instead
+		s.spawn(s.ctx, context.WithoutCancel(s.ctx), j)
+	}
+}
+
+// lead schedules a leader-only job for the given leadership term until the term
+// ends or the scheduler is stopped. Its runs belong to the term, so they are
+// cancelled when the leadership is lost but not on shutdown.
+func (s *Scheduler) lead(term context.Context, j *scheduledJob) {
+	ctx, cancel := context.WithCancel(term)
+	defer cancel()
+	stop := context.AfterFunc(s.ctx, cancel)
+	defer stop()
+
+	s.spawn(ctx, term, j)
+	<-ctx.Done()
+}
+
+// Stop stops scheduling and waits until running jobs, including their final
+// RunOnStop execution, have returned or ctx is done. The leader elector must
+// keep its lease until Stop returns so that leader-only jobs flush as leader.
+func (s *Scheduler) Stop(ctx context.Context) error {
+	s.mu.Lock()
+	s.stopped = true
+	if s.cancel != nil {
+		s.cancel()
+	}
+	s.mu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		s.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return fmt.Errorf("scheduler stop: %w", ctx.Err())
+	}
+}
+
+// Jobs returns a snapshot of every registered job and its run history
+func (s *Scheduler) Jobs() []domain.ScheduledJob {
+	s.mu.Lock()
+	jobs := s.jobs
+	s.mu.Unlock()
+
+	res := make([]domain.ScheduledJob, 0, len(jobs))
+	for _, j := range jobs {
+		j.mu.Lock()
+		stats := j.stats
+		stats.Running = j.running
+		stats.NextRun = j.next
+		stats.History = append([]domain.JobRun(nil), j.stats.History...)
+		j.mu.Unlock()
+		res = append(res, stats)
+	}
+	return res
+}
+
+// spawn schedules j until ctx is done, every run is started under runCtx
+func (s *Scheduler) spawn(ctx, runCtx context.Context, j *scheduledJob) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.stopped {
+		return
+	}
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		s.loop(ctx, runCtx, j)
+	}()
+}
+
+func (s *Scheduler) loop(ctx, runCtx context.Context, j *scheduledJob) {
+	if j.daemon {
+		s.supervise(ctx, j)
+		return
+	}
+	if j.RunOnStart {
+		s.execute(runCtx, j)
+	}
+
+	for {
+		next := j.schedule.Next(time.Now())
+		if j.Jitter > 0 {
+			next = next.Add(rand.N(j.Jitter))
+		}
+		j.setNext(next)
+
+		timer := time.NewTimer(time.Until(next))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			j.setNext(time.Time{})
+			// only flush on shutdown, and for a leader-only job only while still leading
+			if j.RunOnStop && s.ctx.Err() != nil && runCtx.Err() == nil {
+				s.execute(runCtx, j)
+			}
+			return
+		case <-timer.C:
+			s.execute(runCtx, j)
+		}
+	}
+}
+
+// supervise keeps a daemon running until ctx is done
+func (s *Scheduler) supervise(ctx context.Context, j *scheduledJob) {
+	for {
+		j.track(ctx)
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(daemonRestartDelay):
+			log.Printf("daemon %s returned, restarting...", j.Name)
+		}
+	}
+}
+
+// execute runs the job once under runCtx, bounded by the job timeout
+func (s *Scheduler) execute(runCtx context.Context, j *scheduledJob) {
+	ctx, cancel := context.WithTimeout(runCtx, j.Timeout)
+	defer cancel()
+	j.track(ctx)
+}
+
+// track runs the job once and records the outcome in its stats
+func (j *scheduledJob) track(ctx context.Context) {
+	j.mu.Lock()
+	j.running = true
+	j.mu.Unlock()
+
+	started := time.Now()
+	panicked, err := safeRun(ctx, j.Run)
+	run := domain.JobRun{
+		StartedAt: started,
+		Duration:  time.Since(started),
+	}
+	if err != nil {
+		run.Err = err.Error()
+		log.Printf("job %s failed: %v", j.Name, err)
+	}
+
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	j.running = false
+	j.stats.Runs++
+	if err != nil {
+		j.stats.Failures++
+	}
+	if panicked {
+		j.stats.Panics++
+	}
+	j.stats.LastRun = run
+	j.stats.History = append(j.stats.History, run)
+	if len(j.stats.History) > historySize {
+		j.stats.History = j.stats.History[len(j.stats.History)-historySize:]
+	}
+}
+
+func (j *scheduledJob) setNext(next time.Time) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	j.next = next
+}
+
+func safeRun(ctx context.Context, run func(ctx context.Context) error) (panicked bool, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			log.Printf("job panicked(recovered): %v\n%s", r, debug.Stack())
+			panicked, err = true, fmt.Errorf("panic: %v", r)
+		}
+	}()
+	return false, run(ctx)
+}
@@ -0,0 +1,134 @@
+package workers_test
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bxcodec/go-clean-arch/internal/workers"
+)
+
+type fakeElector struct {
+	elected []func(ctx context.Context)
+}
+
+func (f *fakeElector) OnElected(fn func(ctx context.Context)) {
+	f.elected = append(f.elected, fn)
+}
+
+func TestRegisterValidation(t *testing.T) {
+	s := workers.NewScheduler(nil)
+	noop := func(context.Context) error { return nil }
+
+	assert.Error(t, s.Register(workers.Job{Name: "no-schedule", Run: noop}))
+	assert.Error(t, s.Register(workers.Job{Name: "both", Interval: time.Second, Cron: "* * * * *", Run: noop}))
+	assert.Error(t, s.Register(workers.Job{Name: "bad-cron", Cron: "not a cron", Run: noop}))
+	assert.Error(t, s.Register(workers.Job{Name: "leader", Interval: time.Second, Mode: workers.LeaderOnly, Run: noop}))
+	assert.NoError(t, s.Register(workers.Job{Name: "cron", Cron: "*/5 * * * *", Run: noop}))
+	assert.Error(t, s.Register(workers.Job{Name: "cron", Interval: time.Second, Run: noop}))
+}
+
+func TestSchedulerRunsAndRecovers(t *testing.T) {
+	s := workers.NewScheduler(nil)
+	var runs atomic.Int64
+	require.NoError(t, s.Register(workers.Job{
+		Name:       "flaky",
+		Interval:   10 * time.Millisecond,
+		RunOnStart: true,
+		Run: func(context.Context) error {
+			switch runs.Add(1) {
+			case 1:
+				panic("boom")
+			case 2:
+				return errors.New("failed")
+			}
+			return nil
+		},
+	}))
+
+	s.Start(context.Background())
+	assert.Eventually(t, func() bool { return runs.Load() >= 3 }, time.Second, 5*time.Millisecond)
+	require.NoError(t, s.Stop(context.Background()))
+
+	jobs := s.Jobs()
+	require.Len(t, jobs, 1)
+	assert.Equal(t, "flaky", jobs[0].Name)
+	assert.Equal(t, runs.Load(), jobs[0].Runs)
+	assert.Equal(t, int64(2), jobs[0].Failures)
+	assert.Equal(t, int64(1), jobs[0].Panics)
+	assert.Equal(t, "panic: boom", jobs[0].History[0].Err)
+	assert.False(t, jobs[0].Running)
+}
+
+func TestSchedulerLeaderOnlyRunOnStop(t *testing.T) {
+	elector := &fakeElector{}
+	s := workers.NewScheduler(elector)
+	var runs atomic.Int64
+	require.NoError(t, s.Register(workers.Job{
+		Name:      "flush",
+		Interval:  time.Hour,
+		Mode:      workers.LeaderOnly,
+		RunOnStop: true,
+		Run: func(context.Context) error {
+			runs.Add(1)
+			return nil
+		},
+	}))
+
+	s.Start(context.Background())
+	require.Len(t, elector.elected, 1)
+
+	// losing leadership stops the job without flushing
+	term, stepDown := context.WithCancel(context.Background())
+	go elector.elected[0](term)
+	scheduled := func() bool { return !s.Jobs()[0].NextRun.IsZero() }
+	assert.Eventually(t, scheduled, time.Second, time.Millisecond)
+	stepDown()
+	assert.Eventually(t, func() bool { return !scheduled() }, time.Second, time.Millisecond)
+
+	// shutting down while leading flushes once, before the term ends
+	term, stepDown = context.WithCancel(context.Background())
+	defer stepDown()
+	go elector.elected[0](term)
+	assert.Eventually(t, scheduled, time.Second, time.Millisecond)
+	require.NoError(t, s.Stop(context.Background()))
+	assert.Equal(t, int64(1), runs.Load())
+}
+
+func TestSchedulerLeadershipLossCancelsRun(t *testing.T) {
+	elector := &fakeElector{}
+	s := workers.NewScheduler(elector)
+	started := make(chan struct{})
+	cancelled := make(chan struct{})
+	require.NoError(t, s.Register(workers.Job{
+		Name:       "sync",
+		Interval:   time.Hour,
+		Timeout:    time.Minute,
+		Mode:       workers.LeaderOnly,
+		RunOnStart: true,
+		Run: func(ctx context.Context) error {
+			close(started)
+			<-ctx.Done()
+			close(cancelled)
+			return ctx.Err()
+		},
+	}))
+
+	s.Start(context.Background())
+	term, stepDown := context.WithCancel(context.Background())
+	go elector.elected[0](term)
+	<-started
+	stepDown()
+
+	select {
+	case <-cancelled:
+	case <-time.After(time.Second):
+		t.Fatal("the run outlived the leadership")
+	}
+	require.NoError(t, s.Stop(context.Background()))
+}
@@ -0,0 +1,50 @@
+package workers
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+const sitemapBatch = 20
+
+type SitemapGenerator interface {
+	Regenerate(ctx context.Context, limit int) (int, error)
+}
+
+// SitemapWorker rebuilds the sitemap chunks whose pages changed, so that
+// serving a sitemap never scans the article table
+type SitemapWorker struct {
+	Generator SitemapGenerator
+}
+
+func NewSitemapWorker(g SitemapGenerator) *SitemapWorker {
+	return &SitemapWorker{
+		Generator: g,
+	}
+}
+
+// Job rebuilds a batch of changed chunks every five minutes
+func (w *SitemapWorker) Job() Job {
+	return Job{
+		Name:     "sitemap-regenerate",
+		Interval: 5 * time.Minute,
+		Jitter:   30 * time.Second,
+		Timeout:  5 * time.Minute,
+		Mode:     LeaderOnly,
+		Run:      w.regenerate,
+	}
+}
+
+func (w *SitemapWorker) regenerate(ctx context.Context) error {
+	n, err := w.Generator.Regenerate(ctx, sitemapBatch)
+	if err != nil {
+		return fmt.Errorf("failed to regenerate sitemaps: %w", err)
+	}
+	if n > 0 {
+		logrus.Infof("regenerated %d sitemap chunks", n)
+	}
+	return nil
+}
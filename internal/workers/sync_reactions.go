@@ -0,0 +1,114 @@
+package workers
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/bxcodec/go-clean-arch/domain"
+)
+
+// reconcileBatchSize is the number of articles whose counters are recounted per run
+const reconcileBatchSize = 100
+
+type SyncReactionsWorker struct {
+	ReactionRepo  domain.ReactionRepository
+	ReactionCache domain.ReactionCache
+	Fence         Fence
+
+	// cursor is the last article recounted, the recount wraps around once every
+	// article was visited
+	cursor int64
+}
+
+func NewSyncReactionsWorker(rr domain.ReactionRepository, rc domain.ReactionCache, f Fence) *SyncReactionsWorker {
+	return &SyncReactionsWorker{
+		ReactionRepo:  rr,
+		ReactionCache: rc,
+		Fence:         f,
+	}
+}
+
+// Job syncs the reaction counters the same way SyncViewsWorker syncs views.
+// Every run also recounts a slice of the counters from the stored reactions,
+// which repairs the drift left by increments lost after the reaction was stored.
+func (s *SyncReactionsWorker) Job() Job {
+	return Job{
+		Name:       "sync-reactions",
+		Interval:   1 * time.Minute,
+		Jitter:     5 * time.Second,
+		Timeout:    30 * time.Second,
+		Mode:       LeaderOnly,
+		RunOnStart: true,
+		RunOnStop:  true,
+		Run:        s.syncReactions,
+	}
+}
+
+func (s *SyncReactionsWorker) syncReactions(ctx context.Context) error {
+	token, err := fencingToken(s.Fence)
+	if err != nil {
+		return err
+	}
+
+	drained, err := syncCounters(ctx, "reactions", token, counterStore[domain.ReactionKey]{
+		Fetch: func(ctx context.Context) (string, map[domain.ReactionKey]int64, error) {
+			batch, err := s.ReactionCache.FetchReactions(ctx)
+			return batch.ID, batch.Counts, err
+		},
+		Apply: func(ctx context.Context, batchID string, counts map[domain.ReactionKey]int64, token int64) error {
+			return s.ReactionRepo.AddCounts(ctx, domain.ReactionBatch{ID: batchID, Counts: counts, FencingToken: token})
+		},
+		Fail: s.ReactionCache.FailReactions,
+		Ack:  s.ReactionCache.AckReactions,
+	})
+	if err != nil {
+		return err
+	}
+	if drained {
+		if err := s.ReactionRepo.PurgeBatches(ctx, time.Now().Add(-syncedBatchRetention)); err != nil {
+			logrus.Warnf("failed to purge synced reaction batches: %v", err)
+		}
+	}
+	return s.reconcile(ctx, token)
+}
+
+// reconcile overwrites a slice of the counters with the number of stored
+// reactions. The recount and the buffered deltas are separate reads, a
+// reaction stored in between would be counted wrong, so counters with deltas
+// still buffered or in a batch being synced are left for a later pass.
+func (s *SyncReactionsWorker) reconcile(ctx context.Context, token int64) error {
+	counts, err := s.ReactionRepo.Recount(ctx, s.cursor, reconcileBatchSize)
+	if err != nil {
+		return fmt.Errorf("failed to recount reactions: %w", err)
+	}
+
+	keys := make([]domain.ReactionKey, 0, len(counts))
+	articles := make(map[int64]bool)
+	last := int64(0)
+	for key := range counts {
+		keys = append(keys, key)
+		articles[key.ArticleID] = true
+		last = max(last, key.ArticleID)
+	}
+	pending, err := s.ReactionCache.PendingKeys(ctx, keys)
+	if err != nil {
+		return fmt.Errorf("failed to get pending reactions: %w", err)
+	}
+	for key, n := range pending {
+		if n != 0 {
+			delete(counts, key)
+		}
+	}
+
+	if err := s.ReactionRepo.SetCounts(ctx, counts, token); err != nil {
+		return err
+	}
+	s.cursor = last
+	if len(articles) < reconcileBatchSize {
+		s.cursor = 0
+	}
+	return nil
+}
@@ -0,0 +1,146 @@
+package workers
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bxcodec/go-clean-arch/domain"
+)
+
+type fakeReactionRepo struct {
+	err      error
+	applied  []domain.ReactionBatch
+	purged   int
+	recount  map[domain.ReactionKey]int64
+	afterIDs []int64
+	set      map[domain.ReactionKey]int64
+}
+
+func (f *fakeReactionRepo) Add(context.Context, *domain.Reaction) (bool, error)    { return false, nil }
+func (f *fakeReactionRepo) Remove(context.Context, *domain.Reaction) (bool, error) { return false, nil }
+func (f *fakeReactionRepo) ListKinds(context.Context, int64, int64) ([]string, error) {
+	return nil, nil
+}
+func (f *fakeReactionRepo) Counts(context.Context, int64) (map[string]int64, error) {
+	return nil, nil
+}
+
+func (f *fakeReactionRepo) AddCounts(_ context.Context, batch domain.ReactionBatch) error {
+	if f.err != nil {
+		return f.err
+	}
+	f.applied = append(f.applied, batch)
+	return nil
+}
+
+func (f *fakeReactionRepo) PurgeBatches(context.Context, time.Time) error {
+	f.purged++
+	return nil
+}
+
+func (f *fakeReactionRepo) Recount(_ context.Context, afterArticleID int64, _ int) (map[domain.ReactionKey]int64, error) {
+	f.afterIDs = append(f.afterIDs, afterArticleID)
+	res := make(map[domain.ReactionKey]int64)
+	for key, n := range f.recount {
+		if key.ArticleID > afterArticleID {
+			res[key] = n
+		}
+	}
+	return res, nil
+}
+
+func (f *fakeReactionRepo) SetCounts(_ context.Context, counts map[domain.ReactionKey]int64, _ int64) error {
+	f.set = counts
+	return nil
+}
+
+type fakeReactionCache struct {
+	domain.ReactionCache
+	batch    domain.ReactionBatch
+	pending  map[domain.ReactionKey]int64
+	acked    []domain.ReactionKey
+	attempts int
+}
+
+func (f *fakeReactionCache) FetchReactions(context.Context) (domain.ReactionBatch, error) {
+	return f.batch, nil
+}
+
+func (f *fakeReactionCache) AckReactions(_ context.Context, _ string, keys []domain.ReactionKey) error {
+	f.acked = append(f.acked, keys...)
+	return nil
+}
+
+func (f *fakeReactionCache) FailReactions(_ context.Context, _ string, maxAttempts int) (bool, error) {
+	f.attempts++
+	return f.attempts >= maxAttempts, nil
+}
+
+func (f *fakeReactionCache) PendingKeys(_ context.Context, keys []domain.ReactionKey) (map[domain.ReactionKey]int64, error) {
+	res := make(map[domain.ReactionKey]int64)
+	for _, key := range keys {
+		if n, ok := f.pending[key]; ok {
+			res[key] = n
+		}
+	}
+	return res, nil
+}
+
+func TestSyncReactions(t *testing.T) {
+	like := domain.ReactionKey{ArticleID: 1, Kind: "like"}
+	wow := domain.ReactionKey{ArticleID: 2, Kind: "wow"}
+	repo := &fakeReactionRepo{recount: map[domain.ReactionKey]int64{like: 5, wow: 0}}
+	cache := &fakeReactionCache{
+		batch:   domain.ReactionBatch{ID: "batch-1", Counts: map[domain.ReactionKey]int64{like: 2}},
+		pending: map[domain.ReactionKey]int64{like: 1},
+	}
+	w := NewSyncReactionsWorker(repo, cache, fixedFence(3))
+
+	require.NoError(t, w.syncReactions(context.Background()))
+
+	require.Len(t, repo.applied, 1)
+	assert.Equal(t, int64(3), repo.applied[0].FencingToken)
+	assert.Equal(t, []domain.ReactionKey{like}, cache.acked)
+	assert.Zero(t, repo.purged)
+	// the counter with a delta still waiting in redis is left alone
+	assert.Equal(t, map[domain.ReactionKey]int64{wow: 0}, repo.set)
+
+	// every article was recounted, so the next run starts over
+	cache.batch = domain.ReactionBatch{}
+	require.NoError(t, w.syncReactions(context.Background()))
+	assert.Equal(t, []int64{0, 0}, repo.afterIDs)
+	assert.Equal(t, 1, repo.purged)
+}
+
+func TestSyncReactionsFailure(t *testing.T) {
+	repo := &fakeReactionRepo{err: errors.New("unavailable")}
+	cache := &fakeReactionCache{batch: domain.ReactionBatch{ID: "batch-1", Counts: map[domain.ReactionKey]int64{{ArticleID: 1, Kind: "like"}: 1}}}
+	w := NewSyncReactionsWorker(repo, cache, fixedFence(3))
+
+	assert.Error(t, w.syncReactions(context.Background()))
+	assert.Equal(t, 1, cache.attempts)
+	assert.Empty(t, cache.acked)
+	assert.Nil(t, repo.set, "counters are not recounted while a batch is stuck")
+
+	repo.err = domain.ErrStaleLeader
+	assert.ErrorIs(t, w.syncReactions(context.Background()), domain.ErrStaleLeader)
+	assert.Equal(t, 1, cache.attempts, "a rejected write does not count against the batch")
+}
+
+func TestReconcileSkipsCountersWithConcurrentReactions(t *testing.T) {
+	like := domain.ReactionKey{ArticleID: 1, Kind: "like"}
+	wow := domain.ReactionKey{ArticleID: 1, Kind: "wow"}
+	// a like is stored after the recount read 5 and buffers its +1 before
+	// the pending deltas are read, 6 - 1 would lose it
+	repo := &fakeReactionRepo{recount: map[domain.ReactionKey]int64{like: 5, wow: 2}}
+	cache := &fakeReactionCache{pending: map[domain.ReactionKey]int64{like: 1, wow: 0}}
+	w := NewSyncReactionsWorker(repo, cache, fixedFence(3))
+
+	require.NoError(t, w.reconcile(context.Background(), 3))
+	assert.Equal(t, map[domain.ReactionKey]int64{wow: 2}, repo.set)
+}
@@ -2,7 +2,6 @@ package workers
 
 import (
 	"context"
-	"log"
 	"time"
 
 	"github.com/bxcodec/go-clean-arch/domain"
@@ -12,80 +11,58 @@ import (
 type SyncViewsWorker struct {
 	ArticleRepo  domain.ArticleRepository
 	ArticleCache domain.ArticleCache
+	Fence        Fence
 }
 
-func NewSyncViewWorker(ar domain.ArticleRepository, ac domain.ArticleCache) *SyncViewsWorker {
+func NewSyncViewWorker(ar domain.ArticleRepository, ac domain.ArticleCache, f Fence) *SyncViewsWorker {
 	return &SyncViewsWorker{
 		ArticleRepo:  ar,
 		ArticleCache: ac,
+		Fence:        f,
 	}
 }
 
-func (s *SyncViewsWorker) Start(ctx context.Context) {
-	go func() {
-		for {
-			select {
-			case <-ctx.Done():
-				log.Println("SyncViewWorker stoped...")
-				return
-			default:
-
-			}
-
-			s.safeRun(ctx)
-
-			time.Sleep(1 * time.Second)
-			log.Println("Worker restarting...")
-		}
-	}()
-}
-
-func (s *SyncViewsWorker) safeRun(ctx context.Context) {
-	defer func() {
-		if err := recover(); err != nil {
-			log.Printf("SyncViewWorker cashed(recovered): %v", err)
-		}
-	}()
-
-	ticker := time.NewTicker(1 * time.Minute)
-	defer ticker.Stop()
-
-	for {
-		select {
-		case <-ctx.Done():
-			s.flush(context.Background())
-			return
-		case <-ticker.C:
-			s.sync(context.Background())
-		}
+// Job syncs the views buffer every minute. It is leader-only since every replica
+// shares the same buffer, runs on start to recover a batch left behind by a
+// previous process and runs on stop to flush the buffer.
+func (s *SyncViewsWorker) Job() Job {
+	return Job{
+		Name:       "sync-views",
+		Interval:   1 * time.Minute,
+		Jitter:     5 * time.Second,
+		Timeout:    30 * time.Second,
+		Mode:       LeaderOnly,
+		RunOnStart: true,
+		RunOnStop:  true,
+		Run:        s.syncViews,
 	}
 }
 
-func (s *SyncViewsWorker) syncViews(ctx context.Context) {
-	views, err := s.ArticleCache.FetchAndResetViews(ctx)
+func (s *SyncViewsWorker) syncViews(ctx context.Context) error {
+	token, err := fencingToken(s.Fence)
 	if err != nil {
-		log.Printf("failed to get views from redis: %v", err)
-		return
+		return err
 	}
 
-	if len(views) == 0 {
-		return
-	}
-
-	for id, view := range views {
-		err = s.ArticleRepo.AddViews(ctx, id, view)
-		if err != nil {
-			logrus.Warnf("failed to update views: %v", err)
-			continue
-		}
-
+	drained, err := syncCounters(ctx, "views", token, counterStore[int64]{
+		Fetch: func(ctx context.Context) (string, map[int64]int64, error) {
+			batch, err := s.ArticleCache.FetchViews(ctx)
+			return batch.ID, batch.Views, err
+		},
+		Apply: func(ctx context.Context, batchID string, views map[int64]int64, token int64) error {
+			return s.ArticleRepo.AddViews(ctx, domain.ViewBatch{ID: batchID, Views: views, FencingToken: token})
+		},
+		Fail: s.ArticleCache.FailViews,
+		Ack:  s.ArticleCache.AckViews,
+	})
+	if drained {
+		s.purge(ctx)
 	}
+	return err
 }
 
-func (s *SyncViewsWorker) sync(ctx context.Context) {
-	s.syncViews(ctx)
-}
-
-func (s *SyncViewsWorker) flush(ctx context.Context) {
-	s.syncViews(ctx)
+func (s *SyncViewsWorker) purge(ctx context.Context) {
+	if err := s.ArticleRepo.PurgeViewBatches(ctx, time.Now().Add(-syncedBatchRetention)); err != nil {
+		logrus.Warnf("failed to purge synced view batches: %v", err)
+	}
 }
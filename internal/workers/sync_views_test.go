@@ -0,0 +1,95 @@
+package workers
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/bxcodec/go-clean-arch/domain"
+)
+
+type fixedFence int64
+
+func (f fixedFence) Token() int64 { return int64(f) }
+
+type fakeViewRepo struct {
+	domain.ArticleRepository
+	err     error
+	applied []domain.ViewBatch
+	purged  int
+}
+
+func (f *fakeViewRepo) AddViews(_ context.Context, batch domain.ViewBatch) error {
+	if f.err != nil {
+		return f.err
+	}
+	f.applied = append(f.applied, batch)
+	return nil
+}
+
+func (f *fakeViewRepo) PurgeViewBatches(context.Context, time.Time) error {
+	f.purged++
+	return nil
+}
+
+type fakeViewCache struct {
+	domain.ArticleCache
+	batch    domain.ViewBatch
+	acked    []int64
+	attempts int
+}
+
+func (f *fakeViewCache) FetchViews(context.Context) (domain.ViewBatch, error) {
+	return f.batch, nil
+}
+
+func (f *fakeViewCache) AckViews(_ context.Context, _ string, ids []int64) error {
+	f.acked = append(f.acked, ids...)
+	return nil
+}
+
+func (f *fakeViewCache) FailViews(_ context.Context, _ string, maxAttempts int) (bool, error) {
+	f.attempts++
+	return f.attempts >= maxAttempts, nil
+}
+
+func TestSyncViews(t *testing.T) {
+	repo := &fakeViewRepo{}
+	cache := &fakeViewCache{batch: domain.ViewBatch{ID: "batch-1", Views: map[int64]int64{1: 3}}}
+	w := NewSyncViewWorker(repo, cache, fixedFence(3))
+
+	assert.NoError(t, w.syncViews(context.Background()))
+	synced := cache.batch
+	synced.FencingToken = 3
+	assert.Equal(t, []domain.ViewBatch{synced}, repo.applied)
+	assert.Equal(t, []int64{1}, cache.acked)
+
+	cache.batch = domain.ViewBatch{Views: map[int64]int64{}}
+	assert.NoError(t, w.syncViews(context.Background()))
+	assert.Equal(t, 1, repo.purged, "applied batches are purged once the buffer is drained")
+}
+
+func TestSyncViewsFailure(t *testing.T) {
+	repo := &fakeViewRepo{err: errors.New("unavailable")}
+	cache := &fakeViewCache{batch: domain.ViewBatch{ID: "batch-1", Views: map[int64]int64{1: 3}}}
+	w := NewSyncViewWorker(repo, cache, fixedFence(3))
+
+	for range maxSyncAttempts {
+		assert.Error(t, w.syncViews(context.Background()))
+	}
+	assert.Equal(t, maxSyncAttempts, cache.attempts)
+	assert.Empty(t, cache.acked, "a failed batch is never acknowledged")
+}
+
+func TestSyncViewsStaleLeader(t *testing.T) {
+	repo := &fakeViewRepo{err: domain.ErrStaleLeader}
+	cache := &fakeViewCache{batch: domain.ViewBatch{ID: "batch-1", Views: map[int64]int64{1: 3}}}
+	w := NewSyncViewWorker(repo, cache, fixedFence(3))
+
+	assert.ErrorIs(t, w.syncViews(context.Background()), domain.ErrStaleLeader)
+	assert.Zero(t, cache.attempts, "a rejected write does not count against the batch")
+	assert.Empty(t, cache.acked)
+}
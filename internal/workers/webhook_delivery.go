@@ -0,0 +1,33 @@
+package workers
+
+import (
+	"context"
+	"time"
+)
+
+type WebhookDeliverer interface {
+	DeliverDue(ctx context.Context) error
+}
+
+// WebhookDeliveryWorker sends pending webhook deliveries and their retries
+type WebhookDeliveryWorker struct {
+	Deliverer WebhookDeliverer
+}
+
+func NewWebhookDeliveryWorker(d WebhookDeliverer) *WebhookDeliveryWorker {
+	return &WebhookDeliveryWorker{
+		Deliverer: d,
+	}
+}
+
+// Job sends due deliveries every five seconds. It is leader-only so that a
+// delivery is never sent by two replicas at once.
+func (w *WebhookDeliveryWorker) Job() Job {
+	return Job{
+		Name:     "webhook-delivery",
+		Interval: 5 * time.Second,
+		Timeout:  2 * time.Minute,
+		Mode:     LeaderOnly,
+		Run:      w.Deliverer.DeliverDue,
+	}
+}